@@ -23,6 +23,11 @@ const (
 	// response
 
 	LIMIT_PAGINATED_DATA int = 100
+
+	// storage
+
+	MAX_UPLOAD_SIZE   int64 = 20 << 20  // 20 MB
+	STORAGE_DIR_LOCAL       = "storage" // fallback of STORAGE_DIR env
 )
 
 var (
@@ -38,4 +43,39 @@ var (
 	CRON_USER_INTERVAL     string        = "0 2 * * *"           // every day at 2 AM
 	CRON_USER_DELETE_AFTER time.Duration = (time.Hour * 24) * 30 // 30 days
 	CRON_REVOKED_INTERVAL  string        = "0 */6 * * *"         // every 6 hours
+	CRON_UNSETTED_INTERVAL string        = "0 1 * * *"           // every day at 1 AM, before archived users are deleted
+	CRON_UNSETTED_EXPIRY   time.Duration = (time.Hour * 24) * 30 // 30 days, sign up without approval is archived after this
+	CRON_GRANT_INTERVAL    string        = "0 * * * *"           // every hour, expired grants are ignored by permission check before cleaned up
+	CRON_CHANGE_INTERVAL   string        = "30 * * * *"          // every hour, expired change requests can not be approved before marked
+
+	// change request
+
+	CHANGE_REQUEST_EXPIRY time.Duration = time.Hour * 24 // sensitive change must be approved by another admin within this duration
+
+	// alumni
+
+	ALUMNI_ACCESS_DURATION time.Duration = (time.Hour * 24) * 365 // 1 year after graduation, used if access date not provided
+
+	// quiz
+
+	QUIZ_SUBMIT_GRACE time.Duration = time.Minute // tolerance of network delay after attempt deadline
+
+	// internship
+
+	INTERNSHIP_EVALUATION_LINK_EXPIRY time.Duration = (time.Hour * 24) * 30 // 30 days, evaluation link of industry mentor
+
+	// storage
+
+	ALLOWED_UPLOAD_MIMES = []string{
+		"application/pdf",
+		"application/msword",
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		"application/vnd.ms-powerpoint",
+		"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+		"application/vnd.ms-excel",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"image/png",
+		"image/jpeg",
+		"video/mp4",
+	}
 )
@@ -15,4 +15,6 @@ var (
 	ACCESS_SECRET  = os.Getenv("ACCESS_SECRET")
 
 	INITIATE_ADMIN_KEY = os.Getenv("INITIATE_ADMIN_KEY")
+
+	STORAGE_DIR = os.Getenv("STORAGE_DIR")
 )
@@ -12,7 +12,8 @@ func Migrate(db *gorm.DB) {
 		string(models.RoleStudent),
 		string(models.RoleTeacher),
 		string(models.RoleAdmin),
-		string(models.RoleUnsetted)},
+		string(models.RoleUnsetted),
+		string(models.RoleAlumni)},
 	); err != nil {
 		log.Fatal("[MIGRATE] failed to create user role enum", err.Error())
 	}
@@ -35,10 +36,165 @@ func Migrate(db *gorm.DB) {
 
 		string(models.ResourceSubject),
 		string(models.ResourceClass),
+
+		string(models.ResourceAlumni),
+		string(models.ResourceAdmission),
+		string(models.ResourceMaterial),
+		string(models.ResourceAssignment),
+		string(models.ResourceQuiz),
+		string(models.ResourceQuestionBank),
+		string(models.ResourceExam),
+		string(models.ResourceRoom),
+		string(models.ResourceHealth),
+		string(models.ResourceAchievement),
+		string(models.ResourceScholarship),
+		string(models.ResourceDormitory),
+		string(models.ResourceInternship),
+		string(models.ResourceTimetable),
 	}); err != nil {
 		log.Fatal("[MIGRATE] failed to create permission resource enum", err.Error())
 	}
 
+	if err := CreateEnum(db, "transfer_direction", []string{
+		string(models.TransferIn),
+		string(models.TransferOut),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create transfer direction enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "admission_stage", []string{
+		string(models.StageSubmitted),
+		string(models.StageVerified),
+		string(models.StageTested),
+		string(models.StageAccepted),
+		string(models.StageRejected),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create admission stage enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "material_type", []string{
+		string(models.MaterialFile),
+		string(models.MaterialLink),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create material type enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "score_source", []string{
+		string(models.ScoreAssignment),
+		string(models.ScoreQuiz),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create score source enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "question_type", []string{
+		string(models.QuestionMultipleChoice),
+		string(models.QuestionMultipleAnswer),
+		string(models.QuestionShortAnswer),
+		string(models.QuestionEssay),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create question type enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "question_difficulty", []string{
+		string(models.DifficultyEasy),
+		string(models.DifficultyMedium),
+		string(models.DifficultyHard),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create question difficulty enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "exam_type", []string{
+		string(models.ExamMidterm),
+		string(models.ExamFinal),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create exam type enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "room_type", []string{
+		string(models.RoomClassroom),
+		string(models.RoomLab),
+		string(models.RoomHall),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create room type enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "booking_status", []string{
+		string(models.BookingPending),
+		string(models.BookingApproved),
+		string(models.BookingRejected),
+		string(models.BookingCancelled),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create booking status enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "achievement_level", []string{
+		string(models.LevelSchool),
+		string(models.LevelDistrict),
+		string(models.LevelProvince),
+		string(models.LevelNational),
+		string(models.LevelInternational),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create achievement level enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "scholarship_source", []string{
+		string(models.ScholarshipInternal),
+		string(models.ScholarshipGovernment),
+		string(models.ScholarshipPrivate),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create scholarship source enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "night_roll_status", []string{
+		string(models.NightPresent),
+		string(models.NightPermitted),
+		string(models.NightSick),
+		string(models.NightAbsent),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create night roll status enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "permission_audit_action", []string{
+		string(models.AuditGrantPermission),
+		string(models.AuditRevokePermission),
+		string(models.AuditGrantBundle),
+		string(models.AuditRevokeBundle),
+		string(models.AuditBundleAdd),
+		string(models.AuditBundleRemove),
+		string(models.AuditBundleDelete),
+		string(models.AuditExpirePermission),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create permission audit action enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "change_request_kind", []string{
+		string(models.ChangeGrantPermission),
+		string(models.ChangeRevokePermission),
+		string(models.ChangeSetRole),
+		string(models.ChangeRemoveRole),
+		string(models.ChangeGrantBundle),
+		string(models.ChangeRevokeBundle),
+		string(models.ChangeUpdateBundle),
+		string(models.ChangeDeleteBundle),
+		string(models.ChangeDeactivateAdmin),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create change request kind enum", err.Error())
+	}
+
+	if err := CreateEnum(db, "change_request_status", []string{
+		string(models.ChangePending),
+		string(models.ChangeApproved),
+		string(models.ChangeRejected),
+		string(models.ChangeExpired),
+	}); err != nil {
+		log.Fatal("[MIGRATE] failed to create change request status enum", err.Error())
+	}
+
+	// direct grants of permission carry their validity
+	if err := db.SetupJoinTable(&models.Admin{}, "Permissions", &models.AdminPermission{}); err != nil {
+		log.Fatal("[MIGRATE] failed to setup admin permissions join table", err.Error())
+	}
+
 	if err := db.AutoMigrate(
 		&models.User{},
 		&models.Student{},
@@ -46,10 +202,68 @@ func Migrate(db *gorm.DB) {
 		&models.Admin{},
 		&models.Teacher{},
 		&models.Permission{},
+		&models.PermissionBundle{},
+		&models.PermissionAudit{},
+		&models.Notification{},
+		&models.ChangeRequest{},
+		&models.Room{},
 		&models.Class{},
 		&models.Subject{},
 		&models.Revoked{},
+		&models.StudentTransfer{},
+		&models.Alumni{},
+		&models.Admission{},
+		&models.StoredFile{},
+		&models.LearningMaterial{},
+		&models.Score{},
+		&models.Assignment{},
+		&models.Submission{},
+		&models.BankQuestion{},
+		&models.BankQuestionVersion{},
+		&models.Quiz{},
+		&models.QuizQuestion{},
+		&models.QuizWindow{},
+		&models.QuizAttempt{},
+		&models.QuizAnswer{},
+		&models.ExamPeriod{},
+		&models.ExamRoom{},
+		&models.ExamSession{},
+		&models.ExamSeat{},
+		&models.ExamInvigilator{},
+		&models.RoomBooking{},
+		&models.StudentHealth{},
+		&models.ClinicVisit{},
+		&models.Achievement{},
+		&models.ScholarshipProgram{},
+		&models.ScholarshipRecipient{},
+		&models.ScholarshipDisbursement{},
+		&models.Dormitory{},
+		&models.DormRoom{},
+		&models.DormAssignment{},
+		&models.NightRoll{},
+		&models.IndustryPartner{},
+		&models.InternshipPlacement{},
+		&models.InternshipJournal{},
+		&models.MonitoringVisit{},
+		&models.TimetableSlot{},
+		&models.TeachingJournal{},
 	); err != nil {
 		log.Fatal("[MIGRATE] failed to migrate databases model", err.Error())
 	}
+
+	// fill roles of users created before users could hold multiple roles
+	if err := db.Exec("UPDATE users SET roles = json_build_array(role)::text WHERE roles IN ('[]', 'null') AND role <> ?", models.RoleUnsetted).Error; err != nil {
+		log.Fatal("[MIGRATE] failed to fill roles of users", err.Error())
+	}
+
+	// mark users activated before activation was recorded, user with a role or a profile of any role has been activated
+	if err := db.Exec(`UPDATE users SET activated_at = created_at WHERE activated_at IS NULL AND (
+		role <> ?
+		OR EXISTS (SELECT 1 FROM students WHERE students.user_id = users.id)
+		OR EXISTS (SELECT 1 FROM teachers WHERE teachers.user_id = users.id)
+		OR EXISTS (SELECT 1 FROM admins WHERE admins.user_id = users.id)
+		OR EXISTS (SELECT 1 FROM alumni WHERE alumni.user_id = users.id)
+	)`, models.RoleUnsetted).Error; err != nil {
+		log.Fatal("[MIGRATE] failed to mark activated users", err.Error())
+	}
 }
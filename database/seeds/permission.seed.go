@@ -18,6 +18,21 @@ const (
 
 	PermSubjectName = "subject full manage"
 	PermClassName   = "class full manage"
+
+	PermAlumniName       = "alumni full manage"
+	PermAdmissionName    = "admission full manage"
+	PermMaterialName     = "material full manage"
+	PermAssignmentName   = "assignment full manage"
+	PermQuizName         = "quiz full manage"
+	PermQuestionBankName = "question bank full manage"
+	PermExamName         = "exam full manage"
+	PermRoomName         = "room full manage"
+	PermHealthName       = "health full manage"
+	PermAchievementName  = "achievement full manage"
+	PermScholarshipName  = "scholarship full manage"
+	PermDormitoryName    = "dormitory full manage"
+	PermInternshipName   = "internship full manage"
+	PermTimetableName    = "timetable full manage"
 )
 
 var PermissionSeeds = []models.Permission{
@@ -71,6 +86,91 @@ var PermissionSeeds = []models.Permission{
 		Description: "Full access to manage parents",
 		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
 	},
+
+	{
+		Name:        PermAlumniName,
+		Resource:    models.ResourceAlumni,
+		Description: "Full access to manage graduation and alumni",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermAdmissionName,
+		Resource:    models.ResourceAdmission,
+		Description: "Full access to review and enroll admission applicants",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermMaterialName,
+		Resource:    models.ResourceMaterial,
+		Description: "Full access to manage learning materials of all classes",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermAssignmentName,
+		Resource:    models.ResourceAssignment,
+		Description: "Full access to manage homework assignments and submissions of all classes",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermQuizName,
+		Resource:    models.ResourceQuiz,
+		Description: "Full access to manage quizzes, windows and attempts of all classes",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermQuestionBankName,
+		Resource:    models.ResourceQuestionBank,
+		Description: "Full access to manage question bank of all subjects",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermExamName,
+		Resource:    models.ResourceExam,
+		Description: "Full access to manage exam schedules, room allocation and invigilators",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermRoomName,
+		Resource:    models.ResourceRoom,
+		Description: "Full access to manage rooms, facilities and room bookings",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermHealthName,
+		Resource:    models.ResourceHealth,
+		Description: "Full access to manage health records and clinic visits of students",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermAchievementName,
+		Resource:    models.ResourceAchievement,
+		Description: "Full access to manage achievements and portfolios of all students",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermScholarshipName,
+		Resource:    models.ResourceScholarship,
+		Description: "Full access to manage scholarship programs, recipients and disbursements",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermDormitoryName,
+		Resource:    models.ResourceDormitory,
+		Description: "Full access to manage dormitories, room assignments, supervisors and night rolls",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermInternshipName,
+		Resource:    models.ResourceInternship,
+		Description: "Full access to manage industry partners, internship placements, journals, monitoring visits and evaluations",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
+	{
+		Name:        PermTimetableName,
+		Resource:    models.ResourceTimetable,
+		Description: "Full access to manage timetable slots and teaching journals, including journal compliance report",
+		Actions:     []models.PermissionAction{models.ActionCreate, models.ActionRead, models.ActionUpdate, models.ActionDelete},
+	},
 }
 
 var PermissionSeedIDs = make(map[string]struct{}, len(PermissionSeeds))
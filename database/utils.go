@@ -38,6 +38,25 @@ func CreateEnum(db *gorm.DB, name string, enums []string) error {
 			}
 		}
 
+		// append new values if existing values unchanged, keep columns that depend on enum
+		if len(existingValues) < len(enums) {
+			prefixSame := true
+			for i, val := range existingValues {
+				if "'"+val+"'" != enums[i] {
+					prefixSame = false
+					break
+				}
+			}
+			if prefixSame {
+				for _, val := range enums[len(existingValues):] {
+					if err := db.Exec(fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS %s", name, val)).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+
 		// delete to update
 		if err := db.Exec(fmt.Sprintf("DROP TYPE IF EXISTS %s CASCADE", name)).Error; err != nil {
 			return err
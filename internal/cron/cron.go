@@ -2,8 +2,10 @@ package cron
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"school-information-system/config"
+	"school-information-system/internal/models"
 	"school-information-system/internal/repos"
 	"time"
 
@@ -15,6 +17,10 @@ import (
 func New(db *gorm.DB) *cron.Cron {
 	userRepo := repos.NewUser(db)
 	revokedRepo := repos.NewRevoked(db)
+	adminRepo := repos.NewAdmin(db)
+	auditRepo := repos.NewPermissionAudit(db)
+	notificationRepo := repos.NewNotification(db)
+	changeRepo := repos.NewChangeRequest(db)
 	ctx := context.Background()
 
 	loc, err := time.LoadLocation(config.APP_TIMEZONE)
@@ -39,6 +45,20 @@ func New(db *gorm.DB) *cron.Cron {
 		}
 	})
 
+	// Archive sign ups which are not activated for more than configured duration, purged later with other archived users
+	scheduler.AddFunc(config.CRON_UNSETTED_INTERVAL, func() {
+		rows, err := userRepo.ArchiveAll(ctx, "role = ? AND activated_at IS NULL AND created_at <= ?", models.RoleUnsetted, time.Now().Add(-config.CRON_UNSETTED_EXPIRY))
+
+		if err != nil {
+			log.Printf("[CLEANUP] Failed to archive expired sign ups: %v", err)
+			return
+		}
+
+		if rows > 0 {
+			log.Printf("[CLEANUP] Archived %d expired sign ups at %s", rows, time.Now().Format(time.RFC3339))
+		}
+	})
+
 	// Delete revoked tokens that have expired
 	scheduler.AddFunc(config.CRON_REVOKED_INTERVAL, func() {
 		rows, err := revokedRepo.Delete(ctx, "revoked_until IS NOT NULL AND revoked_until <= ?", time.Now())
@@ -53,5 +73,97 @@ func New(db *gorm.DB) *cron.Cron {
 		}
 	})
 
+	// Revoke expired permission grants and notify admins who granted them
+	scheduler.AddFunc(config.CRON_GRANT_INTERVAL, func() {
+		var rows int
+		err := db.Transaction(func(tx *gorm.DB) error {
+			grants, err := adminRepo.WithTx(tx).ExpiredGrants(ctx, time.Now())
+			if err != nil || len(grants) == 0 {
+				return err
+			}
+			if err := adminRepo.WithTx(tx).DeleteGrants(ctx, grants); err != nil {
+				return err
+			}
+
+			audits := make([]models.PermissionAudit, 0, len(grants))
+			notifications := make([]models.Notification, 0, len(grants))
+			for _, grant := range grants {
+				audits = append(audits, models.PermissionAudit{
+					Action:         models.AuditExpirePermission,
+					TargetAdminID:  &grant.AdminID,
+					PermissionID:   &grant.PermissionID,
+					PermissionName: grant.Permission.Name,
+				})
+				if grant.GrantedByID == nil {
+					continue
+				}
+				grantee := "an archived admin"
+				if grant.Admin != nil {
+					if user, err := userRepo.WithTx(tx).GetByID(ctx, grant.Admin.UserID); err == nil {
+						grantee = user.FullName
+					}
+				}
+				notifications = append(notifications, models.Notification{
+					UserID:  *grant.GrantedByID,
+					Title:   "permission grant expired",
+					Message: fmt.Sprintf("grant of permission %s to %s expired at %s and has been revoked", grant.Permission.Name, grantee, grant.ValidUntil.Format(time.RFC3339)),
+				})
+			}
+			if err := auditRepo.WithTx(tx).CreateAll(ctx, &audits); err != nil {
+				return err
+			}
+			if len(notifications) > 0 {
+				if err := notificationRepo.WithTx(tx).CreateAll(ctx, &notifications); err != nil {
+					return err
+				}
+			}
+			rows = len(grants)
+			return nil
+		})
+
+		if err != nil {
+			log.Printf("[CLEANUP] Failed to revoke expired permission grants: %v", err)
+			return
+		}
+
+		if rows > 0 {
+			log.Printf("[CLEANUP] Revoked %d expired permission grants at %s", rows, time.Now().Format(time.RFC3339))
+		}
+	})
+
+	// Expire change requests which are not reviewed in time and notify admins who requested them
+	scheduler.AddFunc(config.CRON_CHANGE_INTERVAL, func() {
+		var rows int
+		err := db.Transaction(func(tx *gorm.DB) error {
+			requests, err := changeRepo.WithTx(tx).ExpirePending(ctx, time.Now())
+			if err != nil || len(requests) == 0 {
+				return err
+			}
+
+			notifications := make([]models.Notification, 0, len(requests))
+			for _, request := range requests {
+				notifications = append(notifications, models.Notification{
+					UserID:  request.RequesterID,
+					Title:   "change request expired",
+					Message: fmt.Sprintf("your change request %s (%s) is not reviewed before %s and has expired", request.ID, request.Kind, request.ExpiresAt.Format(time.RFC3339)),
+				})
+			}
+			if err := notificationRepo.WithTx(tx).CreateAll(ctx, &notifications); err != nil {
+				return err
+			}
+			rows = len(requests)
+			return nil
+		})
+
+		if err != nil {
+			log.Printf("[CLEANUP] Failed to expire change requests: %v", err)
+			return
+		}
+
+		if rows > 0 {
+			log.Printf("[CLEANUP] Expired %d change requests at %s", rows, time.Now().Format(time.RFC3339))
+		}
+	})
+
 	return scheduler
 }
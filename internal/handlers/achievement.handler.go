@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Achievement struct {
+	achievementService *services.Achievement
+}
+
+func NewAchievement(achievementService *services.Achievement) *Achievement {
+	return &Achievement{achievementService}
+}
+
+// @Summary      Create achievement
+// @Description  Admin with permission create achievement resource or teacher only
+// @Tags         achievement
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param				 Cookie   			header 		string 	false	"access_token"
+// @Param				 Cookie2  			header 		string 	true	"refresh_token"
+// @Param				 payload  			formData	payloads.RequestCreateAchievement	true	"data of achievement"
+// @Param				 certificate  	formData	file	false	"certificate of achievement"
+// @Success      201  		{object}  swaglib.Envelope{data=models.Achievement}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /achievements [post]
+func (h *Achievement) CreateAchievement(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	// extra 1 MB for other form fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MAX_UPLOAD_SIZE+(1<<20))
+
+	var payload payloads.RequestCreateAchievement
+	if err := c.ShouldBind(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	achievement, errPayload := h.achievementService.ApplyContext(c).CreateAchievement(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(achievement).CreatedJSON()
+}
+
+// @Summary      Get achievements
+// @Description  Admin with permission read achievement resource or teacher only. Year filters by academic year starting in July of the year
+// @Tags         achievement
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetAchievements	true	"config to accept achievements"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.Achievement,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /achievements [get]
+func (h *Achievement) GetAchievements(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAchievements
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	achievements, errPayload := h.achievementService.ApplyContext(c).GetAchievements(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(achievements).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get achievement report for accreditation
+// @Description  Admin with permission read achievement resource only. Achievement count per academic year and level
+// @Tags         achievement
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetAchievementReport	true	"range of academic years"
+// @Success      200  		{object}  swaglib.Envelope{data=[]payloads.ResponseAchievementReportYear}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /achievements/report [get]
+func (h *Achievement) GetAchievementReport(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAchievementReport
+	c.ShouldBindQuery(&payload)
+
+	report, errPayload := h.achievementService.ApplyContext(c).GetReport(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(report).OkJSON()
+}
+
+// @Summary      Get achievement portfolio of student
+// @Description  Admin with permission read achievement resource, teacher, or the student only. Summary per level and all achievements ordered by date, the same data shown on report card
+// @Tags         achievement
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "student id"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponsePortfolio}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /achievements/students/{id}/portfolio [get]
+func (h *Achievement) GetPortfolio(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetPortfolio
+	c.ShouldBindUri(&payload)
+
+	portfolio, errPayload := h.achievementService.ApplyContext(c).GetPortfolio(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(portfolio).OkJSON()
+}
+
+// @Summary      Get achievement with id
+// @Description  Admin with permission read achievement resource, teacher, or the student only
+// @Tags         achievement
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "achievement id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Achievement}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /achievements/{id} [get]
+func (h *Achievement) GetAchievement(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAchievement
+	c.ShouldBindUri(&payload)
+
+	achievement, errPayload := h.achievementService.ApplyContext(c).GetAchievement(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(achievement).OkJSON()
+}
+
+// @Summary      Download certificate of achievement
+// @Description  Admin with permission read achievement resource, teacher, or the student only
+// @Tags         achievement
+// @Produce      octet-stream
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "achievement id"
+// @Success      200  		{file}  	file
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /achievements/{id}/certificate [get]
+func (h *Achievement) DownloadCertificate(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAchievement
+	c.ShouldBindUri(&payload)
+
+	achievement, content, errPayload := h.achievementService.ApplyContext(c).DownloadCertificate(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, achievement.File.Size, achievement.File.MimeType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": achievement.FileName}),
+	})
+}
+
+// @Summary      Delete achievement
+// @Description  Admin with permission delete achievement resource only. Certificate is deleted too
+// @Tags         achievement
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "achievement id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /achievements/{id} [delete]
+func (h *Achievement) DeleteAchievement(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteAchievement
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.achievementService.ApplyContext(c).DeleteAchievement(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
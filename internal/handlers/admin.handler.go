@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"fmt"
+	"net/http"
+	"school-information-system/config"
 	"school-information-system/internal/libs/replylib"
 	"school-information-system/internal/models/payloads"
 	"school-information-system/internal/services"
+	"time"
 
 	adapter "github.com/chesta132/goreply/adapter/gin"
 	"github.com/gin-gonic/gin"
@@ -46,7 +49,10 @@ func (h *Admin) InitiateAdmin(c *gin.Context) {
 }
 
 // @Summary      Set another user's role
-// @Description  Admin with permission update role resource only
+// @Description  Admin with permission update role resource only. Set append to give target role in addition to held roles, otherwise user moves from every held role to target role.
+// @Description  Profile of a left role is archived and restored when the user moves back to the role, form teacher of classes is detached and admin permissions are revoked. Alumni role is only given by graduation.
+// @Description  Sessions of targeted user are invalidated, access token remains valid until it expires.
+// @Description  Giving admin role or moving admin to another role needs approval of another admin, a change request is responded instead
 // @Tags         admin
 // @Accept       json
 // @Produce      json
@@ -54,6 +60,7 @@ func (h *Admin) InitiateAdmin(c *gin.Context) {
 // @Param				 Cookie2  header 		string 	true	"refresh_token"
 // @Param				 payload  body			payloads.RequestSetRole	true	"data of targeted user's role, only insert data that match with target_role"
 // @Success      200  		{object}  swaglib.Envelope{data=models.User{admin_profile=models.Admin,student_profile=models.Student{class=models.Class,parents=[]models.Parent},teacher_profile=models.Teacher{subjects=[]models.Subject}},meta=swaglib.Info} "*_data is match with role"
+// @Success      202  		{object}  swaglib.Envelope{data=models.ChangeRequest,meta=swaglib.Info} "change is requested for approval of another admin"
 // @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
 // @Router       /admins/set-role [put]
 func (h *Admin) SetRole(c *gin.Context) {
@@ -64,11 +71,209 @@ func (h *Admin) SetRole(c *gin.Context) {
 		return
 	}
 
-	user, errPayload := h.roleSetterService.ApplyContext(c).SetRole(payload)
+	user, request, errPayload := h.roleSetterService.ApplyContext(c).SetRole(payload)
 	if errPayload != nil {
 		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
 		return
 	}
+	if request != nil {
+		rp.Success(request).Info(fmt.Sprintf("change requested, it is applied once another admin approves it before %s", request.ExpiresAt.Format(time.RFC3339))).ReplyJSON(http.StatusAccepted)
+		return
+	}
 
 	rp.Success(user).Info(fmt.Sprintf("%s's role setted", user.FullName)).OkJSON()
 }
+
+// @Summary      Remove one of another user's roles
+// @Description  Admin with permission update role resource only. Profile of the role is archived like moving from the role, another held role becomes primary role if the role was primary role.
+// @Description  Sessions of targeted user are invalidated, access token remains valid until it expires.
+// @Description  Removing admin role needs approval of another admin, a change request is responded instead
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestRemoveRole	true	"targeted user and role to remove"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User}
+// @Success      202  		{object}  swaglib.Envelope{data=models.ChangeRequest,meta=swaglib.Info} "change is requested for approval of another admin"
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admins/remove-role [put]
+func (h *Admin) RemoveRole(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestRemoveRole
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	user, request, errPayload := h.roleSetterService.ApplyContext(c).RemoveRole(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+	if request != nil {
+		rp.Success(request).Info(fmt.Sprintf("change requested, it is applied once another admin approves it before %s", request.ExpiresAt.Format(time.RFC3339))).ReplyJSON(http.StatusAccepted)
+		return
+	}
+
+	rp.Success(user).Info(fmt.Sprintf("%s's role %s removed", user.FullName, payload.Role)).OkJSON()
+}
+
+// @Summary      Get admins
+// @Description  Admin with permission read admin resource only. Get users of active admin profiles with their permissions
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetAdmins	true	"query of admins"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.User{admin_profile=models.Admin{permissions=[]models.Permission}},meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admins [get]
+func (h *Admin) GetAdmins(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAdmins
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	admins, errPayload := h.adminService.ApplyContext(c).GetAdmins(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(admins).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get an admin
+// @Description  Admin with permission read admin resource only. ID is ID of admin profile
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of admin profile"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User{admin_profile=models.Admin{permissions=[]models.Permission}}}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admins/{id} [get]
+func (h *Admin) GetAdmin(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAdmin
+	c.ShouldBindUri(&payload)
+
+	user, errPayload := h.adminService.ApplyContext(c).GetAdmin(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(user).OkJSON()
+}
+
+// @Summary      Get effective permissions of an admin
+// @Description  Admin with permission read admin resource only. ID is ID of admin profile. Every action on every resource is computed like permission check of endpoints when the admin is signed in as primary role,
+// @Description  including roles skipping the check on some endpoints and class scope of permissions
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of admin profile"
+// @Success      200  		{object}  swaglib.Envelope{data=models.PermissionMatrix}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admins/{id}/permissions/effective [get]
+func (h *Admin) GetEffectivePermissions(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetEffectivePermissions
+	c.ShouldBindUri(&payload)
+
+	matrix, errPayload := h.adminService.ApplyContext(c).GetEffectivePermissions(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(matrix).OkJSON()
+}
+
+// @Summary      Update an admin
+// @Description  Admin with permission update admin resource only. Empty field is not updated
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of admin profile"
+// @Param				 payload  body			payloads.RequestUpdateAdmin	true	"updated data of admin"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User{admin_profile=models.Admin{permissions=[]models.Permission}}}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admins/{id} [put]
+func (h *Admin) UpdateAdmin(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateAdmin
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	user, errPayload := h.adminService.ApplyContext(c).UpdateAdmin(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(user).OkJSON()
+}
+
+// @Summary      Deactivate an admin
+// @Description  Admin with permission delete admin resource only. Deactivation always needs approval of another admin holding delete admin resource, a change request is responded.
+// @Description  Once approved, permissions are revoked and profile is archived, approval is rejected if admin is the only holder of a seed permission. Sessions of the user are invalidated
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of admin profile"
+// @Success      202  		{object}  swaglib.Envelope{data=models.ChangeRequest,meta=swaglib.Info} "change is requested for approval of another admin"
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admins/{id} [delete]
+func (h *Admin) DeactivateAdmin(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeactivateAdmin
+	c.ShouldBindUri(&payload)
+
+	request, errPayload := h.adminService.ApplyContext(c).DeactivateAdmin(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(request).Info(fmt.Sprintf("deactivation requested, it is applied once another admin approves it before %s", request.ExpiresAt.Format(time.RFC3339))).ReplyJSON(http.StatusAccepted)
+}
+
+// @Summary      Get my effective permissions
+// @Description  Signed in user only. Every action on every resource is computed like permission check of endpoints for current session,
+// @Description  including roles skipping the check on some endpoints and class scope of permissions
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Success      200  		{object}  swaglib.Envelope{data=models.PermissionMatrix}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /me/permissions [get]
+func (h *Admin) GetMyPermissions(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+
+	matrix, errPayload := h.adminService.ApplyContext(c).GetMyPermissions()
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(matrix).OkJSON()
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Admission struct {
+	admissionService *services.Admission
+}
+
+func NewAdmission(admissionService *services.Admission) *Admission {
+	return &Admission{admissionService}
+}
+
+// @Summary      Submit new student application
+// @Description  Public endpoint. Registration number is returned to track the application
+// @Tags         admission
+// @Accept       json
+// @Produce      json
+// @Param				 payload  body			payloads.RequestSubmitAdmission	true	"data of applicant"
+// @Success      201  		{object}  swaglib.Envelope{data=models.Admission}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admissions [post]
+func (h *Admission) SubmitAdmission(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestSubmitAdmission
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	admission, errPayload := h.admissionService.ApplyContext(c).SubmitAdmission(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(admission).Info("application submitted, keep the registration number to track it").CreatedJSON()
+}
+
+// @Summary      Track student application
+// @Description  Public endpoint. Registration number and email of applicant must match
+// @Tags         admission
+// @Accept       json
+// @Produce      json
+// @Param				 payload  query			payloads.RequestTrackAdmission	true	"registration number and email"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Admission}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admissions/track [get]
+func (h *Admission) TrackAdmission(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestTrackAdmission
+	c.ShouldBindQuery(&payload)
+
+	admission, errPayload := h.admissionService.ApplyContext(c).TrackAdmission(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(admission).OkJSON()
+}
+
+// @Summary      Get student application with id
+// @Description  Admin with permission read admission resource only
+// @Tags         admission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "admission id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Admission}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admissions/{id} [get]
+func (h *Admission) GetAdmission(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAdmission
+	c.ShouldBindUri(&payload)
+
+	admission, errPayload := h.admissionService.ApplyContext(c).GetAdmission(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(admission).OkJSON()
+}
+
+// @Summary      Get student applications
+// @Description  Admin with permission read admission resource only
+// @Tags         admission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetAdmissions	true	"config to accept applications"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.Admission,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admissions [get]
+func (h *Admission) GetAdmissions(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAdmissions
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	admissions, errPayload := h.admissionService.ApplyContext(c).GetAdmissions(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(admissions).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Move student application to next review stage
+// @Description  Admin with permission update admission resource only. Flow is submitted > verified > tested > accepted, rejection is allowed before accepted
+// @Tags         admission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "admission id"
+// @Param				 payload  body			payloads.RequestMoveAdmissionStage	true	"next stage"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Admission}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admissions/{id}/stage [put]
+func (h *Admission) MoveStage(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestMoveAdmissionStage
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	admission, errPayload := h.admissionService.ApplyContext(c).MoveStage(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(admission).OkJSON()
+}
+
+// @Summary      Enroll accepted applicant as student
+// @Description  Admin with permission create admission resource only. Creates student account, reuses registered parents with same phone or email
+// @Tags         admission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "admission id"
+// @Param				 payload  body			payloads.RequestEnrollAdmission	true	"class of new student"
+// @Success      201  		{object}  swaglib.Envelope{data=models.User{student_profile=models.Student}}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admissions/{id}/enroll [post]
+func (h *Admission) Enroll(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestEnrollAdmission
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	user, errPayload := h.admissionService.ApplyContext(c).Enroll(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(user).CreatedJSON()
+}
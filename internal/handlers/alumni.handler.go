@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Alumni struct {
+	alumniService *services.Alumni
+}
+
+func NewAlumni(alumniService *services.Alumni) *Alumni {
+	return &Alumni{alumniService}
+}
+
+// @Summary      Graduate students of grade 12 classes
+// @Description  Admin with permission create alumni resource only. Students become alumni, student profiles are released from classes
+// @Tags         alumni
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestGraduate	true	"data of graduation"
+// @Success      201  		{object}  swaglib.Envelope{data=[]models.Alumni,meta=swaglib.Info}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /alumni/graduate [post]
+func (h *Alumni) Graduate(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGraduate
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	alumni, errPayload := h.alumniService.ApplyContext(c).Graduate(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(alumni).Info(fmt.Sprintf("%d student(s) graduated in %d", len(alumni), payload.GraduationYear)).CreatedJSON()
+}
+
+// @Summary      Get alumni directory
+// @Description  Admin with permission read alumni resource, teacher, or alumni only
+// @Tags         alumni
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetAlumniDirectory	true	"config to accept alumni"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.User{alumni_profile=models.Alumni},meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /alumni [get]
+func (h *Alumni) GetDirectory(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAlumniDirectory
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	users, errPayload := h.alumniService.ApplyContext(c).GetDirectory(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(users).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get own alumni record
+// @Description  Alumni with unexpired access only
+// @Tags         alumni
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User{alumni_profile=models.Alumni}}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /alumni/me [get]
+func (h *Alumni) GetSelf(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+
+	user, errPayload := h.alumniService.ApplyContext(c).GetSelf()
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(user).OkJSON()
+}
+
+// @Summary      Get alumni with id
+// @Description  Admin with permission read alumni resource only
+// @Tags         alumni
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "alumni id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User{alumni_profile=models.Alumni}}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /alumni/{id} [get]
+func (h *Alumni) GetAlumni(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAlumni
+	c.ShouldBindUri(&payload)
+
+	user, errPayload := h.alumniService.ApplyContext(c).GetAlumni(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(user).OkJSON()
+}
+
+// @Summary      Update sign in access date of alumni
+// @Description  Admin with permission update alumni resource only
+// @Tags         alumni
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "alumni id"
+// @Param				 payload  body			payloads.RequestUpdateAlumniAccess	true	"new access date"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Alumni}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /alumni/{id}/access [put]
+func (h *Alumni) UpdateAccess(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateAlumniAccess
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	alumni, errPayload := h.alumniService.ApplyContext(c).UpdateAccess(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(alumni).OkJSON()
+}
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Assignment struct {
+	assignmentService *services.Assignment
+}
+
+func NewAssignment(assignmentService *services.Assignment) *Assignment {
+	return &Assignment{assignmentService}
+}
+
+// @Summary      Create homework assignment
+// @Description  Teacher only, the subject must be taught by the teacher
+// @Tags         assignment
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  formData	payloads.RequestCreateAssignment	true	"data of assignment"
+// @Param				 file  		formData	file	false	"attachment of assignment"
+// @Success      201  		{object}  swaglib.Envelope{data=models.Assignment}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments [post]
+func (h *Assignment) CreateAssignment(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	// extra 1 MB for other form fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MAX_UPLOAD_SIZE+(1<<20))
+
+	var payload payloads.RequestCreateAssignment
+	if err := c.ShouldBind(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	assignment, errPayload := h.assignmentService.ApplyContext(c).CreateAssignment(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(assignment).CreatedJSON()
+}
+
+// @Summary      Get homework assignments
+// @Description  Admin with permission read assignment resource, teacher, or student only. Student only get assignments of own class
+// @Tags         assignment
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetAssignments	true	"config to accept assignments"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.Assignment,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments [get]
+func (h *Assignment) GetAssignments(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAssignments
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	assignments, errPayload := h.assignmentService.ApplyContext(c).GetAssignments(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(assignments).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get own pending homework
+// @Description  Student only. Assignments of own class not submitted yet and still accepting submission
+// @Tags         assignment
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetPendingAssignments	true	"config to accept assignments"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.Assignment,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments/pending [get]
+func (h *Assignment) GetPendingAssignments(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetPendingAssignments
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	assignments, errPayload := h.assignmentService.ApplyContext(c).GetPendingAssignments(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(assignments).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get homework assignment with id
+// @Description  Admin with permission read assignment resource, teacher, or student of the class only
+// @Tags         assignment
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "assignment id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Assignment}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments/{id} [get]
+func (h *Assignment) GetAssignment(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAssignment
+	c.ShouldBindUri(&payload)
+
+	assignment, errPayload := h.assignmentService.ApplyContext(c).GetAssignment(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(assignment).OkJSON()
+}
+
+// @Summary      Download attachment of homework assignment
+// @Description  Admin with permission read assignment resource, teacher, or student of the class only
+// @Tags         assignment
+// @Produce      octet-stream
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "assignment id"
+// @Success      200  		{file}  	file
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments/{id}/download [get]
+func (h *Assignment) DownloadAttachment(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetAssignment
+	c.ShouldBindUri(&payload)
+
+	assignment, content, errPayload := h.assignmentService.ApplyContext(c).DownloadAttachment(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, assignment.File.Size, assignment.File.MimeType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": assignment.FileName}),
+	})
+}
+
+// @Summary      Delete homework assignment
+// @Description  Admin with permission delete assignment resource, or teacher who created the assignment only. Submissions and gradebook scores of the assignment are deleted
+// @Tags         assignment
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "assignment id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments/{id} [delete]
+func (h *Assignment) DeleteAssignment(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteAssignment
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.assignmentService.ApplyContext(c).DeleteAssignment(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Submit homework assignment
+// @Description  Student of the class only. Text or file is required, resubmission replaces previous submission until graded. Submission after due date is flagged late
+// @Tags         assignment
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "assignment id"
+// @Param				 payload  formData	payloads.RequestSubmitAssignment	true	"answer of student"
+// @Param				 file  		formData	file	false	"answer file"
+// @Success      201  		{object}  swaglib.Envelope{data=models.Submission}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments/{id}/submissions [post]
+func (h *Assignment) SubmitAssignment(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	// extra 1 MB for other form fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MAX_UPLOAD_SIZE+(1<<20))
+
+	var payload payloads.RequestSubmitAssignment
+	if err := c.ShouldBind(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	submission, errPayload := h.assignmentService.ApplyContext(c).SubmitAssignment(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(submission).CreatedJSON()
+}
+
+// @Summary      Get submissions of homework assignment
+// @Description  Admin with permission read assignment resource, or teacher who created the assignment only
+// @Tags         assignment
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "assignment id"
+// @Param				 payload  query			payloads.RequestGetSubmissions	true	"config to accept submissions"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.Submission,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments/{id}/submissions [get]
+func (h *Assignment) GetSubmissions(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetSubmissions
+	c.ShouldBindQuery(&payload)
+	payload.ID = c.Param("id")
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	submissions, errPayload := h.assignmentService.ApplyContext(c).GetSubmissions(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(submissions).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get own ungraded submissions
+// @Description  Teacher only. Submissions of assignments created by the teacher waiting to be graded
+// @Tags         assignment
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetUngradedSubmissions	true	"config to accept submissions"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.Submission{assignment=models.Assignment},meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments/submissions/ungraded [get]
+func (h *Assignment) GetUngradedSubmissions(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetUngradedSubmissions
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	submissions, errPayload := h.assignmentService.ApplyContext(c).GetUngradedSubmissions(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(submissions).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Download file of submission
+// @Description  Admin with permission read assignment resource, teacher who created the assignment, or student who submitted only
+// @Tags         assignment
+// @Produce      octet-stream
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "submission id"
+// @Success      200  		{file}  	file
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments/submissions/{id}/download [get]
+func (h *Assignment) DownloadSubmission(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetSubmission
+	c.ShouldBindUri(&payload)
+
+	submission, content, errPayload := h.assignmentService.ApplyContext(c).DownloadSubmission(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, submission.File.Size, submission.File.MimeType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": submission.FileName}),
+	})
+}
+
+// @Summary      Grade submission
+// @Description  Admin with permission update assignment resource, or teacher who created the assignment only. Grade can be recorded as score in gradebook
+// @Tags         assignment
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "submission id"
+// @Param				 payload  body			payloads.RequestGradeSubmission	true	"grade and comment"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Submission}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /assignments/submissions/{id}/grade [put]
+func (h *Assignment) GradeSubmission(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGradeSubmission
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	submission, errPayload := h.assignmentService.ApplyContext(c).GradeSubmission(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(submission).OkJSON()
+}
@@ -78,3 +78,31 @@ func (h *Auth) SignOut(c *gin.Context) {
 	cookies := h.authService.ApplyContext(c).SignOut()
 	rp.SetCookies(cookies...).Success(nil).OkJSON()
 }
+
+// @Summary      Switch active role of session
+// @Description  Replace current session with session of another role held by current user, current refresh token is revoked
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestSwitchRole	true	"role to be active"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /auth/role [put]
+func (h *Auth) SwitchRole(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestSwitchRole
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	user, cookies, errPayload := h.authService.ApplyContext(c).SwitchRole(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.SetCookies(cookies...).Success(user).OkJSON()
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type QuestionBank struct {
+	bankService *services.QuestionBank
+}
+
+func NewQuestionBank(bankService *services.QuestionBank) *QuestionBank {
+	return &QuestionBank{bankService}
+}
+
+// @Summary      Create bank question
+// @Description  Admin with permission create question bank resource, or teacher of the subject only. Content is saved as version 1
+// @Tags         question bank
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateBankQuestion	true	"data of question"
+// @Success      201  		{object}  swaglib.Envelope{data=models.BankQuestion}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /question-bank [post]
+func (h *QuestionBank) CreateQuestion(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateBankQuestion
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	question, errPayload := h.bankService.ApplyContext(c).CreateQuestion(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(question).CreatedJSON()
+}
+
+// @Summary      Get bank questions
+// @Description  Admin with permission read question bank resource, or teacher only. Teacher only get questions of own subjects. Each question comes with its current version only
+// @Tags         question bank
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetBankQuestions	true	"config to accept questions"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.BankQuestion,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /question-bank [get]
+func (h *QuestionBank) GetQuestions(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetBankQuestions
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	questions, errPayload := h.bankService.ApplyContext(c).GetQuestions(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(questions).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get bank question with id
+// @Description  Admin with permission read question bank resource, or teacher of the subject only. All versions are included, newest first
+// @Tags         question bank
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "bank question id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.BankQuestion}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /question-bank/{id} [get]
+func (h *QuestionBank) GetQuestion(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetBankQuestion
+	c.ShouldBindUri(&payload)
+
+	question, errPayload := h.bankService.ApplyContext(c).GetQuestion(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(question).OkJSON()
+}
+
+// @Summary      Update bank question
+// @Description  Admin with permission update question bank resource, or teacher of the subject only. Content is saved as new version, quizzes which copied older version are not changed
+// @Tags         question bank
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "bank question id"
+// @Param				 payload  body			payloads.RequestUpdateBankQuestion	true	"new data of question"
+// @Success      200  		{object}  swaglib.Envelope{data=models.BankQuestion}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /question-bank/{id} [put]
+func (h *QuestionBank) UpdateQuestion(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateBankQuestion
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	question, errPayload := h.bankService.ApplyContext(c).UpdateQuestion(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(question).OkJSON()
+}
+
+// @Summary      Delete bank question
+// @Description  Admin with permission delete question bank resource, or teacher of the subject only. Questions copied to quizzes are kept
+// @Tags         question bank
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "bank question id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /question-bank/{id} [delete]
+func (h *QuestionBank) DeleteQuestion(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteBankQuestion
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.bankService.ApplyContext(c).DeleteQuestion(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Make exam paper
+// @Description  Admin with permission read question bank resource, or teacher of the subjects only. Printable paper is assembled from current version of the questions
+// @Tags         question bank
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestMakeExamPaper	true	"questions of paper"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponseExamPaper}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /question-bank/paper [post]
+func (h *QuestionBank) MakeExamPaper(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestMakeExamPaper
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	paper, errPayload := h.bankService.ApplyContext(c).MakeExamPaper(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(paper).OkJSON()
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type ChangeRequest struct {
+	changeRequestService *services.ChangeRequest
+}
+
+func NewChangeRequest(changeRequestService *services.ChangeRequest) *ChangeRequest {
+	return &ChangeRequest{changeRequestService}
+}
+
+// @Summary      Get change requests
+// @Description  Admin only. Get change requests of sensitive permission and admin role changes from the latest
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetChangeRequests	true	"query of change requests"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.ChangeRequest,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admins/change-requests [get]
+func (h *ChangeRequest) GetChangeRequests(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetChangeRequests
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	requests, errPayload := h.changeRequestService.ApplyContext(c).GetChangeRequests(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(requests).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get change request
+// @Description  Admin only. Get change request with its requester and reviewer
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of change request"
+// @Success      200  		{object}  swaglib.Envelope{data=models.ChangeRequest{requester=models.User,reviewer=models.User}}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admins/change-requests/{id} [get]
+func (h *ChangeRequest) GetChangeRequest(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetChangeRequest
+	c.ShouldBindUri(&payload)
+
+	request, errPayload := h.changeRequestService.ApplyContext(c).GetChangeRequest(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(request).OkJSON()
+}
+
+// @Summary      Approve change request
+// @Description  Another admin than requester holding the permission needed by the change only. The change is applied as requested, change request is kept pending if the change fails. Requester is notified
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of change request"
+// @Param				 payload  body			payloads.RequestReviewChangeRequest	true	"review of change request"
+// @Success      200  		{object}  swaglib.Envelope{data=models.ChangeRequest}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admins/change-requests/{id}/approve [put]
+func (h *ChangeRequest) ApproveChangeRequest(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestReviewChangeRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	request, errPayload := h.changeRequestService.ApplyContext(c).ApproveChangeRequest(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(request).OkJSON()
+}
+
+// @Summary      Reject change request
+// @Description  Another admin than requester holding the permission needed by the change only. The change is not applied. Requester is notified
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of change request"
+// @Param				 payload  body			payloads.RequestReviewChangeRequest	true	"review of change request"
+// @Success      200  		{object}  swaglib.Envelope{data=models.ChangeRequest}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /admins/change-requests/{id}/reject [put]
+func (h *ChangeRequest) RejectChangeRequest(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestReviewChangeRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	request, errPayload := h.changeRequestService.ApplyContext(c).RejectChangeRequest(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(request).OkJSON()
+}
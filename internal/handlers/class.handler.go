@@ -73,7 +73,7 @@ func (h *Class) GetClass(c *gin.Context) {
 }
 
 // @Summary      Get existing classes
-// @Description  Admin with permission read class resource or teacher only
+// @Description  Admin with permission read class resource or teacher only. Admin with scoped permission only get classes in the scope
 // @Tags         class
 // @Accept       json
 // @Produce      json
@@ -321,3 +321,58 @@ func (h *Class) RemoveFormTeacher(c *gin.Context) {
 
 	rp.Success(teacher).OkJSON()
 }
+
+// @Summary      Set home room of class
+// @Description  Admin with permission update class resource only. A room is home of one class only
+// @Tags         class
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "class id"
+// @Param				 payload  body			payloads.RequestSetHomeRoom	true	"room to set"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Class}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /classes/{id}/home-room [put]
+func (h *Class) SetHomeRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestSetHomeRoom
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	class, errPayload := h.classService.ApplyContext(c).SetHomeRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(class).OkJSON()
+}
+
+// @Summary      Remove home room of class
+// @Description  Admin with permission update class resource only
+// @Tags         class
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "class id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Class}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /classes/{id}/home-room [delete]
+func (h *Class) RemoveHomeRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetClass
+	c.ShouldBindUri(&payload)
+
+	class, errPayload := h.classService.ApplyContext(c).RemoveHomeRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(class).OkJSON()
+}
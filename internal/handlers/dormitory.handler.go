@@ -0,0 +1,413 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Dormitory struct {
+	dormitoryService *services.Dormitory
+}
+
+func NewDormitory(dormitoryService *services.Dormitory) *Dormitory {
+	return &Dormitory{dormitoryService}
+}
+
+// @Summary      Create dormitory
+// @Description  Admin with permission create dormitory resource only
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateDormitory	true	"data of dormitory"
+// @Success      201  		{object}  swaglib.Envelope{data=models.Dormitory}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories [post]
+func (h *Dormitory) CreateDormitory(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateDormitory
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	dormitory, errPayload := h.dormitoryService.ApplyContext(c).CreateDormitory(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(dormitory).CreatedJSON()
+}
+
+// @Summary      Get dormitories
+// @Description  Admin with permission read dormitory resource or teacher only. Teacher only get dormitories they supervise
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetDormitories	true	"config to accept dormitories"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.Dormitory,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories [get]
+func (h *Dormitory) GetDormitories(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetDormitories
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	dormitories, errPayload := h.dormitoryService.ApplyContext(c).GetDormitories(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(dormitories).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get dormitory with id
+// @Description  Admin with permission read dormitory resource or supervisor of the dormitory only. Supervisors and rooms are included
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "dormitory id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Dormitory}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/{id} [get]
+func (h *Dormitory) GetDormitory(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetDormitory
+	c.ShouldBindUri(&payload)
+
+	dormitory, errPayload := h.dormitoryService.ApplyContext(c).GetDormitory(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(dormitory).OkJSON()
+}
+
+// @Summary      Update dormitory
+// @Description  Admin with permission update dormitory resource only
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "dormitory id"
+// @Param				 payload  body			payloads.RequestUpdateDormitory	true	"new data of dormitory"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Dormitory}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/{id} [put]
+func (h *Dormitory) UpdateDormitory(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateDormitory
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	dormitory, errPayload := h.dormitoryService.ApplyContext(c).UpdateDormitory(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(dormitory).OkJSON()
+}
+
+// @Summary      Delete dormitory
+// @Description  Admin with permission delete dormitory resource only. Dormitory with room assignments can not be deleted
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "dormitory id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/{id} [delete]
+func (h *Dormitory) DeleteDormitory(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteDormitory
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.dormitoryService.ApplyContext(c).DeleteDormitory(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Set supervisors of dormitory
+// @Description  Admin with permission update dormitory resource only. Replaces all supervisors of the dormitory
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "dormitory id"
+// @Param				 payload  body			payloads.RequestSetDormSupervisors	true	"teachers of supervisor"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Dormitory}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/{id}/supervisors [put]
+func (h *Dormitory) SetSupervisors(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestSetDormSupervisors
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	dormitory, errPayload := h.dormitoryService.ApplyContext(c).SetSupervisors(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(dormitory).OkJSON()
+}
+
+// @Summary      Create dorm room
+// @Description  Admin with permission create dormitory resource only
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "dormitory id"
+// @Param				 payload  body			payloads.RequestCreateDormRoom	true	"data of room"
+// @Success      201  		{object}  swaglib.Envelope{data=models.DormRoom}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/{id}/rooms [post]
+func (h *Dormitory) CreateRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateDormRoom
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	room, errPayload := h.dormitoryService.ApplyContext(c).CreateRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(room).CreatedJSON()
+}
+
+// @Summary      Update dorm room
+// @Description  Admin with permission update dormitory resource only. Capacity can not be less than occupants, gender can not be changed while students are assigned
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "room id"
+// @Param				 payload  body			payloads.RequestUpdateDormRoom	true	"new data of room"
+// @Success      200  		{object}  swaglib.Envelope{data=models.DormRoom}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/rooms/{id} [put]
+func (h *Dormitory) UpdateRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateDormRoom
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	room, errPayload := h.dormitoryService.ApplyContext(c).UpdateRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(room).OkJSON()
+}
+
+// @Summary      Delete dorm room
+// @Description  Admin with permission delete dormitory resource only. Room with assignments can not be deleted
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "room id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/rooms/{id} [delete]
+func (h *Dormitory) DeleteRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteDormRoom
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.dormitoryService.ApplyContext(c).DeleteRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Assign students to dorm room
+// @Description  Admin with permission create dormitory resource only. Students must match gender of the room and stay in one room per term
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "room id"
+// @Param				 payload  body			payloads.RequestAssignDormRoom	true	"students and term"
+// @Success      201  		{object}  swaglib.Envelope{data=[]models.DormAssignment}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/rooms/{id}/assignments [post]
+func (h *Dormitory) AssignRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestAssignDormRoom
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	assignments, errPayload := h.dormitoryService.ApplyContext(c).AssignRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(assignments).CreatedJSON()
+}
+
+// @Summary      Get dorm room assignments
+// @Description  Admin with permission read dormitory resource or teacher only. Teacher only get assignments of dormitories they supervise
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetDormAssignments	true	"config to accept assignments"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.DormAssignment,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/assignments [get]
+func (h *Dormitory) GetAssignments(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetDormAssignments
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	assignments, errPayload := h.dormitoryService.ApplyContext(c).GetAssignments(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(assignments).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Delete dorm room assignment
+// @Description  Admin with permission delete dormitory resource only
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "assignment id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/assignments/{id} [delete]
+func (h *Dormitory) DeleteAssignment(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteDormAssignment
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.dormitoryService.ApplyContext(c).DeleteAssignment(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Record night roll
+// @Description  Admin with permission create dormitory resource or supervisor of the dormitory only. Students must stay in the dormitory at the date, recorded student is replaced
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "dormitory id"
+// @Param				 payload  body			payloads.RequestRecordNightRoll	true	"night roll entries"
+// @Success      201  		{object}  swaglib.Envelope{data=[]models.NightRoll}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/{id}/night-rolls [post]
+func (h *Dormitory) RecordNightRoll(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestRecordNightRoll
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	rolls, errPayload := h.dormitoryService.ApplyContext(c).RecordNightRoll(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(rolls).CreatedJSON()
+}
+
+// @Summary      Get night roll
+// @Description  Admin with permission read dormitory resource or supervisor of the dormitory only. All boarding students of the dormitory at the date, status is null if not recorded yet
+// @Tags         dormitory
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "dormitory id"
+// @Param				 payload  query			payloads.RequestGetNightRoll	true	"date of night roll"
+// @Success      200  		{object}  swaglib.Envelope{data=[]payloads.ResponseNightRollEntry}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /dormitories/{id}/night-rolls [get]
+func (h *Dormitory) GetNightRoll(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetNightRoll
+	c.ShouldBindQuery(&payload)
+	payload.ID = c.Param("id")
+
+	entries, errPayload := h.dormitoryService.ApplyContext(c).GetNightRoll(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(entries).OkJSON()
+}
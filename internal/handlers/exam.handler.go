@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Exam struct {
+	examService *services.Exam
+}
+
+func NewExam(examService *services.Exam) *Exam {
+	return &Exam{examService}
+}
+
+// @Summary      Create exam period
+// @Description  Admin with permission create exam resource only
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateExamPeriod	true	"data of exam period"
+// @Success      201  		{object}  swaglib.Envelope{data=models.ExamPeriod}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/periods [post]
+func (h *Exam) CreatePeriod(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateExamPeriod
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	period, errPayload := h.examService.ApplyContext(c).CreatePeriod(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(period).CreatedJSON()
+}
+
+// @Summary      Get exam periods
+// @Description  Admin with permission read exam resource, teacher, or student only
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetExamPeriods	true	"config to accept exam periods"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.ExamPeriod,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/periods [get]
+func (h *Exam) GetPeriods(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetExamPeriods
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	periods, errPayload := h.examService.ApplyContext(c).GetPeriods(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(periods).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get exam period with id
+// @Description  Admin with permission read exam resource, teacher, or student only. Sessions are included as the exam schedule
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam period id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.ExamPeriod}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/periods/{id} [get]
+func (h *Exam) GetPeriod(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetExamPeriod
+	c.ShouldBindUri(&payload)
+
+	period, errPayload := h.examService.ApplyContext(c).GetPeriod(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(period).OkJSON()
+}
+
+// @Summary      Delete exam period
+// @Description  Admin with permission delete exam resource only. Sessions, seats and invigilators of the period are deleted
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam period id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/periods/{id} [delete]
+func (h *Exam) DeletePeriod(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteExamPeriod
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.examService.ApplyContext(c).DeletePeriod(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Create exam session
+// @Description  Admin with permission create exam resource only. A subject is scheduled once per grade in a period and a grade sits one exam at a time
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam period id"
+// @Param				 payload  body			payloads.RequestCreateExamSession	true	"data of exam session"
+// @Success      201  		{object}  swaglib.Envelope{data=models.ExamSession}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/periods/{id}/sessions [post]
+func (h *Exam) CreateSession(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateExamSession
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.PeriodID = c.Param("id")
+
+	session, errPayload := h.examService.ApplyContext(c).CreateSession(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(session).CreatedJSON()
+}
+
+// @Summary      Get exam card
+// @Description  Admin with permission read exam resource, or student only. Student always get own card, admin must set student_id
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam period id"
+// @Param				 payload  query			payloads.RequestGetExamCard	true	"student of the card"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponseExamCard}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/periods/{id}/card [get]
+func (h *Exam) GetExamCard(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetExamCard
+	c.ShouldBindQuery(&payload)
+	payload.ID = c.Param("id")
+
+	card, errPayload := h.examService.ApplyContext(c).GetExamCard(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(card).OkJSON()
+}
+
+// @Summary      Delete exam session
+// @Description  Admin with permission delete exam resource only. Seats and invigilators of the session are deleted
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam session id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/sessions/{id} [delete]
+func (h *Exam) DeleteSession(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteExamSession
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.examService.ApplyContext(c).DeleteSession(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Allocate exam seats
+// @Description  Admin with permission update exam resource only. Replaces seats of the session, students of the grade are spread across the rooms class by class. Seats taken by other sessions at the same time are kept, so grades can share a room
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam session id"
+// @Param				 payload  body			payloads.RequestAllocateExamSeats	true	"rooms to fill"
+// @Success      200  		{object}  swaglib.Envelope{data=[]payloads.ResponseExamRoomList}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/sessions/{id}/seats [put]
+func (h *Exam) AllocateSeats(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestAllocateExamSeats
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	lists, errPayload := h.examService.ApplyContext(c).AllocateSeats(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(lists).OkJSON()
+}
+
+// @Summary      Get room lists of exam session
+// @Description  Admin with permission read exam resource, or teacher only. Printable list of seats and invigilators of every room used by the session
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam session id"
+// @Success      200  		{object}  swaglib.Envelope{data=[]payloads.ResponseExamRoomList}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/sessions/{id}/rooms [get]
+func (h *Exam) GetRoomLists(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetExamRoomLists
+	c.ShouldBindUri(&payload)
+
+	lists, errPayload := h.examService.ApplyContext(c).GetRoomLists(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(lists).OkJSON()
+}
+
+// @Summary      Assign invigilator
+// @Description  Admin with permission update exam resource only. Teacher can not invigilate two sessions at the same time
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam session id"
+// @Param				 payload  body			payloads.RequestAssignInvigilator	true	"room and teacher"
+// @Success      201  		{object}  swaglib.Envelope{data=models.ExamInvigilator}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/sessions/{id}/invigilators [post]
+func (h *Exam) AssignInvigilator(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestAssignInvigilator
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	invigilator, errPayload := h.examService.ApplyContext(c).AssignInvigilator(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(invigilator).CreatedJSON()
+}
+
+// @Summary      Remove invigilator
+// @Description  Admin with permission update exam resource only
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam invigilator id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/invigilators/{id} [delete]
+func (h *Exam) RemoveInvigilator(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestRemoveInvigilator
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.examService.ApplyContext(c).RemoveInvigilator(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Create exam room
+// @Description  Admin with permission create exam resource only
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateExamRoom	true	"data of exam room"
+// @Success      201  		{object}  swaglib.Envelope{data=models.ExamRoom}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/rooms [post]
+func (h *Exam) CreateRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateExamRoom
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	room, errPayload := h.examService.ApplyContext(c).CreateRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(room).CreatedJSON()
+}
+
+// @Summary      Get exam rooms
+// @Description  Admin with permission read exam resource, or teacher only
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetExamRooms	true	"config to accept exam rooms"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.ExamRoom,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/rooms [get]
+func (h *Exam) GetRooms(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetExamRooms
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	rooms, errPayload := h.examService.ApplyContext(c).GetRooms(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(rooms).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Update exam room
+// @Description  Admin with permission update exam resource only. Capacity can not be lower than allocated seat number
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam room id"
+// @Param				 payload  body			payloads.RequestUpdateExamRoom	true	"new data of exam room"
+// @Success      200  		{object}  swaglib.Envelope{data=models.ExamRoom}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/rooms/{id} [put]
+func (h *Exam) UpdateRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateExamRoom
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	room, errPayload := h.examService.ApplyContext(c).UpdateRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(room).OkJSON()
+}
+
+// @Summary      Delete exam room
+// @Description  Admin with permission delete exam resource only. Room used by allocation can not be deleted
+// @Tags         exam
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "exam room id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /exams/rooms/{id} [delete]
+func (h *Exam) DeleteRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteExamRoom
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.examService.ApplyContext(c).DeleteRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
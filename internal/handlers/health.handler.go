@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Health struct {
+	healthService *services.Health
+}
+
+func NewHealth(healthService *services.Health) *Health {
+	return &Health{healthService}
+}
+
+// @Summary      Get health record of student
+// @Description  Admin with permission read health resource only
+// @Tags         student health
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "student id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.StudentHealth}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /student-health/students/{id} [get]
+func (h *Health) GetStudentHealth(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetStudentHealth
+	c.ShouldBindUri(&payload)
+
+	health, errPayload := h.healthService.ApplyContext(c).GetStudentHealth(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(health).OkJSON()
+}
+
+// @Summary      Set health record of student
+// @Description  Admin with permission update health resource only. Replaces whole health record of the student
+// @Tags         student health
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "student id"
+// @Param				 payload  body			payloads.RequestSetStudentHealth	true	"health record"
+// @Success      200  		{object}  swaglib.Envelope{data=models.StudentHealth}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /student-health/students/{id} [put]
+func (h *Health) SetStudentHealth(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestSetStudentHealth
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	health, errPayload := h.healthService.ApplyContext(c).SetStudentHealth(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(health).OkJSON()
+}
+
+// @Summary      Log clinic visit
+// @Description  Admin with permission create health resource only
+// @Tags         student health
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateClinicVisit	true	"data of visit"
+// @Success      201  		{object}  swaglib.Envelope{data=models.ClinicVisit}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /student-health/visits [post]
+func (h *Health) CreateVisit(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateClinicVisit
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	visit, errPayload := h.healthService.ApplyContext(c).CreateVisit(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(visit).CreatedJSON()
+}
+
+// @Summary      Get clinic visits
+// @Description  Admin with permission read health resource only
+// @Tags         student health
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetClinicVisits	true	"config to accept visits"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.ClinicVisit,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /student-health/visits [get]
+func (h *Health) GetVisits(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetClinicVisits
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	visits, errPayload := h.healthService.ApplyContext(c).GetVisits(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(visits).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Mark parent of clinic visit notified
+// @Description  Admin with permission update health resource only
+// @Tags         student health
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "clinic visit id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.ClinicVisit}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /student-health/visits/{id}/parent-notified [put]
+func (h *Health) NotifyVisitParent(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestNotifyClinicVisitParent
+	c.ShouldBindUri(&payload)
+
+	visit, errPayload := h.healthService.ApplyContext(c).NotifyVisitParent(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(visit).OkJSON()
+}
+
+// @Summary      Delete clinic visit
+// @Description  Admin with permission delete health resource only
+// @Tags         student health
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "clinic visit id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /student-health/visits/{id} [delete]
+func (h *Health) DeleteVisit(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteClinicVisit
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.healthService.ApplyContext(c).DeleteVisit(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Get allergy flags of class
+// @Description  Admin with permission read health resource, or teacher who is form teacher of the class or teaches it in timetable. Only allergies are shown, other health data stays restricted
+// @Tags         student health
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "class id"
+// @Success      200  		{object}  swaglib.Envelope{data=[]payloads.ResponseAllergyFlag}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /student-health/classes/{id}/allergies [get]
+func (h *Health) GetClassAllergies(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetClassAllergies
+	c.ShouldBindUri(&payload)
+
+	flags, errPayload := h.healthService.ApplyContext(c).GetClassAllergies(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(flags).OkJSON()
+}
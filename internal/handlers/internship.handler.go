@@ -0,0 +1,518 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Internship struct {
+	internshipService *services.Internship
+}
+
+func NewInternship(internshipService *services.Internship) *Internship {
+	return &Internship{internshipService}
+}
+
+// @Summary      Create industry partner
+// @Description  Admin with permission create internship resource only
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateIndustryPartner	true	"data of partner"
+// @Success      201  		{object}  swaglib.Envelope{data=models.IndustryPartner}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/partners [post]
+func (h *Internship) CreatePartner(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateIndustryPartner
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	partner, errPayload := h.internshipService.ApplyContext(c).CreatePartner(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(partner).CreatedJSON()
+}
+
+// @Summary      Get industry partners
+// @Description  Admin with permission read internship resource, teacher, or student only. Major filters partners accepting the major
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetIndustryPartners	true	"config to accept partners"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.IndustryPartner,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/partners [get]
+func (h *Internship) GetPartners(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetIndustryPartners
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	partners, errPayload := h.internshipService.ApplyContext(c).GetPartners(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(partners).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get industry partner with id
+// @Description  Admin with permission read internship resource, teacher, or student only
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "partner id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.IndustryPartner}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/partners/{id} [get]
+func (h *Internship) GetPartner(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetIndustryPartner
+	c.ShouldBindUri(&payload)
+
+	partner, errPayload := h.internshipService.ApplyContext(c).GetPartner(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(partner).OkJSON()
+}
+
+// @Summary      Update industry partner
+// @Description  Admin with permission update internship resource only
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "partner id"
+// @Param				 payload  body			payloads.RequestUpdateIndustryPartner	true	"new data of partner"
+// @Success      200  		{object}  swaglib.Envelope{data=models.IndustryPartner}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/partners/{id} [put]
+func (h *Internship) UpdatePartner(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateIndustryPartner
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	partner, errPayload := h.internshipService.ApplyContext(c).UpdatePartner(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(partner).OkJSON()
+}
+
+// @Summary      Delete industry partner
+// @Description  Admin with permission delete internship resource only. Partner with placements can not be deleted, deactivate it instead
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "partner id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/partners/{id} [delete]
+func (h *Internship) DeletePartner(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteIndustryPartner
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.internshipService.ApplyContext(c).DeletePartner(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Create internship placement
+// @Description  Admin with permission create internship resource only. Partner must accept major of the student, a student only has one internship at a time
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreatePlacement	true	"data of placement"
+// @Success      201  		{object}  swaglib.Envelope{data=models.InternshipPlacement}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/placements [post]
+func (h *Internship) CreatePlacement(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreatePlacement
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	placement, errPayload := h.internshipService.ApplyContext(c).CreatePlacement(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(placement).CreatedJSON()
+}
+
+// @Summary      Get internship placements
+// @Description  Admin with permission read internship resource, teacher, or student only. Teacher only get placements they supervise, student only get own placements
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetPlacements	true	"config to accept placements"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.InternshipPlacement,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/placements [get]
+func (h *Internship) GetPlacements(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetPlacements
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	placements, errPayload := h.internshipService.ApplyContext(c).GetPlacements(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(placements).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get internship placement with id
+// @Description  Admin with permission read internship resource, supervisor teacher, or the student only. Partner and monitoring visits are included
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "placement id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.InternshipPlacement}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/placements/{id} [get]
+func (h *Internship) GetPlacement(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetPlacement
+	c.ShouldBindUri(&payload)
+
+	placement, errPayload := h.internshipService.ApplyContext(c).GetPlacement(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(placement).OkJSON()
+}
+
+// @Summary      Update internship placement
+// @Description  Admin with permission update internship resource only
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "placement id"
+// @Param				 payload  body			payloads.RequestUpdatePlacement	true	"new data of placement"
+// @Success      200  		{object}  swaglib.Envelope{data=models.InternshipPlacement}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/placements/{id} [put]
+func (h *Internship) UpdatePlacement(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdatePlacement
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	placement, errPayload := h.internshipService.ApplyContext(c).UpdatePlacement(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(placement).OkJSON()
+}
+
+// @Summary      Delete internship placement
+// @Description  Admin with permission delete internship resource only. Evaluated placement can not be deleted
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "placement id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/placements/{id} [delete]
+func (h *Internship) DeletePlacement(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeletePlacement
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.internshipService.ApplyContext(c).DeletePlacement(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Write internship journal
+// @Description  Student only, for own internship. Journal of the same date is replaced until it is reviewed
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "placement id"
+// @Param				 payload  body			payloads.RequestWriteJournal	true	"daily activity"
+// @Success      201  		{object}  swaglib.Envelope{data=models.InternshipJournal}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/placements/{id}/journals [post]
+func (h *Internship) WriteJournal(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestWriteJournal
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	journal, errPayload := h.internshipService.ApplyContext(c).WriteJournal(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(journal).CreatedJSON()
+}
+
+// @Summary      Get internship journals
+// @Description  Admin with permission read internship resource, supervisor teacher, or the student only
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "placement id"
+// @Param				 payload  query			payloads.RequestGetJournals	true	"config to accept journals"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.InternshipJournal,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/placements/{id}/journals [get]
+func (h *Internship) GetJournals(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetJournals
+	c.ShouldBindQuery(&payload)
+	payload.ID = c.Param("id")
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	journals, errPayload := h.internshipService.ApplyContext(c).GetJournals(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(journals).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Review internship journal
+// @Description  Admin with permission update internship resource or supervisor teacher only
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "journal id"
+// @Param				 payload  body			payloads.RequestReviewJournal	true	"feedback of supervisor"
+// @Success      200  		{object}  swaglib.Envelope{data=models.InternshipJournal}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/journals/{id}/review [put]
+func (h *Internship) ReviewJournal(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestReviewJournal
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	journal, errPayload := h.internshipService.ApplyContext(c).ReviewJournal(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(journal).OkJSON()
+}
+
+// @Summary      Record monitoring visit
+// @Description  Supervisor teacher only
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "placement id"
+// @Param				 payload  body			payloads.RequestCreateMonitoringVisit	true	"data of visit"
+// @Success      201  		{object}  swaglib.Envelope{data=models.MonitoringVisit}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/placements/{id}/visits [post]
+func (h *Internship) CreateVisit(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateMonitoringVisit
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	visit, errPayload := h.internshipService.ApplyContext(c).CreateVisit(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(visit).CreatedJSON()
+}
+
+// @Summary      Delete monitoring visit
+// @Description  Admin with permission delete internship resource or teacher who recorded the visit only
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "visit id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/visits/{id} [delete]
+func (h *Internship) DeleteVisit(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteMonitoringVisit
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.internshipService.ApplyContext(c).DeleteVisit(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Create evaluation link for industry mentor
+// @Description  Admin with permission update internship resource or supervisor teacher only. Token is only shown once and replaces previous token, send it to the mentor as link
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "placement id"
+// @Success      201  		{object}  swaglib.Envelope{data=payloads.ResponseEvaluationLink}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/placements/{id}/evaluation-link [post]
+func (h *Internship) CreateEvaluationLink(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateEvaluationLink
+	c.ShouldBindUri(&payload)
+
+	link, errPayload := h.internshipService.ApplyContext(c).CreateEvaluationLink(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(link).CreatedJSON()
+}
+
+// @Summary      Get evaluation form
+// @Description  Public, for industry mentor with token of evaluation link
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetEvaluationForm	true	"token of evaluation link"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponseEvaluationForm}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/evaluation [get]
+func (h *Internship) GetEvaluationForm(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetEvaluationForm
+	c.ShouldBindQuery(&payload)
+
+	form, errPayload := h.internshipService.ApplyContext(c).GetEvaluationForm(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(form).OkJSON()
+}
+
+// @Summary      Submit internship evaluation
+// @Description  Public, for industry mentor with token of evaluation link. Link can only be used once, final score is average of aspect scores
+// @Tags         internship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestSubmitEvaluation	true	"evaluation of mentor"
+// @Success      201  		{object}  swaglib.Envelope{data=models.InternshipEvaluation}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /internships/evaluation [post]
+func (h *Internship) SubmitEvaluation(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestSubmitEvaluation
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	evaluation, errPayload := h.internshipService.ApplyContext(c).SubmitEvaluation(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(evaluation).CreatedJSON()
+}
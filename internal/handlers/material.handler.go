@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Material struct {
+	materialService *services.Material
+}
+
+func NewMaterial(materialService *services.Material) *Material {
+	return &Material{materialService}
+}
+
+// @Summary      Upload learning material
+// @Description  Teacher only, the subject must be taught by the teacher. File is limited by size and type
+// @Tags         material
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  formData	payloads.RequestCreateMaterial	true	"data of material"
+// @Param				 file  		formData	file	false	"material file, required if type is file"
+// @Success      201  		{object}  swaglib.Envelope{data=models.LearningMaterial}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /materials [post]
+func (h *Material) CreateMaterial(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	// extra 1 MB for other form fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MAX_UPLOAD_SIZE+(1<<20))
+
+	var payload payloads.RequestCreateMaterial
+	if err := c.ShouldBind(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	material, errPayload := h.materialService.ApplyContext(c).CreateMaterial(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(material).CreatedJSON()
+}
+
+// @Summary      Get learning materials
+// @Description  Admin with permission read material resource, teacher, or student only. Student only get materials of own class
+// @Tags         material
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetMaterials	true	"config to accept materials"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.LearningMaterial,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /materials [get]
+func (h *Material) GetMaterials(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetMaterials
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	materials, errPayload := h.materialService.ApplyContext(c).GetMaterials(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(materials).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get learning material with id
+// @Description  Admin with permission read material resource, teacher, or student of the class only
+// @Tags         material
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "material id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.LearningMaterial}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /materials/{id} [get]
+func (h *Material) GetMaterial(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetMaterial
+	c.ShouldBindUri(&payload)
+
+	material, errPayload := h.materialService.ApplyContext(c).GetMaterial(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(material).OkJSON()
+}
+
+// @Summary      Download file of learning material
+// @Description  Admin with permission read material resource, teacher, or student of the class only
+// @Tags         material
+// @Produce      octet-stream
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "material id"
+// @Success      200  		{file}  	file
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /materials/{id}/download [get]
+func (h *Material) DownloadMaterial(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetMaterial
+	c.ShouldBindUri(&payload)
+
+	material, content, errPayload := h.materialService.ApplyContext(c).DownloadMaterial(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, material.File.Size, material.File.MimeType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": material.FileName}),
+	})
+}
+
+// @Summary      Delete learning material
+// @Description  Admin with permission delete material resource, or teacher who uploaded the material only
+// @Tags         material
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "material id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /materials/{id} [delete]
+func (h *Material) DeleteMaterial(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteMaterial
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.materialService.ApplyContext(c).DeleteMaterial(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
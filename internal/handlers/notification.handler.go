@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Notification struct {
+	notificationService *services.Notification
+}
+
+func NewNotification(notificationService *services.Notification) *Notification {
+	return &Notification{notificationService}
+}
+
+// @Summary      Get notifications
+// @Description  Signed in user only. Get notifications of current user from the latest
+// @Tags         notification
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetNotifications	true	"query of notifications"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.Notification,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /notifications [get]
+func (h *Notification) GetNotifications(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetNotifications
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	notifications, errPayload := h.notificationService.ApplyContext(c).GetNotifications(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(notifications).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Read notification
+// @Description  Signed in user only. Mark notification of current user as read
+// @Tags         notification
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of notification"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Notification}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /notifications/{id}/read [put]
+func (h *Notification) ReadNotification(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestReadNotification
+	c.ShouldBindUri(&payload)
+
+	notification, errPayload := h.notificationService.ApplyContext(c).ReadNotification(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(notification).OkJSON()
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"fmt"
+	"net/http"
 	"school-information-system/config"
 	"school-information-system/internal/libs/replylib"
 	"school-information-system/internal/libs/slicelib"
@@ -9,6 +10,7 @@ import (
 	"school-information-system/internal/models/payloads"
 	"school-information-system/internal/services"
 	"strings"
+	"time"
 
 	adapter "github.com/chesta132/goreply/adapter/gin"
 	"github.com/gin-gonic/gin"
@@ -23,7 +25,7 @@ func NewPermission(permService *services.Permission) *Permission {
 }
 
 // @Summary      Create new permission combination
-// @Description  Admin with permission create permission resource only
+// @Description  Admin with permission create permission resource only. Permission on class and student resource can be limited to grades, majors or classes by scope
 // @Tags         permission
 // @Accept       json
 // @Produce      json
@@ -165,7 +167,8 @@ func (h *Permission) DeletePermission(c *gin.Context) {
 }
 
 // @Summary      Grant existing permission to another admin
-// @Description  Admin with permission update permission resource only. Response granted user
+// @Description  Admin with permission update permission resource only. Grant may be limited by valid_from and valid_until, expired grant is ignored and revoked by cron job. Response granted user.
+// @Description  Granting seed permission needs approval of another admin holding update permission resource, a change request is responded instead
 // @Tags         permission
 // @Accept       json
 // @Produce      json
@@ -173,6 +176,7 @@ func (h *Permission) DeletePermission(c *gin.Context) {
 // @Param				 Cookie2  header 		string 	true	"refresh_token"
 // @Param				 payload  body			payloads.RequestGrantPermission	true	"data to grant permission"
 // @Success      200  		{object}  swaglib.Envelope{data=models.User{admin_profile=models.Admin{permissions=[]models.Permission}},meta=swaglib.Info}
+// @Success      202  		{object}  swaglib.Envelope{data=models.ChangeRequest,meta=swaglib.Info} "change is requested for approval of another admin"
 // @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
 // @Router       /permissions/grant [put]
 func (h *Permission) GrantPermission(c *gin.Context) {
@@ -183,11 +187,15 @@ func (h *Permission) GrantPermission(c *gin.Context) {
 		return
 	}
 
-	user, permission, errPayload := h.permService.ApplyContext(c).GrantPermission(payload)
+	user, permission, request, errPayload := h.permService.ApplyContext(c).GrantPermission(payload)
 	if errPayload != nil {
 		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
 		return
 	}
+	if request != nil {
+		rp.Success(request).Info(fmt.Sprintf("change requested, it is applied once another admin approves it before %s", request.ExpiresAt.Format(time.RFC3339))).ReplyJSON(http.StatusAccepted)
+		return
+	}
 
 	// strings.Join can't process []models.PermissionAction
 	permActs := slicelib.Map(permission.Actions, func(i int, act models.PermissionAction) string { return string(act) })
@@ -195,7 +203,8 @@ func (h *Permission) GrantPermission(c *gin.Context) {
 }
 
 // @Summary      Revoke existing permission of another admin
-// @Description  Admin with permission update permission resource only. Response granted user
+// @Description  Admin with permission update permission resource only. Response granted user.
+// @Description  Revoking seed permission needs approval of another admin holding update permission resource, a change request is responded instead
 // @Tags         permission
 // @Accept       json
 // @Produce      json
@@ -203,6 +212,7 @@ func (h *Permission) GrantPermission(c *gin.Context) {
 // @Param				 Cookie2  header 		string 	true	"refresh_token"
 // @Param				 payload  body			payloads.RequestRevokePermission	true	"data to revoke permission"
 // @Success      200  		{object}  swaglib.Envelope{data=models.User{admin_profile=models.Admin{permissions=[]models.Permission}},meta=swaglib.Info}
+// @Success      202  		{object}  swaglib.Envelope{data=models.ChangeRequest,meta=swaglib.Info} "change is requested for approval of another admin"
 // @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
 // @Router       /permissions/revoke [put]
 func (h *Permission) RevokePermission(c *gin.Context) {
@@ -213,13 +223,263 @@ func (h *Permission) RevokePermission(c *gin.Context) {
 		return
 	}
 
-	user, permission, errPayload := h.permService.ApplyContext(c).RevokePermission(payload)
+	user, permission, request, errPayload := h.permService.ApplyContext(c).RevokePermission(payload)
 	if errPayload != nil {
 		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
 		return
 	}
+	if request != nil {
+		rp.Success(request).Info(fmt.Sprintf("change requested, it is applied once another admin approves it before %s", request.ExpiresAt.Format(time.RFC3339))).ReplyJSON(http.StatusAccepted)
+		return
+	}
 
 	// strings.Join can't process []models.PermissionAction
 	permActs := slicelib.Map(permission.Actions, func(i int, act models.PermissionAction) string { return string(act) })
 	rp.Success(user).Info(fmt.Sprintf("%s's no longer permitted to %s %s", user.FullName, strings.Join(permActs, ", "), permission.Resource)).OkJSON()
 }
+
+// @Summary      Create permission bundle
+// @Description  Admin with permission create permission resource only. Bundle is named set of permissions granted to admins at once
+// @Tags         permission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreatePermissionBundle	true	"data of new permission bundle"
+// @Success      201  		{object}  swaglib.Envelope{data=models.PermissionBundle{permissions=[]models.Permission}}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /permissions/bundles [post]
+func (h *Permission) CreateBundle(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreatePermissionBundle
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	bundle, errPayload := h.permService.ApplyContext(c).CreateBundle(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(bundle).CreatedJSON()
+}
+
+// @Summary      Get permission bundle
+// @Description  Admin with permission read permission resource only
+// @Tags         permission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of permission bundle"
+// @Success      200  		{object}  swaglib.Envelope{data=models.PermissionBundle{permissions=[]models.Permission}}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /permissions/bundles/{id} [get]
+func (h *Permission) GetBundle(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetPermissionBundle
+	c.ShouldBindUri(&payload)
+
+	bundle, errPayload := h.permService.ApplyContext(c).GetBundle(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(bundle).OkJSON()
+}
+
+// @Summary      Get permission bundles
+// @Description  Admin with permission read permission resource only
+// @Tags         permission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetPermissionBundles	true	"query of permission bundles"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.PermissionBundle{permissions=[]models.Permission},meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /permissions/bundles [get]
+func (h *Permission) GetBundles(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetPermissionBundles
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	bundles, errPayload := h.permService.ApplyContext(c).GetBundles(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(bundles).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Update permission bundle
+// @Description  Admin with permission update permission resource only. Replaced permissions are granted to or revoked from every holder of the bundle, revoking the last holder of a seed permission is rejected.
+// @Description  Adding or removing seed permission needs approval of another admin holding update permission resource, a change request is responded instead
+// @Tags         permission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of permission bundle"
+// @Param				 payload  body			payloads.RequestUpdatePermissionBundle	true	"updated data of permission bundle"
+// @Success      200  		{object}  swaglib.Envelope{data=models.PermissionBundle{permissions=[]models.Permission}}
+// @Success      202  		{object}  swaglib.Envelope{data=models.ChangeRequest,meta=swaglib.Info} "change is requested for approval of another admin"
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /permissions/bundles/{id} [put]
+func (h *Permission) UpdateBundle(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdatePermissionBundle
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	bundle, request, errPayload := h.permService.ApplyContext(c).UpdateBundle(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+	if request != nil {
+		rp.Success(request).Info(fmt.Sprintf("change requested, it is applied once another admin approves it before %s", request.ExpiresAt.Format(time.RFC3339))).ReplyJSON(http.StatusAccepted)
+		return
+	}
+
+	rp.Success(bundle).OkJSON()
+}
+
+// @Summary      Delete permission bundle
+// @Description  Admin with permission delete permission resource only. Permissions of the bundle are revoked from every holder of the bundle.
+// @Description  Deleting bundle holding seed permission needs approval of another admin holding delete permission resource, a change request is responded instead
+// @Tags         permission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "ID of permission bundle"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Success      202  		{object}  swaglib.Envelope{data=models.ChangeRequest,meta=swaglib.Info} "change is requested for approval of another admin"
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /permissions/bundles/{id} [delete]
+func (h *Permission) DeleteBundle(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeletePermissionBundle
+	c.ShouldBindUri(&payload)
+
+	request, errPayload := h.permService.ApplyContext(c).DeleteBundle(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+	if request != nil {
+		rp.Success(request).Info(fmt.Sprintf("change requested, it is applied once another admin approves it before %s", request.ExpiresAt.Format(time.RFC3339))).ReplyJSON(http.StatusAccepted)
+		return
+	}
+
+	rp.Success(map[string]string{"id": payload.ID}).OkJSON()
+}
+
+// @Summary      Grant permission bundle to another admin
+// @Description  Admin with permission update permission resource only. Response granted user.
+// @Description  Granting bundle holding seed permission needs approval of another admin holding update permission resource, a change request is responded instead
+// @Tags         permission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestGrantPermissionBundle	true	"data to grant permission bundle"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User{admin_profile=models.Admin{bundles=[]models.PermissionBundle}},meta=swaglib.Info}
+// @Success      202  		{object}  swaglib.Envelope{data=models.ChangeRequest,meta=swaglib.Info} "change is requested for approval of another admin"
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /permissions/bundles/grant [put]
+func (h *Permission) GrantBundle(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGrantPermissionBundle
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	user, bundle, request, errPayload := h.permService.ApplyContext(c).GrantBundle(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+	if request != nil {
+		rp.Success(request).Info(fmt.Sprintf("change requested, it is applied once another admin approves it before %s", request.ExpiresAt.Format(time.RFC3339))).ReplyJSON(http.StatusAccepted)
+		return
+	}
+
+	rp.Success(user).Info(fmt.Sprintf("%s's granted permission bundle %s", user.FullName, bundle.Name)).OkJSON()
+}
+
+// @Summary      Revoke permission bundle of another admin
+// @Description  Admin with permission update permission resource only. Response revoked user.
+// @Description  Revoking bundle holding seed permission needs approval of another admin holding update permission resource, a change request is responded instead
+// @Tags         permission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestRevokePermissionBundle	true	"data to revoke permission bundle"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User{admin_profile=models.Admin{bundles=[]models.PermissionBundle}},meta=swaglib.Info}
+// @Success      202  		{object}  swaglib.Envelope{data=models.ChangeRequest,meta=swaglib.Info} "change is requested for approval of another admin"
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /permissions/bundles/revoke [delete]
+func (h *Permission) RevokeBundle(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestRevokePermissionBundle
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	user, bundle, request, errPayload := h.permService.ApplyContext(c).RevokeBundle(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+	if request != nil {
+		rp.Success(request).Info(fmt.Sprintf("change requested, it is applied once another admin approves it before %s", request.ExpiresAt.Format(time.RFC3339))).ReplyJSON(http.StatusAccepted)
+		return
+	}
+
+	rp.Success(user).Info(fmt.Sprintf("%s's no longer granted permission bundle %s", user.FullName, bundle.Name)).OkJSON()
+}
+
+// @Summary      Get permission audits
+// @Description  Admin with permission read permission resource only. Get every grant and revoke of permissions and bundles from the latest change
+// @Tags         permission
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetPermissionAudits	true	"query of permission audits"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.PermissionAudit,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /permissions/audits [get]
+func (h *Permission) GetAudits(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetPermissionAudits
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	audits, errPayload := h.permService.ApplyContext(c).GetAudits(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(audits).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Quiz struct {
+	quizService *services.Quiz
+}
+
+func NewQuiz(quizService *services.Quiz) *Quiz {
+	return &Quiz{quizService}
+}
+
+// @Summary      Create quiz
+// @Description  Teacher only, the subject must be taught by the teacher
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateQuiz	true	"data of quiz"
+// @Success      201  		{object}  swaglib.Envelope{data=models.Quiz}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes [post]
+func (h *Quiz) CreateQuiz(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateQuiz
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	quiz, errPayload := h.quizService.ApplyContext(c).CreateQuiz(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(quiz).CreatedJSON()
+}
+
+// @Summary      Get quizzes
+// @Description  Admin with permission read quiz resource, teacher, or student only. Student only get quizzes given to own class
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetQuizzes	true	"config to accept quizzes"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.Quiz,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes [get]
+func (h *Quiz) GetQuizzes(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetQuizzes
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	quizzes, errPayload := h.quizService.ApplyContext(c).GetQuizzes(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(quizzes).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get quiz with id
+// @Description  Admin with permission read quiz resource, teacher, or student of the class only. Questions are hidden from student
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "quiz id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Quiz}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes/{id} [get]
+func (h *Quiz) GetQuiz(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetQuiz
+	c.ShouldBindUri(&payload)
+
+	quiz, errPayload := h.quizService.ApplyContext(c).GetQuiz(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(quiz).OkJSON()
+}
+
+// @Summary      Delete quiz
+// @Description  Admin with permission delete quiz resource, or teacher who created the quiz only. Attempts and gradebook scores of the quiz are deleted
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "quiz id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes/{id} [delete]
+func (h *Quiz) DeleteQuiz(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteQuiz
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.quizService.ApplyContext(c).DeleteQuiz(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Set quiz window of class
+// @Description  Admin with permission update quiz resource, or teacher who created the quiz only. Replaces existing window of the class
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "quiz id"
+// @Param				 payload  body			payloads.RequestSetQuizWindow	true	"class and period"
+// @Success      200  		{object}  swaglib.Envelope{data=models.QuizWindow}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes/{id}/windows [put]
+func (h *Quiz) SetWindow(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestSetQuizWindow
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	window, errPayload := h.quizService.ApplyContext(c).SetWindow(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(window).OkJSON()
+}
+
+// @Summary      Add bank questions to quiz
+// @Description  Admin with permission update quiz resource, or teacher who created the quiz only. Current version of each bank question is copied to the end of the quiz, later edits in the bank do not change it. Quiz which already attempted can not be changed
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "quiz id"
+// @Param				 payload  body			payloads.RequestAddBankQuestionsToQuiz	true	"bank questions to add"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Quiz}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes/{id}/questions/bank [post]
+func (h *Quiz) AddBankQuestions(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestAddBankQuestionsToQuiz
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	quiz, errPayload := h.quizService.ApplyContext(c).AddBankQuestions(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(quiz).OkJSON()
+}
+
+// @Summary      Start quiz attempt
+// @Description  Student of class with open window only. Running attempt is continued instead of creating new one
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "quiz id"
+// @Success      201  		{object}  swaglib.Envelope{data=payloads.ResponseQuizPaper}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes/{id}/attempts [post]
+func (h *Quiz) StartAttempt(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestStartQuizAttempt
+	c.ShouldBindUri(&payload)
+
+	paper, errPayload := h.quizService.ApplyContext(c).StartAttempt(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(paper).CreatedJSON()
+}
+
+// @Summary      Get attempts of quiz
+// @Description  Admin with permission read quiz resource, or teacher who created the quiz only
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "quiz id"
+// @Param				 payload  query			payloads.RequestGetQuizAttempts	true	"config to accept attempts"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.QuizAttempt,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes/{id}/attempts [get]
+func (h *Quiz) GetAttempts(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetQuizAttempts
+	c.ShouldBindQuery(&payload)
+	payload.ID = c.Param("id")
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	attempts, errPayload := h.quizService.ApplyContext(c).GetAttempts(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(attempts).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get quiz attempt with id
+// @Description  Admin with permission read quiz resource, teacher who created the quiz, or student who attempted only. Answer keys are hidden from student
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "attempt id"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponseQuizPaper}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes/attempts/{id} [get]
+func (h *Quiz) GetAttempt(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetQuizAttempt
+	c.ShouldBindUri(&payload)
+
+	paper, errPayload := h.quizService.ApplyContext(c).GetAttempt(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(paper).OkJSON()
+}
+
+// @Summary      Submit quiz attempt
+// @Description  Student who attempted only, before the attempt deadline. Objective questions are graded automatically
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "attempt id"
+// @Param				 payload  body			payloads.RequestSubmitQuizAttempt	true	"answers of student"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponseQuizPaper}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes/attempts/{id}/submit [post]
+func (h *Quiz) SubmitAttempt(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestSubmitQuizAttempt
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	paper, errPayload := h.quizService.ApplyContext(c).SubmitAttempt(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(paper).OkJSON()
+}
+
+// @Summary      Grade answer of quiz attempt
+// @Description  Admin with permission update quiz resource, or teacher who created the quiz only. Used for essay, attempt is scored once every answer graded
+// @Tags         quiz
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "answer id"
+// @Param				 payload  body			payloads.RequestGradeQuizAnswer	true	"points and feedback"
+// @Success      200  		{object}  swaglib.Envelope{data=models.QuizAnswer}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /quizzes/answers/{id}/grade [put]
+func (h *Quiz) GradeAnswer(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGradeQuizAnswer
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	answer, errPayload := h.quizService.ApplyContext(c).GradeAnswer(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(answer).OkJSON()
+}
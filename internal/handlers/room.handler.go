@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Room struct {
+	roomService *services.Room
+}
+
+func NewRoom(roomService *services.Room) *Room {
+	return &Room{roomService}
+}
+
+// @Summary      Create room
+// @Description  Admin with permission create room resource only
+// @Tags         room
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateRoom	true	"data of room"
+// @Success      201  		{object}  swaglib.Envelope{data=models.Room}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /rooms [post]
+func (h *Room) CreateRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateRoom
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	room, errPayload := h.roomService.ApplyContext(c).CreateRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(room).CreatedJSON()
+}
+
+// @Summary      Get rooms
+// @Description  Admin with permission read room resource, or teacher only
+// @Tags         room
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetRooms	true	"config to accept rooms"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.Room,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /rooms [get]
+func (h *Room) GetRooms(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetRooms
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	rooms, errPayload := h.roomService.ApplyContext(c).GetRooms(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(rooms).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get room with id
+// @Description  Admin with permission read room resource, or teacher only
+// @Tags         room
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "room id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Room}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /rooms/{id} [get]
+func (h *Room) GetRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetRoom
+	c.ShouldBindUri(&payload)
+
+	room, errPayload := h.roomService.ApplyContext(c).GetRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(room).OkJSON()
+}
+
+// @Summary      Update room
+// @Description  Admin with permission update room resource only
+// @Tags         room
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "room id"
+// @Param				 payload  body			payloads.RequestUpdateRoom	true	"new data of room"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Room}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /rooms/{id} [put]
+func (h *Room) UpdateRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateRoom
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	room, errPayload := h.roomService.ApplyContext(c).UpdateRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(room).OkJSON()
+}
+
+// @Summary      Delete room
+// @Description  Admin with permission delete room resource only. Bookings of the room are deleted, classes and exam rooms using it are unlinked
+// @Tags         room
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "room id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /rooms/{id} [delete]
+func (h *Room) DeleteRoom(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteRoom
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.roomService.ApplyContext(c).DeleteRoom(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Book room
+// @Description  Admin with permission create room resource, or teacher only. Booking must not collide with other booking, exam or timetable lesson in the room. Booking of lab and hall waits for approval, other rooms are approved directly
+// @Tags         room
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateRoomBooking	true	"data of booking"
+// @Success      201  		{object}  swaglib.Envelope{data=models.RoomBooking}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /rooms/bookings [post]
+func (h *Room) CreateBooking(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateRoomBooking
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	booking, errPayload := h.roomService.ApplyContext(c).CreateBooking(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(booking).CreatedJSON()
+}
+
+// @Summary      Get room bookings
+// @Description  Admin with permission read room resource, or teacher only
+// @Tags         room
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetRoomBookings	true	"config to accept bookings"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.RoomBooking,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /rooms/bookings [get]
+func (h *Room) GetBookings(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetRoomBookings
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	bookings, errPayload := h.roomService.ApplyContext(c).GetBookings(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(bookings).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Review room booking
+// @Description  Admin with permission update room resource only. Pending booking is approved or rejected, approval checks collision again
+// @Tags         room
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "booking id"
+// @Param				 payload  body			payloads.RequestReviewRoomBooking	true	"review result"
+// @Success      200  		{object}  swaglib.Envelope{data=models.RoomBooking}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /rooms/bookings/{id}/review [put]
+func (h *Room) ReviewBooking(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestReviewRoomBooking
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	booking, errPayload := h.roomService.ApplyContext(c).ReviewBooking(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(booking).OkJSON()
+}
+
+// @Summary      Cancel room booking
+// @Description  Admin with permission update room resource, or teacher who booked the room only
+// @Tags         room
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "booking id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.RoomBooking}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /rooms/bookings/{id}/cancel [post]
+func (h *Room) CancelBooking(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCancelRoomBooking
+	c.ShouldBindUri(&payload)
+
+	booking, errPayload := h.roomService.ApplyContext(c).CancelBooking(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(booking).OkJSON()
+}
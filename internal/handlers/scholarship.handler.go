@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Scholarship struct {
+	scholarshipService *services.Scholarship
+}
+
+func NewScholarship(scholarshipService *services.Scholarship) *Scholarship {
+	return &Scholarship{scholarshipService}
+}
+
+// @Summary      Create scholarship program
+// @Description  Admin with permission create scholarship resource only
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateScholarshipProgram	true	"data of program"
+// @Success      201  		{object}  swaglib.Envelope{data=models.ScholarshipProgram}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/programs [post]
+func (h *Scholarship) CreateProgram(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateScholarshipProgram
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	program, errPayload := h.scholarshipService.ApplyContext(c).CreateProgram(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(program).CreatedJSON()
+}
+
+// @Summary      Get scholarship programs
+// @Description  Admin with permission read scholarship resource only
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetScholarshipPrograms	true	"config to accept programs"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.ScholarshipProgram,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/programs [get]
+func (h *Scholarship) GetPrograms(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetScholarshipPrograms
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	programs, errPayload := h.scholarshipService.ApplyContext(c).GetPrograms(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(programs).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get scholarship program with id
+// @Description  Admin with permission read scholarship resource only
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "program id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.ScholarshipProgram}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/programs/{id} [get]
+func (h *Scholarship) GetProgram(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetScholarshipProgram
+	c.ShouldBindUri(&payload)
+
+	program, errPayload := h.scholarshipService.ApplyContext(c).GetProgram(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(program).OkJSON()
+}
+
+// @Summary      Update scholarship program
+// @Description  Admin with permission update scholarship resource only. Changed fee discount applies to every term of the recipients
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "program id"
+// @Param				 payload  body			payloads.RequestUpdateScholarshipProgram	true	"new data of program"
+// @Success      200  		{object}  swaglib.Envelope{data=models.ScholarshipProgram}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/programs/{id} [put]
+func (h *Scholarship) UpdateProgram(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateScholarshipProgram
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	program, errPayload := h.scholarshipService.ApplyContext(c).UpdateProgram(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(program).OkJSON()
+}
+
+// @Summary      Delete scholarship program
+// @Description  Admin with permission delete scholarship resource only. Program with recipients can not be deleted, deactivate it instead
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "program id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/programs/{id} [delete]
+func (h *Scholarship) DeleteProgram(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteScholarshipProgram
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.scholarshipService.ApplyContext(c).DeleteProgram(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Add recipients of scholarship program
+// @Description  Admin with permission create scholarship resource only. Program must be active, students are registered as recipients in the term
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "program id"
+// @Param				 payload  body			payloads.RequestAddScholarshipRecipients	true	"students and term"
+// @Success      201  		{object}  swaglib.Envelope{data=[]models.ScholarshipRecipient}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/programs/{id}/recipients [post]
+func (h *Scholarship) AddRecipients(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestAddScholarshipRecipients
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	recipients, errPayload := h.scholarshipService.ApplyContext(c).AddRecipients(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(recipients).CreatedJSON()
+}
+
+// @Summary      Get scholarship recipients
+// @Description  Admin with permission read scholarship resource only
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetScholarshipRecipients	true	"config to accept recipients"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.ScholarshipRecipient,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/recipients [get]
+func (h *Scholarship) GetRecipients(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetScholarshipRecipients
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	recipients, errPayload := h.scholarshipService.ApplyContext(c).GetRecipients(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(recipients).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get scholarship recipient with id
+// @Description  Admin with permission read scholarship resource only. Disbursements are included
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "recipient id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.ScholarshipRecipient}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/recipients/{id} [get]
+func (h *Scholarship) GetRecipient(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetScholarshipRecipient
+	c.ShouldBindUri(&payload)
+
+	recipient, errPayload := h.scholarshipService.ApplyContext(c).GetRecipient(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(recipient).OkJSON()
+}
+
+// @Summary      Remove scholarship recipient
+// @Description  Admin with permission delete scholarship resource only. Recipient with disbursement can not be removed
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "recipient id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/recipients/{id} [delete]
+func (h *Scholarship) RemoveRecipient(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestRemoveScholarshipRecipient
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.scholarshipService.ApplyContext(c).RemoveRecipient(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Record scholarship disbursement
+// @Description  Admin with permission create scholarship resource only
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "recipient id"
+// @Param				 payload  body			payloads.RequestCreateDisbursement	true	"data of disbursement"
+// @Success      201  		{object}  swaglib.Envelope{data=models.ScholarshipDisbursement}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/recipients/{id}/disbursements [post]
+func (h *Scholarship) CreateDisbursement(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateDisbursement
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	disbursement, errPayload := h.scholarshipService.ApplyContext(c).CreateDisbursement(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(disbursement).CreatedJSON()
+}
+
+// @Summary      Delete scholarship disbursement
+// @Description  Admin with permission delete scholarship resource only
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "disbursement id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/disbursements/{id} [delete]
+func (h *Scholarship) DeleteDisbursement(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteDisbursement
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.scholarshipService.ApplyContext(c).DeleteDisbursement(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Get school fee discount of student
+// @Description  Admin with permission read scholarship resource only. Sum of fee discount of programs received in the term, capped at 100 percent
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "student id"
+// @Param				 payload  query			payloads.RequestGetFeeDiscount	true	"term"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponseFeeDiscount}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/students/{id}/fee-discount [get]
+func (h *Scholarship) GetFeeDiscount(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetFeeDiscount
+	c.ShouldBindQuery(&payload)
+	payload.ID = c.Param("id")
+
+	discount, errPayload := h.scholarshipService.ApplyContext(c).GetFeeDiscount(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(discount).OkJSON()
+}
+
+// @Summary      Get scholarship report
+// @Description  Admin with permission read scholarship resource only. Recipients and disbursed amount per program and class for government reporting
+// @Tags         scholarship
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetScholarshipReport	true	"term of report"
+// @Success      200  		{object}  swaglib.Envelope{data=[]payloads.ResponseScholarshipReportRow}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /scholarships/report [get]
+func (h *Scholarship) GetReport(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetScholarshipReport
+	c.ShouldBindQuery(&payload)
+
+	report, errPayload := h.scholarshipService.ApplyContext(c).GetReport(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(report).OkJSON()
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"fmt"
+	"school-information-system/config"
 	"school-information-system/internal/libs/replylib"
 	"school-information-system/internal/models/payloads"
 	"school-information-system/internal/services"
@@ -46,3 +48,116 @@ func (h *Student) UpdateStudent(c *gin.Context) {
 
 	rp.Success(student).OkJSON()
 }
+
+// @Summary      Transfer in student from another school
+// @Description  Admin with permission create student resource only. Creates user directly as student with imported records of previous school
+// @Tags         student
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestTransferIn	true	"data of transferred student"
+// @Success      201  		{object}  swaglib.Envelope{data=models.User{student_profile=models.Student},meta=swaglib.Info}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /students/transfer-in [post]
+func (h *Student) TransferIn(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestTransferIn
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	user, transfer, errPayload := h.studentService.ApplyContext(c).TransferIn(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(user).Info(fmt.Sprintf("%s transferred in from %s (%s)", user.FullName, transfer.SchoolName, transfer.LetterNumber)).CreatedJSON()
+}
+
+// @Summary      Transfer out student to another school
+// @Description  Admin with permission delete student resource only. Archives the student and responses transfer letter with data export
+// @Tags         student
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "student id"
+// @Param				 payload  body			payloads.RequestTransferOut	true	"data of transfer"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponseTransfer,meta=swaglib.Info}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /students/{id}/transfer-out [post]
+func (h *Student) TransferOut(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestTransferOut
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	transfer, errPayload := h.studentService.ApplyContext(c).TransferOut(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	response := payloads.ResponseTransfer{Transfer: transfer, Letter: transfer.Letter()}
+	rp.Success(response).Info(fmt.Sprintf("%s transferred out to %s", transfer.StudentName, transfer.SchoolName)).OkJSON()
+}
+
+// @Summary      Get transfer record with letter
+// @Description  Admin with permission read student resource only
+// @Tags         student
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "transfer id"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponseTransfer}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /students/transfers/{id} [get]
+func (h *Student) GetTransfer(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetTransfer
+	c.ShouldBindUri(&payload)
+
+	transfer, errPayload := h.studentService.ApplyContext(c).GetTransfer(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(payloads.ResponseTransfer{Transfer: transfer, Letter: transfer.Letter()}).OkJSON()
+}
+
+// @Summary      Get transfer records
+// @Description  Admin with permission read student resource only
+// @Tags         student
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetTransfers	true	"config to accept transfers"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.StudentTransfer,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /students/transfers [get]
+func (h *Student) GetTransfers(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetTransfers
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	transfers, errPayload := h.studentService.ApplyContext(c).GetTransfers(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(transfers).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
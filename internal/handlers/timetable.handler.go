@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type Timetable struct {
+	timetableService *services.Timetable
+}
+
+func NewTimetable(timetableService *services.Timetable) *Timetable {
+	return &Timetable{timetableService}
+}
+
+// @Summary      Create timetable slot
+// @Description  Admin with permission create timetable resource only. Teacher must teach the subject, lesson can not collide with other lesson of the class, teacher or room, nor with pending or approved booking of the room within the term
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateTimetableSlot	true	"data of slot"
+// @Success      201  		{object}  swaglib.Envelope{data=models.TimetableSlot}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/slots [post]
+func (h *Timetable) CreateSlot(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateTimetableSlot
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	slot, errPayload := h.timetableService.ApplyContext(c).CreateSlot(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(slot).CreatedJSON()
+}
+
+// @Summary      Get timetable slots
+// @Description  Admin with permission read timetable resource or teacher only. Teacher only get own lessons
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetTimetableSlots	true	"config to accept slots"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.TimetableSlot}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/slots [get]
+func (h *Timetable) GetSlots(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetTimetableSlots
+	c.ShouldBindQuery(&payload)
+
+	slots, errPayload := h.timetableService.ApplyContext(c).GetSlots(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(slots).OkJSON()
+}
+
+// @Summary      Get timetable slot with id
+// @Description  Admin with permission read timetable resource or teacher only
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "slot id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.TimetableSlot}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/slots/{id} [get]
+func (h *Timetable) GetSlot(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetTimetableSlot
+	c.ShouldBindUri(&payload)
+
+	slot, errPayload := h.timetableService.ApplyContext(c).GetSlot(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(slot).OkJSON()
+}
+
+// @Summary      Update timetable slot
+// @Description  Admin with permission update timetable resource only. Updated lesson is checked for collision like new lesson
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "slot id"
+// @Param				 payload  body			payloads.RequestUpdateTimetableSlot	true	"new data of slot"
+// @Success      200  		{object}  swaglib.Envelope{data=models.TimetableSlot}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/slots/{id} [put]
+func (h *Timetable) UpdateSlot(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateTimetableSlot
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	slot, errPayload := h.timetableService.ApplyContext(c).UpdateSlot(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(slot).OkJSON()
+}
+
+// @Summary      Delete timetable slot
+// @Description  Admin with permission delete timetable resource only. Journals of the slot are kept as lesson outside timetable
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "slot id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/slots/{id} [delete]
+func (h *Timetable) DeleteSlot(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteTimetableSlot
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.timetableService.ApplyContext(c).DeleteSlot(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Create teaching journal
+// @Description  Teacher only. Class and subject are taken from the slot if slot_id is filled, lesson of a slot can only be logged once a day
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestCreateTeachingJournal	true	"data of lesson"
+// @Success      201  		{object}  swaglib.Envelope{data=models.TeachingJournal}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/journals [post]
+func (h *Timetable) CreateJournal(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestCreateTeachingJournal
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	journal, errPayload := h.timetableService.ApplyContext(c).CreateJournal(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(journal).CreatedJSON()
+}
+
+// @Summary      Get teaching journals
+// @Description  Admin with permission read timetable resource or teacher only. Teacher only get own journals
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetTeachingJournals	true	"config to accept journals"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.TeachingJournal,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/journals [get]
+func (h *Timetable) GetJournals(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetTeachingJournals
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	journals, errPayload := h.timetableService.ApplyContext(c).GetJournals(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(journals).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get teaching journal with id
+// @Description  Admin with permission read timetable resource or teacher who wrote the journal only
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "journal id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.TeachingJournal}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/journals/{id} [get]
+func (h *Timetable) GetJournal(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetTeachingJournal
+	c.ShouldBindUri(&payload)
+
+	journal, errPayload := h.timetableService.ApplyContext(c).GetJournal(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(journal).OkJSON()
+}
+
+// @Summary      Update teaching journal
+// @Description  Teacher who wrote the journal only
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "journal id"
+// @Param				 payload  body			payloads.RequestUpdateTeachingJournal	true	"new content of journal"
+// @Success      200  		{object}  swaglib.Envelope{data=models.TeachingJournal}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/journals/{id} [put]
+func (h *Timetable) UpdateJournal(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestUpdateTeachingJournal
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+	payload.ID = c.Param("id")
+
+	journal, errPayload := h.timetableService.ApplyContext(c).UpdateJournal(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(journal).OkJSON()
+}
+
+// @Summary      Delete teaching journal
+// @Description  Admin with permission delete timetable resource or teacher who wrote the journal only
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "journal id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.Id}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/journals/{id} [delete]
+func (h *Timetable) DeleteJournal(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestDeleteTeachingJournal
+	c.ShouldBindUri(&payload)
+
+	errPayload := h.timetableService.ApplyContext(c).DeleteJournal(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(models.Id{ID: payload.ID}).OkJSON()
+}
+
+// @Summary      Get teaching journal compliance
+// @Description  Admin with permission read timetable resource only. Lists lessons held by timetable without journal, range is limited to 62 days and dates after today are not counted
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetJournalCompliance	true	"date range and filter"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponseJournalCompliance}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /timetables/compliance [get]
+func (h *Timetable) GetCompliance(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetJournalCompliance
+	c.ShouldBindQuery(&payload)
+
+	report, errPayload := h.timetableService.ApplyContext(c).GetCompliance(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(report).OkJSON()
+}
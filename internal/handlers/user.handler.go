@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"school-information-system/config"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/services"
+
+	adapter "github.com/chesta132/goreply/adapter/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type User struct {
+	userService *services.User
+}
+
+func NewUser(userService *services.User) *User {
+	return &User{userService}
+}
+
+// @Summary      Get users
+// @Description  Admin only. Only users of roles which admin has read permission on are returned: student, teacher, admin and alumni resources for their roles, role resource for users without role
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetUsers	true	"config to accept users"
+// @Success      200  		{object}  swaglib.Envelope{data=[]models.User,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /users [get]
+func (h *User) GetUsers(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetUsers
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	users, errPayload := h.userService.ApplyContext(c).GetUsers(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(users).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Get user with id
+// @Description  Admin with permission read resource of the user's role only. Profile of the role is included, archived user can also be viewed
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "user id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /users/{id} [get]
+func (h *User) GetUser(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetUser
+	c.ShouldBindUri(&payload)
+
+	user, errPayload := h.userService.ApplyContext(c).GetUser(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(user).OkJSON()
+}
+
+// @Summary      Archive user
+// @Description  Admin with permission delete resource of the user's role only. Archived user can not sign in and is deleted permanently after configured duration. Admin must be deactivated first
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "user id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /users/{id}/archive [put]
+func (h *User) ArchiveUser(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestArchiveUser
+	c.ShouldBindUri(&payload)
+
+	user, errPayload := h.userService.ApplyContext(c).ArchiveUser(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(user).OkJSON()
+}
+
+// @Summary      Restore archived user
+// @Description  Admin with permission update resource of the user's role only
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param 			 id				path 			string  true  "user id"
+// @Success      200  		{object}  swaglib.Envelope{data=models.User}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /users/{id}/restore [put]
+func (h *User) RestoreUser(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestRestoreUser
+	c.ShouldBindUri(&payload)
+
+	user, errPayload := h.userService.ApplyContext(c).RestoreUser(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(user).OkJSON()
+}
+
+// @Summary      Get users waiting for activation
+// @Description  Admin with permission read role resource only. Self-registered users are listed from the oldest sign up with duplicate hints: existing parent with the same phone or name, or student with the same name. Sign up is archived automatically after configured duration
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  query			payloads.RequestGetPendingUsers	true	"config to accept pending users"
+// @Success      200  		{object}  swaglib.Envelope{data=[]payloads.ResponsePendingUser,meta=swaglib.Pagination}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /users/pending [get]
+func (h *User) GetPendingUsers(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestGetPendingUsers
+	c.ShouldBindQuery(&payload)
+
+	if payload.Offset < 0 {
+		payload.Offset = 0
+	}
+
+	users, errPayload := h.userService.ApplyContext(c).GetPendingUsers(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(users).PaginateCursor(config.LIMIT_PAGINATED_DATA, payload.Offset).OkJSON()
+}
+
+// @Summary      Reject users waiting for activation
+// @Description  Admin with permission delete role resource only. Archives the accounts, every user must still be waiting for activation
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param				 Cookie   header 		string 	false	"access_token"
+// @Param				 Cookie2  header 		string 	true	"refresh_token"
+// @Param				 payload  body			payloads.RequestRejectPendingUsers	true	"ids of users"
+// @Success      200  		{object}  swaglib.Envelope{data=payloads.ResponseRejectPendingUsers}
+// @Response     default  {object}  swaglib.Envelope{data=reply.ErrorPayload}
+// @Router       /users/pending/reject [post]
+func (h *User) RejectPendingUsers(c *gin.Context) {
+	rp := replylib.Client.Use(adapter.AdaptGin(c))
+	var payload payloads.RequestRejectPendingUsers
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		rp.Error(replylib.CodeBadRequest, err.Error()).FailJSON()
+		return
+	}
+
+	rejected, errPayload := h.userService.ApplyContext(c).RejectPendingUsers(payload)
+	if errPayload != nil {
+		rp.Error(replylib.ErrorPayloadToArgs(errPayload)).FailJSON()
+		return
+	}
+
+	rp.Success(rejected).OkJSON()
+}
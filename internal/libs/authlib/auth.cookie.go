@@ -3,6 +3,7 @@ package authlib
 import (
 	"net/http"
 	"school-information-system/config"
+	"school-information-system/internal/models"
 	"time"
 )
 
@@ -29,9 +30,9 @@ func Invalidate(name string) http.Cookie {
 	return ToCookie(name, "", -1)
 }
 
-func CreateRefreshCookie(id, role string, rememberMe bool) http.Cookie {
+func CreateRefreshCookie(id, role string, roles []string, rememberMe bool) http.Cookie {
 	expires := time.Duration(0)
-	str := CreateRefreshToken(id, role, rememberMe)
+	str := CreateRefreshToken(id, role, roles, rememberMe)
 	if rememberMe {
 		expires = config.REFRESH_TOKEN_EXPIRY
 	}
@@ -39,12 +40,29 @@ func CreateRefreshCookie(id, role string, rememberMe bool) http.Cookie {
 	return ToCookie(config.REFRESH_TOKEN_KEY, str, expires)
 }
 
-func CreateAccessCookie(id, role string, rememberMe bool) http.Cookie {
+func CreateAccessCookie(id, role string, roles []string, rememberMe bool) http.Cookie {
 	expires := time.Duration(0)
-	str := CreateAccessToken(id, role, rememberMe)
+	str := CreateAccessToken(id, role, roles, rememberMe)
 	if rememberMe {
 		expires = config.ACCESS_TOKEN_EXPIRY
 	}
 
 	return ToCookie(config.ACCESS_TOKEN_KEY, str, expires)
 }
+
+// CreateSessionCookies creates access and refresh cookie of user with active role
+func CreateSessionCookies(user *models.User, role models.UserRole, rememberMe bool) []http.Cookie {
+	roles := RoleNames(user.HeldRoles())
+	return []http.Cookie{
+		CreateAccessCookie(user.ID, string(role), roles, rememberMe),
+		CreateRefreshCookie(user.ID, string(role), roles, rememberMe),
+	}
+}
+
+func RoleNames(roles []models.UserRole) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return names
+}
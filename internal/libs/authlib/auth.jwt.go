@@ -3,6 +3,7 @@ package authlib
 import (
 	"errors"
 	"school-information-system/config"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,13 +11,14 @@ import (
 
 type Claims struct {
 	UserID     string     `json:"user_id"`
-	Role       string     `json:"role"`
+	Role       string     `json:"role"`  // active role
+	Roles      []string   `json:"roles"` // every role held by the user
 	RotateAt   *time.Time `json:"rotate_at"`
 	RememberMe bool       `json:"remember_me"`
 	jwt.RegisteredClaims
 }
 
-func createClaim(id, role string, rememberMe bool, expiresAt time.Duration, rotateAt *time.Duration) *jwt.Token {
+func createClaim(id, role string, roles []string, rememberMe bool, expiresAt time.Duration, rotateAt *time.Duration) *jwt.Token {
 	var rotate *time.Time
 
 	if rotateAt != nil {
@@ -35,6 +37,7 @@ func createClaim(id, role string, rememberMe bool, expiresAt time.Duration, rota
 	return jwt.NewWithClaims(config.SIGN_METHOD, Claims{
 		UserID:     id,
 		Role:       role,
+		Roles:      roles,
 		RotateAt:   rotate,
 		RememberMe: rememberMe,
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -45,6 +48,11 @@ func createClaim(id, role string, rememberMe bool, expiresAt time.Duration, rota
 	})
 }
 
+// HasRole reports whether the session holds the role
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
 func createKeyFunc(secret string) jwt.Keyfunc {
 	return func(t *jwt.Token) (any, error) {
 		if t.Method != config.SIGN_METHOD {
@@ -54,14 +62,14 @@ func createKeyFunc(secret string) jwt.Keyfunc {
 	}
 }
 
-func CreateRefreshToken(id, role string, rememberMe bool) string {
-	token := createClaim(id, role, rememberMe, config.REFRESH_TOKEN_EXPIRY, &config.ROTATE_REFRESH_TOKEN_AFTER)
+func CreateRefreshToken(id, role string, roles []string, rememberMe bool) string {
+	token := createClaim(id, role, roles, rememberMe, config.REFRESH_TOKEN_EXPIRY, &config.ROTATE_REFRESH_TOKEN_AFTER)
 	str, _ := token.SignedString([]byte(config.REFRESH_SECRET))
 	return str
 }
 
-func CreateAccessToken(id, role string, rememberMe bool) string {
-	token := createClaim(id, role, rememberMe, config.ACCESS_TOKEN_EXPIRY, nil)
+func CreateAccessToken(id, role string, roles []string, rememberMe bool) string {
+	token := createClaim(id, role, roles, rememberMe, config.ACCESS_TOKEN_EXPIRY, nil)
 	str, _ := token.SignedString([]byte(config.ACCESS_SECRET))
 	return str
 }
@@ -3,11 +3,13 @@ package authlib
 import "school-information-system/internal/models"
 
 const (
-	ReasonUserSignOut = "user sign out"
+	ReasonUserSignOut  = "user sign out"
+	ReasonRoleSwitched = "user switched active role"
 )
 
 var revokedMessages = map[string]string{
-	ReasonUserSignOut: "user already signed out",
+	ReasonUserSignOut:  "user already signed out",
+	ReasonRoleSwitched: "session already replaced by session with another active role",
 }
 
 func MessageOfRevoke(revoked models.Revoked) string {
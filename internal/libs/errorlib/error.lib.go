@@ -9,4 +9,7 @@ var (
 	ErrTargetHavePermission   = errors.New("targeted user already has permission to access the resource")
 	ErrTargetDoesntHavePerm   = errors.New("targeted user doesn't have permission to process")
 	ErrPermHaventAnotherAdmin = errors.New("targeted permission doesn't have another granted admin")
+	ErrAccountNotFound        = errors.New("account not found or archived")
+	ErrSessionRevoked         = errors.New("session is no longer valid, please sign in again")
+	ErrAlumniAccessExpired    = errors.New("alumni access already expired, please contact school administration")
 )
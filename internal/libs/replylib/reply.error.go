@@ -44,6 +44,21 @@ var (
 		Fields:  reply.FieldsError{"key": "invalid key"},
 	}
 
+	// access errors
+
+	ErrAlumniAccessExpired = reply.ErrorPayload{
+		Code:    CodeForbidden,
+		Message: "alumni access already expired, please contact school administration",
+	}
+	ErrOutOfScope = reply.ErrorPayload{
+		Code:    CodeForbidden,
+		Message: "class is out of scope of your permission",
+	}
+	ErrReviewOwnChangeRequest = reply.ErrorPayload{
+		Code:    CodeForbidden,
+		Message: "can not review your own change request, ask another admin to review it",
+	}
+
 	// presence/absence error
 
 	ErrAdminExist = reply.ErrorPayload{
@@ -59,6 +74,10 @@ var (
 		Code:    CodeUnprocessableEntity,
 		Message: "this permission is immutable",
 	}
+	ErrChangeRequestReviewed = reply.ErrorPayload{
+		Code:    CodeConflict,
+		Message: "change request is already reviewed or expired",
+	}
 )
 
 func ErrorPayloadToArgs(errPayload *reply.ErrorPayload) (string, string, reply.ErrorOption, reply.ErrorOption) {
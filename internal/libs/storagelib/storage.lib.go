@@ -0,0 +1,41 @@
+package storagelib
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"school-information-system/config"
+)
+
+var ErrNotFound = errors.New("stored file not found")
+
+// Storage saves uploaded file content by key
+type Storage interface {
+	Save(ctx context.Context, key string, content io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+var Client Storage = NewLocal(storageDir())
+
+func storageDir() string {
+	if config.STORAGE_DIR != "" {
+		return config.STORAGE_DIR
+	}
+	return config.STORAGE_DIR_LOCAL
+}
+
+// DetectMime sniffs content type from first bytes of file, fallback to extension for container formats (office documents are zip)
+func DetectMime(head []byte, fileName string) string {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if sniffed != "application/zip" && sniffed != "application/octet-stream" {
+		return sniffed
+	}
+	if byExt, _, err := mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(fileName))); err == nil && byExt != "" {
+		return byExt
+	}
+	return sniffed
+}
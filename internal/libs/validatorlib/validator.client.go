@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"school-information-system/internal/models"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -38,6 +39,27 @@ func registEnumValidation[E ~string](enum []E) validator.Func {
 	}
 }
 
+// validateAcademicYear validates academic year format such as "2025/2026"
+func validateAcademicYear(fl validator.FieldLevel) bool {
+	if fl.Field().Kind() != reflect.String {
+		return false
+	}
+
+	start, end, ok := strings.Cut(fl.Field().String(), "/")
+	if !ok || len(start) != 4 || len(end) != 4 {
+		return false
+	}
+	startYear, err := strconv.Atoi(start)
+	if err != nil {
+		return false
+	}
+	endYear, err := strconv.Atoi(end)
+	if err != nil {
+		return false
+	}
+	return endYear == startYear+1
+}
+
 func init() {
 	// register tags
 	Client.RegisterTagNameFunc(registFirstTag(map[string]string{
@@ -46,6 +68,9 @@ func init() {
 		"form": ",",
 	}))
 
+	// register academic year tag
+	Client.RegisterValidation("academic_year", validateAcademicYear)
+
 	// register enum user role tag
 	Client.RegisterValidation("user_role", registEnumValidation(models.UserRoles))
 
@@ -57,4 +82,49 @@ func init() {
 
 	// register enum perm resource tag
 	Client.RegisterValidation("permission_resource", registEnumValidation(models.PermissionResources))
+
+	// register enum transfer direction tag
+	Client.RegisterValidation("transfer_direction", registEnumValidation(models.TransferDirections))
+
+	// register enum admission stage tag
+	Client.RegisterValidation("admission_stage", registEnumValidation(models.AdmissionStages))
+
+	// register enum material type tag
+	Client.RegisterValidation("material_type", registEnumValidation(models.MaterialTypes))
+
+	// register enum score source tag
+	Client.RegisterValidation("score_source", registEnumValidation(models.ScoreSources))
+
+	// register enum question type tag
+	Client.RegisterValidation("question_type", registEnumValidation(models.QuestionTypes))
+
+	// register enum question difficulty tag
+	Client.RegisterValidation("question_difficulty", registEnumValidation(models.QuestionDifficulties))
+
+	// register enum exam type tag
+	Client.RegisterValidation("exam_type", registEnumValidation(models.ExamTypes))
+
+	// register enum room type tag
+	Client.RegisterValidation("room_type", registEnumValidation(models.RoomTypes))
+
+	// register enum booking status tag
+	Client.RegisterValidation("booking_status", registEnumValidation(models.BookingStatuses))
+
+	// register enum achievement level tag
+	Client.RegisterValidation("achievement_level", registEnumValidation(models.AchievementLevels))
+
+	// register enum scholarship source tag
+	Client.RegisterValidation("scholarship_source", registEnumValidation(models.ScholarshipSources))
+
+	// register enum night roll status tag
+	Client.RegisterValidation("night_roll_status", registEnumValidation(models.NightRollStatuses))
+
+	// register enum permission audit action tag
+	Client.RegisterValidation("permission_audit_action", registEnumValidation(models.PermissionAuditActions))
+
+	// register enum change request kind tag
+	Client.RegisterValidation("change_request_kind", registEnumValidation(models.ChangeRequestKinds))
+
+	// register enum change request status tag
+	Client.RegisterValidation("change_request_status", registEnumValidation(models.ChangeRequestStatuses))
 }
@@ -12,18 +12,40 @@ import (
 type translator func(fieldName string, err validator.FieldError) string
 
 var translateErrorMap = map[string]translator{
-	"email":               email,
-	"oneof":               enum,
-	"min":                 length,
-	"max":                 length,
-	"required":            required,
-	"required_if":         requiredIf,
-	"required_without":    requiredWithout,
-	"uuid4":               uuid4,
-	"user_role":           createEnum(models.UserRoles),
-	"user_gender":         createEnum(models.UserGenders),
-	"permission_action":   createEnum(models.PermissionActions),
-	"permission_resource": createEnum(models.PermissionResources),
+	"email":                   email,
+	"oneof":                   enum,
+	"min":                     length,
+	"max":                     length,
+	"required":                required,
+	"required_if":             requiredIf,
+	"required_without":        requiredWithout,
+	"uuid4":                   uuid4,
+	"url":                     url,
+	"gt":                      greater,
+	"gtfield":                 greater,
+	"gte":                     greaterOrEqual,
+	"gtefield":                greaterOrEqual,
+	"academic_year":           academicYear,
+	"datetime":                datetime,
+	"user_role":               createEnum(models.UserRoles),
+	"user_gender":             createEnum(models.UserGenders),
+	"permission_action":       createEnum(models.PermissionActions),
+	"permission_resource":     createEnum(models.PermissionResources),
+	"transfer_direction":      createEnum(models.TransferDirections),
+	"admission_stage":         createEnum(models.AdmissionStages),
+	"material_type":           createEnum(models.MaterialTypes),
+	"score_source":            createEnum(models.ScoreSources),
+	"question_type":           createEnum(models.QuestionTypes),
+	"question_difficulty":     createEnum(models.QuestionDifficulties),
+	"exam_type":               createEnum(models.ExamTypes),
+	"room_type":               createEnum(models.RoomTypes),
+	"booking_status":          createEnum(models.BookingStatuses),
+	"achievement_level":       createEnum(models.AchievementLevels),
+	"scholarship_source":      createEnum(models.ScholarshipSources),
+	"night_roll_status":       createEnum(models.NightRollStatuses),
+	"permission_audit_action": createEnum(models.PermissionAuditActions),
+	"change_request_kind":     createEnum(models.ChangeRequestKinds),
+	"change_request_status":   createEnum(models.ChangeRequestStatuses),
 }
 
 func email(fieldName string, err validator.FieldError) string {
@@ -67,6 +89,26 @@ func uuid4(fieldName string, err validator.FieldError) string {
 	return fmt.Sprintf("%s must be an uuid4", fieldName)
 }
 
+func url(fieldName string, err validator.FieldError) string {
+	return fmt.Sprintf("%s is not a valid url", fieldName)
+}
+
+func greater(fieldName string, err validator.FieldError) string {
+	return fmt.Sprintf("%s must be greater than %s", fieldName, err.Param())
+}
+
+func greaterOrEqual(fieldName string, err validator.FieldError) string {
+	return fmt.Sprintf("%s must be greater than or equal to %s", fieldName, err.Param())
+}
+
+func datetime(fieldName string, err validator.FieldError) string {
+	return fmt.Sprintf("%s must be in format %s", fieldName, err.Param())
+}
+
+func academicYear(fieldName string, err validator.FieldError) string {
+	return fmt.Sprintf("%s must be academic year such as 2025/2026", fieldName)
+}
+
 func createEnum[E ~string](enum []E) translator {
 	return func(fieldName string, err validator.FieldError) string {
 		return fmt.Sprintf("%s is not a valid enum of %s", fieldName, enum)
@@ -3,6 +3,7 @@ package middlewares
 import (
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"school-information-system/config"
 	"school-information-system/internal/libs/authlib"
@@ -68,13 +69,46 @@ func (mw *Auth) protected(c *gin.Context) (claims authlib.Claims, newAccessCooki
 		return
 	}
 
+	// check if session is invalidated, role of the user may has changed
+	user, userErr := mw.userRepo.GetFirstWithPreload(ctx, []string{"AlumniProfile"}, "id = ?", claims.UserID)
+	if userErr != nil {
+		err = userErr
+		if errors.Is(userErr, gorm.ErrRecordNotFound) {
+			err = errorlib.ErrAccountNotFound
+		}
+		return
+	}
+	if user.SessionsRevokedAt != nil && (claims.IssuedAt == nil || claims.IssuedAt.Time.Before(user.SessionsRevokedAt.Truncate(time.Second))) {
+		err = errorlib.ErrSessionRevoked
+		return
+	}
+
+	// alumni role is no longer held once access of the graduate expires
+	held := user.HeldRoles()
+	if slices.Contains(held, models.RoleAlumni) && (user.AlumniProfile == nil || !user.AlumniProfile.HasAccess()) {
+		held = slices.DeleteFunc(slices.Clone(held), func(r models.UserRole) bool { return r == models.RoleAlumni })
+		if len(held) == 0 {
+			err = errorlib.ErrAlumniAccessExpired
+			return
+		}
+	}
+
+	// keep active role of the session if it is still held
+	claims.Roles = authlib.RoleNames(held)
+	if !claims.HasRole(claims.Role) {
+		claims.Role = string(user.Role)
+		if !claims.HasRole(claims.Role) {
+			claims.Role = claims.Roles[0]
+		}
+	}
+
 	// update access token
-	na := authlib.CreateAccessCookie(claims.UserID, claims.Role, claims.RememberMe)
+	na := authlib.CreateAccessCookie(claims.UserID, claims.Role, claims.Roles, claims.RememberMe)
 	newAccessCookie = &na
 
 	// rotate refresh token
 	if claims.RotateAt.Before(time.Now()) {
-		nr := authlib.CreateRefreshCookie(claims.UserID, claims.Role, claims.RememberMe)
+		nr := authlib.CreateRefreshCookie(claims.UserID, claims.Role, claims.Roles, claims.RememberMe)
 		newRefreshCookie = &nr
 	}
 	return
@@ -87,8 +121,23 @@ func (*Auth) applyInternalProtectedReturn(c *gin.Context, rp *reply.Reply, claim
 	if newRefreshCookie != nil {
 		rp.SetCookies(*newRefreshCookie)
 	}
+	// token signed before users could hold multiple roles only has active role
+	if len(claims.Roles) == 0 {
+		claims.Roles = []string{claims.Role}
+	}
 	c.Set("userID", claims.UserID)
 	c.Set("role", claims.Role)
+	c.Set("roles", claims.Roles)
+}
+
+// heldRoleOf returns the first role held by current session which is accepted
+func heldRoleOf(c *gin.Context, accept func(role string) bool) (string, bool) {
+	for _, role := range c.GetStringSlice("roles") {
+		if accept(role) {
+			return role, true
+		}
+	}
+	return "", false
 }
 
 // ensureAuthenticated to ensure protected middleware run, return false if not authenticated and aborted
@@ -106,7 +155,7 @@ func (mw *Auth) ensureAuthenticated(c *gin.Context, rp *reply.Reply, allowUnsett
 			return true
 		}
 
-		if errors.Is(err, errorlib.ErrNotActivated) {
+		if errors.Is(err, errorlib.ErrNotActivated) || errors.Is(err, errorlib.ErrAlumniAccessExpired) {
 			rp.Error(replylib.CodeForbidden, err.Error()).FailJSON()
 		} else {
 			rp.Error(replylib.CodeUnauthorized, err.Error()).FailJSON()
@@ -159,11 +208,16 @@ func (mw *Auth) RoleProtected(roles ...models.UserRole) gin.HandlerFunc {
 			return
 		}
 
-		// protect roles
+		// protect roles, active role is preferred but any held role may qualify
 		if !slices.Contains(strRoles, role) {
-			rp.Error(replylib.CodeForbidden, fmt.Sprintf("invalid role, only %s can access this resource", strings.Join(strRoles, ", "))).FailJSON()
-			c.Abort()
-			return
+			held, ok := heldRoleOf(c, func(r string) bool { return slices.Contains(strRoles, r) })
+			if !ok {
+				rp.Error(replylib.CodeForbidden, fmt.Sprintf("invalid role, only %s can access this resource", strings.Join(strRoles, ", "))).FailJSON()
+				c.Abort()
+				return
+			}
+			// act as the qualified role during the request
+			c.Set("role", held)
 		}
 
 		c.Next()
@@ -184,15 +238,23 @@ func WithSkipRole(roles ...models.UserRole) PermissionProtectedOptFunc {
 
 // PermissionProtected protects with permission validation
 func (mw *Auth) PermissionProtected(resource models.PermissionResource, actions []models.PermissionAction, opts ...PermissionProtectedOptFunc) gin.HandlerFunc {
+	option := new(PermissionProtectedOpt)
+	for _, opt := range opts {
+		opt(option)
+	}
+	// skipped roles must be declared, effective permissions of users are computed from the declaration
+	for _, action := range actions {
+		for _, role := range option.skipOnRole {
+			if !slices.Contains(models.RoleSkips[resource][action], role) {
+				log.Fatalf("[PERMISSION-PROTECTED] role %s skipping %s.%s is not declared in models.RoleSkips", role, resource, action)
+			}
+		}
+	}
+
 	return func(c *gin.Context) {
 		rp := replylib.Client.Use(adapter.AdaptGin(c))
 		ctx := c.Request.Context()
 
-		option := new(PermissionProtectedOpt)
-		for _, opt := range opts {
-			opt(option)
-		}
-
 		// make sure user is authenticated
 		if !mw.ensureAuthenticated(c, rp, false) {
 			return
@@ -203,53 +265,68 @@ func (mw *Auth) PermissionProtected(resource models.PermissionResource, actions
 		roleInterface, _ := c.Get("role")
 		role, _ := roleInterface.(string)
 
-		if len(option.skipOnRole) > 0 && slices.Contains(option.skipOnRole, models.UserRole(role)) {
+		// active role is preferred, request is accepted if any held role qualifies
+		skipped := func(r string) bool { return slices.Contains(option.skipOnRole, models.UserRole(r)) }
+		if skipped(role) {
 			c.Next()
 			return
 		}
+		heldSkipped, canSkip := heldRoleOf(c, skipped)
+		skip := func() {
+			c.Set("role", heldSkipped)
+			c.Next()
+		}
 
 		// validate role is admin
-		if role != string(models.RoleAdmin) {
+		if !slices.Contains(c.GetStringSlice("roles"), string(models.RoleAdmin)) {
+			if canSkip {
+				skip()
+				return
+			}
 			rp.Error(replylib.CodeForbidden, "invalid role, only admin can access this resource").FailJSON()
 			c.Abort()
 			return
 		}
 
 		// get and set user with permissions
-		user, err := mw.userRepo.GetFirstWithPreload(ctx, []string{"AdminProfile.Permissions"}, "id = ? AND role = ?", userID, models.RoleAdmin)
+		user, err := mw.userRepo.GetFirstWithPreload(ctx, []string{"AdminProfile.Grants.Permission", "AdminProfile.Bundles.Permissions"}, "id = ?", userID)
 		if err != nil {
 			errPayload := errorlib.MakeNotFound(err, "your user profile not found", nil)
 			rp.Error(errPayload.Code, errPayload.Message).FailJSON()
 			c.Abort()
 			return
 		}
-		if user.AdminProfile == nil || len(user.AdminProfile.Permissions) == 0 {
+		if user.AdminProfile == nil || len(user.AdminProfile.EffectivePermissions()) == 0 {
+			if canSkip {
+				skip()
+				return
+			}
 			rp.Error(replylib.CodeConflict, "your admin profile or permission not registered").FailJSON()
 			c.Abort()
 			return
 		}
-		c.Set("user", user)
-
-		// user's action tracks
-		existingAction := make(map[models.PermissionAction]struct{}, len(actions))
 
-		for _, perm := range user.AdminProfile.Permissions {
-			if perm.Resource == resource {
-				for _, act := range perm.Actions {
-					existingAction[act] = struct{}{}
-				}
+		// validate is permitted
+		if action, missing := user.AdminProfile.MissingAction(resource, actions...); missing {
+			if canSkip {
+				skip()
+				return
 			}
+			rp.Error(replylib.CodeForbidden, fmt.Sprintf("missing permission: %s.%s", resource, action)).FailJSON()
+			c.Abort()
+			return
 		}
 
-		// validate is permitted
+		// limit the request to scope of permissions granting the actions, checked by service
 		for _, action := range actions {
-			if _, ok := existingAction[action]; !ok {
-				rp.Error(replylib.CodeForbidden, fmt.Sprintf("missing permission: %s.%s", resource, action)).FailJSON()
-				c.Abort()
-				return
+			if scope := user.AdminProfile.ScopeOf(resource, action); scope != nil {
+				c.Set(models.ScopeContextKey(resource, action), scope)
 			}
 		}
 
+		// act as admin during the request
+		c.Set("user", user)
+		c.Set("role", string(models.RoleAdmin))
 		c.Next()
 	}
 }
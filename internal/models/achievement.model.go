@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Achievement is achievement of student in a competition
+type Achievement struct {
+	Id
+	StudentID   string           `gorm:"index;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student     *Student         `gorm:"constraint:OnDelete:CASCADE" json:"student,omitempty" swaggerignore:"true"`
+	Competition string           `gorm:"not null" json:"competition" example:"LKS SMK Bidang IT Network Systems Administration"`
+	Organizer   string           `json:"organizer" example:"Dinas Pendidikan Provinsi"`
+	Level       AchievementLevel `gorm:"type:achievement_level;index;not null" json:"level"` // "school", "district", "province", "national", "international"
+	Rank        string           `gorm:"not null" json:"rank" example:"Juara 1"`
+	Date        time.Time        `gorm:"index;not null" json:"date" example:"2006-01-02T15:04:05Z07:00"`
+	// nullable, certificate is optional
+	FileID   *string     `json:"file_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	File     *StoredFile `gorm:"constraint:OnDelete:RESTRICT" json:"file,omitempty"`
+	FileName string      `json:"file_name,omitempty" example:"sertifikat-lks.pdf"`
+	// nullable, set to null if the user is deleted
+	RecordedByID *string `json:"recorded_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	RecordedBy   *User   `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+
+	Timestamp
+}
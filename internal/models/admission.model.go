@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Admission is an application of new student (PPDB), submitted without account
+type Admission struct {
+	Id
+	// used by applicant to track the application
+	RegistrationNumber string         `gorm:"uniqueIndex;not null" json:"registration_number" example:"PPDB/2026/A1B2C3D4"`
+	Stage              AdmissionStage `gorm:"type:admission_stage;default:submitted;not null" json:"stage"` // "submitted", "verified", "tested", "accepted", "rejected"
+
+	// biodata
+	FullName   string     `gorm:"not null" json:"full_name" example:"Chesta Ardiona"`
+	Email      string     `gorm:"index;not null" json:"email" example:"chestaardi4@gmail.com"`
+	Phone      string     `gorm:"not null" json:"phone" example:"+6281234567890"`
+	Gender     UserGender `gorm:"type:user_gender;not null" json:"gender"`
+	BirthPlace string     `gorm:"not null" json:"birth_place" example:"Jakarta"`
+	BirthDate  time.Time  `gorm:"not null" json:"birth_date" example:"2006-01-02T15:04:05Z07:00"`
+	Address    string     `gorm:"not null" json:"address" example:"Jl. Merdeka No. 1, Jakarta"`
+	NISN       string     `gorm:"not null" json:"NISN" example:"0091913711"`
+	Password   string     `gorm:"not null" json:"-"` // hashed, used as password of enrolled account
+
+	PreviousSchool string              `gorm:"not null" json:"previous_school" example:"SMP Negeri 1 Jakarta"`
+	DesiredMajor   string              `gorm:"not null" json:"desired_major" example:"TJKT"`
+	Parents        []AdmissionParent   `gorm:"type:text;serializer:json;not null" json:"parents"`
+	Documents      []AdmissionDocument `gorm:"type:text;serializer:json;not null" json:"documents"`
+
+	// review
+	TestScore *float64            `json:"test_score" example:"87.5"`
+	Note      string              `json:"note" example:"documents verified"` // latest note of staff
+	History   []AdmissionStageLog `gorm:"type:text;serializer:json;not null" json:"history"`
+
+	// user of enrolled applicant, set to null when the user is purged
+	UserID     *string    `gorm:"index" json:"user_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	User       *User      `gorm:"constraint:OnDelete:SET NULL" json:"user,omitempty" swaggerignore:"true"`
+	EnrolledAt *time.Time `json:"enrolled_at" example:"2006-01-02T15:04:05Z07:00"`
+
+	Timestamp
+}
+
+type AdmissionParent struct {
+	FullName string     `json:"full_name" validate:"required" example:"Chesta Ardiona"`
+	Phone    string     `json:"phone" validate:"required" example:"+6281234567890"`
+	Email    string     `json:"email" validate:"required,email" example:"chestaardi4@gmail.com"`
+	Gender   UserGender `json:"gender" validate:"required,user_gender"`
+}
+
+type AdmissionDocument struct {
+	Name string `json:"name" validate:"required" example:"birth certificate"`
+	URL  string `json:"url" validate:"required,url" example:"https://drive.example.com/birth-certificate.pdf"`
+}
+
+type AdmissionStageLog struct {
+	Stage       AdmissionStage `json:"stage"`
+	Note        string         `json:"note,omitempty" example:"documents verified"`
+	ChangedByID string         `json:"changed_by_id,omitempty" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // empty if changed by applicant
+	ChangedAt   time.Time      `json:"changed_at" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+// next stages of review, accepted and rejected are final
+var admissionStageFlow = map[AdmissionStage][]AdmissionStage{
+	StageSubmitted: {StageVerified, StageRejected},
+	StageVerified:  {StageTested, StageRejected},
+	StageTested:    {StageAccepted, StageRejected},
+}
+
+func (a *Admission) NextStages() []AdmissionStage {
+	return admissionStageFlow[a.Stage]
+}
+
+func (a *Admission) CanMoveTo(stage AdmissionStage) bool {
+	for _, next := range admissionStageFlow[a.Stage] {
+		if next == stage {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Alumni is read-only record of graduated student
+type Alumni struct {
+	Id
+	NISN           string `gorm:"not null" json:"NISN" example:"0091913711"`
+	GraduationYear int    `gorm:"index;not null" json:"graduation_year" example:"2026"`
+	// nullable, set to null if the class is deleted
+	FinalClassID   *string `json:"final_class_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	FinalClass     *Class  `gorm:"constraint:OnDelete:SET NULL" json:"final_class,omitempty" swaggerignore:"true"`
+	FinalClassName string  `gorm:"not null" json:"final_class_name" example:"12 TJKT 3"`
+	Major          string  `gorm:"index;not null" json:"major" example:"TJKT"`
+	// graduate can sign in until this date
+	AccessUntil time.Time `gorm:"not null" json:"access_until" example:"2006-01-02T15:04:05Z07:00"`
+
+	UserID string `gorm:"uniqueIndex;not null" json:"-"`
+
+	Timestamp
+}
+
+func (a *Alumni) HasAccess() bool {
+	return time.Now().Before(a.AccessUntil)
+}
+
+func (Alumni) TableName() string {
+	return "alumni"
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+type Assignment struct {
+	Id
+	Title        string    `gorm:"not null" json:"title" example:"Subnetting exercise"`
+	Instructions string    `gorm:"not null" json:"instructions" example:"answer all questions in the attachment"`
+	DueAt        time.Time `gorm:"index;not null" json:"due_at" example:"2006-01-02T15:04:05Z07:00"`
+	AcceptLate   bool      `gorm:"not null" json:"accept_late" example:"true"` // accept submission after due date, flagged as late
+
+	ClassID   string   `gorm:"index;not null" json:"class_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Class     *Class   `gorm:"constraint:OnDelete:CASCADE" json:"class,omitempty" swaggerignore:"true"`
+	SubjectID string   `gorm:"index;not null" json:"subject_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Subject   *Subject `gorm:"constraint:OnDelete:CASCADE" json:"subject,omitempty" swaggerignore:"true"`
+	// nullable, set to null if the teacher is deleted
+	TeacherID *string  `json:"teacher_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Teacher   *Teacher `gorm:"constraint:OnDelete:SET NULL" json:"teacher,omitempty" swaggerignore:"true"`
+
+	// empty if no attachment
+	FileID   *string     `json:"file_id,omitempty" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	File     *StoredFile `gorm:"constraint:OnDelete:RESTRICT" json:"file,omitempty"`
+	FileName string      `json:"file_name,omitempty" example:"exercise.pdf"`
+
+	Timestamp
+}
+
+func (a *Assignment) IsOverdue(at time.Time) bool {
+	return at.After(a.DueAt)
+}
+
+// Submission is answer of student to assignment, can be resubmitted until graded
+type Submission struct {
+	Id
+	AssignmentID string      `gorm:"uniqueIndex:idx_submission_student;not null" json:"assignment_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Assignment   *Assignment `gorm:"constraint:OnDelete:CASCADE" json:"assignment,omitempty" swaggerignore:"true"`
+	StudentID    string      `gorm:"uniqueIndex:idx_submission_student;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student      *Student    `gorm:"constraint:OnDelete:CASCADE" json:"student,omitempty" swaggerignore:"true"`
+
+	Text string `json:"text" example:"my answer"`
+	// empty if no file submitted
+	FileID      *string     `json:"file_id,omitempty" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	File        *StoredFile `gorm:"constraint:OnDelete:RESTRICT" json:"file,omitempty"`
+	FileName    string      `json:"file_name,omitempty" example:"answer.pdf"`
+	SubmittedAt time.Time   `gorm:"not null" json:"submitted_at" example:"2006-01-02T15:04:05Z07:00"`
+	IsLate      bool        `gorm:"not null" json:"is_late" example:"false"`
+
+	// empty until graded
+	Grade    *float64   `json:"grade" example:"87.5"`
+	Comment  string     `json:"comment" example:"good work"`
+	GradedAt *time.Time `gorm:"index" json:"graded_at" example:"2006-01-02T15:04:05Z07:00"`
+
+	Timestamp
+}
+
+func (s *Submission) IsGraded() bool {
+	return s.GradedAt != nil
+}
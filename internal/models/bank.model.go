@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// BankQuestion is shared question of subject, content is versioned so edits never change copied questions
+type BankQuestion struct {
+	Id
+	SubjectID  string             `gorm:"index;not null" json:"subject_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Subject    *Subject           `gorm:"constraint:OnDelete:CASCADE" json:"subject,omitempty" swaggerignore:"true"`
+	Grade      int                `gorm:"index;not null" json:"grade" example:"10"`
+	Topic      string             `gorm:"index;not null" json:"topic" example:"subnetting"`
+	Competency string             `json:"competency" example:"3.2 menerapkan subnetting"`
+	Difficulty QuestionDifficulty `gorm:"type:question_difficulty;not null" json:"difficulty"` // "easy", "medium", "hard"
+	// nullable, set to null if the teacher is deleted
+	AuthorID *string  `json:"author_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Author   *Teacher `gorm:"constraint:OnDelete:SET NULL" json:"author,omitempty" swaggerignore:"true"`
+
+	CurrentVersion int                   `gorm:"not null" json:"current_version" example:"2"`
+	Versions       []BankQuestionVersion `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE" json:"versions,omitempty"`
+
+	Timestamp
+}
+
+// BankQuestionVersion is immutable snapshot of bank question content
+type BankQuestionVersion struct {
+	Id
+	QuestionID string `gorm:"uniqueIndex:idx_bank_question_version;not null" json:"question_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Version    int    `gorm:"uniqueIndex:idx_bank_question_version;not null" json:"version" example:"2"`
+	QuestionContent
+	// nullable, set to null if the teacher is deleted
+	EditedByID *string  `json:"edited_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	EditedBy   *Teacher `gorm:"constraint:OnDelete:SET NULL" json:"edited_by,omitempty" swaggerignore:"true"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime;not null" json:"created_at,omitzero" example:"2006-01-02T15:04:05Z07:00"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -15,10 +16,11 @@ type Timestamp struct {
 	UpdatedAt time.Time `gorm:"autoUpdateTime;not null" json:"updated_at,omitzero" example:"2006-01-02T15:04:05Z07:00"`
 }
 
-type TimestampJoinTime struct {
-	JoinedAt  time.Time `gorm:"not null" json:"joined_at,omitzero" example:"2006-01-02T15:04:05Z07:00"`
-	CreatedAt time.Time `gorm:"autoCreateTime;not null" json:"created_at,omitzero" example:"2006-01-02T15:04:05Z07:00"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime;not null" json:"updated_at,omitzero" example:"2006-01-02T15:04:05Z07:00"`
+type TimestampJoinTimeArchivable struct {
+	JoinedAt  time.Time      `gorm:"not null" json:"joined_at,omitzero" example:"2006-01-02T15:04:05Z07:00"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"archived_at,omitzero" swaggertype:"string" example:"2006-01-02T15:04:05Z07:00"`
+	CreatedAt time.Time      `gorm:"autoCreateTime;not null" json:"created_at,omitzero" example:"2006-01-02T15:04:05Z07:00"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime;not null" json:"updated_at,omitzero" example:"2006-01-02T15:04:05Z07:00"`
 }
 
 type TimestampArchivable struct {
@@ -26,3 +28,41 @@ type TimestampArchivable struct {
 	CreatedAt time.Time      `gorm:"autoCreateTime;not null" json:"created_at,omitzero" example:"2006-01-02T15:04:05Z07:00"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime;not null" json:"updated_at,omitzero" example:"2006-01-02T15:04:05Z07:00"`
 }
+
+// AcademicYearOf returns academic year of the time, academic year starts in July
+func AcademicYearOf(t time.Time) string {
+	start := AcademicYearStart(t)
+	return fmt.Sprintf("%d/%d", start, start+1)
+}
+
+// AcademicYearStart returns the year which academic year of the time starts
+func AcademicYearStart(t time.Time) int {
+	if t.Month() >= time.July {
+		return t.Year()
+	}
+	return t.Year() - 1
+}
+
+// SemesterOf returns semester of the time, first semester starts in July and second semester starts in January
+func SemesterOf(t time.Time) int {
+	if t.Month() >= time.July {
+		return 1
+	}
+	return 2
+}
+
+// TermPeriod returns start and end of the semester of the academic year in the location
+func TermPeriod(academicYear string, semester int, loc *time.Location) (startAt, endAt time.Time) {
+	var start int
+	fmt.Sscanf(academicYear, "%d/", &start)
+	if semester == 1 {
+		return time.Date(start, time.July, 1, 0, 0, 0, 0, loc), time.Date(start+1, time.January, 1, 0, 0, 0, 0, loc)
+	}
+	return time.Date(start+1, time.January, 1, 0, 0, 0, 0, loc), time.Date(start+1, time.July, 1, 0, 0, 0, 0, loc)
+}
+
+// DateOf returns date part of the time at midnight UTC, same as value of date column
+func DateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
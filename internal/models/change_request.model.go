@@ -0,0 +1,33 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ChangeRequest is sensitive change of permissions or admin role waiting for approval of another admin,
+// the change is applied as requested once approved
+type ChangeRequest struct {
+	Id
+	Kind   ChangeRequestKind   `gorm:"type:change_request_kind;not null" json:"kind"`
+	Status ChangeRequestStatus `gorm:"type:change_request_status;default:pending;index;not null" json:"status"`
+	// payload of the change request, same as payload of the endpoint making the change
+	Payload json.RawMessage `gorm:"type:text;serializer:json;not null" json:"payload" swaggertype:"object"`
+	// user ID of targeted user, ID of targeted bundle for update and deletion of bundle
+	TargetID string `gorm:"index;not null" json:"target_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+
+	// permission needed to make the change, reviewer must hold it too
+	Resource PermissionResource `gorm:"type:permission_resource;not null" json:"resource"`
+	Actions  []PermissionAction `gorm:"type:text;serializer:json;not null" json:"actions"`
+
+	RequesterID string  `gorm:"index;not null" json:"requester_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Requester   *User   `gorm:"constraint:OnDelete:CASCADE" json:"requester,omitempty" swaggerignore:"true"`
+	ReviewerID  *string `json:"reviewer_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Reviewer    *User   `gorm:"constraint:OnDelete:SET NULL" json:"reviewer,omitempty" swaggerignore:"true"`
+
+	ReviewNote string     `json:"review_note" example:"confirmed by phone"`
+	ReviewedAt *time.Time `json:"reviewed_at" example:"2006-01-02T15:04:05Z07:00"`
+	ExpiresAt  time.Time  `gorm:"index;not null" json:"expires_at" example:"2006-01-02T15:04:05Z07:00"`
+
+	Timestamp
+}
@@ -10,6 +10,9 @@ type Class struct {
 	Name          string   `gorm:"-" json:"name" example:"10 TJKT 3"`
 	FormTeacherID *string  `json:"form_teacher_id" gorm:"unique" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
 	FormTeacher   *Teacher `json:"form_teacher,omitempty" swaggerignore:"true"`
+	// nullable, set to null if the room is deleted
+	HomeRoomID *string `gorm:"unique" json:"home_room_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	HomeRoom   *Room   `gorm:"constraint:OnDelete:SET NULL" json:"home_room,omitempty" swaggerignore:"true"`
 
 	Timestamp
 }
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Dormitory is a boarding building of school
+type Dormitory struct {
+	Id
+	Name        string     `gorm:"unique;not null" json:"name" example:"Asrama Putra Al-Fatih"`
+	Address     string     `json:"address" example:"Jl. Pesantren No. 3"`
+	Supervisors []*Teacher `gorm:"many2many:dormitory_supervisors;constraint:OnDelete:CASCADE" json:"supervisors,omitempty"`
+	Rooms       []DormRoom `gorm:"foreignKey:DormitoryID;constraint:OnDelete:CASCADE" json:"rooms,omitempty"`
+
+	Timestamp
+}
+
+// DormRoom is a bedroom of dormitory, only students of the gender can be assigned
+type DormRoom struct {
+	Id
+	DormitoryID string     `gorm:"uniqueIndex:idx_dorm_room_name;not null" json:"dormitory_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Dormitory   *Dormitory `json:"dormitory,omitempty" swaggerignore:"true"`
+	Name        string     `gorm:"uniqueIndex:idx_dorm_room_name;not null" json:"name" example:"Kamar 101"`
+	Capacity    int        `gorm:"not null" json:"capacity" example:"6"`
+	Gender      UserGender `gorm:"type:user_gender;not null" json:"gender"` // "male", "female"
+
+	Timestamp
+}
+
+// DormAssignment is a student staying in a dorm room in a term, a student stays in one room per term
+type DormAssignment struct {
+	Id
+	RoomID       string    `gorm:"index;not null" json:"room_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Room         *DormRoom `gorm:"constraint:OnDelete:RESTRICT" json:"room,omitempty"`
+	StudentID    string    `gorm:"uniqueIndex:idx_dorm_assignment_term;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student      *Student  `gorm:"constraint:OnDelete:CASCADE" json:"student,omitempty" swaggerignore:"true"`
+	AcademicYear string    `gorm:"uniqueIndex:idx_dorm_assignment_term;not null" json:"academic_year" example:"2025/2026"`
+	Semester     int       `gorm:"uniqueIndex:idx_dorm_assignment_term;not null" json:"semester" example:"1"`
+	// nullable, set to null if the user is deleted
+	AssignedByID *string `json:"assigned_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	AssignedBy   *User   `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+
+	Timestamp
+}
+
+// NightRoll is night attendance of a boarding student, separated from class attendance
+type NightRoll struct {
+	Id
+	DormitoryID string          `gorm:"index;not null" json:"dormitory_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Dormitory   *Dormitory      `gorm:"constraint:OnDelete:CASCADE" json:"dormitory,omitempty" swaggerignore:"true"`
+	StudentID   string          `gorm:"uniqueIndex:idx_night_roll;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student     *Student        `gorm:"constraint:OnDelete:CASCADE" json:"student,omitempty" swaggerignore:"true"`
+	Date        time.Time       `gorm:"type:date;uniqueIndex:idx_night_roll;not null" json:"date" example:"2006-01-02T15:04:05Z07:00"`
+	Status      NightRollStatus `gorm:"type:night_roll_status;not null" json:"status"` // "present", "permitted", "sick", "absent"
+	Note        string          `json:"note" example:"went home with parent permission"`
+	// nullable, set to null if the user is deleted
+	RecordedByID *string `json:"recorded_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	RecordedBy   *User   `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+
+	Timestamp
+}
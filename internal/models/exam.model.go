@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// ExamPeriod is midterm or final exam week
+type ExamPeriod struct {
+	Id
+	Name      string        `gorm:"not null" json:"name" example:"PTS Ganjil 2025/2026"`
+	Type      ExamType      `gorm:"type:exam_type;not null" json:"type"` // "midterm", "final"
+	StartDate time.Time     `gorm:"not null" json:"start_date" example:"2006-01-02T15:04:05Z07:00"`
+	EndDate   time.Time     `gorm:"not null" json:"end_date" example:"2006-01-02T15:04:05Z07:00"`
+	Sessions  []ExamSession `gorm:"foreignKey:PeriodID;constraint:OnDelete:CASCADE" json:"sessions,omitempty"`
+
+	Timestamp
+}
+
+// ExamRoom is room used for exam, capacity is the number of seats
+type ExamRoom struct {
+	Id
+	Name     string `gorm:"uniqueIndex;not null" json:"name" example:"R. 201"`
+	Capacity int    `gorm:"not null" json:"capacity" example:"36"`
+	// nullable, facility used as this exam room, set to null if the room is deleted
+	RoomID *string `gorm:"uniqueIndex" json:"room_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Room   *Room   `gorm:"constraint:OnDelete:SET NULL" json:"room,omitempty" swaggerignore:"true"`
+
+	Timestamp
+}
+
+// ExamSession is exam of a subject for all classes of a grade at a time
+type ExamSession struct {
+	Id
+	PeriodID  string      `gorm:"uniqueIndex:idx_exam_session_subject;not null" json:"period_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Period    *ExamPeriod `json:"period,omitempty" swaggerignore:"true"`
+	SubjectID string      `gorm:"uniqueIndex:idx_exam_session_subject;not null" json:"subject_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Subject   *Subject    `gorm:"constraint:OnDelete:CASCADE" json:"subject,omitempty" swaggerignore:"true"`
+	Grade     int         `gorm:"uniqueIndex:idx_exam_session_subject;not null" json:"grade" example:"10"`
+	StartAt   time.Time   `gorm:"index;not null" json:"start_at" example:"2006-01-02T15:04:05Z07:00"`
+	EndAt     time.Time   `gorm:"index;not null" json:"end_at" example:"2006-01-02T15:04:05Z07:00"`
+
+	Seats        []ExamSeat        `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE" json:"seats,omitempty"`
+	Invigilators []ExamInvigilator `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE" json:"invigilators,omitempty"`
+
+	Timestamp
+}
+
+// ExamSeat is seat of student in a session, seat number is unique per room across overlapping sessions
+type ExamSeat struct {
+	Id
+	SessionID  string    `gorm:"uniqueIndex:idx_exam_seat_student;not null" json:"session_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	StudentID  string    `gorm:"uniqueIndex:idx_exam_seat_student;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student    *Student  `gorm:"constraint:OnDelete:CASCADE" json:"student,omitempty" swaggerignore:"true"`
+	RoomID     string    `gorm:"index;not null" json:"room_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Room       *ExamRoom `gorm:"constraint:OnDelete:RESTRICT" json:"room,omitempty" swaggerignore:"true"`
+	SeatNumber int       `gorm:"not null" json:"seat_number" example:"12"`
+}
+
+// ExamInvigilator is teacher who supervises a room in a session
+type ExamInvigilator struct {
+	Id
+	SessionID string    `gorm:"uniqueIndex:idx_exam_invigilator_teacher;not null" json:"session_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	TeacherID string    `gorm:"uniqueIndex:idx_exam_invigilator_teacher;not null" json:"teacher_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Teacher   *Teacher  `gorm:"constraint:OnDelete:CASCADE" json:"teacher,omitempty" swaggerignore:"true"`
+	RoomID    string    `gorm:"index;not null" json:"room_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Room      *ExamRoom `gorm:"constraint:OnDelete:RESTRICT" json:"room,omitempty" swaggerignore:"true"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+type Immunization struct {
+	Name string    `json:"name" validate:"required" example:"campak rubella"`
+	Date time.Time `json:"date" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+// StudentHealth is health record of student, never preloaded by student reads because of restricted access
+type StudentHealth struct {
+	Id
+	StudentID         string         `gorm:"uniqueIndex;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student           *Student       `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	BloodType         string         `json:"blood_type" example:"O+"`
+	Allergies         []string       `gorm:"type:text;serializer:json;not null" json:"allergies" example:"kacang"`
+	ChronicConditions []string       `gorm:"type:text;serializer:json;not null" json:"chronic_conditions" example:"asma"`
+	Immunizations     []Immunization `gorm:"type:text;serializer:json;not null" json:"immunizations"`
+	Notes             string         `json:"notes" example:"membawa inhaler"`
+	// nullable, set to null if the user is deleted
+	UpdatedByID *string `json:"updated_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	UpdatedBy   *User   `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+
+	Timestamp
+}
+
+// ClinicVisit is visit of student to school clinic (UKS)
+type ClinicVisit struct {
+	Id
+	StudentID   string    `gorm:"index;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student     *Student  `gorm:"constraint:OnDelete:CASCADE" json:"student,omitempty" swaggerignore:"true"`
+	VisitedAt   time.Time `gorm:"index;not null" json:"visited_at" example:"2006-01-02T15:04:05Z07:00"`
+	Complaint   string    `gorm:"not null" json:"complaint" example:"pusing dan mual"`
+	ActionTaken string    `gorm:"not null" json:"action_taken" example:"istirahat 1 jam, diberi teh hangat"`
+
+	ParentNotified   bool       `gorm:"not null" json:"parent_notified" example:"true"`
+	ParentNotifiedAt *time.Time `json:"parent_notified_at" example:"2006-01-02T15:04:05Z07:00"`
+	// nullable, set to null if the user is deleted
+	HandledByID *string `json:"handled_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	HandledBy   *User   `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+
+	Timestamp
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// IndustryPartner is a company which accepts internship (PKL) students
+type IndustryPartner struct {
+	Id
+	Name         string `gorm:"unique;not null" json:"name" example:"PT Telkom Indonesia"`
+	Field        string `json:"field" example:"network infrastructure"`
+	Address      string `gorm:"not null" json:"address" example:"Jl. Japati No. 1, Bandung"`
+	ContactName  string `json:"contact_name" example:"Budi Santoso"`
+	ContactPhone string `json:"contact_phone" example:"+6281234567890"`
+	ContactEmail string `json:"contact_email" example:"hrd@telkom.co.id"`
+	// majors accepted by partner, empty means every major is accepted
+	Majors []string `gorm:"type:text;serializer:json" json:"majors" example:"TJKT,RPL"`
+	Active bool     `gorm:"not null;default:true" json:"active" example:"true"`
+
+	Timestamp
+}
+
+// AcceptsMajor checks if partner accepts students of the major
+func (p *IndustryPartner) AcceptsMajor(major string) bool {
+	return len(p.Majors) == 0 || slices.Contains(p.Majors, major)
+}
+
+// InternshipPlacement is internship of a student in an industry partner
+type InternshipPlacement struct {
+	Id
+	StudentID string           `gorm:"index;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student   *Student         `gorm:"constraint:OnDelete:CASCADE" json:"student,omitempty" swaggerignore:"true"`
+	PartnerID string           `gorm:"index;not null" json:"partner_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Partner   *IndustryPartner `gorm:"constraint:OnDelete:RESTRICT" json:"partner,omitempty"`
+	// nullable, school supervisor of the student, set to null if the teacher is deleted
+	SupervisorID *string  `gorm:"index" json:"supervisor_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Supervisor   *Teacher `gorm:"constraint:OnDelete:SET NULL" json:"supervisor,omitempty" swaggerignore:"true"`
+
+	// industry mentor, has no account in the system
+	MentorName  string `gorm:"not null" json:"mentor_name" example:"Andi Wijaya"`
+	MentorPhone string `json:"mentor_phone" example:"+6281234567890"`
+	MentorEmail string `json:"mentor_email" example:"andi@telkom.co.id"`
+
+	StartDate time.Time `gorm:"type:date;not null" json:"start_date" example:"2006-01-02T15:04:05Z07:00"`
+	EndDate   time.Time `gorm:"type:date;not null" json:"end_date" example:"2006-01-02T15:04:05Z07:00"`
+
+	// sha256 of evaluation link token given to mentor, cleared after evaluation is submitted
+	EvaluationTokenHash      *string    `gorm:"uniqueIndex" json:"-"`
+	EvaluationTokenExpiresAt *time.Time `json:"-"`
+	// filled by mentor through evaluation link
+	Evaluation  *InternshipEvaluation `gorm:"type:text;serializer:json" json:"evaluation,omitempty"`
+	EvaluatedAt *time.Time            `json:"evaluated_at" example:"2006-01-02T15:04:05Z07:00"`
+
+	Journals []InternshipJournal `gorm:"foreignKey:PlacementID;constraint:OnDelete:CASCADE" json:"journals,omitempty"`
+	Visits   []MonitoringVisit   `gorm:"foreignKey:PlacementID;constraint:OnDelete:CASCADE" json:"visits,omitempty"`
+
+	Timestamp
+}
+
+type InternshipEvaluation struct {
+	Aspects    []InternshipAspectScore `json:"aspects"`
+	FinalScore float64                 `json:"final_score" example:"86.5"` // average of aspect scores
+	Notes      string                  `json:"notes" example:"disciplined and quick to learn"`
+}
+
+type InternshipAspectScore struct {
+	Aspect string  `json:"aspect" validate:"required" example:"discipline"`
+	Score  float64 `json:"score" validate:"min=0,max=100" example:"90"`
+}
+
+// InternshipJournal is daily activity journal of intern student
+type InternshipJournal struct {
+	Id
+	PlacementID string    `gorm:"uniqueIndex:idx_internship_journal_date;not null" json:"placement_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Date        time.Time `gorm:"type:date;uniqueIndex:idx_internship_journal_date;not null" json:"date" example:"2006-01-02T15:04:05Z07:00"`
+	Activity    string    `gorm:"type:text;not null" json:"activity" example:"configured VLAN on core switch with mentor"`
+	// filled by school supervisor
+	Feedback   string     `json:"feedback" example:"add topology diagram next time"`
+	ReviewedAt *time.Time `json:"reviewed_at" example:"2006-01-02T15:04:05Z07:00"`
+
+	Timestamp
+}
+
+// MonitoringVisit is visit of teacher to the industry partner to monitor intern student
+type MonitoringVisit struct {
+	Id
+	PlacementID string    `gorm:"index;not null" json:"placement_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	VisitedAt   time.Time `gorm:"not null" json:"visited_at" example:"2006-01-02T15:04:05Z07:00"`
+	Findings    string    `gorm:"type:text;not null" json:"findings" example:"student adapts well, mentor asks for more network security material"`
+	FollowUp    string    `json:"follow_up" example:"add security module before next visit"`
+	// nullable, set to null if the teacher is deleted
+	TeacherID *string  `json:"teacher_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Teacher   *Teacher `gorm:"constraint:OnDelete:SET NULL" json:"teacher,omitempty" swaggerignore:"true"`
+
+	Timestamp
+}
@@ -0,0 +1,35 @@
+package models
+
+// StoredFile is uploaded file content, deduplicated by checksum
+type StoredFile struct {
+	Id
+	Checksum string `gorm:"uniqueIndex;not null" json:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // sha256 hex, used as storage key
+	Size     int64  `gorm:"not null" json:"size" example:"204800"`                                                                           // in bytes
+	MimeType string `gorm:"not null" json:"mime_type" example:"application/pdf"`
+
+	Timestamp
+}
+
+type LearningMaterial struct {
+	Id
+	Title       string       `gorm:"not null" json:"title" example:"Subnetting basics"`
+	Description string       `json:"description" example:"read before next meeting"`
+	Type        MaterialType `gorm:"type:material_type;not null" json:"type"` // "file", "link"
+
+	ClassID   string   `gorm:"index;not null" json:"class_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Class     *Class   `gorm:"constraint:OnDelete:CASCADE" json:"class,omitempty" swaggerignore:"true"`
+	SubjectID string   `gorm:"index;not null" json:"subject_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Subject   *Subject `gorm:"constraint:OnDelete:CASCADE" json:"subject,omitempty" swaggerignore:"true"`
+	// nullable, set to null if the teacher is deleted
+	TeacherID *string  `json:"teacher_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Teacher   *Teacher `gorm:"constraint:OnDelete:SET NULL" json:"teacher,omitempty" swaggerignore:"true"`
+
+	// empty if type is link
+	FileID   *string     `json:"file_id,omitempty" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	File     *StoredFile `gorm:"constraint:OnDelete:RESTRICT" json:"file,omitempty"`
+	FileName string      `json:"file_name,omitempty" example:"subnetting.pdf"` // original name of uploaded file
+	// empty if type is file
+	URL string `json:"url,omitempty" example:"https://youtu.be/dQw4w9WgXcQ"`
+
+	Timestamp
+}
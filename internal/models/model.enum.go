@@ -1,11 +1,12 @@
 package models
 
-type UserRole string // "student", "teacher", "admin", "unsetted"
+type UserRole string // "student", "teacher", "admin", "unsetted", "alumni"
 const (
 	RoleStudent  UserRole = "student"
 	RoleTeacher  UserRole = "teacher"
 	RoleAdmin    UserRole = "admin"
 	RoleUnsetted UserRole = "unsetted"
+	RoleAlumni   UserRole = "alumni"
 )
 
 var UserRoles = []UserRole{
@@ -13,6 +14,7 @@ var UserRoles = []UserRole{
 	RoleTeacher,
 	RoleAdmin,
 	RoleUnsetted,
+	RoleAlumni,
 }
 
 type UserGender string // "male", "female"
@@ -38,7 +40,7 @@ var PermissionActions = []PermissionAction{
 	ActionDelete,
 }
 
-type PermissionResource string // "role", "permission", "admin", "teacher", "student", "parent", "subject", "class"
+type PermissionResource string // "role", "permission", "admin", "teacher", "student", "parent", "subject", "class", "alumni", "admission", "material", "assignment", "quiz", "question_bank", "exam", "room", "health", "achievement", "scholarship", "dormitory", "internship", "timetable"
 const (
 	ResourceRole       PermissionResource = "role"
 	ResourcePermission PermissionResource = "permission"
@@ -50,6 +52,21 @@ const (
 
 	ResourceSubject PermissionResource = "subject"
 	ResourceClass   PermissionResource = "class"
+
+	ResourceAlumni       PermissionResource = "alumni"
+	ResourceAdmission    PermissionResource = "admission"
+	ResourceMaterial     PermissionResource = "material"
+	ResourceAssignment   PermissionResource = "assignment"
+	ResourceQuiz         PermissionResource = "quiz"
+	ResourceQuestionBank PermissionResource = "question_bank"
+	ResourceExam         PermissionResource = "exam"
+	ResourceRoom         PermissionResource = "room"
+	ResourceHealth       PermissionResource = "health"
+	ResourceAchievement  PermissionResource = "achievement"
+	ResourceScholarship  PermissionResource = "scholarship"
+	ResourceDormitory    PermissionResource = "dormitory"
+	ResourceInternship   PermissionResource = "internship"
+	ResourceTimetable    PermissionResource = "timetable"
 )
 
 var PermissionResources = []PermissionResource{
@@ -62,4 +79,198 @@ var PermissionResources = []PermissionResource{
 
 	ResourceSubject,
 	ResourceClass,
+
+	ResourceAlumni,
+	ResourceAdmission,
+	ResourceMaterial,
+	ResourceAssignment,
+	ResourceQuiz,
+	ResourceQuestionBank,
+	ResourceExam,
+	ResourceRoom,
+	ResourceHealth,
+	ResourceAchievement,
+	ResourceScholarship,
+	ResourceDormitory,
+	ResourceInternship,
+	ResourceTimetable,
+}
+
+type TransferDirection string // "in", "out"
+const (
+	TransferIn  TransferDirection = "in"
+	TransferOut TransferDirection = "out"
+)
+
+var TransferDirections = []TransferDirection{TransferIn, TransferOut}
+
+type AdmissionStage string // "submitted", "verified", "tested", "accepted", "rejected"
+const (
+	StageSubmitted AdmissionStage = "submitted"
+	StageVerified  AdmissionStage = "verified"
+	StageTested    AdmissionStage = "tested"
+	StageAccepted  AdmissionStage = "accepted"
+	StageRejected  AdmissionStage = "rejected"
+)
+
+var AdmissionStages = []AdmissionStage{
+	StageSubmitted,
+	StageVerified,
+	StageTested,
+	StageAccepted,
+	StageRejected,
+}
+
+type MaterialType string // "file", "link"
+const (
+	MaterialFile MaterialType = "file"
+	MaterialLink MaterialType = "link"
+)
+
+var MaterialTypes = []MaterialType{MaterialFile, MaterialLink}
+
+type ScoreSource string // "assignment", "quiz"
+const (
+	ScoreAssignment ScoreSource = "assignment"
+	ScoreQuiz       ScoreSource = "quiz"
+)
+
+var ScoreSources = []ScoreSource{ScoreAssignment, ScoreQuiz}
+
+type QuestionType string // "multiple_choice", "multiple_answer", "short_answer", "essay"
+const (
+	QuestionMultipleChoice QuestionType = "multiple_choice"
+	QuestionMultipleAnswer QuestionType = "multiple_answer"
+	QuestionShortAnswer    QuestionType = "short_answer"
+	QuestionEssay          QuestionType = "essay"
+)
+
+var QuestionTypes = []QuestionType{
+	QuestionMultipleChoice,
+	QuestionMultipleAnswer,
+	QuestionShortAnswer,
+	QuestionEssay,
 }
+
+type QuestionDifficulty string // "easy", "medium", "hard"
+const (
+	DifficultyEasy   QuestionDifficulty = "easy"
+	DifficultyMedium QuestionDifficulty = "medium"
+	DifficultyHard   QuestionDifficulty = "hard"
+)
+
+var QuestionDifficulties = []QuestionDifficulty{DifficultyEasy, DifficultyMedium, DifficultyHard}
+
+type ExamType string // "midterm", "final"
+const (
+	ExamMidterm ExamType = "midterm"
+	ExamFinal   ExamType = "final"
+)
+
+var ExamTypes = []ExamType{ExamMidterm, ExamFinal}
+
+type RoomType string // "classroom", "lab", "hall"
+const (
+	RoomClassroom RoomType = "classroom"
+	RoomLab       RoomType = "lab"
+	RoomHall      RoomType = "hall"
+)
+
+var RoomTypes = []RoomType{RoomClassroom, RoomLab, RoomHall}
+
+type BookingStatus string // "pending", "approved", "rejected", "cancelled"
+const (
+	BookingPending   BookingStatus = "pending"
+	BookingApproved  BookingStatus = "approved"
+	BookingRejected  BookingStatus = "rejected"
+	BookingCancelled BookingStatus = "cancelled"
+)
+
+var BookingStatuses = []BookingStatus{BookingPending, BookingApproved, BookingRejected, BookingCancelled}
+
+type AchievementLevel string // "school", "district", "province", "national", "international"
+const (
+	LevelSchool        AchievementLevel = "school"
+	LevelDistrict      AchievementLevel = "district"
+	LevelProvince      AchievementLevel = "province"
+	LevelNational      AchievementLevel = "national"
+	LevelInternational AchievementLevel = "international"
+)
+
+var AchievementLevels = []AchievementLevel{
+	LevelSchool,
+	LevelDistrict,
+	LevelProvince,
+	LevelNational,
+	LevelInternational,
+}
+
+type ScholarshipSource string // "internal", "government", "private"
+const (
+	ScholarshipInternal   ScholarshipSource = "internal"
+	ScholarshipGovernment ScholarshipSource = "government"
+	ScholarshipPrivate    ScholarshipSource = "private"
+)
+
+var ScholarshipSources = []ScholarshipSource{ScholarshipInternal, ScholarshipGovernment, ScholarshipPrivate}
+
+type NightRollStatus string // "present", "permitted", "sick", "absent"
+const (
+	NightPresent   NightRollStatus = "present"
+	NightPermitted NightRollStatus = "permitted"
+	NightSick      NightRollStatus = "sick"
+	NightAbsent    NightRollStatus = "absent"
+)
+
+var NightRollStatuses = []NightRollStatus{NightPresent, NightPermitted, NightSick, NightAbsent}
+
+type PermissionAuditAction string // "grant_permission", "revoke_permission", "grant_bundle", "revoke_bundle", "bundle_add", "bundle_remove", "bundle_delete", "expire_permission"
+const (
+	AuditGrantPermission  PermissionAuditAction = "grant_permission"
+	AuditRevokePermission PermissionAuditAction = "revoke_permission"
+	AuditGrantBundle      PermissionAuditAction = "grant_bundle"
+	AuditRevokeBundle     PermissionAuditAction = "revoke_bundle"
+	AuditBundleAdd        PermissionAuditAction = "bundle_add"    // permission added to bundle, granted to every holder of the bundle
+	AuditBundleRemove     PermissionAuditAction = "bundle_remove" // permission removed from bundle, revoked from every holder of the bundle
+	AuditBundleDelete     PermissionAuditAction = "bundle_delete"
+	AuditExpirePermission PermissionAuditAction = "expire_permission" // time-limited grant revoked by cron job
+)
+
+var PermissionAuditActions = []PermissionAuditAction{
+	AuditGrantPermission,
+	AuditRevokePermission,
+	AuditGrantBundle,
+	AuditRevokeBundle,
+	AuditBundleAdd,
+	AuditBundleRemove,
+	AuditBundleDelete,
+	AuditExpirePermission,
+}
+
+type ChangeRequestKind string // "grant_permission", "revoke_permission", "set_role", "remove_role", "grant_bundle", "revoke_bundle", "update_bundle", "delete_bundle", "deactivate_admin"
+const (
+	ChangeGrantPermission  ChangeRequestKind = "grant_permission"
+	ChangeRevokePermission ChangeRequestKind = "revoke_permission"
+	ChangeSetRole          ChangeRequestKind = "set_role"
+	ChangeRemoveRole       ChangeRequestKind = "remove_role"
+	ChangeGrantBundle      ChangeRequestKind = "grant_bundle"
+	ChangeRevokeBundle     ChangeRequestKind = "revoke_bundle"
+	ChangeUpdateBundle     ChangeRequestKind = "update_bundle" // permissions of bundle holding seed permission replaced
+	ChangeDeleteBundle     ChangeRequestKind = "delete_bundle"
+	ChangeDeactivateAdmin  ChangeRequestKind = "deactivate_admin"
+)
+
+var ChangeRequestKinds = []ChangeRequestKind{
+	ChangeGrantPermission, ChangeRevokePermission, ChangeSetRole, ChangeRemoveRole,
+	ChangeGrantBundle, ChangeRevokeBundle, ChangeUpdateBundle, ChangeDeleteBundle, ChangeDeactivateAdmin,
+}
+
+type ChangeRequestStatus string // "pending", "approved", "rejected", "expired"
+const (
+	ChangePending  ChangeRequestStatus = "pending"
+	ChangeApproved ChangeRequestStatus = "approved"
+	ChangeRejected ChangeRequestStatus = "rejected"
+	ChangeExpired  ChangeRequestStatus = "expired"
+)
+
+var ChangeRequestStatuses = []ChangeRequestStatus{ChangePending, ChangeApproved, ChangeRejected, ChangeExpired}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Notification is a message for a user from the system
+type Notification struct {
+	Id
+	UserID  string     `gorm:"index;not null" json:"user_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	User    *User      `gorm:"constraint:OnDelete:CASCADE" json:"user,omitempty" swaggerignore:"true"`
+	Title   string     `gorm:"not null" json:"title" example:"permission grant expired"`
+	Message string     `gorm:"not null" json:"message" example:"grant of permission student manage to Chesta Ardiona expired"`
+	ReadAt  *time.Time `json:"read_at" example:"2006-01-02T15:04:05Z07:00"` // null if not read yet
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index;not null" json:"created_at" example:"2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,62 @@
+package payloads
+
+import (
+	"mime/multipart"
+	"school-information-system/internal/models"
+	"time"
+)
+
+type RequestCreateAchievement struct {
+	StudentID   string                  `form:"student_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Competition string                  `form:"competition" validate:"required" example:"LKS SMK Bidang IT Network Systems Administration"`
+	Organizer   string                  `form:"organizer" example:"Dinas Pendidikan Provinsi"`
+	Level       models.AchievementLevel `form:"level" validate:"required,achievement_level"`
+	Rank        string                  `form:"rank" validate:"required" example:"Juara 1"`
+	Date        time.Time               `form:"date" validate:"required" time_format:"2006-01-02" example:"2006-01-02"`
+	// certificate, optional
+	Certificate *multipart.FileHeader `form:"certificate" swaggerignore:"true"`
+}
+
+type RequestGetAchievement struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetAchievements struct {
+	Offset    int                     `form:"offset" example:"10"`
+	StudentID string                  `form:"student_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ClassID   string                  `form:"class_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Level     models.AchievementLevel `form:"level" validate:"omitempty,achievement_level"`
+	Year      int                     `form:"year" example:"2025"` // start year of academic year
+}
+
+type RequestDeleteAchievement struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetPortfolio struct {
+	ID string `uri:"id" validate:"required,uuid4"` // student id
+}
+
+type RequestGetAchievementReport struct {
+	FromYear int `form:"from_year" validate:"required,min=2000" example:"2021"` // start year of first academic year
+	ToYear   int `form:"to_year" validate:"required,gtefield=FromYear" example:"2025"`
+}
+
+type ResponseAchievementCount struct {
+	Level models.AchievementLevel `json:"level"`
+	Count int                     `json:"count" example:"3"`
+}
+
+// ResponsePortfolio is achievements of a student, ready to be shown on report card
+type ResponsePortfolio struct {
+	ResponseStudentIdentity
+	Summary      []ResponseAchievementCount `json:"summary"`
+	Achievements []models.Achievement       `json:"achievements"`
+}
+
+// ResponseAchievementReportYear is achievements of an academic year for accreditation report
+type ResponseAchievementReportYear struct {
+	AcademicYear string                     `json:"academic_year" example:"2025/2026"`
+	Total        int                        `json:"total" example:"12"`
+	Levels       []ResponseAchievementCount `json:"levels"`
+}
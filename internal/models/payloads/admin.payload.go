@@ -13,9 +13,34 @@ type RequestInitiateAdmin struct {
 	JoinedAt   time.Time `json:"joined_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
 }
 
+type RequestGetAdmins struct {
+	Offset int    `form:"offset" example:"10"`
+	Query  string `form:"q" example:"chesta"` // search full name or employee ID
+}
+
+type RequestGetAdmin struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetEffectivePermissions struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestUpdateAdmin struct {
+	ID         string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	StaffRole  string `json:"staff_role" example:"developer"`
+	EmployeeID string `json:"employee_id" example:"DEV001"`
+}
+
+type RequestDeactivateAdmin struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
 type RequestSetRole struct {
 	TargetID   string          `json:"target_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
 	TargetRole models.UserRole `json:"target_role" validate:"required,user_role"`
+	// true to give target role in addition to held roles, false to move user from held roles to target role
+	Append bool `json:"append"`
 
 	// empty ig target_role not student
 	StudentData *RequestSetRoleStudent `json:"student_data" prefix:"student_data." validate:"required_if=TargetRole student"`
@@ -25,6 +50,11 @@ type RequestSetRole struct {
 	AdminData *RequestSetRoleAdmin `json:"admin_data" prefix:"admin_data." validate:"required_if=TargetRole admin"`
 }
 
+type RequestRemoveRole struct {
+	TargetID string          `json:"target_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Role     models.UserRole `json:"role" validate:"required,user_role" example:"teacher"`
+}
+
 type RequestSetRoleStudent struct {
 	ClassID   string   `json:"class_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
 	ParentIDs []string `json:"parent_ids" validate:"required,min=2,max=2,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6,479b5b5f-81b1-4669-91a5-b5bf69e597c7"`
@@ -43,3 +73,18 @@ type RequestSetRoleAdmin struct {
 	EmployeeID string    `json:"employee_id" validate:"required" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
 	JoinedAt   time.Time `json:"joined_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
 }
+
+type RequestGetChangeRequests struct {
+	Offset int                        `form:"offset" example:"10"`
+	Status models.ChangeRequestStatus `form:"status" validate:"omitempty,change_request_status" example:"pending"`
+	Kind   models.ChangeRequestKind   `form:"kind" validate:"omitempty,change_request_kind" example:"grant_permission"`
+}
+
+type RequestGetChangeRequest struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestReviewChangeRequest struct {
+	ID   string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Note string `json:"note" validate:"max=255" example:"confirmed by phone"`
+}
@@ -0,0 +1,51 @@
+package payloads
+
+import (
+	"school-information-system/internal/models"
+	"time"
+)
+
+type RequestSubmitAdmission struct {
+	FullName   string            `json:"full_name" validate:"required" example:"Chesta Ardiona"`
+	Email      string            `json:"email" validate:"required,email" example:"chestaardi4@gmail.com"`
+	Password   string            `json:"password" validate:"required,min=8" example:"super.secret871798"` // password of account if enrolled
+	Phone      string            `json:"phone" validate:"required" example:"+6281234567890"`
+	Gender     models.UserGender `json:"gender" validate:"required,user_gender"`
+	BirthPlace string            `json:"birth_place" validate:"required" example:"Jakarta"`
+	BirthDate  time.Time         `json:"birth_date" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	Address    string            `json:"address" validate:"required" example:"Jl. Merdeka No. 1, Jakarta"`
+	NISN       string            `validate:"required" example:"0091913711"`
+
+	PreviousSchool string                     `json:"previous_school" validate:"required" example:"SMP Negeri 1 Jakarta"`
+	DesiredMajor   string                     `json:"desired_major" validate:"required" example:"TJKT"`
+	Parents        []models.AdmissionParent   `json:"parents" validate:"required,min=2,max=2,dive"`
+	Documents      []models.AdmissionDocument `json:"documents" validate:"required,min=1,dive"`
+}
+
+type RequestTrackAdmission struct {
+	RegistrationNumber string `form:"registration_number" validate:"required" example:"PPDB/2026/A1B2C3D4"`
+	Email              string `form:"email" validate:"required,email" example:"chestaardi4@gmail.com"`
+}
+
+type RequestGetAdmission struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetAdmissions struct {
+	Offset int                   `form:"offset" example:"10"`
+	Query  string                `form:"q" example:"chesta"` // match name, email, or registration number
+	Stage  models.AdmissionStage `form:"stage" validate:"omitempty,admission_stage"`
+	Major  string                `form:"major" example:"TJKT"` // desired major
+}
+
+type RequestMoveAdmissionStage struct {
+	ID        string                `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Stage     models.AdmissionStage `json:"stage" validate:"required,admission_stage"`
+	Note      string                `json:"note" validate:"required_if=Stage rejected" example:"documents verified"`
+	TestScore *float64              `json:"test_score" validate:"required_if=Stage tested,omitempty,min=0,max=100" example:"87.5"`
+}
+
+type RequestEnrollAdmission struct {
+	ID      string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	ClassID string `json:"class_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
@@ -0,0 +1,26 @@
+package payloads
+
+import "time"
+
+type RequestGraduate struct {
+	ClassIDs       []string `json:"class_ids" validate:"required,min=1,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6,479b5b5f-81b1-4669-91a5-b5bf69e597c7"` // grade 12 classes
+	GraduationYear int      `json:"graduation_year" validate:"required,min=2000" example:"2026"`
+	// graduates keep sign in access until this date, default to configured duration after graduation
+	AccessUntil *time.Time `json:"access_until" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+type RequestGetAlumni struct {
+	ID string `uri:"id" validate:"required,uuid4"` // alumni id
+}
+
+type RequestGetAlumniDirectory struct {
+	Offset int    `form:"offset" example:"10"`
+	Year   int    `form:"year" example:"2026"`
+	Major  string `form:"major" example:"TJKT"`
+	Query  string `form:"q" example:"chesta"` // match name
+}
+
+type RequestUpdateAlumniAccess struct {
+	ID          string    `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	AccessUntil time.Time `json:"access_until" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+}
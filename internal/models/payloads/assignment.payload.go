@@ -0,0 +1,64 @@
+package payloads
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+type RequestCreateAssignment struct {
+	Title        string    `form:"title" validate:"required" example:"Subnetting exercise"`
+	Instructions string    `form:"instructions" validate:"required" example:"answer all questions in the attachment"`
+	DueAt        time.Time `form:"due_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	AcceptLate   bool      `form:"accept_late" example:"true"` // accept submission after due date, flagged as late
+	ClassID      string    `form:"class_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	SubjectID    string    `form:"subject_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+
+	File *multipart.FileHeader `form:"file" swaggerignore:"true"` // optional attachment
+}
+
+type RequestGetAssignment struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetAssignments struct {
+	Offset    int    `form:"offset" example:"10"`
+	ClassID   string `form:"class_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // ignored for student, always own class
+	SubjectID string `form:"subject_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	TeacherID string `form:"teacher_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
+
+type RequestDeleteAssignment struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestSubmitAssignment struct {
+	ID   string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Text string `form:"text" validate:"required_without=File" example:"my answer"`
+
+	File *multipart.FileHeader `form:"file" validate:"required_without=Text" swaggerignore:"true"`
+}
+
+type RequestGetSubmissions struct {
+	ID     string `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // assignment id
+	Offset int    `form:"offset" example:"10"`
+}
+
+type RequestGetSubmission struct {
+	ID string `uri:"id" validate:"required,uuid4"` // submission id
+}
+
+type RequestGetPendingAssignments struct {
+	Offset int `form:"offset" example:"10"`
+}
+
+type RequestGetUngradedSubmissions struct {
+	Offset int `form:"offset" example:"10"`
+}
+
+type RequestGradeSubmission struct {
+	ID      string  `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Grade   float64 `json:"grade" validate:"min=0,max=100" example:"87.5"`
+	Comment string  `json:"comment" example:"good work"`
+	// record grade as score in gradebook
+	ToGradebook bool `json:"to_gradebook" example:"true"`
+}
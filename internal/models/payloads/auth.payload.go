@@ -18,3 +18,7 @@ type RequestSignIn struct {
 	Password   string `json:"password" validate:"required" example:"super.secret871798"`
 	RememberMe bool   `json:"remember_me"`
 }
+
+type RequestSwitchRole struct {
+	Role models.UserRole `json:"role" validate:"required,user_role" example:"teacher"`
+}
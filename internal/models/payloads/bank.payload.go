@@ -0,0 +1,70 @@
+package payloads
+
+import "school-information-system/internal/models"
+
+type RequestCreateBankQuestion struct {
+	SubjectID  string                    `json:"subject_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Grade      int                       `json:"grade" validate:"required,min=1,max=12" example:"10"`
+	Topic      string                    `json:"topic" validate:"required" example:"subnetting"`
+	Competency string                    `json:"competency" example:"3.2 menerapkan subnetting"`
+	Difficulty models.QuestionDifficulty `json:"difficulty" validate:"required,question_difficulty"`
+	RequestQuestionContent
+}
+
+type RequestGetBankQuestion struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetBankQuestions struct {
+	Offset     int                       `form:"offset" example:"10"`
+	SubjectID  string                    `form:"subject_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Grade      int                       `form:"grade" example:"10"`
+	Topic      string                    `form:"topic" example:"subnetting"`
+	Competency string                    `form:"competency" example:"3.2"` // prefix of competency
+	Difficulty models.QuestionDifficulty `form:"difficulty" validate:"omitempty,question_difficulty"`
+	Query      string                    `form:"q" example:"subnet mask"` // match prompt of current version
+}
+
+// RequestUpdateBankQuestion saves content as new version, tags are updated in place
+type RequestUpdateBankQuestion struct {
+	ID         string                    `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Grade      int                       `json:"grade" validate:"required,min=1,max=12" example:"10"`
+	Topic      string                    `json:"topic" validate:"required" example:"subnetting"`
+	Competency string                    `json:"competency" example:"3.2 menerapkan subnetting"`
+	Difficulty models.QuestionDifficulty `json:"difficulty" validate:"required,question_difficulty"`
+	RequestQuestionContent
+}
+
+type RequestDeleteBankQuestion struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestAddBankQuestionsToQuiz struct {
+	ID          string   `uri:"id" validate:"required,uuid4" swaggerignore:"true"`                                                 // quiz id
+	QuestionIDs []string `json:"question_ids" validate:"required,min=1,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // current version is copied
+}
+
+type RequestMakeExamPaper struct {
+	Title          string   `json:"title" validate:"required" example:"Penilaian Akhir Semester Informatika"`
+	QuestionIDs    []string `json:"question_ids" validate:"required,min=1,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // current version is used, in this order
+	Shuffle        bool     `json:"shuffle" example:"false"`
+	IncludeAnswers bool     `json:"include_answers" example:"false"` // answer key for teacher copy
+}
+
+type ResponseExamPaperQuestion struct {
+	Number    int                     `json:"number" example:"1"`
+	Type      models.QuestionType     `json:"type"`
+	Prompt    string                  `json:"prompt" example:"subnet mask of /24 network?"`
+	Options   []models.QuestionOption `json:"options,omitempty"`
+	Answers   []string                `json:"answers,omitempty" example:"a"`
+	Points    float64                 `json:"points" example:"10"`
+	VersionID string                  `json:"version_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
+
+// ResponseExamPaper is printable exam paper assembled from question bank
+type ResponseExamPaper struct {
+	Title       string                      `json:"title" example:"Penilaian Akhir Semester Informatika"`
+	Subjects    []string                    `json:"subjects" example:"informatika"`
+	TotalPoints float64                     `json:"total_points" example:"100"`
+	Questions   []ResponseExamPaperQuestion `json:"questions"`
+}
@@ -45,3 +45,8 @@ type RequestAddStudents struct {
 	ID         string   `uri:"id" validate:"required,uuid4"`
 	StudentIDs []string `json:"student_ids" validate:"required,min=1,dive,uuid4"`
 }
+
+type RequestSetHomeRoom struct {
+	ID     string `uri:"id" validate:"required,uuid4"`
+	RoomID string `json:"room_id" validate:"required,uuid4"`
+}
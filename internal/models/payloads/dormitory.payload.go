@@ -0,0 +1,95 @@
+package payloads
+
+import "school-information-system/internal/models"
+
+type RequestCreateDormitory struct {
+	Name    string `json:"name" validate:"required" example:"Asrama Putra Al-Fatih"`
+	Address string `json:"address" example:"Jl. Pesantren No. 3"`
+}
+
+type RequestGetDormitory struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetDormitories struct {
+	Offset int `form:"offset" example:"10"`
+}
+
+type RequestUpdateDormitory struct {
+	ID      string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Name    string `json:"name" validate:"required" example:"Asrama Putra Al-Fatih"`
+	Address string `json:"address" example:"Jl. Pesantren No. 3"`
+}
+
+type RequestDeleteDormitory struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestSetDormSupervisors struct {
+	ID         string   `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	TeacherIDs []string `json:"teacher_ids" validate:"dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // empty to remove all supervisors
+}
+
+type RequestCreateDormRoom struct {
+	ID       string            `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // dormitory id
+	Name     string            `json:"name" validate:"required" example:"Kamar 101"`
+	Capacity int               `json:"capacity" validate:"required,min=1" example:"6"`
+	Gender   models.UserGender `json:"gender" validate:"required,user_gender"`
+}
+
+type RequestUpdateDormRoom struct {
+	ID       string            `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Name     string            `json:"name" validate:"required" example:"Kamar 101"`
+	Capacity int               `json:"capacity" validate:"required,min=1" example:"6"`
+	Gender   models.UserGender `json:"gender" validate:"required,user_gender"`
+}
+
+type RequestDeleteDormRoom struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestAssignDormRoom struct {
+	ID           string   `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // room id
+	StudentIDs   []string `json:"student_ids" validate:"required,min=1,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	AcademicYear string   `json:"academic_year" validate:"required,academic_year" example:"2025/2026"`
+	Semester     int      `json:"semester" validate:"required,min=1,max=2" example:"1"`
+}
+
+type RequestGetDormAssignments struct {
+	Offset       int    `form:"offset" example:"10"`
+	DormitoryID  string `form:"dormitory_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	RoomID       string `form:"room_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	StudentID    string `form:"student_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	AcademicYear string `form:"academic_year" validate:"omitempty,academic_year" example:"2025/2026"`
+	Semester     int    `form:"semester" validate:"omitempty,min=1,max=2" example:"1"`
+}
+
+type RequestDeleteDormAssignment struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestNightRollEntry struct {
+	StudentID string                 `json:"student_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Status    models.NightRollStatus `json:"status" validate:"required,night_roll_status"`
+	Note      string                 `json:"note" example:"went home with parent permission"`
+}
+
+type RequestRecordNightRoll struct {
+	ID      string                  `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // dormitory id
+	Date    string                  `json:"date" validate:"required,datetime=2006-01-02" example:"2006-01-02"`
+	Entries []RequestNightRollEntry `json:"entries" validate:"required,min=1,dive"`
+}
+
+type RequestGetNightRoll struct {
+	ID   string `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // dormitory id
+	Date string `form:"date" validate:"required,datetime=2006-01-02" example:"2006-01-02"`
+}
+
+// ResponseNightRollEntry is a boarding student in night roll of a date, status is null if not recorded yet
+type ResponseNightRollEntry struct {
+	StudentID string                  `json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	FullName  string                  `json:"full_name" example:"Chesta Ardiona"`
+	Room      string                  `json:"room" example:"Kamar 101"`
+	Status    *models.NightRollStatus `json:"status"`
+	Note      string                  `json:"note" example:"went home with parent permission"`
+}
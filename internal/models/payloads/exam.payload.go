@@ -0,0 +1,118 @@
+package payloads
+
+import (
+	"school-information-system/internal/models"
+	"time"
+)
+
+type RequestCreateExamPeriod struct {
+	Name      string          `json:"name" validate:"required" example:"PTS Ganjil 2025/2026"`
+	Type      models.ExamType `json:"type" validate:"required,exam_type"`
+	StartDate time.Time       `json:"start_date" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	EndDate   time.Time       `json:"end_date" validate:"required,gtefield=StartDate" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+type RequestGetExamPeriod struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetExamPeriods struct {
+	Offset int             `form:"offset" example:"10"`
+	Type   models.ExamType `form:"type" validate:"omitempty,exam_type"`
+}
+
+type RequestDeleteExamPeriod struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestCreateExamRoom struct {
+	Name     string `json:"name" validate:"required" example:"R. 201"`
+	Capacity int    `json:"capacity" validate:"required,min=1" example:"36"`
+	RoomID   string `json:"room_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // facility used as this exam room
+}
+
+type RequestGetExamRooms struct {
+	Offset int `form:"offset" example:"10"`
+}
+
+type RequestUpdateExamRoom struct {
+	ID       string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Name     string `json:"name" validate:"required" example:"R. 201"`
+	Capacity int    `json:"capacity" validate:"required,min=1" example:"36"`
+	RoomID   string `json:"room_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // facility used as this exam room
+}
+
+type RequestDeleteExamRoom struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestCreateExamSession struct {
+	PeriodID  string    `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	SubjectID string    `json:"subject_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Grade     int       `json:"grade" validate:"required,min=1,max=12" example:"10"`
+	StartAt   time.Time `json:"start_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	EndAt     time.Time `json:"end_at" validate:"required,gtfield=StartAt" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+type RequestDeleteExamSession struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+// RequestAllocateExamSeats replaces seats of the session
+type RequestAllocateExamSeats struct {
+	ID      string   `uri:"id" validate:"required,uuid4" swaggerignore:"true"`                                             // session id
+	RoomIDs []string `json:"room_ids" validate:"required,min=1,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // rooms to fill, in this order
+}
+
+type RequestAssignInvigilator struct {
+	ID        string `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // session id
+	RoomID    string `json:"room_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	TeacherID string `json:"teacher_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
+
+type RequestRemoveInvigilator struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetExamRoomLists struct {
+	ID string `uri:"id" validate:"required,uuid4"` // session id
+}
+
+type RequestGetExamCard struct {
+	ID        string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`                                     // period id
+	StudentID string `form:"student_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // required for staff, ignored for student
+}
+
+type ResponseExamRoomSeat struct {
+	SeatNumber int    `json:"seat_number" example:"12"`
+	StudentID  string `json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	NISN       string `json:"nisn" example:"0091913711"`
+	FullName   string `json:"full_name" example:"Chesta Ardiona"`
+	ClassName  string `json:"class_name" example:"10 TJKT 3"`
+}
+
+// ResponseExamRoomList is printable attendance list of a room in a session
+type ResponseExamRoomList struct {
+	Room         models.ExamRoom        `json:"room"`
+	Subject      string                 `json:"subject" example:"informatika"`
+	Grade        int                    `json:"grade" example:"10"`
+	StartAt      time.Time              `json:"start_at" example:"2006-01-02T15:04:05Z07:00"`
+	EndAt        time.Time              `json:"end_at" example:"2006-01-02T15:04:05Z07:00"`
+	Invigilators []string               `json:"invigilators" example:"Chesta Ardiona"`
+	Seats        []ResponseExamRoomSeat `json:"seats"`
+}
+
+type ResponseExamCardSession struct {
+	Subject    string    `json:"subject" example:"informatika"`
+	StartAt    time.Time `json:"start_at" example:"2006-01-02T15:04:05Z07:00"`
+	EndAt      time.Time `json:"end_at" example:"2006-01-02T15:04:05Z07:00"`
+	Room       string    `json:"room" example:"R. 201"`
+	SeatNumber int       `json:"seat_number" example:"12"`
+}
+
+// ResponseExamCard is printable exam card of a student in a period
+type ResponseExamCard struct {
+	Period string `json:"period" example:"PTS Ganjil 2025/2026"`
+	ResponseStudentIdentity
+	Sessions []ResponseExamCardSession `json:"sessions"`
+}
@@ -0,0 +1,57 @@
+package payloads
+
+import (
+	"school-information-system/internal/models"
+	"time"
+)
+
+type RequestGetStudentHealth struct {
+	ID string `uri:"id" validate:"required,uuid4"` // student id
+}
+
+// RequestSetStudentHealth replaces whole health record of the student
+type RequestSetStudentHealth struct {
+	ID                string                `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // student id
+	BloodType         string                `json:"blood_type" validate:"omitempty,oneof=A+ A- B+ B- AB+ AB- O+ O-" example:"O+"`
+	Allergies         []string              `json:"allergies" validate:"dive,required" example:"kacang"`
+	ChronicConditions []string              `json:"chronic_conditions" validate:"dive,required" example:"asma"`
+	Immunizations     []models.Immunization `json:"immunizations" validate:"dive"`
+	Notes             string                `json:"notes" example:"membawa inhaler"`
+}
+
+type RequestCreateClinicVisit struct {
+	StudentID      string    `json:"student_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	VisitedAt      time.Time `json:"visited_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	Complaint      string    `json:"complaint" validate:"required" example:"pusing dan mual"`
+	ActionTaken    string    `json:"action_taken" validate:"required" example:"istirahat 1 jam, diberi teh hangat"`
+	ParentNotified bool      `json:"parent_notified" example:"true"`
+}
+
+type RequestGetClinicVisits struct {
+	Offset    int       `form:"offset" example:"10"`
+	StudentID string    `form:"student_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ClassID   string    `form:"class_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	From      time.Time `form:"from" example:"2006-01-02T15:04:05Z07:00"`
+	To        time.Time `form:"to" example:"2006-01-02T15:04:05Z07:00"`
+	// only visits which parent not notified yet
+	ParentNotNotified bool `form:"parent_not_notified" example:"true"`
+}
+
+type RequestNotifyClinicVisitParent struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestDeleteClinicVisit struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetClassAllergies struct {
+	ID string `uri:"id" validate:"required,uuid4"` // class id
+}
+
+// ResponseAllergyFlag is allergy of student shown to teachers, other health data is not included
+type ResponseAllergyFlag struct {
+	StudentID string   `json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	FullName  string   `json:"full_name" example:"Chesta Ardiona"`
+	Allergies []string `gorm:"serializer:json" json:"allergies" example:"kacang"`
+}
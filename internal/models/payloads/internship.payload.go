@@ -0,0 +1,138 @@
+package payloads
+
+import (
+	"school-information-system/internal/models"
+	"time"
+)
+
+type RequestCreateIndustryPartner struct {
+	Name         string   `json:"name" validate:"required" example:"PT Telkom Indonesia"`
+	Field        string   `json:"field" example:"network infrastructure"`
+	Address      string   `json:"address" validate:"required" example:"Jl. Japati No. 1, Bandung"`
+	ContactName  string   `json:"contact_name" example:"Budi Santoso"`
+	ContactPhone string   `json:"contact_phone" example:"+6281234567890"`
+	ContactEmail string   `json:"contact_email" validate:"omitempty,email" example:"hrd@telkom.co.id"`
+	Majors       []string `json:"majors" example:"TJKT,RPL"` // empty to accept every major
+}
+
+type RequestGetIndustryPartner struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetIndustryPartners struct {
+	Offset int    `form:"offset" example:"10"`
+	Query  string `form:"q" example:"telkom"`
+	Major  string `form:"major" example:"TJKT"`
+	Active *bool  `form:"active" example:"true"`
+}
+
+type RequestUpdateIndustryPartner struct {
+	ID           string   `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Name         string   `json:"name" validate:"required" example:"PT Telkom Indonesia"`
+	Field        string   `json:"field" example:"network infrastructure"`
+	Address      string   `json:"address" validate:"required" example:"Jl. Japati No. 1, Bandung"`
+	ContactName  string   `json:"contact_name" example:"Budi Santoso"`
+	ContactPhone string   `json:"contact_phone" example:"+6281234567890"`
+	ContactEmail string   `json:"contact_email" validate:"omitempty,email" example:"hrd@telkom.co.id"`
+	Majors       []string `json:"majors" example:"TJKT,RPL"`
+	Active       bool     `json:"active" example:"true"`
+}
+
+type RequestDeleteIndustryPartner struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestCreatePlacement struct {
+	StudentID    string    `json:"student_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	PartnerID    string    `json:"partner_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	SupervisorID string    `json:"supervisor_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // teacher id
+	MentorName   string    `json:"mentor_name" validate:"required" example:"Andi Wijaya"`
+	MentorPhone  string    `json:"mentor_phone" example:"+6281234567890"`
+	MentorEmail  string    `json:"mentor_email" validate:"omitempty,email" example:"andi@telkom.co.id"`
+	StartDate    time.Time `json:"start_date" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	EndDate      time.Time `json:"end_date" validate:"required,gtfield=StartDate" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+type RequestGetPlacement struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetPlacements struct {
+	Offset       int    `form:"offset" example:"10"`
+	StudentID    string `form:"student_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	PartnerID    string `form:"partner_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	SupervisorID string `form:"supervisor_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ClassID      string `form:"class_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Ongoing      bool   `form:"ongoing" example:"true"` // only placements running today
+}
+
+type RequestUpdatePlacement struct {
+	ID           string    `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	SupervisorID string    `json:"supervisor_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	MentorName   string    `json:"mentor_name" validate:"required" example:"Andi Wijaya"`
+	MentorPhone  string    `json:"mentor_phone" example:"+6281234567890"`
+	MentorEmail  string    `json:"mentor_email" validate:"omitempty,email" example:"andi@telkom.co.id"`
+	StartDate    time.Time `json:"start_date" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	EndDate      time.Time `json:"end_date" validate:"required,gtfield=StartDate" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+type RequestDeletePlacement struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestWriteJournal struct {
+	ID       string    `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // placement id
+	Date     time.Time `json:"date" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	Activity string    `json:"activity" validate:"required" example:"configured VLAN on core switch with mentor"`
+}
+
+type RequestGetJournals struct {
+	ID     string `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // placement id
+	Offset int    `form:"offset" example:"10"`
+}
+
+type RequestReviewJournal struct {
+	ID       string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Feedback string `json:"feedback" example:"add topology diagram next time"`
+}
+
+type RequestCreateMonitoringVisit struct {
+	ID        string    `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // placement id
+	VisitedAt time.Time `json:"visited_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	Findings  string    `json:"findings" validate:"required" example:"student adapts well, mentor asks for more network security material"`
+	FollowUp  string    `json:"follow_up" example:"add security module before next visit"`
+}
+
+type RequestDeleteMonitoringVisit struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestCreateEvaluationLink struct {
+	ID string `uri:"id" validate:"required,uuid4"` // placement id
+}
+
+type RequestGetEvaluationForm struct {
+	Token string `form:"token" validate:"required" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
+
+type RequestSubmitEvaluation struct {
+	Token   string                         `json:"token" validate:"required" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Aspects []models.InternshipAspectScore `json:"aspects" validate:"required,min=1,dive"`
+	Notes   string                         `json:"notes" example:"disciplined and quick to learn"`
+}
+
+// ResponseEvaluationLink is token of evaluation link for industry mentor, token is only shown once
+type ResponseEvaluationLink struct {
+	Token     string    `json:"token" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	ExpiresAt time.Time `json:"expires_at" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ResponseEvaluationForm is placement data shown to industry mentor before evaluating
+type ResponseEvaluationForm struct {
+	StudentName string    `json:"student_name" example:"Chesta Ardiona"`
+	ClassName   string    `json:"class_name" example:"12 TJKT 3"`
+	Partner     string    `json:"partner" example:"PT Telkom Indonesia"`
+	MentorName  string    `json:"mentor_name" example:"Andi Wijaya"`
+	StartDate   time.Time `json:"start_date" example:"2006-01-02T15:04:05Z07:00"`
+	EndDate     time.Time `json:"end_date" example:"2006-01-02T15:04:05Z07:00"`
+}
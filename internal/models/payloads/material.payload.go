@@ -0,0 +1,34 @@
+package payloads
+
+import (
+	"mime/multipart"
+	"school-information-system/internal/models"
+)
+
+type RequestCreateMaterial struct {
+	Title       string              `form:"title" validate:"required" example:"Subnetting basics"`
+	Description string              `form:"description" example:"read before next meeting"`
+	Type        models.MaterialType `form:"type" validate:"required,material_type"`
+	ClassID     string              `form:"class_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	SubjectID   string              `form:"subject_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	// required if type is link
+	URL string `form:"url" validate:"required_if=Type link,omitempty,url" example:"https://youtu.be/dQw4w9WgXcQ"`
+	// required if type is file
+	File *multipart.FileHeader `form:"file" validate:"required_if=Type file" swaggerignore:"true"`
+}
+
+type RequestGetMaterial struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetMaterials struct {
+	Offset    int    `form:"offset" example:"10"`
+	ClassID   string `form:"class_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // ignored for student, always own class
+	SubjectID string `form:"subject_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	TeacherID string `form:"teacher_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Query     string `form:"q" example:"subnetting"` // match title
+}
+
+type RequestDeleteMaterial struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
@@ -0,0 +1,10 @@
+package payloads
+
+type RequestGetNotifications struct {
+	Offset int  `form:"offset" example:"10"`
+	Unread bool `form:"unread" example:"true"` // only get notifications which are not read yet
+}
+
+type RequestReadNotification struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
@@ -1,10 +1,16 @@
 package payloads
 
-import "school-information-system/internal/models"
+import (
+	"school-information-system/internal/models"
+	"time"
+)
 
+// RequestGrantPermission grants permission to admin, grant may be limited to a period such as a temporary operator during admission season
 type RequestGrantPermission struct {
-	TargetID     string `json:"target_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
-	PermissionID string `json:"permission_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	TargetID     string     `json:"target_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	PermissionID string     `json:"permission_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ValidFrom    *time.Time `json:"valid_from" example:"2006-01-02T15:04:05Z07:00"`  // empty to be valid since granted
+	ValidUntil   *time.Time `json:"valid_until" example:"2006-01-02T15:04:05Z07:00"` // empty to never expire
 }
 
 type RequestRevokePermission struct {
@@ -17,6 +23,7 @@ type RequestCreatePermission struct {
 	Description string                    `json:"description" validate:"required,min=10" example:"permission to set and read role"`
 	Resource    models.PermissionResource `json:"resource" validate:"required,permission_resource" example:"role"`
 	Actions     []models.PermissionAction `json:"actions" validate:"required,min=1,dive,permission_action" example:"update,read"`
+	Scope       models.PermissionScope    `json:"scope"` // empty to apply to the whole resource, only for "class" and "student" resource
 }
 
 type RequestGetPermission struct {
@@ -39,3 +46,50 @@ type RequestUpdatePermission struct {
 type RequestDeletePermission struct {
 	ID string `uri:"id" validate:"required,uuid4"`
 }
+
+type RequestCreatePermissionBundle struct {
+	Name          string   `json:"name" validate:"required,min=3" example:"curriculum staff"`
+	Description   string   `json:"description" validate:"required,min=10" example:"Manage subjects, classes and timetable"`
+	PermissionIDs []string `json:"permission_ids" validate:"required,min=1,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6,479b5b5f-81b1-4669-91a5-b5bf69e597c7"`
+}
+
+type RequestGetPermissionBundle struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetPermissionBundles struct {
+	Offset int    `form:"offset" example:"10"`
+	Query  string `form:"q" example:"staff"`
+}
+
+type RequestUpdatePermissionBundle struct {
+	ID          string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Name        string `json:"name" validate:"omitempty,min=3" example:"curriculum staff"`
+	Description string `json:"description" validate:"omitempty,min=10" example:"Manage subjects, classes and timetable"`
+	// replace permissions of bundle, applies to every holder of the bundle
+	PermissionIDs []string `json:"permission_ids" validate:"omitempty,min=1,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6,479b5b5f-81b1-4669-91a5-b5bf69e597c7"`
+}
+
+type RequestDeletePermissionBundle struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGrantPermissionBundle struct {
+	TargetID string `json:"target_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	BundleID string `json:"bundle_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
+
+type RequestRevokePermissionBundle struct {
+	TargetID string `json:"target_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	BundleID string `json:"bundle_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
+
+// RequestGetPermissionAudits gets audits from the latest change
+type RequestGetPermissionAudits struct {
+	Offset        int                          `form:"offset" example:"10"`
+	Action        models.PermissionAuditAction `form:"action" validate:"omitempty,permission_audit_action"`
+	ActorID       string                       `form:"actor_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`        // user ID of admin who made the change
+	TargetAdminID string                       `form:"target_admin_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // ID of admin profile
+	PermissionID  string                       `form:"permission_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	BundleID      string                       `form:"bundle_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
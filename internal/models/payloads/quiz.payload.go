@@ -0,0 +1,83 @@
+package payloads
+
+import (
+	"school-information-system/internal/models"
+	"time"
+)
+
+type RequestQuestionContent struct {
+	Type    models.QuestionType     `json:"type" validate:"required,question_type"`
+	Prompt  string                  `json:"prompt" validate:"required" example:"subnet mask of /24 network?"`
+	Options []models.QuestionOption `json:"options" validate:"omitempty,dive"` // required for multiple choice and multiple answer
+	// keys of correct options, or accepted answers of short answer. Empty for essay
+	Answers []string `json:"answers" example:"a"`
+	Points  float64  `json:"points" validate:"required,gt=0" example:"10"`
+}
+
+type RequestCreateQuiz struct {
+	Title            string                   `json:"title" validate:"required" example:"Subnetting daily quiz"`
+	Description      string                   `json:"description" example:"chapter 3"`
+	SubjectID        string                   `json:"subject_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	TimeLimit        int                      `json:"time_limit" validate:"min=0" example:"30"`  // in minutes, 0 means limited by window only
+	MaxAttempts      int                      `json:"max_attempts" validate:"min=0" example:"1"` // 0 means unlimited
+	ShuffleQuestions bool                     `json:"shuffle_questions" example:"true"`
+	ShuffleOptions   bool                     `json:"shuffle_options" example:"true"`
+	Questions        []RequestQuestionContent `json:"questions" validate:"required,min=1,dive"`
+}
+
+type RequestGetQuiz struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetQuizzes struct {
+	Offset    int    `form:"offset" example:"10"`
+	SubjectID string `form:"subject_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ClassID   string `form:"class_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // has window for the class, ignored for student, always own class
+	TeacherID string `form:"teacher_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
+
+type RequestDeleteQuiz struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestSetQuizWindow struct {
+	ID      string    `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	ClassID string    `json:"class_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	StartAt time.Time `json:"start_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	EndAt   time.Time `json:"end_at" validate:"required,gtfield=StartAt" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+type RequestStartQuizAttempt struct {
+	ID string `uri:"id" validate:"required,uuid4"` // quiz id
+}
+
+type RequestQuizResponse struct {
+	QuestionID string   `json:"question_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Response   []string `json:"response" example:"a"` // option keys, or single text of short answer and essay
+}
+
+type RequestSubmitQuizAttempt struct {
+	ID      string                `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // attempt id
+	Answers []RequestQuizResponse `json:"answers" validate:"dive"`
+}
+
+type RequestGetQuizAttempt struct {
+	ID string `uri:"id" validate:"required,uuid4"` // attempt id
+}
+
+type RequestGetQuizAttempts struct {
+	ID     string `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // quiz id
+	Offset int    `form:"offset" example:"10"`
+}
+
+type RequestGradeQuizAnswer struct {
+	ID       string  `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // answer id
+	Points   float64 `json:"points" validate:"min=0" example:"8"`
+	Feedback string  `json:"feedback" example:"explain more"`
+}
+
+// ResponseQuizPaper is attempt with questions in the order given to student
+type ResponseQuizPaper struct {
+	Attempt   *models.QuizAttempt   `json:"attempt"`
+	Questions []models.QuizQuestion `json:"questions"`
+}
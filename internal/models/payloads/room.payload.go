@@ -0,0 +1,64 @@
+package payloads
+
+import (
+	"school-information-system/internal/models"
+	"time"
+)
+
+type RequestCreateRoom struct {
+	Code     string          `json:"code" validate:"required" example:"LAB-KOM-1"`
+	Name     string          `json:"name" validate:"required" example:"Lab Komputer 1"`
+	Building string          `json:"building" validate:"required" example:"Gedung B"`
+	Capacity int             `json:"capacity" validate:"required,min=1" example:"36"`
+	Type     models.RoomType `json:"type" validate:"required,room_type"`
+}
+
+type RequestGetRoom struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetRooms struct {
+	Offset      int             `form:"offset" example:"10"`
+	Type        models.RoomType `form:"type" validate:"omitempty,room_type"`
+	Building    string          `form:"building" example:"Gedung B"`
+	MinCapacity int             `form:"min_capacity" example:"30"`
+}
+
+type RequestUpdateRoom struct {
+	ID       string          `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Code     string          `json:"code" validate:"required" example:"LAB-KOM-1"`
+	Name     string          `json:"name" validate:"required" example:"Lab Komputer 1"`
+	Building string          `json:"building" validate:"required" example:"Gedung B"`
+	Capacity int             `json:"capacity" validate:"required,min=1" example:"36"`
+	Type     models.RoomType `json:"type" validate:"required,room_type"`
+}
+
+type RequestDeleteRoom struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestCreateRoomBooking struct {
+	RoomID  string    `json:"room_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Purpose string    `json:"purpose" validate:"required" example:"praktikum jaringan 11 TJKT 2"`
+	StartAt time.Time `json:"start_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	EndAt   time.Time `json:"end_at" validate:"required,gtfield=StartAt" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+type RequestGetRoomBookings struct {
+	Offset int                  `form:"offset" example:"10"`
+	RoomID string               `form:"room_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Status models.BookingStatus `form:"status" validate:"omitempty,booking_status"`
+	From   time.Time            `form:"from" example:"2006-01-02T15:04:05Z07:00"` // bookings ending after this time
+	To     time.Time            `form:"to" example:"2006-01-02T15:04:05Z07:00"`   // bookings starting before this time
+	Mine   bool                 `form:"mine" example:"true"`                      // only bookings of current user
+}
+
+type RequestReviewRoomBooking struct {
+	ID     string               `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Status models.BookingStatus `json:"status" validate:"required,oneof=approved rejected" example:"approved"`
+	Note   string               `json:"note" example:"lab dipakai UKK"`
+}
+
+type RequestCancelRoomBooking struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
@@ -0,0 +1,108 @@
+package payloads
+
+import (
+	"school-information-system/internal/models"
+	"time"
+)
+
+type RequestCreateScholarshipProgram struct {
+	Name          string                   `json:"name" validate:"required" example:"Program Indonesia Pintar"`
+	Source        models.ScholarshipSource `json:"source" validate:"required,scholarship_source"`
+	Provider      string                   `json:"provider" example:"Kemendikbudristek"`
+	Eligibility   string                   `json:"eligibility" example:"holder of KIP card or from family registered in DTKS"`
+	AmountPerTerm int64                    `json:"amount_per_term" validate:"min=0" example:"500000"`
+	FeeDiscount   int                      `json:"fee_discount" validate:"min=0,max=100" example:"50"`
+}
+
+type RequestGetScholarshipProgram struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetScholarshipPrograms struct {
+	Offset int                      `form:"offset" example:"10"`
+	Source models.ScholarshipSource `form:"source" validate:"omitempty,scholarship_source"`
+	Active *bool                    `form:"active" example:"true"`
+}
+
+type RequestUpdateScholarshipProgram struct {
+	ID            string                   `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Name          string                   `json:"name" validate:"required" example:"Program Indonesia Pintar"`
+	Source        models.ScholarshipSource `json:"source" validate:"required,scholarship_source"`
+	Provider      string                   `json:"provider" example:"Kemendikbudristek"`
+	Eligibility   string                   `json:"eligibility" example:"holder of KIP card or from family registered in DTKS"`
+	AmountPerTerm int64                    `json:"amount_per_term" validate:"min=0" example:"500000"`
+	FeeDiscount   int                      `json:"fee_discount" validate:"min=0,max=100" example:"50"`
+	Active        bool                     `json:"active" example:"true"`
+}
+
+type RequestDeleteScholarshipProgram struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestAddScholarshipRecipients struct {
+	ID           string   `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // program id
+	StudentIDs   []string `json:"student_ids" validate:"required,min=1,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	AcademicYear string   `json:"academic_year" validate:"required,academic_year" example:"2025/2026"`
+	Semester     int      `json:"semester" validate:"required,min=1,max=2" example:"1"`
+	Note         string   `json:"note" example:"recommended by homeroom teacher"`
+}
+
+type RequestGetScholarshipRecipient struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetScholarshipRecipients struct {
+	Offset       int    `form:"offset" example:"10"`
+	ProgramID    string `form:"program_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	StudentID    string `form:"student_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ClassID      string `form:"class_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	AcademicYear string `form:"academic_year" validate:"omitempty,academic_year" example:"2025/2026"`
+	Semester     int    `form:"semester" validate:"omitempty,min=1,max=2" example:"1"`
+}
+
+type RequestRemoveScholarshipRecipient struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestCreateDisbursement struct {
+	ID          string    `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // recipient id
+	Amount      int64     `json:"amount" validate:"required,min=1" example:"500000"`
+	DisbursedAt time.Time `json:"disbursed_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	Reference   string    `json:"reference" example:"SP2D/2025/0012"`
+	Note        string    `json:"note" example:"paid through BRI account of the student"`
+}
+
+type RequestDeleteDisbursement struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetFeeDiscount struct {
+	ID           string `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // student id
+	AcademicYear string `form:"academic_year" validate:"required,academic_year" example:"2025/2026"`
+	Semester     int    `form:"semester" validate:"required,min=1,max=2" example:"1"`
+}
+
+type RequestGetScholarshipReport struct {
+	AcademicYear string `form:"academic_year" validate:"required,academic_year" example:"2025/2026"`
+	Semester     int    `form:"semester" validate:"omitempty,min=1,max=2" example:"1"` // empty for whole academic year
+}
+
+// ResponseFeeDiscount is total school fee discount of a student in a term, capped at 100 percent
+type ResponseFeeDiscount struct {
+	StudentID    string                      `json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	AcademicYear string                      `json:"academic_year" example:"2025/2026"`
+	Semester     int                         `json:"semester" example:"1"`
+	FeeDiscount  int                         `json:"fee_discount" example:"50"`
+	Programs     []models.ScholarshipProgram `json:"programs"`
+}
+
+// ResponseScholarshipReportRow is recipients of a program in a class
+type ResponseScholarshipReportRow struct {
+	ProgramID  string                   `json:"program_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Program    string                   `json:"program" example:"Program Indonesia Pintar"`
+	Source     models.ScholarshipSource `json:"source"`
+	ClassID    string                   `json:"class_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ClassName  string                   `json:"class_name" example:"10 TJKT 3"`
+	Recipients int                      `json:"recipients" example:"12"`
+	Disbursed  int64                    `json:"disbursed" example:"6000000"` // total amount disbursed in rupiah
+}
@@ -1,7 +1,60 @@
 package payloads
 
+import (
+	"school-information-system/internal/models"
+	"time"
+)
+
 type RequestUpdateStudent struct {
 	ID        string   `uri:"id" validate:"required,uuid4"`
 	NISN      string   `example:"0091913711"`
 	ParentIDs []string `json:"parent_ids" validate:"omitempty,min=2,max=2"` // replace current parents
 }
+
+type RequestTransferIn struct {
+	FullName string            `json:"full_name" validate:"required" example:"Chesta Ardiona"`
+	Email    string            `json:"email" validate:"required,email" example:"chestaardi4@gmail.com"`
+	Password string            `json:"password" validate:"required,min=8" example:"super.secret871798"` // initial password of student
+	Gender   models.UserGender `json:"gender" validate:"required,user_gender"`
+	Phone    string            `json:"phone" validate:"required" example:"+6281234567890"`
+
+	ClassID   string   `json:"class_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ParentIDs []string `json:"parent_ids" validate:"required,min=2,max=2,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6,479b5b5f-81b1-4669-91a5-b5bf69e597c7"`
+	NISN      string   `validate:"required" example:"0091913711"`
+
+	OriginSchool     string                      `json:"origin_school" validate:"required" example:"SMK Negeri 1 Jakarta"`
+	Reason           string                      `json:"reason" validate:"required" example:"parents relocation"`
+	TransferredAt    time.Time                   `json:"transferred_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	PriorGrades      []models.TransferGrade      `json:"prior_grades" validate:"dive"`
+	PriorAttendances []models.TransferAttendance `json:"prior_attendances" validate:"dive"`
+}
+
+type RequestTransferOut struct {
+	ID                string    `uri:"id" validate:"required,uuid4" swaggerignore:"true"` // student id
+	DestinationSchool string    `json:"destination_school" validate:"required" example:"SMA Negeri 3 Bandung"`
+	Reason            string    `json:"reason" validate:"required" example:"parents relocation"`
+	TransferredAt     time.Time `json:"transferred_at" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+type RequestGetTransfer struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetTransfers struct {
+	Offset    int                      `form:"offset" example:"10"`
+	Query     string                   `form:"q" example:"chesta"` // match student name or NISN
+	Direction models.TransferDirection `form:"direction" validate:"omitempty,transfer_direction"`
+}
+
+type ResponseTransfer struct {
+	Transfer *models.StudentTransfer `json:"transfer"`
+	Letter   models.TransferLetter   `json:"letter"`
+}
+
+// ResponseStudentIdentity is identity of student shown on printable documents
+type ResponseStudentIdentity struct {
+	StudentID string `json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	NISN      string `json:"nisn" example:"0091913711"`
+	FullName  string `json:"full_name" example:"Chesta Ardiona"`
+	ClassName string `json:"class_name" example:"10 TJKT 3"`
+}
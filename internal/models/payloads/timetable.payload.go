@@ -0,0 +1,119 @@
+package payloads
+
+import (
+	"school-information-system/internal/models"
+	"time"
+)
+
+type RequestCreateTimetableSlot struct {
+	ClassID      string `json:"class_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	SubjectID    string `json:"subject_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	TeacherID    string `json:"teacher_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	RoomID       string `json:"room_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // empty to use home room of the class
+	AcademicYear string `json:"academic_year" validate:"required,academic_year" example:"2025/2026"`
+	Semester     int    `json:"semester" validate:"required,min=1,max=2" example:"1"`
+	Weekday      int    `json:"weekday" validate:"required,min=1,max=6" example:"1"` // 1 (monday) until 6 (saturday)
+	StartTime    string `json:"start_time" validate:"required,datetime=15:04" example:"07:00"`
+	EndTime      string `json:"end_time" validate:"required,datetime=15:04" example:"08:30"`
+}
+
+type RequestGetTimetableSlot struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+// RequestGetTimetableSlots gets whole timetable of a term, filter by class, teacher or room to get their weekly timetable
+type RequestGetTimetableSlots struct {
+	AcademicYear string `form:"academic_year" validate:"required,academic_year" example:"2025/2026"`
+	Semester     int    `form:"semester" validate:"required,min=1,max=2" example:"1"`
+	ClassID      string `form:"class_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	TeacherID    string `form:"teacher_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	RoomID       string `form:"room_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Weekday      int    `form:"weekday" validate:"omitempty,min=1,max=6" example:"1"`
+}
+
+type RequestUpdateTimetableSlot struct {
+	ID           string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	ClassID      string `json:"class_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	SubjectID    string `json:"subject_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	TeacherID    string `json:"teacher_id" validate:"required,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	RoomID       string `json:"room_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	AcademicYear string `json:"academic_year" validate:"required,academic_year" example:"2025/2026"`
+	Semester     int    `json:"semester" validate:"required,min=1,max=2" example:"1"`
+	Weekday      int    `json:"weekday" validate:"required,min=1,max=6" example:"1"`
+	StartTime    string `json:"start_time" validate:"required,datetime=15:04" example:"07:00"`
+	EndTime      string `json:"end_time" validate:"required,datetime=15:04" example:"08:30"`
+}
+
+type RequestDeleteTimetableSlot struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+// RequestCreateTeachingJournal logs a lesson, class and subject are taken from the slot if slot is filled
+type RequestCreateTeachingJournal struct {
+	SlotID          string    `json:"slot_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // empty for lesson outside timetable
+	ClassID         string    `json:"class_id" validate:"required_without=SlotID,omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	SubjectID       string    `json:"subject_id" validate:"required_without=SlotID,omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Date            time.Time `json:"date" validate:"required" example:"2006-01-02T15:04:05Z07:00"`
+	Topic           string    `json:"topic" validate:"required" example:"subnetting IPv4"`
+	Method          string    `json:"method" example:"discussion and practice"`
+	AttendanceCount int       `json:"attendance_count" validate:"min=0" example:"34"`
+	Notes           string    `json:"notes" example:"3 students sick"`
+}
+
+type RequestGetTeachingJournal struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetTeachingJournals struct {
+	Offset    int    `form:"offset" example:"10"`
+	TeacherID string `form:"teacher_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ClassID   string `form:"class_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	SubjectID string `form:"subject_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	From      string `form:"from" validate:"omitempty,datetime=2006-01-02" example:"2006-01-02"`
+	To        string `form:"to" validate:"omitempty,datetime=2006-01-02" example:"2006-01-02"`
+}
+
+type RequestUpdateTeachingJournal struct {
+	ID              string `uri:"id" validate:"required,uuid4" swaggerignore:"true"`
+	Topic           string `json:"topic" validate:"required" example:"subnetting IPv4"`
+	Method          string `json:"method" example:"discussion and practice"`
+	AttendanceCount int    `json:"attendance_count" validate:"min=0" example:"34"`
+	Notes           string `json:"notes" example:"3 students sick"`
+}
+
+type RequestDeleteTeachingJournal struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetJournalCompliance struct {
+	From      string `form:"from" validate:"required,datetime=2006-01-02" example:"2006-01-02"`
+	To        string `form:"to" validate:"required,datetime=2006-01-02" example:"2006-01-02"`
+	TeacherID string `form:"teacher_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ClassID   string `form:"class_id" validate:"omitempty,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
+
+// ResponseJournalCompliance compares scheduled sessions of timetable with teaching journals in a date range
+type ResponseJournalCompliance struct {
+	From      string                      `json:"from" example:"2006-01-02"`
+	To        string                      `json:"to" example:"2006-01-02"`
+	Scheduled int                         `json:"scheduled" example:"120"` // sessions held by timetable, up to today
+	Filled    int                         `json:"filled" example:"112"`    // sessions with journal
+	Teachers  []ResponseTeacherCompliance `json:"teachers"`
+	Missing   []ResponseMissingJournal    `json:"missing"`
+}
+
+type ResponseTeacherCompliance struct {
+	TeacherID string `json:"teacher_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	FullName  string `json:"full_name" example:"Chesta Ardiona"`
+	Scheduled int    `json:"scheduled" example:"20"`
+	Filled    int    `json:"filled" example:"18"`
+}
+
+// ResponseMissingJournal is a scheduled session without teaching journal
+type ResponseMissingJournal struct {
+	Date        string                `json:"date" example:"2006-01-02"`
+	ClassName   string                `json:"class_name" example:"10 TJKT 3"`
+	SubjectName string                `json:"subject_name" example:"informatika"`
+	TeacherName string                `json:"teacher_name" example:"Chesta Ardiona"`
+	Slot        *models.TimetableSlot `json:"slot"`
+}
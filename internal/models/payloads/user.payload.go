@@ -0,0 +1,51 @@
+package payloads
+
+import "school-information-system/internal/models"
+
+// RequestGetUsers gets users of roles which current admin can read, all readable roles if role is empty
+type RequestGetUsers struct {
+	Offset   int               `form:"offset" example:"10"`
+	Query    string            `form:"q" example:"chesta"` // search full name or email
+	Role     models.UserRole   `form:"role" validate:"omitempty,user_role"`
+	Gender   models.UserGender `form:"gender" validate:"omitempty,user_gender"`
+	Archived bool              `form:"archived" example:"false"` // true to only get archived users
+}
+
+type RequestGetUser struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestArchiveUser struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestRestoreUser struct {
+	ID string `uri:"id" validate:"required,uuid4"`
+}
+
+type RequestGetPendingUsers struct {
+	Offset int    `form:"offset" example:"10"`
+	Query  string `form:"q" example:"chesta"` // search full name or email
+}
+
+type RequestRejectPendingUsers struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100,dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
+
+// ResponsePendingUser is self-registered user waiting for activation
+type ResponsePendingUser struct {
+	models.User
+	WaitingDays int                     `json:"waiting_days" example:"3"` // days since sign up
+	Duplicates  []ResponseDuplicateHint `json:"duplicates"`               // existing parents or students which might be the same person
+}
+
+type ResponseDuplicateHint struct {
+	Kind      string `json:"kind" example:"parent"` // "parent", "student"
+	ID        string `json:"id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	FullName  string `json:"full_name" example:"Chesta Ardiona"`
+	MatchedBy string `json:"matched_by" example:"phone"` // "phone", "name"
+}
+
+type ResponseRejectPendingUsers struct {
+	Rejected int `json:"rejected" example:"5"`
+}
@@ -1,11 +1,213 @@
 package models
 
+import (
+	"slices"
+	"time"
+)
+
 type Permission struct {
 	Id
 	Name        string             `gorm:"unique;not null" json:"name" example:"role full manage"`
 	Resource    PermissionResource `gorm:"type:permission_resource;not null" json:"resource"`
 	Description string             `gorm:"not null" json:"description" example:"Full access to manage role of users"`
 	Actions     []PermissionAction `gorm:"type:text;serializer:json;not null" json:"actions"` // []("create", "read", "update", "delete")
+	// limits permission to some classes, only for class-bound resources
+	Scope PermissionScope `gorm:"embedded;embeddedPrefix:scope_" json:"scope"`
+
+	Timestamp
+}
+
+// ScopableResources are resources bound to classes, permission on them can be limited by scope
+var ScopableResources = []PermissionResource{ResourceClass, ResourceStudent}
+
+// PermissionScope limits permission to classes of grades and majors or listed classes, empty scope applies to the whole resource
+type PermissionScope struct {
+	Grades   []int    `gorm:"type:text;serializer:json;default:'[]';not null" json:"grades" validate:"dive,min=1,max=12" example:"10"`
+	Majors   []string `gorm:"type:text;serializer:json;default:'[]';not null" json:"majors" validate:"dive,required" example:"TJKT"`
+	ClassIDs []string `gorm:"type:text;serializer:json;default:'[]';not null" json:"class_ids" validate:"dive,uuid4" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+}
+
+func (s *PermissionScope) IsEmpty() bool {
+	return len(s.Grades) == 0 && len(s.Majors) == 0 && len(s.ClassIDs) == 0
+}
+
+// Contains reports whether the class is listed or matches both grades and majors of the scope, empty grades or majors match any
+func (s *PermissionScope) Contains(class *Class) bool {
+	if slices.Contains(s.ClassIDs, class.ID) {
+		return true
+	}
+	if len(s.Grades) == 0 && len(s.Majors) == 0 {
+		return false
+	}
+	return (len(s.Grades) == 0 || slices.Contains(s.Grades, class.Grade)) &&
+		(len(s.Majors) == 0 || slices.Contains(s.Majors, class.Major))
+}
+
+// ClassScope is union of scopes of permissions granting an action, nil scope applies to the whole resource
+type ClassScope []PermissionScope
+
+func (s ClassScope) Contains(class *Class) bool {
+	if s == nil {
+		return true
+	}
+	for _, scope := range s {
+		if scope.Contains(class) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeContextKey returns key of gin context holding class scope of the action, set by permission middleware
+func ScopeContextKey(resource PermissionResource, action PermissionAction) string {
+	return "scope:" + string(resource) + "." + string(action)
+}
+
+// RoleSkips holds roles which skip permission check of the action on resource at some endpoints,
+// endpoints may skip fewer roles but never a role not listed here
+var RoleSkips = map[PermissionResource]map[PermissionAction][]UserRole{
+	ResourceTeacher: {ActionRead: {RoleTeacher}},
+	ResourceSubject: {ActionRead: {RoleTeacher}},
+	ResourceClass:   {ActionRead: {RoleTeacher}},
+	ResourceAlumni:  {ActionRead: {RoleTeacher, RoleAlumni}},
+	ResourceMaterial: {
+		ActionRead:   {RoleTeacher, RoleStudent},
+		ActionDelete: {RoleTeacher},
+	},
+	ResourceAssignment: {
+		ActionRead:   {RoleTeacher, RoleStudent},
+		ActionUpdate: {RoleTeacher},
+		ActionDelete: {RoleTeacher},
+	},
+	ResourceQuiz: {
+		ActionRead:   {RoleTeacher, RoleStudent},
+		ActionUpdate: {RoleTeacher},
+		ActionDelete: {RoleTeacher},
+	},
+	ResourceQuestionBank: {
+		ActionCreate: {RoleTeacher},
+		ActionRead:   {RoleTeacher},
+		ActionUpdate: {RoleTeacher},
+		ActionDelete: {RoleTeacher},
+	},
+	ResourceExam: {ActionRead: {RoleTeacher, RoleStudent}},
+	ResourceRoom: {
+		ActionCreate: {RoleTeacher},
+		ActionRead:   {RoleTeacher},
+		ActionUpdate: {RoleTeacher},
+	},
+	ResourceHealth: {ActionRead: {RoleTeacher}},
+	ResourceAchievement: {
+		ActionCreate: {RoleTeacher},
+		ActionRead:   {RoleTeacher, RoleStudent},
+	},
+	ResourceDormitory: {
+		ActionCreate: {RoleTeacher},
+		ActionRead:   {RoleTeacher},
+	},
+	ResourceInternship: {
+		ActionRead:   {RoleTeacher, RoleStudent},
+		ActionUpdate: {RoleTeacher},
+		ActionDelete: {RoleTeacher},
+	},
+	ResourceTimetable: {
+		ActionRead:   {RoleTeacher},
+		ActionDelete: {RoleTeacher},
+	},
+}
+
+// EffectiveAction is whether an action on resource is allowed, as checked by permission middleware
+type EffectiveAction struct {
+	Allowed bool `json:"allowed" example:"true"`
+	// role which skips permission check of the action on at least one endpoint, empty if allowed by permission
+	Role  UserRole   `json:"role,omitempty" example:"teacher"`
+	Scope ClassScope `json:"scope"` // class scope of permission, null if it applies to the whole resource
+}
+
+// PermissionMatrix is effective actions of every resource
+type PermissionMatrix map[PermissionResource]map[PermissionAction]EffectiveAction
+
+// NewPermissionMatrix computes effective actions for user holding the roles, preferred role first.
+// Admin profile is nil if user doesn't hold admin role, grants and bundles must be preloaded with permissions
+func NewPermissionMatrix(roles []UserRole, admin *Admin) PermissionMatrix {
+	if !slices.Contains(roles, RoleAdmin) {
+		admin = nil
+	}
+
+	matrix := make(PermissionMatrix, len(PermissionResources))
+	for _, resource := range PermissionResources {
+		actions := make(map[PermissionAction]EffectiveAction, len(PermissionActions))
+		for _, action := range PermissionActions {
+			var effective EffectiveAction
+			if i := slices.IndexFunc(roles, func(r UserRole) bool { return slices.Contains(RoleSkips[resource][action], r) }); i >= 0 {
+				effective = EffectiveAction{Allowed: true, Role: roles[i]}
+			} else if admin != nil {
+				if _, missing := admin.MissingAction(resource, action); !missing {
+					effective = EffectiveAction{Allowed: true, Scope: admin.ScopeOf(resource, action)}
+				}
+			}
+			actions[action] = effective
+		}
+		matrix[resource] = actions
+	}
+	return matrix
+}
+
+// ResourceOfRole returns permission resource which manages users of the role, users without role are managed by role resource
+func ResourceOfRole(role UserRole) PermissionResource {
+	switch role {
+	case RoleStudent:
+		return ResourceStudent
+	case RoleTeacher:
+		return ResourceTeacher
+	case RoleAdmin:
+		return ResourceAdmin
+	case RoleAlumni:
+		return ResourceAlumni
+	}
+	return ResourceRole
+}
+
+// AdminPermission is permission granted directly to an admin, grant may be limited to a period
+type AdminPermission struct {
+	AdminID      string      `gorm:"primaryKey" json:"admin_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Admin        *Admin      `json:"admin,omitempty" swaggerignore:"true"`
+	PermissionID string      `gorm:"primaryKey" json:"permission_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Permission   *Permission `json:"permission,omitempty" swaggerignore:"true"`
+	// user ID of admin who granted the permission, empty for permissions granted on admin initiation
+	GrantedByID *string    `gorm:"index" json:"granted_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ValidFrom   *time.Time `json:"valid_from" example:"2006-01-02T15:04:05Z07:00"`               // null to be valid since granted
+	ValidUntil  *time.Time `gorm:"index" json:"valid_until" example:"2006-01-02T15:04:05Z07:00"` // null to never expire, revoked by cron job after expired
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at" example:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ActiveAt reports whether the grant is valid at the time
+func (g *AdminPermission) ActiveAt(t time.Time) bool {
+	return (g.ValidFrom == nil || !g.ValidFrom.After(t)) && (g.ValidUntil == nil || g.ValidUntil.After(t))
+}
+
+// PermissionBundle is named set of permissions granted to admins at once, editing bundle applies to every holder
+type PermissionBundle struct {
+	Id
+	Name        string        `gorm:"unique;not null" json:"name" example:"curriculum staff"`
+	Description string        `gorm:"not null" json:"description" example:"Manage subjects, classes and timetable"`
+	Permissions []*Permission `gorm:"many2many:bundle_permissions" json:"permissions,omitempty" swaggerignore:"true"`
 
 	Timestamp
 }
+
+// PermissionAudit records change of permissions held by admins, names are kept in case related records are deleted
+type PermissionAudit struct {
+	Id
+	Action PermissionAuditAction `gorm:"type:permission_audit_action;not null" json:"action"`
+	// user ID of admin who made the change, empty for change made by the system such as expired grant
+	ActorID string `gorm:"index;not null" json:"actor_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	// empty for change of bundle which applies to every holder
+	TargetAdminID  *string `gorm:"index" json:"target_admin_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	PermissionID   *string `gorm:"index" json:"permission_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	PermissionName string  `json:"permission_name,omitempty" example:"role full manage"`
+	BundleID       *string `gorm:"index" json:"bundle_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	BundleName     string  `json:"bundle_name,omitempty" example:"curriculum staff"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index;not null" json:"created_at" example:"2006-01-02T15:04:05Z07:00"`
+}
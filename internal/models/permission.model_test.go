@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdminPermissionActiveAt(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		grant AdminPermission
+		want  bool
+	}{
+		{"unlimited", AdminPermission{}, true},
+		{"within period", AdminPermission{ValidFrom: &past, ValidUntil: &future}, true},
+		{"starts now", AdminPermission{ValidFrom: &now}, true},
+		{"not started", AdminPermission{ValidFrom: &future}, false},
+		{"ends now", AdminPermission{ValidUntil: &now}, false},
+		{"expired", AdminPermission{ValidUntil: &past}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.grant.ActiveAt(now); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPermissionScopeContains(t *testing.T) {
+	class := &Class{Id: Id{ID: "class-a"}, Grade: 10, Major: "TJKT"}
+
+	tests := []struct {
+		name  string
+		scope PermissionScope
+		want  bool
+	}{
+		{"empty", PermissionScope{}, false},
+		{"listed", PermissionScope{ClassIDs: []string{"class-a"}}, true},
+		{"not listed", PermissionScope{ClassIDs: []string{"class-b"}}, false},
+		{"grade", PermissionScope{Grades: []int{10, 11}}, true},
+		{"major", PermissionScope{Majors: []string{"TJKT"}}, true},
+		{"grade and major", PermissionScope{Grades: []int{10}, Majors: []string{"TJKT"}}, true},
+		{"grade without major", PermissionScope{Grades: []int{10}, Majors: []string{"AKL"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scope.Contains(class); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewPermissionMatrix(t *testing.T) {
+	admin := &Admin{Grants: []*AdminPermission{
+		{Permission: newPermission("class", ResourceClass, PermissionScope{Grades: []int{10}}, ActionRead)},
+		{Permission: newPermission("room", ResourceRoom, PermissionScope{}, ActionDelete)},
+	}}
+
+	t.Run("admin", func(t *testing.T) {
+		matrix := NewPermissionMatrix([]UserRole{RoleAdmin}, admin)
+
+		if got := matrix[ResourceClass][ActionRead]; !got.Allowed || got.Role != "" || len(got.Scope) != 1 {
+			t.Errorf("expected class read allowed by scoped permission, got %+v", got)
+		}
+		if got := matrix[ResourceRoom][ActionDelete]; !got.Allowed || got.Scope != nil {
+			t.Errorf("expected room delete allowed on whole resource, got %+v", got)
+		}
+		if got := matrix[ResourceRoom][ActionRead]; got.Allowed {
+			t.Errorf("expected room read denied, got %+v", got)
+		}
+	})
+
+	t.Run("preferred role skipping the check", func(t *testing.T) {
+		matrix := NewPermissionMatrix([]UserRole{RoleStudent, RoleTeacher, RoleAdmin}, admin)
+
+		if got := matrix[ResourceMaterial][ActionRead]; !got.Allowed || got.Role != RoleStudent {
+			t.Errorf("expected material read skipped by student, got %+v", got)
+		}
+		if got := matrix[ResourceRoom][ActionRead]; !got.Allowed || got.Role != RoleTeacher {
+			t.Errorf("expected room read skipped by teacher, got %+v", got)
+		}
+		if got := matrix[ResourceRoom][ActionDelete]; !got.Allowed || got.Role != "" {
+			t.Errorf("expected room delete allowed by permission, got %+v", got)
+		}
+	})
+
+	t.Run("admin profile without admin role", func(t *testing.T) {
+		matrix := NewPermissionMatrix([]UserRole{RoleTeacher}, admin)
+
+		if got := matrix[ResourceRoom][ActionDelete]; got.Allowed {
+			t.Errorf("expected room delete denied, got %+v", got)
+		}
+		if len(matrix) != len(PermissionResources) || len(matrix[ResourceRole]) != len(PermissionActions) {
+			t.Error("expected every action of every resource in matrix")
+		}
+	})
+}
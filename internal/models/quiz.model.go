@@ -0,0 +1,184 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Quiz struct {
+	Id
+	Title       string `gorm:"not null" json:"title" example:"Subnetting daily quiz"`
+	Description string `json:"description" example:"chapter 3"`
+
+	SubjectID string   `gorm:"index;not null" json:"subject_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Subject   *Subject `gorm:"constraint:OnDelete:CASCADE" json:"subject,omitempty" swaggerignore:"true"`
+	// nullable, set to null if the teacher is deleted
+	TeacherID *string  `json:"teacher_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Teacher   *Teacher `gorm:"constraint:OnDelete:SET NULL" json:"teacher,omitempty" swaggerignore:"true"`
+
+	TimeLimit        int  `gorm:"not null" json:"time_limit" example:"30"`  // in minutes, 0 means limited by window only
+	MaxAttempts      int  `gorm:"not null" json:"max_attempts" example:"1"` // 0 means unlimited
+	ShuffleQuestions bool `gorm:"not null" json:"shuffle_questions" example:"true"`
+	ShuffleOptions   bool `gorm:"not null" json:"shuffle_options" example:"true"`
+
+	Questions []QuizQuestion `gorm:"constraint:OnDelete:CASCADE" json:"questions,omitempty"`
+	Windows   []QuizWindow   `gorm:"constraint:OnDelete:CASCADE" json:"windows,omitempty"`
+
+	Timestamp
+}
+
+type QuestionOption struct {
+	Key  string `json:"key" validate:"required" example:"a"`
+	Text string `json:"text" validate:"required" example:"255.255.255.0"`
+}
+
+// QuestionContent is gradable content of question, shared by quiz and bank questions
+type QuestionContent struct {
+	Type    QuestionType     `gorm:"type:question_type;not null" json:"type"` // "multiple_choice", "multiple_answer", "short_answer", "essay"
+	Prompt  string           `gorm:"not null" json:"prompt" example:"subnet mask of /24 network?"`
+	Options []QuestionOption `gorm:"serializer:json" json:"options,omitempty"` // empty for short answer and essay
+	// keys of correct options, or accepted answers of short answer. Empty for essay, hidden from student
+	Answers []string `gorm:"serializer:json" json:"answers,omitempty" example:"a"`
+	Points  float64  `gorm:"not null" json:"points" example:"10"`
+}
+
+// CheckAnswerKey validates options and answers match question type
+func (q *QuestionContent) CheckAnswerKey() error {
+	switch q.Type {
+	case QuestionMultipleChoice, QuestionMultipleAnswer:
+		if len(q.Options) < 2 {
+			return errors.New("choice question must have at least 2 options")
+		}
+		keys := make([]string, 0, len(q.Options))
+		for _, opt := range q.Options {
+			if slices.Contains(keys, opt.Key) {
+				return errors.New("option keys must be unique")
+			}
+			keys = append(keys, opt.Key)
+		}
+		if len(q.Answers) == 0 {
+			return errors.New("choice question must have correct answer")
+		}
+		if q.Type == QuestionMultipleChoice && len(q.Answers) != 1 {
+			return errors.New("multiple choice question must have exactly 1 correct answer")
+		}
+		for _, ans := range q.Answers {
+			if !slices.Contains(keys, ans) {
+				return errors.New("answers must be keys of options")
+			}
+		}
+	case QuestionShortAnswer:
+		if len(q.Answers) == 0 {
+			return errors.New("short answer question must have accepted answers")
+		}
+	case QuestionEssay:
+		if len(q.Answers) > 0 || len(q.Options) > 0 {
+			return errors.New("essay question can not have options or answers")
+		}
+	}
+	return nil
+}
+
+// AutoGrade returns points of response, ok is false if question must be graded manually
+func (q *QuestionContent) AutoGrade(response []string) (points float64, ok bool) {
+	switch q.Type {
+	case QuestionMultipleChoice, QuestionMultipleAnswer:
+		// compare as sets so repeated keys can't stand in for missing ones
+		given := slices.Compact(slices.Sorted(slices.Values(response)))
+		correct := slices.Compact(slices.Sorted(slices.Values(q.Answers)))
+		if !slices.Equal(given, correct) {
+			return 0, true
+		}
+		return q.Points, true
+	case QuestionShortAnswer:
+		if len(response) != 1 {
+			return 0, true
+		}
+		given := strings.TrimSpace(response[0])
+		for _, ans := range q.Answers {
+			if strings.EqualFold(given, strings.TrimSpace(ans)) {
+				return q.Points, true
+			}
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+type QuizQuestion struct {
+	Id
+	QuizID string `gorm:"index;not null" json:"quiz_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	QuestionContent
+	Position int `gorm:"not null" json:"position" example:"1"`
+	// copied from this bank version, nullable, set to null if the bank question is deleted
+	BankVersionID *string              `json:"bank_version_id,omitempty" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	BankVersion   *BankQuestionVersion `gorm:"constraint:OnDelete:SET NULL" json:"-" swaggerignore:"true"`
+
+	Timestamp
+}
+
+// QuizWindow is period a class can attempt the quiz
+type QuizWindow struct {
+	Id
+	QuizID  string    `gorm:"uniqueIndex:idx_quiz_window_class;not null" json:"quiz_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ClassID string    `gorm:"uniqueIndex:idx_quiz_window_class;not null" json:"class_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Class   *Class    `gorm:"constraint:OnDelete:CASCADE" json:"class,omitempty" swaggerignore:"true"`
+	StartAt time.Time `gorm:"not null" json:"start_at" example:"2006-01-02T15:04:05Z07:00"`
+	EndAt   time.Time `gorm:"not null" json:"end_at" example:"2006-01-02T15:04:05Z07:00"`
+
+	Timestamp
+}
+
+func (w *QuizWindow) IsOpen(at time.Time) bool {
+	return !at.Before(w.StartAt) && at.Before(w.EndAt)
+}
+
+type QuizAttempt struct {
+	Id
+	QuizID    string   `gorm:"index;not null" json:"quiz_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Quiz      *Quiz    `gorm:"constraint:OnDelete:CASCADE" json:"quiz,omitempty" swaggerignore:"true"`
+	StudentID string   `gorm:"index;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student   *Student `gorm:"constraint:OnDelete:CASCADE" json:"student,omitempty" swaggerignore:"true"`
+
+	// randomized order for the student, kept so the paper is stable across requests
+	QuestionOrder []string            `gorm:"serializer:json" json:"question_order"`
+	OptionOrder   map[string][]string `gorm:"serializer:json" json:"option_order"` // question id to option keys
+
+	StartedAt   time.Time  `gorm:"not null" json:"started_at" example:"2006-01-02T15:04:05Z07:00"`
+	Deadline    time.Time  `gorm:"not null" json:"deadline" example:"2006-01-02T15:04:05Z07:00"`
+	SubmittedAt *time.Time `json:"submitted_at" example:"2006-01-02T15:04:05Z07:00"`
+	// empty until every answer graded
+	Score    *float64 `json:"score" example:"85"`
+	MaxScore float64  `gorm:"not null" json:"max_score" example:"100"`
+
+	Answers []QuizAnswer `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"answers,omitempty"`
+
+	Timestamp
+}
+
+func (a *QuizAttempt) IsSubmitted() bool {
+	return a.SubmittedAt != nil
+}
+
+// Percentage of score to max score, used as gradebook value
+func (a *QuizAttempt) Percentage() float64 {
+	if a.Score == nil || a.MaxScore == 0 {
+		return 0
+	}
+	return *a.Score / a.MaxScore * 100
+}
+
+type QuizAnswer struct {
+	Id
+	AttemptID  string        `gorm:"uniqueIndex:idx_attempt_question;not null" json:"attempt_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	QuestionID string        `gorm:"uniqueIndex:idx_attempt_question;not null" json:"question_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Question   *QuizQuestion `gorm:"constraint:OnDelete:CASCADE" json:"question,omitempty" swaggerignore:"true"`
+	Response   []string      `gorm:"serializer:json" json:"response" example:"a"` // option keys, or single text of short answer and essay
+	// empty until graded, essay is graded manually
+	Points   *float64 `json:"points" example:"10"`
+	Feedback string   `json:"feedback" example:"explain more"`
+
+	Timestamp
+}
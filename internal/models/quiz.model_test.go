@@ -0,0 +1,40 @@
+package models
+
+import "testing"
+
+func TestQuestionContentAutoGrade(t *testing.T) {
+	multipleAnswer := QuestionContent{Type: QuestionMultipleAnswer, Answers: []string{"a", "b"}, Points: 10}
+	multipleChoice := QuestionContent{Type: QuestionMultipleChoice, Answers: []string{"c"}, Points: 5}
+	shortAnswer := QuestionContent{Type: QuestionShortAnswer, Answers: []string{"Jakarta"}, Points: 4}
+	essay := QuestionContent{Type: QuestionEssay, Points: 20}
+
+	tests := []struct {
+		name       string
+		question   QuestionContent
+		response   []string
+		wantPoints float64
+		wantOk     bool
+	}{
+		{"multiple answer correct", multipleAnswer, []string{"a", "b"}, 10, true},
+		{"multiple answer any order", multipleAnswer, []string{"b", "a"}, 10, true},
+		{"multiple answer duplicate", multipleAnswer, []string{"a", "a"}, 0, true},
+		{"multiple answer missing", multipleAnswer, []string{"a"}, 0, true},
+		{"multiple answer extra", multipleAnswer, []string{"a", "b", "c"}, 0, true},
+		{"multiple answer wrong", multipleAnswer, []string{"a", "c"}, 0, true},
+		{"multiple answer empty", multipleAnswer, nil, 0, true},
+		{"multiple choice correct", multipleChoice, []string{"c"}, 5, true},
+		{"multiple choice wrong", multipleChoice, []string{"a"}, 0, true},
+		{"short answer case and space insensitive", shortAnswer, []string{" jakarta "}, 4, true},
+		{"short answer wrong", shortAnswer, []string{"Bandung"}, 0, true},
+		{"short answer several responses", shortAnswer, []string{"Jakarta", "Bandung"}, 0, true},
+		{"essay graded manually", essay, []string{"long answer"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, ok := tt.question.AutoGrade(tt.response)
+			if points != tt.wantPoints || ok != tt.wantOk {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.wantPoints, tt.wantOk, points, ok)
+			}
+		})
+	}
+}
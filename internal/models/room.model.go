@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Room is physical room or facility of the school
+type Room struct {
+	Id
+	Code     string   `gorm:"uniqueIndex;not null" json:"code" example:"LAB-KOM-1"`
+	Name     string   `gorm:"not null" json:"name" example:"Lab Komputer 1"`
+	Building string   `gorm:"index;not null" json:"building" example:"Gedung B"`
+	Capacity int      `gorm:"not null" json:"capacity" example:"36"`
+	Type     RoomType `gorm:"type:room_type;not null" json:"type"` // "classroom", "lab", "hall"
+
+	Timestamp
+}
+
+// NeedsApproval reports whether booking of the room must be approved first
+func (r *Room) NeedsApproval() bool {
+	return r.Type == RoomLab || r.Type == RoomHall
+}
+
+type RoomBooking struct {
+	Id
+	RoomID  string    `gorm:"index;not null" json:"room_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Room    *Room     `gorm:"constraint:OnDelete:CASCADE" json:"room,omitempty" swaggerignore:"true"`
+	Purpose string    `gorm:"not null" json:"purpose" example:"praktikum jaringan 11 TJKT 2"`
+	StartAt time.Time `gorm:"index;not null" json:"start_at" example:"2006-01-02T15:04:05Z07:00"`
+	EndAt   time.Time `gorm:"index;not null" json:"end_at" example:"2006-01-02T15:04:05Z07:00"`
+
+	Status BookingStatus `gorm:"type:booking_status;index;not null" json:"status"` // "pending", "approved", "rejected", "cancelled"
+	// user who booked the room
+	BookedByID string `gorm:"index;not null" json:"booked_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	BookedBy   *User  `gorm:"constraint:OnDelete:CASCADE" json:"booked_by,omitempty" swaggerignore:"true"`
+	// nullable, set to null if the reviewer is deleted
+	ReviewedByID *string    `json:"reviewed_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	ReviewedBy   *User      `gorm:"constraint:OnDelete:SET NULL" json:"reviewed_by,omitempty" swaggerignore:"true"`
+	ReviewedAt   *time.Time `json:"reviewed_at" example:"2006-01-02T15:04:05Z07:00"`
+	ReviewNote   string     `json:"review_note" example:"lab dipakai UKK"`
+
+	Timestamp
+}
+
+// Blocking reports whether booking occupies the room
+func (b *RoomBooking) Blocking() bool {
+	return b.Status == BookingPending || b.Status == BookingApproved
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// ScholarshipProgram is a scholarship given to students, internal or from outside of school such as KIP or PIP
+type ScholarshipProgram struct {
+	Id
+	Name        string            `gorm:"unique;not null" json:"name" example:"Program Indonesia Pintar"`
+	Source      ScholarshipSource `gorm:"type:scholarship_source;index;not null" json:"source"` // "internal", "government", "private"
+	Provider    string            `json:"provider" example:"Kemendikbudristek"`
+	Eligibility string            `gorm:"type:text" json:"eligibility" example:"holder of KIP card or from family registered in DTKS"`
+	// amount given to each recipient every term in rupiah, 0 if the program only gives fee discount
+	AmountPerTerm int64 `gorm:"not null;default:0" json:"amount_per_term" example:"500000"`
+	// discount percentage of school fee for recipients, applied by billing
+	FeeDiscount int  `gorm:"not null;default:0" json:"fee_discount" example:"50"`
+	Active      bool `gorm:"not null;default:true" json:"active" example:"true"`
+
+	Timestamp
+}
+
+// ScholarshipRecipient is a student receiving a scholarship program in a term
+type ScholarshipRecipient struct {
+	Id
+	ProgramID    string              `gorm:"uniqueIndex:idx_scholarship_recipient;not null" json:"program_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Program      *ScholarshipProgram `gorm:"constraint:OnDelete:CASCADE" json:"program,omitempty"`
+	StudentID    string              `gorm:"uniqueIndex:idx_scholarship_recipient;index;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student      *Student            `gorm:"constraint:OnDelete:CASCADE" json:"student,omitempty" swaggerignore:"true"`
+	AcademicYear string              `gorm:"uniqueIndex:idx_scholarship_recipient;not null" json:"academic_year" example:"2025/2026"`
+	Semester     int                 `gorm:"uniqueIndex:idx_scholarship_recipient;not null" json:"semester" example:"1"`
+	Note         string              `json:"note" example:"recommended by homeroom teacher"`
+	// nullable, set to null if the user is deleted
+	AwardedByID *string `json:"awarded_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	AwardedBy   *User   `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+
+	Disbursements []ScholarshipDisbursement `gorm:"foreignKey:RecipientID;constraint:OnDelete:CASCADE" json:"disbursements,omitempty"`
+
+	Timestamp
+}
+
+// ScholarshipDisbursement is a payment of scholarship to a recipient
+type ScholarshipDisbursement struct {
+	Id
+	RecipientID string    `gorm:"index;not null" json:"recipient_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Amount      int64     `gorm:"not null" json:"amount" example:"500000"`
+	DisbursedAt time.Time `gorm:"not null" json:"disbursed_at" example:"2006-01-02T15:04:05Z07:00"`
+	Reference   string    `json:"reference" example:"SP2D/2025/0012"` // transfer or disbursement letter number
+	Note        string    `json:"note" example:"paid through BRI account of the student"`
+	// nullable, set to null if the user is deleted
+	RecordedByID *string `json:"recorded_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	RecordedBy   *User   `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+
+	Timestamp
+}
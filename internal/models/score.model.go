@@ -0,0 +1,20 @@
+package models
+
+// Score is gradebook entry of student, one entry per graded source
+type Score struct {
+	Id
+	StudentID string   `gorm:"uniqueIndex:idx_score_source;not null" json:"student_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Student   *Student `gorm:"constraint:OnDelete:CASCADE" json:"student,omitempty" swaggerignore:"true"`
+	SubjectID string   `gorm:"index;not null" json:"subject_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Subject   *Subject `gorm:"constraint:OnDelete:CASCADE" json:"subject,omitempty" swaggerignore:"true"`
+
+	Source   ScoreSource `gorm:"type:score_source;uniqueIndex:idx_score_source;not null" json:"source"`                                 // "assignment", "quiz"
+	SourceID string      `gorm:"uniqueIndex:idx_score_source;not null" json:"source_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // id of assignment or quiz
+	Value    float64     `gorm:"not null" json:"value" example:"87.5"`
+	Note     string      `json:"note" example:"good work"`
+	// nullable, set to null if the teacher is deleted
+	GradedByID *string  `json:"graded_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	GradedBy   *Teacher `gorm:"constraint:OnDelete:SET NULL" json:"graded_by,omitempty" swaggerignore:"true"`
+
+	Timestamp
+}
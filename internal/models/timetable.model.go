@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// TimetableSlot is a weekly lesson of a class in a term
+type TimetableSlot struct {
+	Id
+	ClassID   string   `gorm:"index;not null" json:"class_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Class     *Class   `gorm:"constraint:OnDelete:CASCADE" json:"class,omitempty" swaggerignore:"true"`
+	SubjectID string   `gorm:"index;not null" json:"subject_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Subject   *Subject `gorm:"constraint:OnDelete:CASCADE" json:"subject,omitempty" swaggerignore:"true"`
+	TeacherID string   `gorm:"index;not null" json:"teacher_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Teacher   *Teacher `gorm:"constraint:OnDelete:CASCADE" json:"teacher,omitempty" swaggerignore:"true"`
+	// nullable, empty to use home room of the class, set to null if the room is deleted
+	RoomID *string `gorm:"index" json:"room_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Room   *Room   `gorm:"constraint:OnDelete:SET NULL" json:"room,omitempty" swaggerignore:"true"`
+
+	AcademicYear string       `gorm:"index:idx_timetable_slot_term;not null" json:"academic_year" example:"2025/2026"`
+	Semester     int          `gorm:"index:idx_timetable_slot_term;not null" json:"semester" example:"1"`
+	Weekday      time.Weekday `gorm:"not null" json:"weekday" example:"1" swaggertype:"integer"` // 1 (monday) until 6 (saturday)
+	StartTime    string       `gorm:"size:5;not null" json:"start_time" example:"07:00"`         // 24 hour clock of the school
+	EndTime      string       `gorm:"size:5;not null" json:"end_time" example:"08:30"`
+
+	Timestamp
+}
+
+// HeldOn reports whether the slot is held on the date
+func (s *TimetableSlot) HeldOn(date time.Time) bool {
+	return date.Weekday() == s.Weekday && AcademicYearOf(date) == s.AcademicYear && SemesterOf(date) == s.Semester
+}
+
+// OverlapsPeriod reports whether a lesson of the slot overlaps the period, lesson time is clock of the location
+func (s *TimetableSlot) OverlapsPeriod(startAt, endAt time.Time, loc *time.Location) bool {
+	startClock, err := time.Parse("15:04", s.StartTime)
+	if err != nil {
+		return false
+	}
+	endClock, err := time.Parse("15:04", s.EndTime)
+	if err != nil {
+		return false
+	}
+
+	startAt, endAt = startAt.In(loc), endAt.In(loc)
+	year, month, day := startAt.Date()
+	for date := time.Date(year, month, day, 0, 0, 0, 0, loc); date.Before(endAt); date = date.AddDate(0, 0, 1) {
+		if !s.HeldOn(date) {
+			continue
+		}
+		lessonStart := date.Add(time.Duration(startClock.Hour())*time.Hour + time.Duration(startClock.Minute())*time.Minute)
+		lessonEnd := date.Add(time.Duration(endClock.Hour())*time.Hour + time.Duration(endClock.Minute())*time.Minute)
+		if lessonStart.Before(endAt) && lessonEnd.After(startAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// TeachingJournal is record of a lesson taught by a teacher (jurnal mengajar)
+type TeachingJournal struct {
+	Id
+	TeacherID string   `gorm:"index;not null" json:"teacher_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Teacher   *Teacher `gorm:"constraint:OnDelete:CASCADE" json:"teacher,omitempty" swaggerignore:"true"`
+	ClassID   string   `gorm:"index;not null" json:"class_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Class     *Class   `gorm:"constraint:OnDelete:CASCADE" json:"class,omitempty" swaggerignore:"true"`
+	SubjectID string   `gorm:"index;not null" json:"subject_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Subject   *Subject `gorm:"constraint:OnDelete:CASCADE" json:"subject,omitempty" swaggerignore:"true"`
+	// nullable, empty for lesson outside timetable such as replacement lesson, set to null if the slot is deleted
+	SlotID *string        `gorm:"uniqueIndex:idx_teaching_journal_slot_date" json:"slot_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	Slot   *TimetableSlot `gorm:"constraint:OnDelete:SET NULL" json:"slot,omitempty" swaggerignore:"true"`
+
+	Date            time.Time `gorm:"type:date;index;uniqueIndex:idx_teaching_journal_slot_date;not null" json:"date" example:"2006-01-02T15:04:05Z07:00"`
+	Topic           string    `gorm:"not null" json:"topic" example:"subnetting IPv4"`
+	Method          string    `json:"method" example:"discussion and practice"`
+	AttendanceCount int       `gorm:"not null" json:"attendance_count" example:"34"` // number of students present
+	Notes           string    `json:"notes" example:"3 students sick"`
+
+	Timestamp
+}
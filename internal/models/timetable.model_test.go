@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimetableSlotOverlapsPeriod(t *testing.T) {
+	loc := time.FixedZone("WIB", 7*60*60)
+	// monday lesson of first semester 2025/2026
+	slot := &TimetableSlot{AcademicYear: "2025/2026", Semester: 1, Weekday: time.Monday, StartTime: "07:00", EndTime: "08:30"}
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name           string
+		startAt, endAt time.Time
+		want           bool
+	}{
+		{"during lesson", at(time.September, 1, 7, 30), at(time.September, 1, 9, 0), true},
+		{"covering lesson", at(time.September, 1, 6, 0), at(time.September, 1, 12, 0), true},
+		{"before lesson", at(time.September, 1, 6, 0), at(time.September, 1, 7, 0), false},
+		{"after lesson", at(time.September, 1, 8, 30), at(time.September, 1, 10, 0), false},
+		{"other weekday", at(time.September, 2, 7, 0), at(time.September, 2, 8, 0), false},
+		{"spanning days", at(time.August, 30, 12, 0), at(time.September, 1, 7, 15), true},
+		{"other term", at(time.March, 2, 7, 0), at(time.March, 2, 8, 0), false},
+		{"period in UTC", time.Date(2025, time.September, 1, 0, 30, 0, 0, time.UTC), time.Date(2025, time.September, 1, 1, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slot.OverlapsPeriod(tt.startAt, tt.endAt, loc); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTermPeriod(t *testing.T) {
+	start, end := TermPeriod("2025/2026", 1, time.UTC)
+	if !start.Equal(time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected first semester %s - %s", start, end)
+	}
+	start, end = TermPeriod("2025/2026", 2, time.UTC)
+	if !start.Equal(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected second semester %s - %s", start, end)
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+type StudentTransfer struct {
+	Id
+	Direction TransferDirection `gorm:"type:transfer_direction;not null" json:"direction"` // "in", "out"
+	// nullable, set to null when the user is purged
+	UserID *string `gorm:"index" json:"user_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"`
+	User   *User   `gorm:"constraint:OnDelete:SET NULL" json:"user,omitempty" swaggerignore:"true"`
+
+	// snapshot of student, kept after the user is purged
+	StudentName string `gorm:"not null" json:"student_name" example:"Chesta Ardiona"`
+	NISN        string `gorm:"not null" json:"NISN" example:"0091913711"`
+
+	// destination school if direction is out, origin school if direction is in
+	SchoolName    string    `gorm:"not null" json:"school_name" example:"SMK Negeri 1 Jakarta"`
+	Reason        string    `gorm:"not null" json:"reason" example:"parents relocation"`
+	LetterNumber  string    `gorm:"uniqueIndex;not null" json:"letter_number" example:"TRF/OUT/2026/479B5B5F"`
+	TransferredAt time.Time `gorm:"not null" json:"transferred_at" example:"2006-01-02T15:04:05Z07:00"`
+
+	// imported records of previous school, only filled if direction is in
+	PriorGrades      []TransferGrade      `gorm:"type:text;serializer:json" json:"prior_grades,omitempty"`
+	PriorAttendances []TransferAttendance `gorm:"type:text;serializer:json" json:"prior_attendances,omitempty"`
+	// data export of student, only filled if direction is out
+	Export *TransferExport `gorm:"type:text;serializer:json" json:"export,omitempty"`
+
+	ProcessedByID string `gorm:"not null" json:"processed_by_id" example:"479b5b5f-81b1-4669-91a5-b5bf69e597c6"` // user id of admin
+
+	Timestamp
+}
+
+type TransferGrade struct {
+	AcademicYear string  `json:"academic_year" validate:"required" example:"2025/2026"`
+	Semester     int     `json:"semester" validate:"required,min=1,max=2" example:"1"`
+	Grade        int     `json:"grade" validate:"required,min=1,max=12" example:"10"`
+	Subject      string  `json:"subject" validate:"required" example:"informatika"`
+	Score        float64 `json:"score" validate:"min=0,max=100" example:"87.5"`
+}
+
+type TransferAttendance struct {
+	AcademicYear string `json:"academic_year" validate:"required" example:"2025/2026"`
+	Semester     int    `json:"semester" validate:"required,min=1,max=2" example:"1"`
+	Present      int    `json:"present" validate:"min=0" example:"110"`
+	Sick         int    `json:"sick" validate:"min=0" example:"2"`
+	Permitted    int    `json:"permitted" validate:"min=0" example:"1"`
+	Absent       int    `json:"absent" validate:"min=0" example:"0"`
+}
+
+// TransferExport is the data of student that handed over to the destination school
+type TransferExport struct {
+	FullName    string               `json:"full_name" example:"Chesta Ardiona"`
+	Email       string               `json:"email" example:"chestaardi4@gmail.com"`
+	Phone       string               `json:"phone" example:"+6281234567890"`
+	Gender      UserGender           `json:"gender"`
+	NISN        string               `json:"NISN" example:"0091913711"`
+	ClassName   string               `json:"class_name" example:"10 TJKT 3"`
+	Parents     []Parent             `json:"parents"`
+	Grades      []TransferGrade      `json:"grades"`
+	Attendances []TransferAttendance `json:"attendances"`
+}
+
+// Letter builds transfer letter of transfer record
+func (t *StudentTransfer) Letter() TransferLetter {
+	return TransferLetter{
+		LetterNumber:  t.LetterNumber,
+		Direction:     t.Direction,
+		StudentName:   t.StudentName,
+		NISN:          t.NISN,
+		SchoolName:    t.SchoolName,
+		Reason:        t.Reason,
+		TransferredAt: t.TransferredAt,
+		IssuedAt:      t.CreatedAt,
+	}
+}
+
+type TransferLetter struct {
+	LetterNumber  string            `json:"letter_number" example:"TRF/OUT/2026/479B5B5F"`
+	Direction     TransferDirection `json:"direction"`
+	StudentName   string            `json:"student_name" example:"Chesta Ardiona"`
+	NISN          string            `json:"NISN" example:"0091913711"`
+	SchoolName    string            `json:"school_name" example:"SMK Negeri 1 Jakarta"`
+	Reason        string            `json:"reason" example:"parents relocation"`
+	TransferredAt time.Time         `json:"transferred_at" example:"2006-01-02T15:04:05Z07:00"`
+	IssuedAt      time.Time         `json:"issued_at" example:"2006-01-02T15:04:05Z07:00"`
+}
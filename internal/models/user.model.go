@@ -1,13 +1,27 @@
 package models
 
+import (
+	"slices"
+	"time"
+)
+
 type User struct {
 	Id
 	FullName string     `json:"full_name" gorm:"not null" example:"Chesta Ardiona"`
 	Email    string     `gorm:"uniqueIndex;not null" json:"email" example:"chestaardi4@gmail.com"` // auth username
 	Password string     `gorm:"not null" json:"-"`                                                 // auth password
-	Role     UserRole   `gorm:"type:user_role;default:unsetted;not null" json:"role"`              // "student", "teacher", "admin", "unsetted"
+	Role     UserRole   `gorm:"type:user_role;default:unsetted;not null" json:"role"`              // primary role, active role on sign in. "student", "teacher", "admin", "unsetted", "alumni"
 	Gender   UserGender `gorm:"type:user_gender;not null" json:"gender"`                           // "male", "female"
 	Phone    string     `gorm:"uniqueIndex;not null" json:"phone" example:"+6281234567890"`        // phone number
+	// every role held by the user, empty if role is unsetted
+	Roles []UserRole `gorm:"type:text;serializer:json;default:'[]';not null" json:"roles"`
+
+	// set when user gets a role for the first time, null if user signed up and is waiting for activation.
+	// User moved to unsetted role later is not a pending sign up
+	ActivatedAt *time.Time `json:"activated_at" example:"2006-01-02T15:04:05Z07:00"`
+
+	// sessions signed in before this time are rejected, set when role of the user changes
+	SessionsRevokedAt *time.Time `json:"-"`
 
 	// empty if user's role not student
 	StudentProfile *Student `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"student_profile,omitempty" swaggerignore:"true"`
@@ -15,10 +29,30 @@ type User struct {
 	TeacherProfile *Teacher `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"teacher_profile,omitempty" swaggerignore:"true"`
 	// empty if user's role not admin
 	AdminProfile *Admin `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"admin_profile,omitempty" swaggerignore:"true"`
+	// empty if user's role not alumni
+	AlumniProfile *Alumni `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"alumni_profile,omitempty" swaggerignore:"true"`
 
 	TimestampArchivable
 }
 
+// HeldRoles returns every role held by the user, unsetted user only holds unsetted role
+func (u *User) HeldRoles() []UserRole {
+	if len(u.Roles) == 0 {
+		return []UserRole{u.Role}
+	}
+	return u.Roles
+}
+
+// HasRole reports whether the user holds the role
+func (u *User) HasRole(role UserRole) bool {
+	return slices.Contains(u.HeldRoles(), role)
+}
+
+// RolesPattern returns LIKE pattern to match users holding the role by roles column
+func RolesPattern(role UserRole) string {
+	return `%"` + string(role) + `"%`
+}
+
 type Student struct {
 	Id
 	ClassID string    `json:"-" gorm:"not null"`
@@ -28,7 +62,8 @@ type Student struct {
 
 	UserID string `gorm:"uniqueIndex;not null" json:"-"`
 
-	Timestamp
+	// archived when user leaves student role
+	TimestampArchivable
 }
 
 type Teacher struct {
@@ -39,18 +74,82 @@ type Teacher struct {
 
 	UserID string `gorm:"uniqueIndex;not null" json:"-"`
 
-	TimestampJoinTime
+	// archived when user leaves the role
+	TimestampJoinTimeArchivable
 }
 
 type Admin struct {
 	Id
-	StaffRole   string        `gorm:"not null" json:"staff_role" example:"developer"`
-	Permissions []*Permission `gorm:"many2many:admin_permissions" json:"permissions,omitempty" swaggerignore:"true"`
-	EmployeeID  string        `gorm:"not null" json:"employee_id" example:"DEV001"`
+	StaffRole   string             `gorm:"not null" json:"staff_role" example:"developer"`
+	Permissions []*Permission      `gorm:"many2many:admin_permissions" json:"permissions,omitempty" swaggerignore:"true"` // granted directly
+	Grants      []*AdminPermission `gorm:"foreignKey:AdminID" json:"grants,omitempty" swaggerignore:"true"`               // direct grants with their validity
+	// permissions of bundles are granted too
+	Bundles    []*PermissionBundle `gorm:"many2many:admin_bundles" json:"bundles,omitempty" swaggerignore:"true"`
+	EmployeeID string              `gorm:"not null" json:"employee_id" example:"DEV001"`
 
 	UserID string `gorm:"uniqueIndex;not null" json:"-"`
 
-	TimestampJoinTime
+	// archived when user leaves the role
+	TimestampJoinTimeArchivable
+}
+
+// EffectivePermissions returns union of permissions granted directly which are active now and by bundles,
+// grants and bundles must be preloaded with permissions
+func (a *Admin) EffectivePermissions() []*Permission {
+	seen := make(map[string]struct{}, len(a.Grants))
+	perms := make([]*Permission, 0, len(a.Grants))
+	add := func(perm *Permission) {
+		if _, ok := seen[perm.ID]; !ok {
+			seen[perm.ID] = struct{}{}
+			perms = append(perms, perm)
+		}
+	}
+	now := time.Now()
+	for _, grant := range a.Grants {
+		if grant.Permission != nil && grant.ActiveAt(now) {
+			add(grant.Permission)
+		}
+	}
+	for _, bundle := range a.Bundles {
+		for _, perm := range bundle.Permissions {
+			add(perm)
+		}
+	}
+	return perms
+}
+
+// MissingAction returns the first action of the resource which is not granted by effective permissions of the admin
+func (a *Admin) MissingAction(resource PermissionResource, actions ...PermissionAction) (action PermissionAction, missing bool) {
+	granted := make(map[PermissionAction]struct{}, len(actions))
+	for _, perm := range a.EffectivePermissions() {
+		if perm.Resource == resource {
+			for _, act := range perm.Actions {
+				granted[act] = struct{}{}
+			}
+		}
+	}
+	for _, action := range actions {
+		if _, ok := granted[action]; !ok {
+			return action, true
+		}
+	}
+	return "", false
+}
+
+// ScopeOf returns class scope of the action on resource, nil if any permission granting it applies to the whole resource.
+// Action must be granted, check it with MissingAction first
+func (a *Admin) ScopeOf(resource PermissionResource, action PermissionAction) ClassScope {
+	var scope ClassScope
+	for _, perm := range a.EffectivePermissions() {
+		if perm.Resource != resource || !slices.Contains(perm.Actions, action) {
+			continue
+		}
+		if perm.Scope.IsEmpty() {
+			return nil
+		}
+		scope = append(scope, perm.Scope)
+	}
+	return scope
 }
 
 type Parent struct {
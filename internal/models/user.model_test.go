@@ -0,0 +1,109 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func newPermission(id string, resource PermissionResource, scope PermissionScope, actions ...PermissionAction) *Permission {
+	return &Permission{Id: Id{ID: id}, Resource: resource, Actions: actions, Scope: scope}
+}
+
+func TestEffectivePermissions(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	direct := newPermission("direct", ResourceClass, PermissionScope{}, ActionRead)
+	expired := newPermission("expired", ResourceStudent, PermissionScope{}, ActionRead)
+	upcoming := newPermission("upcoming", ResourceSubject, PermissionScope{}, ActionRead)
+	bundled := newPermission("bundled", ResourceRoom, PermissionScope{}, ActionCreate)
+
+	admin := &Admin{
+		Grants: []*AdminPermission{
+			{Permission: direct},
+			{Permission: expired, ValidUntil: &past},
+			{Permission: upcoming, ValidFrom: &future},
+			{PermissionID: "not-preloaded"},
+		},
+		Bundles: []*PermissionBundle{
+			{Permissions: []*Permission{bundled, direct}},
+		},
+	}
+
+	got := admin.EffectivePermissions()
+	if len(got) != 2 || got[0].ID != "direct" || got[1].ID != "bundled" {
+		ids := make([]string, len(got))
+		for i, perm := range got {
+			ids[i] = perm.ID
+		}
+		t.Fatalf("expected [direct bundled], got %v", ids)
+	}
+}
+
+func TestMissingAction(t *testing.T) {
+	admin := &Admin{
+		Grants: []*AdminPermission{
+			{Permission: newPermission("read", ResourceClass, PermissionScope{}, ActionRead)},
+		},
+		Bundles: []*PermissionBundle{
+			{Permissions: []*Permission{newPermission("update", ResourceClass, PermissionScope{}, ActionUpdate)}},
+		},
+	}
+
+	if action, missing := admin.MissingAction(ResourceClass, ActionRead, ActionUpdate); missing {
+		t.Fatalf("expected read and update granted, missing %s", action)
+	}
+	if action, missing := admin.MissingAction(ResourceClass, ActionRead, ActionDelete); !missing || action != ActionDelete {
+		t.Fatalf("expected delete missing, got %q missing=%v", action, missing)
+	}
+	if _, missing := admin.MissingAction(ResourceStudent, ActionRead); !missing {
+		t.Fatal("expected read of another resource missing")
+	}
+}
+
+func TestScopeOf(t *testing.T) {
+	grade10 := PermissionScope{Grades: []int{10}}
+	listed := PermissionScope{ClassIDs: []string{"class-a"}}
+
+	t.Run("union of scoped permissions", func(t *testing.T) {
+		admin := &Admin{Grants: []*AdminPermission{
+			{Permission: newPermission("grade", ResourceClass, grade10, ActionRead)},
+			{Permission: newPermission("listed", ResourceClass, listed, ActionRead, ActionUpdate)},
+		}}
+
+		scope := admin.ScopeOf(ResourceClass, ActionRead)
+		if len(scope) != 2 {
+			t.Fatalf("expected 2 scopes, got %d", len(scope))
+		}
+		if !scope.Contains(&Class{Id: Id{ID: "class-b"}, Grade: 10, Major: "TJKT"}) {
+			t.Error("expected class of grade 10 in scope")
+		}
+		if !scope.Contains(&Class{Id: Id{ID: "class-a"}, Grade: 12}) {
+			t.Error("expected listed class in scope")
+		}
+		if scope.Contains(&Class{Id: Id{ID: "class-c"}, Grade: 11}) {
+			t.Error("expected class of grade 11 out of scope")
+		}
+		if scope := admin.ScopeOf(ResourceClass, ActionUpdate); len(scope) != 1 {
+			t.Errorf("expected only listed scope for update, got %d scopes", len(scope))
+		}
+	})
+
+	t.Run("unscoped permission covers whole resource", func(t *testing.T) {
+		admin := &Admin{
+			Grants: []*AdminPermission{
+				{Permission: newPermission("grade", ResourceClass, grade10, ActionRead)},
+			},
+			Bundles: []*PermissionBundle{
+				{Permissions: []*Permission{newPermission("all", ResourceClass, PermissionScope{}, ActionRead)}},
+			},
+		}
+
+		scope := admin.ScopeOf(ResourceClass, ActionRead)
+		if scope != nil {
+			t.Fatalf("expected nil scope, got %v", scope)
+		}
+		if !scope.Contains(&Class{Grade: 12}) {
+			t.Error("expected nil scope to contain any class")
+		}
+	})
+}
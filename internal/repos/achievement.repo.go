@@ -0,0 +1,26 @@
+package repos
+
+import (
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type Achievement struct {
+	db *gorm.DB
+	create[models.Achievement]
+	read[models.Achievement]
+	delete[models.Achievement]
+}
+
+func NewAchievement(db *gorm.DB) *Achievement {
+	return &Achievement{db, create[models.Achievement]{db}, read[models.Achievement]{db}, delete[models.Achievement]{db}}
+}
+
+func (r *Achievement) WithTx(tx *gorm.DB) *Achievement {
+	return NewAchievement(tx)
+}
+
+func (r *Achievement) DB() *gorm.DB {
+	return r.db
+}
@@ -1,9 +1,12 @@
 package repos
 
 import (
+	"context"
 	"school-information-system/internal/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Admin struct {
@@ -12,10 +15,11 @@ type Admin struct {
 	read[models.Admin]
 	update[models.Admin]
 	delete[models.Admin]
+	archivable[models.Admin]
 }
 
 func NewAdmin(db *gorm.DB) *Admin {
-	return &Admin{db, create[models.Admin]{db}, read[models.Admin]{db}, update[models.Admin]{db}, delete[models.Admin]{db}}
+	return &Admin{db, create[models.Admin]{db}, read[models.Admin]{db}, update[models.Admin]{db}, delete[models.Admin]{db}, archivable[models.Admin]{db}}
 }
 
 func (r *Admin) WithTx(tx *gorm.DB) *Admin {
@@ -25,3 +29,66 @@ func (r *Admin) WithTx(tx *gorm.DB) *Admin {
 func (r *Admin) DB() *gorm.DB {
 	return r.db
 }
+
+// UnheldPermissions returns permissions of the IDs which are not held by any active admin of active user, directly or by bundle.
+// Direct grant only counts if it is active and never expires
+func (r *Admin) UnheldPermissions(ctx context.Context, permissionIDs []string) (permissions []models.Permission, err error) {
+	err = r.db.WithContext(ctx).
+		Where("id IN ?", permissionIDs).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM admins a
+			JOIN users u ON u.id = a.user_id AND u.deleted_at IS NULL
+			WHERE a.deleted_at IS NULL AND (
+				EXISTS (
+					SELECT 1 FROM admin_permissions ap
+					WHERE ap.admin_id = a.id AND ap.permission_id = permissions.id
+						AND ap.valid_until IS NULL AND (ap.valid_from IS NULL OR ap.valid_from <= NOW())
+				)
+				OR EXISTS (
+					SELECT 1 FROM admin_bundles ab
+					JOIN bundle_permissions bp ON bp.permission_bundle_id = ab.permission_bundle_id
+					WHERE ab.admin_id = a.id AND bp.permission_id = permissions.id
+				)
+			)
+		)`).
+		Find(&permissions).Error
+	return
+}
+
+// Holders returns active admins of active users whose effective permissions grant the actions on the resource
+func (r *Admin) Holders(ctx context.Context, resource models.PermissionResource, actions ...models.PermissionAction) (admins []models.Admin, err error) {
+	var all []models.Admin
+	err = r.db.WithContext(ctx).
+		Joins("JOIN users ON users.id = admins.user_id AND users.deleted_at IS NULL").
+		Preload("Grants.Permission").
+		Preload("Bundles.Permissions").
+		Find(&all).Error
+	for _, admin := range all {
+		if _, missing := admin.MissingAction(resource, actions...); !missing {
+			admins = append(admins, admin)
+		}
+	}
+	return
+}
+
+// ExpiredGrants returns direct grants of permission which expired at the time, with the permission and the admin
+func (r *Admin) ExpiredGrants(ctx context.Context, at time.Time) (grants []models.AdminPermission, err error) {
+	err = r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Permission").
+		Preload("Admin").
+		Where("valid_until IS NOT NULL AND valid_until <= ?", at).
+		Find(&grants).Error
+	return
+}
+
+// DeleteGrants deletes direct grants of permission
+func (r *Admin) DeleteGrants(ctx context.Context, grants []models.AdminPermission) error {
+	for _, grant := range grants {
+		err := r.db.WithContext(ctx).Where("admin_id = ? AND permission_id = ?", grant.AdminID, grant.PermissionID).Delete(new(models.AdminPermission)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
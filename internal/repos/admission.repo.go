@@ -0,0 +1,27 @@
+package repos
+
+import (
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type Admission struct {
+	db *gorm.DB
+	create[models.Admission]
+	read[models.Admission]
+	update[models.Admission]
+	delete[models.Admission]
+}
+
+func NewAdmission(db *gorm.DB) *Admission {
+	return &Admission{db, create[models.Admission]{db}, read[models.Admission]{db}, update[models.Admission]{db}, delete[models.Admission]{db}}
+}
+
+func (r *Admission) WithTx(tx *gorm.DB) *Admission {
+	return NewAdmission(tx)
+}
+
+func (r *Admission) DB() *gorm.DB {
+	return r.db
+}
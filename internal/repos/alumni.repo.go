@@ -0,0 +1,26 @@
+package repos
+
+import (
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type Alumni struct {
+	db *gorm.DB
+	create[models.Alumni]
+	read[models.Alumni]
+	update[models.Alumni]
+}
+
+func NewAlumni(db *gorm.DB) *Alumni {
+	return &Alumni{db, create[models.Alumni]{db}, read[models.Alumni]{db}, update[models.Alumni]{db}}
+}
+
+func (r *Alumni) WithTx(tx *gorm.DB) *Alumni {
+	return NewAlumni(tx)
+}
+
+func (r *Alumni) DB() *gorm.DB {
+	return r.db
+}
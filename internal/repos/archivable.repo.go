@@ -25,3 +25,7 @@ func (r *archivable[T]) Restore(ctx context.Context, where any, args ...any) (er
 	}
 	return
 }
+
+func (r *archivable[T]) ArchiveAll(ctx context.Context, where any, args ...any) (rowsAffected int, err error) {
+	return gorm.G[T](r.db).Where(where, args...).Delete(ctx)
+}
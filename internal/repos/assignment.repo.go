@@ -0,0 +1,45 @@
+package repos
+
+import (
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type Assignment struct {
+	db *gorm.DB
+	create[models.Assignment]
+	read[models.Assignment]
+	delete[models.Assignment]
+}
+
+func NewAssignment(db *gorm.DB) *Assignment {
+	return &Assignment{db, create[models.Assignment]{db}, read[models.Assignment]{db}, delete[models.Assignment]{db}}
+}
+
+func (r *Assignment) WithTx(tx *gorm.DB) *Assignment {
+	return NewAssignment(tx)
+}
+
+func (r *Assignment) DB() *gorm.DB {
+	return r.db
+}
+
+type Submission struct {
+	db *gorm.DB
+	create[models.Submission]
+	read[models.Submission]
+	update[models.Submission]
+}
+
+func NewSubmission(db *gorm.DB) *Submission {
+	return &Submission{db, create[models.Submission]{db}, read[models.Submission]{db}, update[models.Submission]{db}}
+}
+
+func (r *Submission) WithTx(tx *gorm.DB) *Submission {
+	return NewSubmission(tx)
+}
+
+func (r *Submission) DB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,51 @@
+package repos
+
+import (
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type BankQuestion struct {
+	db *gorm.DB
+	create[models.BankQuestion]
+	read[models.BankQuestion]
+	update[models.BankQuestion]
+	delete[models.BankQuestion]
+}
+
+func NewBankQuestion(db *gorm.DB) *BankQuestion {
+	return &BankQuestion{db, create[models.BankQuestion]{db}, read[models.BankQuestion]{db}, update[models.BankQuestion]{db}, delete[models.BankQuestion]{db}}
+}
+
+func (r *BankQuestion) WithTx(tx *gorm.DB) *BankQuestion {
+	return NewBankQuestion(tx)
+}
+
+func (r *BankQuestion) DB() *gorm.DB {
+	return r.db
+}
+
+// WithCurrentVersion preloads only current version of each question
+func WithCurrentVersion(db gorm.PreloadBuilder) error {
+	db.Where("version = (SELECT current_version FROM bank_questions WHERE bank_questions.id = bank_question_versions.question_id)")
+	return nil
+}
+
+type BankQuestionVersion struct {
+	db *gorm.DB
+	create[models.BankQuestionVersion]
+	read[models.BankQuestionVersion]
+}
+
+func NewBankQuestionVersion(db *gorm.DB) *BankQuestionVersion {
+	return &BankQuestionVersion{db, create[models.BankQuestionVersion]{db}, read[models.BankQuestionVersion]{db}}
+}
+
+func (r *BankQuestionVersion) WithTx(tx *gorm.DB) *BankQuestionVersion {
+	return NewBankQuestionVersion(tx)
+}
+
+func (r *BankQuestionVersion) DB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,52 @@
+package repos
+
+import (
+	"context"
+	"school-information-system/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChangeRequest struct {
+	db *gorm.DB
+	create[models.ChangeRequest]
+	read[models.ChangeRequest]
+	update[models.ChangeRequest]
+}
+
+func NewChangeRequest(db *gorm.DB) *ChangeRequest {
+	return &ChangeRequest{db, create[models.ChangeRequest]{db}, read[models.ChangeRequest]{db}, update[models.ChangeRequest]{db}}
+}
+
+func (r *ChangeRequest) WithTx(tx *gorm.DB) *ChangeRequest {
+	return NewChangeRequest(tx)
+}
+
+func (r *ChangeRequest) DB() *gorm.DB {
+	return r.db
+}
+
+// Review sets status and reviewer of the change request if it is still pending, returns false if it is not
+func (r *ChangeRequest) Review(ctx context.Context, id string, status models.ChangeRequestStatus, reviewerID, note string, at time.Time) (bool, error) {
+	rows, err := gorm.G[models.ChangeRequest](r.db).
+		Where("id = ? AND status = ?", id, models.ChangePending).
+		Updates(ctx, models.ChangeRequest{Status: status, ReviewerID: &reviewerID, ReviewNote: note, ReviewedAt: &at})
+	return rows > 0, err
+}
+
+// Reopen sets reviewed change request back to pending
+func (r *ChangeRequest) Reopen(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&models.ChangeRequest{}).Where("id = ?", id).
+		Updates(map[string]any{"status": models.ChangePending, "reviewer_id": nil, "review_note": "", "reviewed_at": nil}).Error
+}
+
+// ExpirePending marks pending change requests which expired at the time as expired and returns them
+func (r *ChangeRequest) ExpirePending(ctx context.Context, at time.Time) (requests []models.ChangeRequest, err error) {
+	err = r.db.WithContext(ctx).Model(&requests).
+		Clauses(clause.Returning{}).
+		Where("status = ? AND expires_at <= ?", models.ChangePending, at).
+		Update("status", models.ChangeExpired).Error
+	return
+}
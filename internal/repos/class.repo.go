@@ -30,7 +30,7 @@ func (r *Class) DB() *gorm.DB {
 func (s *Class) GetFormTeacher(ctx context.Context, classID string) (models.User, error) {
 	var user models.User
 	err := s.db.WithContext(ctx).Preload("TeacherProfile").
-		Joins("JOIN teachers ON teachers.user_id = users.id").
+		Joins("JOIN teachers ON teachers.user_id = users.id AND teachers.deleted_at IS NULL").
 		Joins("JOIN classes ON classes.form_teacher_id = teachers.id").
 		Where("classes.id = ?", classID).
 		First(&user).Error
@@ -40,7 +40,7 @@ func (s *Class) GetFormTeacher(ctx context.Context, classID string) (models.User
 func (s *Class) GetStudents(ctx context.Context, classID string) ([]models.User, error) {
 	var students []models.User
 	err := s.db.WithContext(ctx).Preload("StudentProfile").
-		Joins("JOIN students ON students.user_id = users.id").
+		Joins("JOIN students ON students.user_id = users.id AND students.deleted_at IS NULL").
 		Where("students.class_id = ?", classID).
 		Find(&students).Error
 	return students, err
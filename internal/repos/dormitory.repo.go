@@ -0,0 +1,101 @@
+package repos
+
+import (
+	"context"
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Dormitory struct {
+	db *gorm.DB
+	create[models.Dormitory]
+	read[models.Dormitory]
+	update[models.Dormitory]
+	delete[models.Dormitory]
+}
+
+func NewDormitory(db *gorm.DB) *Dormitory {
+	return &Dormitory{db, create[models.Dormitory]{db}, read[models.Dormitory]{db}, update[models.Dormitory]{db}, delete[models.Dormitory]{db}}
+}
+
+func (r *Dormitory) WithTx(tx *gorm.DB) *Dormitory {
+	return NewDormitory(tx)
+}
+
+func (r *Dormitory) DB() *gorm.DB {
+	return r.db
+}
+
+type DormRoom struct {
+	db *gorm.DB
+	create[models.DormRoom]
+	read[models.DormRoom]
+	update[models.DormRoom]
+	delete[models.DormRoom]
+}
+
+func NewDormRoom(db *gorm.DB) *DormRoom {
+	return &DormRoom{db, create[models.DormRoom]{db}, read[models.DormRoom]{db}, update[models.DormRoom]{db}, delete[models.DormRoom]{db}}
+}
+
+func (r *DormRoom) WithTx(tx *gorm.DB) *DormRoom {
+	return NewDormRoom(tx)
+}
+
+func (r *DormRoom) DB() *gorm.DB {
+	return r.db
+}
+
+type DormAssignment struct {
+	db *gorm.DB
+	create[models.DormAssignment]
+	read[models.DormAssignment]
+	delete[models.DormAssignment]
+}
+
+func NewDormAssignment(db *gorm.DB) *DormAssignment {
+	return &DormAssignment{db, create[models.DormAssignment]{db}, read[models.DormAssignment]{db}, delete[models.DormAssignment]{db}}
+}
+
+func (r *DormAssignment) WithTx(tx *gorm.DB) *DormAssignment {
+	return NewDormAssignment(tx)
+}
+
+func (r *DormAssignment) DB() *gorm.DB {
+	return r.db
+}
+
+type NightRoll struct {
+	db *gorm.DB
+	create[models.NightRoll]
+	read[models.NightRoll]
+}
+
+func NewNightRoll(db *gorm.DB) *NightRoll {
+	return &NightRoll{db, create[models.NightRoll]{db}, read[models.NightRoll]{db}}
+}
+
+func (r *NightRoll) WithTx(tx *gorm.DB) *NightRoll {
+	return NewNightRoll(tx)
+}
+
+func (r *NightRoll) DB() *gorm.DB {
+	return r.db
+}
+
+// HasSupervisor checks if the teacher supervises the dormitory
+func (r *Dormitory) HasSupervisor(ctx context.Context, dormitoryID, teacherID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("dormitory_supervisors").Where("dormitory_id = ? AND teacher_id = ?", dormitoryID, teacherID).Count(&count).Error
+	return count > 0, err
+}
+
+// Record creates night rolls or replaces existing roll of the students at the date
+func (r *NightRoll) Record(ctx context.Context, rolls *[]models.NightRoll) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"dormitory_id", "status", "note", "recorded_by_id", "updated_at"}),
+	}).Create(rolls).Error
+}
@@ -0,0 +1,120 @@
+package repos
+
+import (
+	"context"
+	"school-information-system/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ExamPeriod struct {
+	db *gorm.DB
+	create[models.ExamPeriod]
+	read[models.ExamPeriod]
+	update[models.ExamPeriod]
+	delete[models.ExamPeriod]
+}
+
+func NewExamPeriod(db *gorm.DB) *ExamPeriod {
+	return &ExamPeriod{db, create[models.ExamPeriod]{db}, read[models.ExamPeriod]{db}, update[models.ExamPeriod]{db}, delete[models.ExamPeriod]{db}}
+}
+
+func (r *ExamPeriod) WithTx(tx *gorm.DB) *ExamPeriod {
+	return NewExamPeriod(tx)
+}
+
+func (r *ExamPeriod) DB() *gorm.DB {
+	return r.db
+}
+
+type ExamRoom struct {
+	db *gorm.DB
+	create[models.ExamRoom]
+	read[models.ExamRoom]
+	update[models.ExamRoom]
+	delete[models.ExamRoom]
+}
+
+func NewExamRoom(db *gorm.DB) *ExamRoom {
+	return &ExamRoom{db, create[models.ExamRoom]{db}, read[models.ExamRoom]{db}, update[models.ExamRoom]{db}, delete[models.ExamRoom]{db}}
+}
+
+func (r *ExamRoom) WithTx(tx *gorm.DB) *ExamRoom {
+	return NewExamRoom(tx)
+}
+
+func (r *ExamRoom) DB() *gorm.DB {
+	return r.db
+}
+
+type ExamSession struct {
+	db *gorm.DB
+	create[models.ExamSession]
+	read[models.ExamSession]
+	update[models.ExamSession]
+	delete[models.ExamSession]
+}
+
+func NewExamSession(db *gorm.DB) *ExamSession {
+	return &ExamSession{db, create[models.ExamSession]{db}, read[models.ExamSession]{db}, update[models.ExamSession]{db}, delete[models.ExamSession]{db}}
+}
+
+func (r *ExamSession) WithTx(tx *gorm.DB) *ExamSession {
+	return NewExamSession(tx)
+}
+
+func (r *ExamSession) DB() *gorm.DB {
+	return r.db
+}
+
+// UsesRoom reports whether exam session overlapping the period is held in exam room linked to the room
+func (r *ExamSession) UsesRoom(ctx context.Context, roomID string, startAt, endAt time.Time) (used bool, err error) {
+	err = r.db.WithContext(ctx).
+		Raw(`SELECT EXISTS (
+			SELECT 1 FROM exam_sessions
+			JOIN exam_seats ON exam_seats.session_id = exam_sessions.id
+			JOIN exam_rooms ON exam_rooms.id = exam_seats.room_id
+			WHERE exam_rooms.room_id = ? AND exam_sessions.start_at < ? AND exam_sessions.end_at > ?
+		)`, roomID, endAt, startAt).
+		Scan(&used).Error
+	return
+}
+
+type ExamSeat struct {
+	db *gorm.DB
+	create[models.ExamSeat]
+	read[models.ExamSeat]
+	delete[models.ExamSeat]
+}
+
+func NewExamSeat(db *gorm.DB) *ExamSeat {
+	return &ExamSeat{db, create[models.ExamSeat]{db}, read[models.ExamSeat]{db}, delete[models.ExamSeat]{db}}
+}
+
+func (r *ExamSeat) WithTx(tx *gorm.DB) *ExamSeat {
+	return NewExamSeat(tx)
+}
+
+func (r *ExamSeat) DB() *gorm.DB {
+	return r.db
+}
+
+type ExamInvigilator struct {
+	db *gorm.DB
+	create[models.ExamInvigilator]
+	read[models.ExamInvigilator]
+	delete[models.ExamInvigilator]
+}
+
+func NewExamInvigilator(db *gorm.DB) *ExamInvigilator {
+	return &ExamInvigilator{db, create[models.ExamInvigilator]{db}, read[models.ExamInvigilator]{db}, delete[models.ExamInvigilator]{db}}
+}
+
+func (r *ExamInvigilator) WithTx(tx *gorm.DB) *ExamInvigilator {
+	return NewExamInvigilator(tx)
+}
+
+func (r *ExamInvigilator) DB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,56 @@
+package repos
+
+import (
+	"context"
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StudentHealth struct {
+	db *gorm.DB
+	create[models.StudentHealth]
+	read[models.StudentHealth]
+	update[models.StudentHealth]
+}
+
+func NewStudentHealth(db *gorm.DB) *StudentHealth {
+	return &StudentHealth{db, create[models.StudentHealth]{db}, read[models.StudentHealth]{db}, update[models.StudentHealth]{db}}
+}
+
+func (r *StudentHealth) WithTx(tx *gorm.DB) *StudentHealth {
+	return NewStudentHealth(tx)
+}
+
+func (r *StudentHealth) DB() *gorm.DB {
+	return r.db
+}
+
+type ClinicVisit struct {
+	db *gorm.DB
+	create[models.ClinicVisit]
+	read[models.ClinicVisit]
+	update[models.ClinicVisit]
+	delete[models.ClinicVisit]
+}
+
+func NewClinicVisit(db *gorm.DB) *ClinicVisit {
+	return &ClinicVisit{db, create[models.ClinicVisit]{db}, read[models.ClinicVisit]{db}, update[models.ClinicVisit]{db}, delete[models.ClinicVisit]{db}}
+}
+
+func (r *ClinicVisit) WithTx(tx *gorm.DB) *ClinicVisit {
+	return NewClinicVisit(tx)
+}
+
+func (r *ClinicVisit) DB() *gorm.DB {
+	return r.db
+}
+
+// Set creates health record or replaces existing record of the student
+func (r *StudentHealth) Set(ctx context.Context, health *models.StudentHealth) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"blood_type", "allergies", "chronic_conditions", "immunizations", "notes", "updated_by_id", "updated_at"}),
+	}).Create(health).Error
+}
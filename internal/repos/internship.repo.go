@@ -0,0 +1,85 @@
+package repos
+
+import (
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type IndustryPartner struct {
+	db *gorm.DB
+	create[models.IndustryPartner]
+	read[models.IndustryPartner]
+	update[models.IndustryPartner]
+	delete[models.IndustryPartner]
+}
+
+func NewIndustryPartner(db *gorm.DB) *IndustryPartner {
+	return &IndustryPartner{db, create[models.IndustryPartner]{db}, read[models.IndustryPartner]{db}, update[models.IndustryPartner]{db}, delete[models.IndustryPartner]{db}}
+}
+
+func (r *IndustryPartner) WithTx(tx *gorm.DB) *IndustryPartner {
+	return NewIndustryPartner(tx)
+}
+
+func (r *IndustryPartner) DB() *gorm.DB {
+	return r.db
+}
+
+type InternshipPlacement struct {
+	db *gorm.DB
+	create[models.InternshipPlacement]
+	read[models.InternshipPlacement]
+	update[models.InternshipPlacement]
+	delete[models.InternshipPlacement]
+}
+
+func NewInternshipPlacement(db *gorm.DB) *InternshipPlacement {
+	return &InternshipPlacement{db, create[models.InternshipPlacement]{db}, read[models.InternshipPlacement]{db}, update[models.InternshipPlacement]{db}, delete[models.InternshipPlacement]{db}}
+}
+
+func (r *InternshipPlacement) WithTx(tx *gorm.DB) *InternshipPlacement {
+	return NewInternshipPlacement(tx)
+}
+
+func (r *InternshipPlacement) DB() *gorm.DB {
+	return r.db
+}
+
+type InternshipJournal struct {
+	db *gorm.DB
+	create[models.InternshipJournal]
+	read[models.InternshipJournal]
+	update[models.InternshipJournal]
+}
+
+func NewInternshipJournal(db *gorm.DB) *InternshipJournal {
+	return &InternshipJournal{db, create[models.InternshipJournal]{db}, read[models.InternshipJournal]{db}, update[models.InternshipJournal]{db}}
+}
+
+func (r *InternshipJournal) WithTx(tx *gorm.DB) *InternshipJournal {
+	return NewInternshipJournal(tx)
+}
+
+func (r *InternshipJournal) DB() *gorm.DB {
+	return r.db
+}
+
+type MonitoringVisit struct {
+	db *gorm.DB
+	create[models.MonitoringVisit]
+	read[models.MonitoringVisit]
+	delete[models.MonitoringVisit]
+}
+
+func NewMonitoringVisit(db *gorm.DB) *MonitoringVisit {
+	return &MonitoringVisit{db, create[models.MonitoringVisit]{db}, read[models.MonitoringVisit]{db}, delete[models.MonitoringVisit]{db}}
+}
+
+func (r *MonitoringVisit) WithTx(tx *gorm.DB) *MonitoringVisit {
+	return NewMonitoringVisit(tx)
+}
+
+func (r *MonitoringVisit) DB() *gorm.DB {
+	return r.db
+}
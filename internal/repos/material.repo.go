@@ -0,0 +1,45 @@
+package repos
+
+import (
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type Material struct {
+	db *gorm.DB
+	create[models.LearningMaterial]
+	read[models.LearningMaterial]
+	delete[models.LearningMaterial]
+}
+
+func NewMaterial(db *gorm.DB) *Material {
+	return &Material{db, create[models.LearningMaterial]{db}, read[models.LearningMaterial]{db}, delete[models.LearningMaterial]{db}}
+}
+
+func (r *Material) WithTx(tx *gorm.DB) *Material {
+	return NewMaterial(tx)
+}
+
+func (r *Material) DB() *gorm.DB {
+	return r.db
+}
+
+type StoredFile struct {
+	db *gorm.DB
+	create[models.StoredFile]
+	read[models.StoredFile]
+	delete[models.StoredFile]
+}
+
+func NewStoredFile(db *gorm.DB) *StoredFile {
+	return &StoredFile{db, create[models.StoredFile]{db}, read[models.StoredFile]{db}, delete[models.StoredFile]{db}}
+}
+
+func (r *StoredFile) WithTx(tx *gorm.DB) *StoredFile {
+	return NewStoredFile(tx)
+}
+
+func (r *StoredFile) DB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,26 @@
+package repos
+
+import (
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type Notification struct {
+	db *gorm.DB
+	create[models.Notification]
+	read[models.Notification]
+	update[models.Notification]
+}
+
+func NewNotification(db *gorm.DB) *Notification {
+	return &Notification{db, create[models.Notification]{db}, read[models.Notification]{db}, update[models.Notification]{db}}
+}
+
+func (r *Notification) WithTx(tx *gorm.DB) *Notification {
+	return NewNotification(tx)
+}
+
+func (r *Notification) DB() *gorm.DB {
+	return r.db
+}
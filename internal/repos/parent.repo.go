@@ -30,7 +30,7 @@ func (r *Parent) DB() *gorm.DB {
 func (r *Parent) GetStudents(ctx context.Context, parentID string) ([]models.User, error) {
 	var students []models.User
 	err := r.db.Model(new(models.User)).WithContext(ctx).
-		Joins("JOIN students ON students.user_id = users.id").
+		Joins("JOIN students ON students.user_id = users.id AND students.deleted_at IS NULL").
 		Joins("JOIN student_parents sp ON sp.student_id = students.id").
 		Where("sp.parent_id = ?", parentID).
 		Preload("StudentProfile").
@@ -25,3 +25,41 @@ func (r *Permission) WithTx(tx *gorm.DB) *Permission {
 func (r *Permission) DB() *gorm.DB {
 	return r.db
 }
+
+type PermissionBundle struct {
+	db *gorm.DB
+	create[models.PermissionBundle]
+	read[models.PermissionBundle]
+	update[models.PermissionBundle]
+	delete[models.PermissionBundle]
+}
+
+func NewPermissionBundle(db *gorm.DB) *PermissionBundle {
+	return &PermissionBundle{db, create[models.PermissionBundle]{db}, read[models.PermissionBundle]{db}, update[models.PermissionBundle]{db}, delete[models.PermissionBundle]{db}}
+}
+
+func (r *PermissionBundle) WithTx(tx *gorm.DB) *PermissionBundle {
+	return NewPermissionBundle(tx)
+}
+
+func (r *PermissionBundle) DB() *gorm.DB {
+	return r.db
+}
+
+type PermissionAudit struct {
+	db *gorm.DB
+	create[models.PermissionAudit]
+	read[models.PermissionAudit]
+}
+
+func NewPermissionAudit(db *gorm.DB) *PermissionAudit {
+	return &PermissionAudit{db, create[models.PermissionAudit]{db}, read[models.PermissionAudit]{db}}
+}
+
+func (r *PermissionAudit) WithTx(tx *gorm.DB) *PermissionAudit {
+	return NewPermissionAudit(tx)
+}
+
+func (r *PermissionAudit) DB() *gorm.DB {
+	return r.db
+}
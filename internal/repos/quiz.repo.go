@@ -0,0 +1,94 @@
+package repos
+
+import (
+	"context"
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Quiz struct {
+	db *gorm.DB
+	create[models.Quiz]
+	read[models.Quiz]
+	delete[models.Quiz]
+}
+
+func NewQuiz(db *gorm.DB) *Quiz {
+	return &Quiz{db, create[models.Quiz]{db}, read[models.Quiz]{db}, delete[models.Quiz]{db}}
+}
+
+func (r *Quiz) WithTx(tx *gorm.DB) *Quiz {
+	return NewQuiz(tx)
+}
+
+func (r *Quiz) DB() *gorm.DB {
+	return r.db
+}
+
+type QuizWindow struct {
+	db *gorm.DB
+	create[models.QuizWindow]
+	read[models.QuizWindow]
+	update[models.QuizWindow]
+	delete[models.QuizWindow]
+}
+
+func NewQuizWindow(db *gorm.DB) *QuizWindow {
+	return &QuizWindow{db, create[models.QuizWindow]{db}, read[models.QuizWindow]{db}, update[models.QuizWindow]{db}, delete[models.QuizWindow]{db}}
+}
+
+func (r *QuizWindow) WithTx(tx *gorm.DB) *QuizWindow {
+	return NewQuizWindow(tx)
+}
+
+func (r *QuizWindow) DB() *gorm.DB {
+	return r.db
+}
+
+// Set creates window or replaces period of existing window of the same class
+func (r *QuizWindow) Set(ctx context.Context, window *models.QuizWindow) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "quiz_id"}, {Name: "class_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"start_at", "end_at", "updated_at"}),
+	}).Create(window).Error
+}
+
+type QuizAttempt struct {
+	db *gorm.DB
+	create[models.QuizAttempt]
+	read[models.QuizAttempt]
+	update[models.QuizAttempt]
+}
+
+func NewQuizAttempt(db *gorm.DB) *QuizAttempt {
+	return &QuizAttempt{db, create[models.QuizAttempt]{db}, read[models.QuizAttempt]{db}, update[models.QuizAttempt]{db}}
+}
+
+func (r *QuizAttempt) WithTx(tx *gorm.DB) *QuizAttempt {
+	return NewQuizAttempt(tx)
+}
+
+func (r *QuizAttempt) DB() *gorm.DB {
+	return r.db
+}
+
+type QuizAnswer struct {
+	db *gorm.DB
+	create[models.QuizAnswer]
+	read[models.QuizAnswer]
+	update[models.QuizAnswer]
+}
+
+func NewQuizAnswer(db *gorm.DB) *QuizAnswer {
+	return &QuizAnswer{db, create[models.QuizAnswer]{db}, read[models.QuizAnswer]{db}, update[models.QuizAnswer]{db}}
+}
+
+func (r *QuizAnswer) WithTx(tx *gorm.DB) *QuizAnswer {
+	return NewQuizAnswer(tx)
+}
+
+func (r *QuizAnswer) DB() *gorm.DB {
+	return r.db
+}
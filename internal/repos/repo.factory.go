@@ -3,16 +3,57 @@ package repos
 import "gorm.io/gorm"
 
 type Repos struct {
-	db         *gorm.DB
-	user       *User
-	student    *Student
-	teacher    *Teacher
-	admin      *Admin
-	parent     *Parent
-	class      *Class
-	permission *Permission
-	revoked    *Revoked
-	subject    *Subject
+	db                      *gorm.DB
+	user                    *User
+	student                 *Student
+	teacher                 *Teacher
+	admin                   *Admin
+	parent                  *Parent
+	class                   *Class
+	permission              *Permission
+	revoked                 *Revoked
+	subject                 *Subject
+	transfer                *StudentTransfer
+	alumni                  *Alumni
+	admission               *Admission
+	material                *Material
+	storedFile              *StoredFile
+	score                   *Score
+	assignment              *Assignment
+	submission              *Submission
+	quiz                    *Quiz
+	quizWindow              *QuizWindow
+	quizAttempt             *QuizAttempt
+	quizAnswer              *QuizAnswer
+	bank                    *BankQuestion
+	bankVersion             *BankQuestionVersion
+	examPeriod              *ExamPeriod
+	examRoom                *ExamRoom
+	examSession             *ExamSession
+	examSeat                *ExamSeat
+	examInvigilator         *ExamInvigilator
+	room                    *Room
+	roomBooking             *RoomBooking
+	studentHealth           *StudentHealth
+	clinicVisit             *ClinicVisit
+	achievement             *Achievement
+	scholarshipProgram      *ScholarshipProgram
+	scholarshipRecipient    *ScholarshipRecipient
+	scholarshipDisbursement *ScholarshipDisbursement
+	dormitory               *Dormitory
+	dormRoom                *DormRoom
+	dormAssignment          *DormAssignment
+	nightRoll               *NightRoll
+	industryPartner         *IndustryPartner
+	internshipPlacement     *InternshipPlacement
+	internshipJournal       *InternshipJournal
+	monitoringVisit         *MonitoringVisit
+	timetableSlot           *TimetableSlot
+	teachingJournal         *TeachingJournal
+	permissionBundle        *PermissionBundle
+	permissionAudit         *PermissionAudit
+	notification            *Notification
+	changeRequest           *ChangeRequest
 }
 
 func New(db *gorm.DB) *Repos {
@@ -81,3 +122,290 @@ func (r *Repos) Subject() *Subject {
 	}
 	return r.subject
 }
+
+func (r *Repos) StudentTransfer() *StudentTransfer {
+	if r.transfer == nil {
+		r.transfer = NewStudentTransfer(r.db)
+	}
+	return r.transfer
+}
+
+func (r *Repos) Alumni() *Alumni {
+	if r.alumni == nil {
+		r.alumni = NewAlumni(r.db)
+	}
+	return r.alumni
+}
+
+func (r *Repos) Admission() *Admission {
+	if r.admission == nil {
+		r.admission = NewAdmission(r.db)
+	}
+	return r.admission
+}
+
+func (r *Repos) Material() *Material {
+	if r.material == nil {
+		r.material = NewMaterial(r.db)
+	}
+	return r.material
+}
+
+func (r *Repos) StoredFile() *StoredFile {
+	if r.storedFile == nil {
+		r.storedFile = NewStoredFile(r.db)
+	}
+	return r.storedFile
+}
+
+func (r *Repos) Score() *Score {
+	if r.score == nil {
+		r.score = NewScore(r.db)
+	}
+	return r.score
+}
+
+func (r *Repos) Assignment() *Assignment {
+	if r.assignment == nil {
+		r.assignment = NewAssignment(r.db)
+	}
+	return r.assignment
+}
+
+func (r *Repos) Submission() *Submission {
+	if r.submission == nil {
+		r.submission = NewSubmission(r.db)
+	}
+	return r.submission
+}
+
+func (r *Repos) Quiz() *Quiz {
+	if r.quiz == nil {
+		r.quiz = NewQuiz(r.db)
+	}
+	return r.quiz
+}
+
+func (r *Repos) QuizWindow() *QuizWindow {
+	if r.quizWindow == nil {
+		r.quizWindow = NewQuizWindow(r.db)
+	}
+	return r.quizWindow
+}
+
+func (r *Repos) QuizAttempt() *QuizAttempt {
+	if r.quizAttempt == nil {
+		r.quizAttempt = NewQuizAttempt(r.db)
+	}
+	return r.quizAttempt
+}
+
+func (r *Repos) QuizAnswer() *QuizAnswer {
+	if r.quizAnswer == nil {
+		r.quizAnswer = NewQuizAnswer(r.db)
+	}
+	return r.quizAnswer
+}
+
+func (r *Repos) BankQuestion() *BankQuestion {
+	if r.bank == nil {
+		r.bank = NewBankQuestion(r.db)
+	}
+	return r.bank
+}
+
+func (r *Repos) BankQuestionVersion() *BankQuestionVersion {
+	if r.bankVersion == nil {
+		r.bankVersion = NewBankQuestionVersion(r.db)
+	}
+	return r.bankVersion
+}
+
+func (r *Repos) ExamPeriod() *ExamPeriod {
+	if r.examPeriod == nil {
+		r.examPeriod = NewExamPeriod(r.db)
+	}
+	return r.examPeriod
+}
+
+func (r *Repos) ExamRoom() *ExamRoom {
+	if r.examRoom == nil {
+		r.examRoom = NewExamRoom(r.db)
+	}
+	return r.examRoom
+}
+
+func (r *Repos) ExamSession() *ExamSession {
+	if r.examSession == nil {
+		r.examSession = NewExamSession(r.db)
+	}
+	return r.examSession
+}
+
+func (r *Repos) ExamSeat() *ExamSeat {
+	if r.examSeat == nil {
+		r.examSeat = NewExamSeat(r.db)
+	}
+	return r.examSeat
+}
+
+func (r *Repos) ExamInvigilator() *ExamInvigilator {
+	if r.examInvigilator == nil {
+		r.examInvigilator = NewExamInvigilator(r.db)
+	}
+	return r.examInvigilator
+}
+
+func (r *Repos) Room() *Room {
+	if r.room == nil {
+		r.room = NewRoom(r.db)
+	}
+	return r.room
+}
+
+func (r *Repos) RoomBooking() *RoomBooking {
+	if r.roomBooking == nil {
+		r.roomBooking = NewRoomBooking(r.db)
+	}
+	return r.roomBooking
+}
+
+func (r *Repos) StudentHealth() *StudentHealth {
+	if r.studentHealth == nil {
+		r.studentHealth = NewStudentHealth(r.db)
+	}
+	return r.studentHealth
+}
+
+func (r *Repos) ClinicVisit() *ClinicVisit {
+	if r.clinicVisit == nil {
+		r.clinicVisit = NewClinicVisit(r.db)
+	}
+	return r.clinicVisit
+}
+
+func (r *Repos) Achievement() *Achievement {
+	if r.achievement == nil {
+		r.achievement = NewAchievement(r.db)
+	}
+	return r.achievement
+}
+
+func (r *Repos) ScholarshipProgram() *ScholarshipProgram {
+	if r.scholarshipProgram == nil {
+		r.scholarshipProgram = NewScholarshipProgram(r.db)
+	}
+	return r.scholarshipProgram
+}
+
+func (r *Repos) ScholarshipRecipient() *ScholarshipRecipient {
+	if r.scholarshipRecipient == nil {
+		r.scholarshipRecipient = NewScholarshipRecipient(r.db)
+	}
+	return r.scholarshipRecipient
+}
+
+func (r *Repos) ScholarshipDisbursement() *ScholarshipDisbursement {
+	if r.scholarshipDisbursement == nil {
+		r.scholarshipDisbursement = NewScholarshipDisbursement(r.db)
+	}
+	return r.scholarshipDisbursement
+}
+
+func (r *Repos) Dormitory() *Dormitory {
+	if r.dormitory == nil {
+		r.dormitory = NewDormitory(r.db)
+	}
+	return r.dormitory
+}
+
+func (r *Repos) DormRoom() *DormRoom {
+	if r.dormRoom == nil {
+		r.dormRoom = NewDormRoom(r.db)
+	}
+	return r.dormRoom
+}
+
+func (r *Repos) DormAssignment() *DormAssignment {
+	if r.dormAssignment == nil {
+		r.dormAssignment = NewDormAssignment(r.db)
+	}
+	return r.dormAssignment
+}
+
+func (r *Repos) NightRoll() *NightRoll {
+	if r.nightRoll == nil {
+		r.nightRoll = NewNightRoll(r.db)
+	}
+	return r.nightRoll
+}
+
+func (r *Repos) IndustryPartner() *IndustryPartner {
+	if r.industryPartner == nil {
+		r.industryPartner = NewIndustryPartner(r.db)
+	}
+	return r.industryPartner
+}
+
+func (r *Repos) InternshipPlacement() *InternshipPlacement {
+	if r.internshipPlacement == nil {
+		r.internshipPlacement = NewInternshipPlacement(r.db)
+	}
+	return r.internshipPlacement
+}
+
+func (r *Repos) InternshipJournal() *InternshipJournal {
+	if r.internshipJournal == nil {
+		r.internshipJournal = NewInternshipJournal(r.db)
+	}
+	return r.internshipJournal
+}
+
+func (r *Repos) MonitoringVisit() *MonitoringVisit {
+	if r.monitoringVisit == nil {
+		r.monitoringVisit = NewMonitoringVisit(r.db)
+	}
+	return r.monitoringVisit
+}
+
+func (r *Repos) TimetableSlot() *TimetableSlot {
+	if r.timetableSlot == nil {
+		r.timetableSlot = NewTimetableSlot(r.db)
+	}
+	return r.timetableSlot
+}
+
+func (r *Repos) TeachingJournal() *TeachingJournal {
+	if r.teachingJournal == nil {
+		r.teachingJournal = NewTeachingJournal(r.db)
+	}
+	return r.teachingJournal
+}
+
+func (r *Repos) PermissionBundle() *PermissionBundle {
+	if r.permissionBundle == nil {
+		r.permissionBundle = NewPermissionBundle(r.db)
+	}
+	return r.permissionBundle
+}
+
+func (r *Repos) PermissionAudit() *PermissionAudit {
+	if r.permissionAudit == nil {
+		r.permissionAudit = NewPermissionAudit(r.db)
+	}
+	return r.permissionAudit
+}
+
+func (r *Repos) Notification() *Notification {
+	if r.notification == nil {
+		r.notification = NewNotification(r.db)
+	}
+	return r.notification
+}
+
+func (r *Repos) ChangeRequest() *ChangeRequest {
+	if r.changeRequest == nil {
+		r.changeRequest = NewChangeRequest(r.db)
+	}
+	return r.changeRequest
+}
@@ -0,0 +1,72 @@
+package repos
+
+import (
+	"context"
+	"school-information-system/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Room struct {
+	db *gorm.DB
+	create[models.Room]
+	read[models.Room]
+	update[models.Room]
+	delete[models.Room]
+}
+
+func NewRoom(db *gorm.DB) *Room {
+	return &Room{db, create[models.Room]{db}, read[models.Room]{db}, update[models.Room]{db}, delete[models.Room]{db}}
+}
+
+func (r *Room) WithTx(tx *gorm.DB) *Room {
+	return NewRoom(tx)
+}
+
+func (r *Room) DB() *gorm.DB {
+	return r.db
+}
+
+type RoomBooking struct {
+	db *gorm.DB
+	create[models.RoomBooking]
+	read[models.RoomBooking]
+	update[models.RoomBooking]
+}
+
+func NewRoomBooking(db *gorm.DB) *RoomBooking {
+	return &RoomBooking{db, create[models.RoomBooking]{db}, read[models.RoomBooking]{db}, update[models.RoomBooking]{db}}
+}
+
+func (r *RoomBooking) WithTx(tx *gorm.DB) *RoomBooking {
+	return NewRoomBooking(tx)
+}
+
+func (r *RoomBooking) DB() *gorm.DB {
+	return r.db
+}
+
+// Active returns pending and approved bookings of the room overlapping the period
+func (r *RoomBooking) Active(ctx context.Context, roomID string, startAt, endAt time.Time) (bookings []models.RoomBooking, err error) {
+	err = r.db.WithContext(ctx).
+		Where("room_id = ? AND status IN ?", roomID, []models.BookingStatus{models.BookingPending, models.BookingApproved}).
+		Where("start_at < ? AND end_at > ?", endAt, startAt).
+		Order("start_at").
+		Find(&bookings).Error
+	return
+}
+
+// Collides reports whether room has pending or approved booking overlapping the period, booking of excludeID is ignored
+func (r *RoomBooking) Collides(ctx context.Context, roomID string, startAt, endAt time.Time, excludeID string) (bool, error) {
+	q := r.db.Model(new(models.RoomBooking)).WithContext(ctx).
+		Where("room_id = ? AND status IN ?", roomID, []models.BookingStatus{models.BookingPending, models.BookingApproved}).
+		Where("start_at < ? AND end_at > ?", endAt, startAt)
+	if excludeID != "" {
+		q = q.Where("id <> ?", excludeID)
+	}
+
+	var count int64
+	err := q.Limit(1).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,66 @@
+package repos
+
+import (
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ScholarshipProgram struct {
+	db *gorm.DB
+	create[models.ScholarshipProgram]
+	read[models.ScholarshipProgram]
+	update[models.ScholarshipProgram]
+	delete[models.ScholarshipProgram]
+}
+
+func NewScholarshipProgram(db *gorm.DB) *ScholarshipProgram {
+	return &ScholarshipProgram{db, create[models.ScholarshipProgram]{db}, read[models.ScholarshipProgram]{db}, update[models.ScholarshipProgram]{db}, delete[models.ScholarshipProgram]{db}}
+}
+
+func (r *ScholarshipProgram) WithTx(tx *gorm.DB) *ScholarshipProgram {
+	return NewScholarshipProgram(tx)
+}
+
+func (r *ScholarshipProgram) DB() *gorm.DB {
+	return r.db
+}
+
+type ScholarshipRecipient struct {
+	db *gorm.DB
+	create[models.ScholarshipRecipient]
+	read[models.ScholarshipRecipient]
+	update[models.ScholarshipRecipient]
+	delete[models.ScholarshipRecipient]
+}
+
+func NewScholarshipRecipient(db *gorm.DB) *ScholarshipRecipient {
+	return &ScholarshipRecipient{db, create[models.ScholarshipRecipient]{db}, read[models.ScholarshipRecipient]{db}, update[models.ScholarshipRecipient]{db}, delete[models.ScholarshipRecipient]{db}}
+}
+
+func (r *ScholarshipRecipient) WithTx(tx *gorm.DB) *ScholarshipRecipient {
+	return NewScholarshipRecipient(tx)
+}
+
+func (r *ScholarshipRecipient) DB() *gorm.DB {
+	return r.db
+}
+
+type ScholarshipDisbursement struct {
+	db *gorm.DB
+	create[models.ScholarshipDisbursement]
+	read[models.ScholarshipDisbursement]
+	delete[models.ScholarshipDisbursement]
+}
+
+func NewScholarshipDisbursement(db *gorm.DB) *ScholarshipDisbursement {
+	return &ScholarshipDisbursement{db, create[models.ScholarshipDisbursement]{db}, read[models.ScholarshipDisbursement]{db}, delete[models.ScholarshipDisbursement]{db}}
+}
+
+func (r *ScholarshipDisbursement) WithTx(tx *gorm.DB) *ScholarshipDisbursement {
+	return NewScholarshipDisbursement(tx)
+}
+
+func (r *ScholarshipDisbursement) DB() *gorm.DB {
+	return r.db
+}
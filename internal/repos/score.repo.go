@@ -0,0 +1,37 @@
+package repos
+
+import (
+	"context"
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Score struct {
+	db *gorm.DB
+	create[models.Score]
+	read[models.Score]
+	update[models.Score]
+	delete[models.Score]
+}
+
+func NewScore(db *gorm.DB) *Score {
+	return &Score{db, create[models.Score]{db}, read[models.Score]{db}, update[models.Score]{db}, delete[models.Score]{db}}
+}
+
+func (r *Score) WithTx(tx *gorm.DB) *Score {
+	return NewScore(tx)
+}
+
+func (r *Score) DB() *gorm.DB {
+	return r.db
+}
+
+// Record creates score or replaces value of existing score with same source and student
+func (r *Score) Record(ctx context.Context, score *models.Score) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_id"}, {Name: "source"}, {Name: "source_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "note", "graded_by_id", "updated_at"}),
+	}).Create(score).Error
+}
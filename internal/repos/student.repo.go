@@ -12,10 +12,11 @@ type Student struct {
 	read[models.Student]
 	update[models.Student]
 	delete[models.Student]
+	archivable[models.Student]
 }
 
 func NewStudent(db *gorm.DB) *Student {
-	return &Student{db, create[models.Student]{db}, read[models.Student]{db}, update[models.Student]{db}, delete[models.Student]{db}}
+	return &Student{db, create[models.Student]{db}, read[models.Student]{db}, update[models.Student]{db}, delete[models.Student]{db}, archivable[models.Student]{db}}
 }
 
 func (r *Student) WithTx(tx *gorm.DB) *Student {
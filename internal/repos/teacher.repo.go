@@ -1,6 +1,7 @@
 package repos
 
 import (
+	"context"
 	"school-information-system/internal/models"
 
 	"gorm.io/gorm"
@@ -12,10 +13,11 @@ type Teacher struct {
 	read[models.Teacher]
 	update[models.Teacher]
 	delete[models.Teacher]
+	archivable[models.Teacher]
 }
 
 func NewTeacher(db *gorm.DB) *Teacher {
-	return &Teacher{db, create[models.Teacher]{db}, read[models.Teacher]{db}, update[models.Teacher]{db}, delete[models.Teacher]{db}}
+	return &Teacher{db, create[models.Teacher]{db}, read[models.Teacher]{db}, update[models.Teacher]{db}, delete[models.Teacher]{db}, archivable[models.Teacher]{db}}
 }
 
 func (r *Teacher) WithTx(tx *gorm.DB) *Teacher {
@@ -25,3 +27,20 @@ func (r *Teacher) WithTx(tx *gorm.DB) *Teacher {
 func (r *Teacher) DB() *gorm.DB {
 	return r.db
 }
+
+// TeachesSubject reports whether subject registered as subject of the teacher
+func (r *Teacher) TeachesSubject(ctx context.Context, teacherID, subjectID string) (teaches bool, err error) {
+	err = r.db.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM teacher_subjects WHERE teacher_id = ? AND subject_id = ?)", teacherID, subjectID).
+		Scan(&teaches).Error
+	return
+}
+
+// TeachesClass reports whether teacher is form teacher of the class or teaches a timetable slot of the class
+func (r *Teacher) TeachesClass(ctx context.Context, teacherID, classID string) (teaches bool, err error) {
+	err = r.db.WithContext(ctx).
+		Raw(`SELECT EXISTS (SELECT 1 FROM classes WHERE id = ? AND form_teacher_id = ?)
+			OR EXISTS (SELECT 1 FROM timetable_slots WHERE class_id = ? AND teacher_id = ?)`, classID, teacherID, classID, teacherID).
+		Scan(&teaches).Error
+	return
+}
@@ -0,0 +1,57 @@
+package repos
+
+import (
+	"context"
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type TimetableSlot struct {
+	db *gorm.DB
+	create[models.TimetableSlot]
+	read[models.TimetableSlot]
+	update[models.TimetableSlot]
+	delete[models.TimetableSlot]
+}
+
+func NewTimetableSlot(db *gorm.DB) *TimetableSlot {
+	return &TimetableSlot{db, create[models.TimetableSlot]{db}, read[models.TimetableSlot]{db}, update[models.TimetableSlot]{db}, delete[models.TimetableSlot]{db}}
+}
+
+func (r *TimetableSlot) WithTx(tx *gorm.DB) *TimetableSlot {
+	return NewTimetableSlot(tx)
+}
+
+func (r *TimetableSlot) DB() *gorm.DB {
+	return r.db
+}
+
+// UsingRoom returns slots of the academic years held in the room, slot without room is held in home room of the class
+func (r *TimetableSlot) UsingRoom(ctx context.Context, roomID string, academicYears ...string) (slots []models.TimetableSlot, err error) {
+	err = r.db.WithContext(ctx).
+		Where("academic_year IN ?", academicYears).
+		Where("room_id = ? OR (room_id IS NULL AND class_id IN (SELECT id FROM classes WHERE home_room_id = ?))", roomID, roomID).
+		Find(&slots).Error
+	return
+}
+
+type TeachingJournal struct {
+	db *gorm.DB
+	create[models.TeachingJournal]
+	read[models.TeachingJournal]
+	update[models.TeachingJournal]
+	delete[models.TeachingJournal]
+}
+
+func NewTeachingJournal(db *gorm.DB) *TeachingJournal {
+	return &TeachingJournal{db, create[models.TeachingJournal]{db}, read[models.TeachingJournal]{db}, update[models.TeachingJournal]{db}, delete[models.TeachingJournal]{db}}
+}
+
+func (r *TeachingJournal) WithTx(tx *gorm.DB) *TeachingJournal {
+	return NewTeachingJournal(tx)
+}
+
+func (r *TeachingJournal) DB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,26 @@
+package repos
+
+import (
+	"school-information-system/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type StudentTransfer struct {
+	db *gorm.DB
+	create[models.StudentTransfer]
+	read[models.StudentTransfer]
+	update[models.StudentTransfer]
+}
+
+func NewStudentTransfer(db *gorm.DB) *StudentTransfer {
+	return &StudentTransfer{db, create[models.StudentTransfer]{db}, read[models.StudentTransfer]{db}, update[models.StudentTransfer]{db}}
+}
+
+func (r *StudentTransfer) WithTx(tx *gorm.DB) *StudentTransfer {
+	return NewStudentTransfer(tx)
+}
+
+func (r *StudentTransfer) DB() *gorm.DB {
+	return r.db
+}
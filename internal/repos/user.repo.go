@@ -44,7 +44,7 @@ func (r *User) DeleteUser(ctx context.Context, where any, args ...any) error {
 			return err
 		}
 
-		if user.Role != models.RoleStudent || user.StudentProfile == nil {
+		if !user.HasRole(models.RoleStudent) || user.StudentProfile == nil {
 			_, err := gorm.G[models.User](tx.Unscoped()).Where(where, args...).Delete(ctx)
 			return err
 		}
@@ -98,7 +98,7 @@ func (r *User) DeleteAllUser(ctx context.Context, where any, args ...any) (rowsA
 
 		var studentProfileIDs []string
 		for _, user := range users {
-			if user.Role == models.RoleStudent && user.StudentProfile != nil {
+			if user.HasRole(models.RoleStudent) && user.StudentProfile != nil {
 				studentProfileIDs = append(studentProfileIDs, user.StudentProfile.ID)
 			}
 		}
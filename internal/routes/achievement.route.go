@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterAchievement(group *gin.RouterGroup) {
+	achievementService := services.NewAchievement(rt.rp.Achievement(), rt.rp.StoredFile(), rt.rp.Student())
+	handler := handlers.NewAchievement(achievementService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.POST("/", mw.PermissionProtected(
+		models.ResourceAchievement,
+		[]models.PermissionAction{models.ActionCreate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.CreateAchievement)
+
+	group.GET("/", mw.PermissionProtected(
+		models.ResourceAchievement,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetAchievements)
+
+	group.GET("/report", mw.PermissionProtected(
+		models.ResourceAchievement,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetAchievementReport)
+
+	group.GET("/students/:id/portfolio", mw.PermissionProtected(
+		models.ResourceAchievement,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetPortfolio)
+
+	group.GET("/:id", mw.PermissionProtected(
+		models.ResourceAchievement,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetAchievement)
+
+	group.GET("/:id/certificate", mw.PermissionProtected(
+		models.ResourceAchievement,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.DownloadCertificate)
+
+	group.DELETE("/:id", mw.PermissionProtected(
+		models.ResourceAchievement,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteAchievement)
+}
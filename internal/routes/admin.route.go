@@ -10,7 +10,7 @@ import (
 )
 
 func (rt *Route) RegisterAdmin(group *gin.RouterGroup) {
-	adminService := services.NewAdmin(rt.rp.User(), rt.rp.Admin())
+	adminService := services.NewAdmin(rt.rp.User(), rt.rp.Admin(), rt.rp.PermissionAudit(), rt.rp.ChangeRequest(), rt.rp.Notification())
 	roleSetterService := services.NewRoleSetter(
 		rt.rp.User(),
 		rt.rp.Admin(),
@@ -19,6 +19,9 @@ func (rt *Route) RegisterAdmin(group *gin.RouterGroup) {
 		rt.rp.Parent(),
 		rt.rp.Teacher(),
 		rt.rp.Subject(),
+		rt.rp.PermissionAudit(),
+		rt.rp.ChangeRequest(),
+		rt.rp.Notification(),
 	)
 
 	handler := handlers.NewAdmin(adminService, roleSetterService)
@@ -33,6 +36,33 @@ func (rt *Route) RegisterAdmin(group *gin.RouterGroup) {
 		mw.PermissionProtected(models.ResourceRole, []models.PermissionAction{models.ActionRead, models.ActionUpdate}),
 		handler.SetRole,
 	)
+	group.PUT(
+		"/remove-role",
+		mw.PermissionProtected(models.ResourceRole, []models.PermissionAction{models.ActionRead, models.ActionUpdate}),
+		handler.RemoveRole,
+	)
+
+	group.GET("/", mw.PermissionProtected(
+		models.ResourceAdmin,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetAdmins)
+	group.GET("/:id", mw.PermissionProtected(
+		models.ResourceAdmin,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetAdmin)
+	group.GET("/:id/permissions/effective", mw.PermissionProtected(
+		models.ResourceAdmin,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetEffectivePermissions)
+	group.PUT("/:id", mw.PermissionProtected(
+		models.ResourceAdmin,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdateAdmin)
+	group.DELETE("/:id", mw.PermissionProtected(
+		models.ResourceAdmin,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeactivateAdmin)
 
 	rt.RegisterPermission(group.Group("/permissions"))
+	rt.RegisterChangeRequest(group.Group("/change-requests"))
 }
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterAdmission(group *gin.RouterGroup) {
+	admissionService := services.NewAdmission(rt.rp.Admission(), rt.rp.User(), rt.rp.Student(), rt.rp.Class(), rt.rp.Parent())
+	handler := handlers.NewAdmission(admissionService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.POST("/", handler.SubmitAdmission)
+	group.GET("/track", handler.TrackAdmission)
+
+	group.GET("/", mw.PermissionProtected(
+		models.ResourceAdmission,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetAdmissions)
+
+	group.GET("/:id", mw.PermissionProtected(
+		models.ResourceAdmission,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetAdmission)
+
+	group.PUT("/:id/stage", mw.PermissionProtected(
+		models.ResourceAdmission,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.MoveStage)
+
+	group.POST("/:id/enroll", mw.PermissionProtected(
+		models.ResourceAdmission,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.Enroll)
+}
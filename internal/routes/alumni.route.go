@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterAlumni(group *gin.RouterGroup) {
+	alumniService := services.NewAlumni(rt.rp.Alumni(), rt.rp.User(), rt.rp.Student(), rt.rp.Class())
+	handler := handlers.NewAlumni(alumniService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.POST("/graduate", mw.PermissionProtected(
+		models.ResourceAlumni,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.Graduate)
+
+	group.GET("/", mw.PermissionProtected(
+		models.ResourceAlumni,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleAlumni),
+	), handler.GetDirectory)
+
+	group.GET("/me", mw.RoleProtected(models.RoleAlumni), handler.GetSelf)
+
+	group.GET("/:id", mw.PermissionProtected(
+		models.ResourceAlumni,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetAlumni)
+
+	group.PUT("/:id/access", mw.PermissionProtected(
+		models.ResourceAlumni,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdateAccess)
+}
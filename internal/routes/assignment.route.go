@@ -0,0 +1,77 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterAssignment(group *gin.RouterGroup) {
+	assignmentService := services.NewAssignment(
+		rt.rp.Assignment(),
+		rt.rp.Submission(),
+		rt.rp.Score(),
+		rt.rp.StoredFile(),
+		rt.rp.Teacher(),
+		rt.rp.Student(),
+		rt.rp.Class(),
+	)
+	handler := handlers.NewAssignment(assignmentService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.POST("/", mw.RoleProtected(models.RoleTeacher), handler.CreateAssignment)
+
+	group.GET("/", mw.PermissionProtected(
+		models.ResourceAssignment,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetAssignments)
+
+	group.GET("/pending", mw.RoleProtected(models.RoleStudent), handler.GetPendingAssignments)
+
+	group.GET("/:id", mw.PermissionProtected(
+		models.ResourceAssignment,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetAssignment)
+
+	group.GET("/:id/download", mw.PermissionProtected(
+		models.ResourceAssignment,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.DownloadAttachment)
+
+	group.DELETE("/:id", mw.PermissionProtected(
+		models.ResourceAssignment,
+		[]models.PermissionAction{models.ActionDelete},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.DeleteAssignment)
+
+	// submissions
+
+	group.POST("/:id/submissions", mw.RoleProtected(models.RoleStudent), handler.SubmitAssignment)
+
+	group.GET("/:id/submissions", mw.PermissionProtected(
+		models.ResourceAssignment,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetSubmissions)
+
+	group.GET("/submissions/ungraded", mw.RoleProtected(models.RoleTeacher), handler.GetUngradedSubmissions)
+
+	group.GET("/submissions/:id/download", mw.PermissionProtected(
+		models.ResourceAssignment,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.DownloadSubmission)
+
+	group.PUT("/submissions/:id/grade", mw.PermissionProtected(
+		models.ResourceAssignment,
+		[]models.PermissionAction{models.ActionUpdate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GradeSubmission)
+}
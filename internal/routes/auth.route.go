@@ -2,17 +2,20 @@ package routes
 
 import (
 	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
 	"school-information-system/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func (rt *Route) RegisterAuth(group *gin.RouterGroup) {
-	authService := services.NewAuth(rt.rp.User(), rt.rp.Revoked())
+	authService := services.NewAuth(rt.rp.User(), rt.rp.Revoked(), rt.rp.Alumni())
 
 	handler := handlers.NewAuth(authService)
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
 
 	group.POST("/sign-up", handler.SignUp)
 	group.POST("/sign-in", handler.SignIn)
 	group.POST("/sign-out", handler.SignOut)
+	group.PUT("/role", mw.Protected(), handler.SwitchRole)
 }
@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterQuestionBank(group *gin.RouterGroup) {
+	bankService := services.NewQuestionBank(rt.rp.BankQuestion(), rt.rp.BankQuestionVersion(), rt.rp.Teacher())
+	handler := handlers.NewQuestionBank(bankService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.POST("/", mw.PermissionProtected(
+		models.ResourceQuestionBank,
+		[]models.PermissionAction{models.ActionCreate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.CreateQuestion)
+
+	group.GET("/", mw.PermissionProtected(
+		models.ResourceQuestionBank,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetQuestions)
+
+	group.POST("/paper", mw.PermissionProtected(
+		models.ResourceQuestionBank,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.MakeExamPaper)
+
+	group.GET("/:id", mw.PermissionProtected(
+		models.ResourceQuestionBank,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetQuestion)
+
+	group.PUT("/:id", mw.PermissionProtected(
+		models.ResourceQuestionBank,
+		[]models.PermissionAction{models.ActionUpdate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.UpdateQuestion)
+
+	group.DELETE("/:id", mw.PermissionProtected(
+		models.ResourceQuestionBank,
+		[]models.PermissionAction{models.ActionDelete},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.DeleteQuestion)
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterChangeRequest(group *gin.RouterGroup) {
+	permService := services.NewPermission(rt.rp.User(), rt.rp.Permission(), rt.rp.Admin(), rt.rp.PermissionBundle(), rt.rp.PermissionAudit(), rt.rp.ChangeRequest(), rt.rp.Notification())
+	roleSetterService := services.NewRoleSetter(
+		rt.rp.User(),
+		rt.rp.Admin(),
+		rt.rp.Student(),
+		rt.rp.Class(),
+		rt.rp.Parent(),
+		rt.rp.Teacher(),
+		rt.rp.Subject(),
+		rt.rp.PermissionAudit(),
+		rt.rp.ChangeRequest(),
+		rt.rp.Notification(),
+	)
+	adminService := services.NewAdmin(rt.rp.User(), rt.rp.Admin(), rt.rp.PermissionAudit(), rt.rp.ChangeRequest(), rt.rp.Notification())
+	changeRequestService := services.NewChangeRequest(rt.rp.ChangeRequest(), rt.rp.User(), rt.rp.Notification(), permService, roleSetterService, adminService)
+
+	handler := handlers.NewChangeRequest(changeRequestService)
+
+	// admin role is protected by admin group, reviewer permission is validated by service since it depends on the change
+	group.GET("/", handler.GetChangeRequests)
+	group.GET("/:id", handler.GetChangeRequest)
+	group.PUT("/:id/approve", handler.ApproveChangeRequest)
+	group.PUT("/:id/reject", handler.RejectChangeRequest)
+}
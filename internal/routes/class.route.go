@@ -10,7 +10,7 @@ import (
 )
 
 func (rt *Route) RegisterClass(group *gin.RouterGroup) {
-	classService := services.NewClass(rt.rp.Class(), rt.rp.User(), rt.rp.Teacher(), rt.rp.Student())
+	classService := services.NewClass(rt.rp.Class(), rt.rp.User(), rt.rp.Teacher(), rt.rp.Student(), rt.rp.Room())
 
 	handler := handlers.NewClass(classService)
 
@@ -94,4 +94,14 @@ func (rt *Route) RegisterClass(group *gin.RouterGroup) {
 		models.ResourceTeacher,
 		[]models.PermissionAction{models.ActionUpdate},
 	), handler.RemoveFormTeacher)
+
+	group.PUT("/:id/home-room", mw.PermissionProtected(
+		models.ResourceClass,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.SetHomeRoom)
+
+	group.DELETE("/:id/home-room", mw.PermissionProtected(
+		models.ResourceClass,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.RemoveHomeRoom)
 }
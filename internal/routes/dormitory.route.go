@@ -0,0 +1,105 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterDormitory(group *gin.RouterGroup) {
+	dormitoryService := services.NewDormitory(
+		rt.rp.Dormitory(),
+		rt.rp.DormRoom(),
+		rt.rp.DormAssignment(),
+		rt.rp.NightRoll(),
+		rt.rp.Teacher(),
+		rt.rp.Student(),
+	)
+	handler := handlers.NewDormitory(dormitoryService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.POST("/", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreateDormitory)
+
+	group.GET("/", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetDormitories)
+
+	group.GET("/:id", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetDormitory)
+
+	group.PUT("/:id", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdateDormitory)
+
+	group.DELETE("/:id", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteDormitory)
+
+	group.PUT("/:id/supervisors", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.SetSupervisors)
+
+	// rooms
+
+	group.POST("/:id/rooms", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreateRoom)
+
+	group.PUT("/rooms/:id", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdateRoom)
+
+	group.DELETE("/rooms/:id", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteRoom)
+
+	// assignments
+
+	group.POST("/rooms/:id/assignments", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.AssignRoom)
+
+	group.GET("/assignments", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetAssignments)
+
+	group.DELETE("/assignments/:id", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteAssignment)
+
+	// night rolls, recorded by supervisors
+
+	group.POST("/:id/night-rolls", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionCreate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.RecordNightRoll)
+
+	group.GET("/:id/night-rolls", mw.PermissionProtected(
+		models.ResourceDormitory,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetNightRoll)
+}
@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterExam(group *gin.RouterGroup) {
+	examService := services.NewExam(
+		rt.rp.ExamPeriod(),
+		rt.rp.ExamRoom(),
+		rt.rp.ExamSession(),
+		rt.rp.ExamSeat(),
+		rt.rp.ExamInvigilator(),
+		rt.rp.Subject(),
+		rt.rp.Teacher(),
+		rt.rp.Student(),
+		rt.rp.Room(),
+	)
+	handler := handlers.NewExam(examService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	// periods
+
+	group.POST("/periods", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreatePeriod)
+
+	group.GET("/periods", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetPeriods)
+
+	group.GET("/periods/:id", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetPeriod)
+
+	group.DELETE("/periods/:id", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeletePeriod)
+
+	group.POST("/periods/:id/sessions", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreateSession)
+
+	group.GET("/periods/:id/card", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleStudent),
+	), handler.GetExamCard)
+
+	// sessions
+
+	group.DELETE("/sessions/:id", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteSession)
+
+	group.PUT("/sessions/:id/seats", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.AllocateSeats)
+
+	group.GET("/sessions/:id/rooms", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetRoomLists)
+
+	group.POST("/sessions/:id/invigilators", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.AssignInvigilator)
+
+	group.DELETE("/invigilators/:id", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.RemoveInvigilator)
+
+	// rooms
+
+	group.POST("/rooms", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreateRoom)
+
+	group.GET("/rooms", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetRooms)
+
+	group.PUT("/rooms/:id", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdateRoom)
+
+	group.DELETE("/rooms/:id", mw.PermissionProtected(
+		models.ResourceExam,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteRoom)
+}
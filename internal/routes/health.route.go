@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterHealth(group *gin.RouterGroup) {
+	healthService := services.NewHealth(rt.rp.StudentHealth(), rt.rp.ClinicVisit(), rt.rp.Student(), rt.rp.Class(), rt.rp.Teacher())
+	handler := handlers.NewHealth(healthService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.GET("/students/:id", mw.PermissionProtected(
+		models.ResourceHealth,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetStudentHealth)
+
+	group.PUT("/students/:id", mw.PermissionProtected(
+		models.ResourceHealth,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.SetStudentHealth)
+
+	// clinic visits
+
+	group.POST("/visits", mw.PermissionProtected(
+		models.ResourceHealth,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreateVisit)
+
+	group.GET("/visits", mw.PermissionProtected(
+		models.ResourceHealth,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetVisits)
+
+	group.PUT("/visits/:id/parent-notified", mw.PermissionProtected(
+		models.ResourceHealth,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.NotifyVisitParent)
+
+	group.DELETE("/visits/:id", mw.PermissionProtected(
+		models.ResourceHealth,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteVisit)
+
+	// allergy flags are shared to teachers of the class, the rest of health data is not
+
+	group.GET("/classes/:id/allergies", mw.PermissionProtected(
+		models.ResourceHealth,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetClassAllergies)
+}
@@ -0,0 +1,119 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterInternship(group *gin.RouterGroup) {
+	internshipService := services.NewInternship(
+		rt.rp.IndustryPartner(),
+		rt.rp.InternshipPlacement(),
+		rt.rp.InternshipJournal(),
+		rt.rp.MonitoringVisit(),
+		rt.rp.Teacher(),
+		rt.rp.Student(),
+	)
+	handler := handlers.NewInternship(internshipService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	// evaluation by industry mentor, authorized by token of evaluation link
+
+	group.GET("/evaluation", handler.GetEvaluationForm)
+	group.POST("/evaluation", handler.SubmitEvaluation)
+
+	// partners
+
+	group.POST("/partners", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreatePartner)
+
+	group.GET("/partners", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetPartners)
+
+	group.GET("/partners/:id", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetPartner)
+
+	group.PUT("/partners/:id", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdatePartner)
+
+	group.DELETE("/partners/:id", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeletePartner)
+
+	// placements
+
+	group.POST("/placements", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreatePlacement)
+
+	group.GET("/placements", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetPlacements)
+
+	group.GET("/placements/:id", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetPlacement)
+
+	group.PUT("/placements/:id", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdatePlacement)
+
+	group.DELETE("/placements/:id", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeletePlacement)
+
+	group.POST("/placements/:id/evaluation-link", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionUpdate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.CreateEvaluationLink)
+
+	// journals, written by students and reviewed by supervisors
+
+	group.POST("/placements/:id/journals", mw.RoleProtected(models.RoleStudent), handler.WriteJournal)
+
+	group.GET("/placements/:id/journals", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetJournals)
+
+	group.PUT("/journals/:id/review", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionUpdate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.ReviewJournal)
+
+	// monitoring visits
+
+	group.POST("/placements/:id/visits", mw.RoleProtected(models.RoleTeacher), handler.CreateVisit)
+
+	group.DELETE("/visits/:id", mw.PermissionProtected(
+		models.ResourceInternship,
+		[]models.PermissionAction{models.ActionDelete},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.DeleteVisit)
+}
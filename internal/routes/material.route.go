@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterMaterial(group *gin.RouterGroup) {
+	materialService := services.NewMaterial(rt.rp.Material(), rt.rp.StoredFile(), rt.rp.Teacher(), rt.rp.Student(), rt.rp.Class())
+	handler := handlers.NewMaterial(materialService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.POST("/", mw.RoleProtected(models.RoleTeacher), handler.CreateMaterial)
+
+	group.GET("/", mw.PermissionProtected(
+		models.ResourceMaterial,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetMaterials)
+
+	group.GET("/:id", mw.PermissionProtected(
+		models.ResourceMaterial,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetMaterial)
+
+	group.GET("/:id/download", mw.PermissionProtected(
+		models.ResourceMaterial,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.DownloadMaterial)
+
+	group.DELETE("/:id", mw.PermissionProtected(
+		models.ResourceMaterial,
+		[]models.PermissionAction{models.ActionDelete},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.DeleteMaterial)
+}
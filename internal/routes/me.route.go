@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterMe(group *gin.RouterGroup) {
+	adminService := services.NewAdmin(rt.rp.User(), rt.rp.Admin(), rt.rp.PermissionAudit(), rt.rp.ChangeRequest(), rt.rp.Notification())
+	handler := handlers.NewAdmin(adminService, nil)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.Use(mw.Protected())
+
+	group.GET("/permissions", handler.GetMyPermissions)
+}
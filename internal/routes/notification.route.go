@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterNotification(group *gin.RouterGroup) {
+	notificationService := services.NewNotification(rt.rp.Notification())
+	handler := handlers.NewNotification(notificationService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.Use(mw.Protected())
+
+	group.GET("/", handler.GetNotifications)
+	group.PUT("/:id/read", handler.ReadNotification)
+}
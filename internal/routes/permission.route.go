@@ -10,7 +10,7 @@ import (
 )
 
 func (rt *Route) RegisterPermission(group *gin.RouterGroup) {
-	permService := services.NewPermission(rt.rp.User(), rt.rp.Permission())
+	permService := services.NewPermission(rt.rp.User(), rt.rp.Permission(), rt.rp.Admin(), rt.rp.PermissionBundle(), rt.rp.PermissionAudit(), rt.rp.ChangeRequest(), rt.rp.Notification())
 
 	handler := handlers.NewPermission(permService)
 
@@ -51,4 +51,40 @@ func (rt *Route) RegisterPermission(group *gin.RouterGroup) {
 		models.ResourcePermission,
 		[]models.PermissionAction{models.ActionUpdate},
 	), handler.RevokePermission)
+
+	// bundles
+	group.POST("/bundles", mw.PermissionProtected(
+		models.ResourcePermission,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreateBundle)
+	group.GET("/bundles/:id", mw.PermissionProtected(
+		models.ResourcePermission,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetBundle)
+	group.GET("/bundles", mw.PermissionProtected(
+		models.ResourcePermission,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetBundles)
+	group.PUT("/bundles/:id", mw.PermissionProtected(
+		models.ResourcePermission,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdateBundle)
+	group.DELETE("/bundles/:id", mw.PermissionProtected(
+		models.ResourcePermission,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteBundle)
+	group.PUT("/bundles/grant", mw.PermissionProtected(
+		models.ResourcePermission,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.GrantBundle)
+	group.DELETE("/bundles/revoke", mw.PermissionProtected(
+		models.ResourcePermission,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.RevokeBundle)
+
+	// audits
+	group.GET("/audits", mw.PermissionProtected(
+		models.ResourcePermission,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetAudits)
 }
@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterQuiz(group *gin.RouterGroup) {
+	quizService := services.NewQuiz(
+		rt.rp.Quiz(),
+		rt.rp.QuizWindow(),
+		rt.rp.QuizAttempt(),
+		rt.rp.QuizAnswer(),
+		rt.rp.Score(),
+		rt.rp.Teacher(),
+		rt.rp.Student(),
+		rt.rp.Class(),
+		rt.rp.BankQuestion(),
+	)
+	handler := handlers.NewQuiz(quizService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.POST("/", mw.RoleProtected(models.RoleTeacher), handler.CreateQuiz)
+
+	group.GET("/", mw.PermissionProtected(
+		models.ResourceQuiz,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetQuizzes)
+
+	group.GET("/:id", mw.PermissionProtected(
+		models.ResourceQuiz,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetQuiz)
+
+	group.DELETE("/:id", mw.PermissionProtected(
+		models.ResourceQuiz,
+		[]models.PermissionAction{models.ActionDelete},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.DeleteQuiz)
+
+	group.PUT("/:id/windows", mw.PermissionProtected(
+		models.ResourceQuiz,
+		[]models.PermissionAction{models.ActionUpdate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.SetWindow)
+
+	group.POST("/:id/questions/bank", mw.PermissionProtected(
+		models.ResourceQuiz,
+		[]models.PermissionAction{models.ActionUpdate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.AddBankQuestions)
+
+	// attempts
+
+	group.POST("/:id/attempts", mw.RoleProtected(models.RoleStudent), handler.StartAttempt)
+
+	group.GET("/:id/attempts", mw.PermissionProtected(
+		models.ResourceQuiz,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetAttempts)
+
+	group.GET("/attempts/:id", mw.PermissionProtected(
+		models.ResourceQuiz,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher, models.RoleStudent),
+	), handler.GetAttempt)
+
+	group.POST("/attempts/:id/submit", mw.RoleProtected(models.RoleStudent), handler.SubmitAttempt)
+
+	group.PUT("/answers/:id/grade", mw.PermissionProtected(
+		models.ResourceQuiz,
+		[]models.PermissionAction{models.ActionUpdate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GradeAnswer)
+}
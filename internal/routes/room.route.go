@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterRoom(group *gin.RouterGroup) {
+	roomService := services.NewRoom(rt.rp.Room(), rt.rp.RoomBooking(), rt.rp.ExamSession(), rt.rp.TimetableSlot())
+	handler := handlers.NewRoom(roomService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.POST("/", mw.PermissionProtected(
+		models.ResourceRoom,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreateRoom)
+
+	group.GET("/", mw.PermissionProtected(
+		models.ResourceRoom,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetRooms)
+
+	group.GET("/:id", mw.PermissionProtected(
+		models.ResourceRoom,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetRoom)
+
+	group.PUT("/:id", mw.PermissionProtected(
+		models.ResourceRoom,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdateRoom)
+
+	group.DELETE("/:id", mw.PermissionProtected(
+		models.ResourceRoom,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteRoom)
+
+	// bookings
+
+	group.POST("/bookings", mw.PermissionProtected(
+		models.ResourceRoom,
+		[]models.PermissionAction{models.ActionCreate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.CreateBooking)
+
+	group.GET("/bookings", mw.PermissionProtected(
+		models.ResourceRoom,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetBookings)
+
+	group.PUT("/bookings/:id/review", mw.PermissionProtected(
+		models.ResourceRoom,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.ReviewBooking)
+
+	group.POST("/bookings/:id/cancel", mw.PermissionProtected(
+		models.ResourceRoom,
+		[]models.PermissionAction{models.ActionUpdate},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.CancelBooking)
+}
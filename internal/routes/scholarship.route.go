@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterScholarship(group *gin.RouterGroup) {
+	scholarshipService := services.NewScholarship(rt.rp.ScholarshipProgram(), rt.rp.ScholarshipRecipient(), rt.rp.ScholarshipDisbursement(), rt.rp.Student())
+	handler := handlers.NewScholarship(scholarshipService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.POST("/programs", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreateProgram)
+
+	group.GET("/programs", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetPrograms)
+
+	group.GET("/programs/:id", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetProgram)
+
+	group.PUT("/programs/:id", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdateProgram)
+
+	group.DELETE("/programs/:id", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteProgram)
+
+	// recipients
+
+	group.POST("/programs/:id/recipients", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.AddRecipients)
+
+	group.GET("/recipients", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetRecipients)
+
+	group.GET("/recipients/:id", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetRecipient)
+
+	group.DELETE("/recipients/:id", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.RemoveRecipient)
+
+	// disbursements
+
+	group.POST("/recipients/:id/disbursements", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreateDisbursement)
+
+	group.DELETE("/disbursements/:id", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteDisbursement)
+
+	// reporting
+
+	group.GET("/students/:id/fee-discount", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetFeeDiscount)
+
+	group.GET("/report", mw.PermissionProtected(
+		models.ResourceScholarship,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetReport)
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func (rt *Route) RegisterStudent(group *gin.RouterGroup) {
-	studentService := services.NewStudent(rt.rp.Student(), rt.rp.Parent())
+	studentService := services.NewStudent(rt.rp.Student(), rt.rp.Parent(), rt.rp.User(), rt.rp.Class(), rt.rp.StudentTransfer())
 	handler := handlers.NewStudent(studentService)
 
 	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
@@ -19,4 +19,25 @@ func (rt *Route) RegisterStudent(group *gin.RouterGroup) {
 		models.ResourceStudent,
 		[]models.PermissionAction{models.ActionUpdate},
 	), handler.UpdateStudent)
+
+	// TRANSFER
+
+	group.POST("/transfer-in", mw.PermissionProtected(
+		models.ResourceStudent,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.TransferIn)
+
+	group.POST("/:id/transfer-out", mw.PermissionProtected(
+		models.ResourceStudent,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.TransferOut)
+
+	group.GET("/transfers/:id", mw.PermissionProtected(
+		models.ResourceStudent,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetTransfer)
+	group.GET("/transfers", mw.PermissionProtected(
+		models.ResourceStudent,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetTransfers)
 }
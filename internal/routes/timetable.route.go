@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterTimetable(group *gin.RouterGroup) {
+	timetableService := services.NewTimetable(
+		rt.rp.TimetableSlot(),
+		rt.rp.TeachingJournal(),
+		rt.rp.Teacher(),
+		rt.rp.Class(),
+		rt.rp.Subject(),
+		rt.rp.Student(),
+		rt.rp.RoomBooking(),
+	)
+	handler := handlers.NewTimetable(timetableService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	// slots
+
+	group.POST("/slots", mw.PermissionProtected(
+		models.ResourceTimetable,
+		[]models.PermissionAction{models.ActionCreate},
+	), handler.CreateSlot)
+
+	group.GET("/slots", mw.PermissionProtected(
+		models.ResourceTimetable,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetSlots)
+
+	group.GET("/slots/:id", mw.PermissionProtected(
+		models.ResourceTimetable,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetSlot)
+
+	group.PUT("/slots/:id", mw.PermissionProtected(
+		models.ResourceTimetable,
+		[]models.PermissionAction{models.ActionUpdate},
+	), handler.UpdateSlot)
+
+	group.DELETE("/slots/:id", mw.PermissionProtected(
+		models.ResourceTimetable,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.DeleteSlot)
+
+	// teaching journals, written by teachers
+
+	group.POST("/journals", mw.RoleProtected(models.RoleTeacher), handler.CreateJournal)
+
+	group.GET("/journals", mw.PermissionProtected(
+		models.ResourceTimetable,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetJournals)
+
+	group.GET("/journals/:id", mw.PermissionProtected(
+		models.ResourceTimetable,
+		[]models.PermissionAction{models.ActionRead},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.GetJournal)
+
+	group.PUT("/journals/:id", mw.RoleProtected(models.RoleTeacher), handler.UpdateJournal)
+
+	group.DELETE("/journals/:id", mw.PermissionProtected(
+		models.ResourceTimetable,
+		[]models.PermissionAction{models.ActionDelete},
+		middlewares.WithSkipRole(models.RoleTeacher),
+	), handler.DeleteJournal)
+
+	group.GET("/compliance", mw.PermissionProtected(
+		models.ResourceTimetable,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetCompliance)
+}
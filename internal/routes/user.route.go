@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"school-information-system/internal/handlers"
+	"school-information-system/internal/middlewares"
+	"school-information-system/internal/models"
+	"school-information-system/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rt *Route) RegisterUser(group *gin.RouterGroup) {
+	userService := services.NewUser(rt.rp.User())
+	handler := handlers.NewUser(userService)
+
+	mw := middlewares.NewAuth(rt.rp.User(), rt.rp.Revoked())
+
+	group.Use(mw.RoleProtected(models.RoleAdmin))
+
+	// activation queue of self-registered users
+
+	group.GET("/pending", mw.PermissionProtected(
+		models.ResourceRole,
+		[]models.PermissionAction{models.ActionRead},
+	), handler.GetPendingUsers)
+
+	group.POST("/pending/reject", mw.PermissionProtected(
+		models.ResourceRole,
+		[]models.PermissionAction{models.ActionDelete},
+	), handler.RejectPendingUsers)
+
+	// permission depends on role of targeted user, checked by service
+
+	group.GET("/", handler.GetUsers)
+	group.GET("/:id", handler.GetUser)
+	group.PUT("/:id/archive", handler.ArchiveUser)
+	group.PUT("/:id/restore", handler.RestoreUser)
+}
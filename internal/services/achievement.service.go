@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"io"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Achievement struct {
+	achievementRepo *repos.Achievement
+	storedFileRepo  *repos.StoredFile
+	studentRepo     *repos.Student
+}
+
+type ContextedAchievement struct {
+	*Achievement
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewAchievement(achievementRepo *repos.Achievement, storedFileRepo *repos.StoredFile, studentRepo *repos.Student) *Achievement {
+	return &Achievement{achievementRepo, storedFileRepo, studentRepo}
+}
+
+func (s *Achievement) ApplyContext(c *gin.Context) *ContextedAchievement {
+	return &ContextedAchievement{s, c, c.Request.Context()}
+}
+
+// ensureOwnStudent makes sure student only access own achievements, other roles are always allowed
+func (s *ContextedAchievement) ensureOwnStudent(studentID string) *reply.ErrorPayload {
+	if s.c.GetString("role") != string(models.RoleStudent) {
+		return nil
+	}
+	student, err := s.studentRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return errorlib.MakeNotFound(err, "your student profile not found", nil)
+	}
+	if student.ID != studentID {
+		return &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "you can only access your own achievements"}
+	}
+	return nil
+}
+
+// countByLevel returns achievement count of every level in order of the levels, query must select achievements table
+func countByLevel(ctx context.Context, q *gorm.DB) ([]payloads.ResponseAchievementCount, error) {
+	var rows []payloads.ResponseAchievementCount
+	if err := q.WithContext(ctx).Select("level, COUNT(*) AS count").Group("level").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[models.AchievementLevel]int, len(rows))
+	for _, row := range rows {
+		counts[row.Level] = row.Count
+	}
+
+	result := make([]payloads.ResponseAchievementCount, 0, len(models.AchievementLevels))
+	for _, level := range models.AchievementLevels {
+		result = append(result, payloads.ResponseAchievementCount{Level: level, Count: counts[level]})
+	}
+	return result, nil
+}
+
+func (s *ContextedAchievement) CreateAchievement(payload payloads.RequestCreateAchievement) (*models.Achievement, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.studentRepo.Exists(s.ctx, "id = ?", payload.StudentID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "student not found", reply.FieldsError{"student_id": "student with this ID not found"})
+	}
+
+	if payload.Date.After(time.Now()) {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "achievement date can not be in the future",
+			Fields:  reply.FieldsError{"date": "date must not be after today"},
+		}
+	}
+
+	recordedByID := s.c.GetString("userID")
+	achievement := &models.Achievement{
+		StudentID:    payload.StudentID,
+		Competition:  payload.Competition,
+		Organizer:    payload.Organizer,
+		Level:        payload.Level,
+		Rank:         payload.Rank,
+		Date:         payload.Date,
+		RecordedByID: &recordedByID,
+	}
+
+	// store certificate
+	if payload.Certificate != nil {
+		stored, errPayload := storeUpload(s.ctx, s.storedFileRepo, "certificate", payload.Certificate)
+		if errPayload != nil {
+			return nil, errPayload
+		}
+		achievement.FileID = &stored.ID
+		achievement.File = stored
+		achievement.FileName = payload.Certificate.Filename
+	}
+
+	if err := s.achievementRepo.Create(s.ctx, achievement); err != nil {
+		if achievement.FileID != nil {
+			releaseFile(s.ctx, s.achievementRepo.DB(), *achievement.FileID)
+		}
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	return achievement, nil
+}
+
+func (s *ContextedAchievement) GetAchievement(payload payloads.RequestGetAchievement) (*models.Achievement, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	achievement, err := s.achievementRepo.GetFirstWithPreload(s.ctx, []string{"File"}, "id = ?", payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "achievement not found", nil)
+	}
+	if errPayload := s.ensureOwnStudent(achievement.StudentID); errPayload != nil {
+		return nil, errPayload
+	}
+
+	return &achievement, nil
+}
+
+func (s *ContextedAchievement) GetAchievements(payload payloads.RequestGetAchievements) ([]models.Achievement, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.Achievement](s.achievementRepo.DB()).
+		Order("date DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.StudentID != "" {
+		q = q.Where("student_id = ?", payload.StudentID)
+	}
+	if payload.ClassID != "" {
+		q = q.Where("student_id IN (SELECT id FROM students WHERE class_id = ? AND deleted_at IS NULL)", payload.ClassID)
+	}
+	if payload.Level != "" {
+		q = q.Where("level = ?", payload.Level)
+	}
+	if payload.Year != 0 {
+		start := time.Date(payload.Year, time.July, 1, 0, 0, 0, 0, time.UTC)
+		q = q.Where("date >= ? AND date < ?", start, start.AddDate(1, 0, 0))
+	}
+
+	achievements, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return achievements, nil
+}
+
+// DownloadCertificate returns certificate content of achievement, caller must close the content
+func (s *ContextedAchievement) DownloadCertificate(payload payloads.RequestGetAchievement) (*models.Achievement, io.ReadCloser, *reply.ErrorPayload) {
+	achievement, errPayload := s.GetAchievement(payload)
+	if errPayload != nil {
+		return nil, nil, errPayload
+	}
+	if achievement.File == nil {
+		return nil, nil, &reply.ErrorPayload{Code: replylib.CodeNotFound, Message: "achievement has no certificate"}
+	}
+
+	content, errPayload := openStoredFile(s.ctx, achievement.File)
+	if errPayload != nil {
+		return nil, nil, errPayload
+	}
+	return achievement, content, nil
+}
+
+func (s *ContextedAchievement) DeleteAchievement(payload payloads.RequestDeleteAchievement) (errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	s.achievementRepo.DB().Transaction(func(tx *gorm.DB) error {
+		achievementRepo := s.achievementRepo.WithTx(tx)
+
+		achievement, err := achievementRepo.GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "achievement not found", nil)
+			return err
+		}
+
+		if _, err := achievementRepo.DeleteByID(s.ctx, achievement.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		if achievement.FileID != nil {
+			if err := releaseFile(s.ctx, tx, *achievement.FileID); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// GetPortfolio returns all achievements of a student with summary per level
+func (s *ContextedAchievement) GetPortfolio(payload payloads.RequestGetPortfolio) (*payloads.ResponsePortfolio, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+	if errPayload := s.ensureOwnStudent(payload.ID); errPayload != nil {
+		return nil, errPayload
+	}
+
+	student, errPayload := findStudentIdentity(s.ctx, s.studentRepo.DB(), payload.ID)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	achievements, err := gorm.G[models.Achievement](s.achievementRepo.DB()).
+		Where("student_id = ?", student.StudentID).
+		Order("date DESC").
+		Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	summary, err := countByLevel(s.ctx, s.achievementRepo.DB().Model(&models.Achievement{}).Where("student_id = ?", student.StudentID))
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	return &payloads.ResponsePortfolio{
+		ResponseStudentIdentity: *student,
+		Summary:                 summary,
+		Achievements:            achievements,
+	}, nil
+}
+
+// GetReport returns achievement count per academic year and level for accreditation report
+func (s *ContextedAchievement) GetReport(payload payloads.RequestGetAchievementReport) ([]payloads.ResponseAchievementReportYear, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+	if payload.ToYear-payload.FromYear >= 20 {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "report range is too long",
+			Fields:  reply.FieldsError{"to_year": "report can cover 20 academic years at most"},
+		}
+	}
+
+	report := make([]payloads.ResponseAchievementReportYear, 0, payload.ToYear-payload.FromYear+1)
+	for year := payload.FromYear; year <= payload.ToYear; year++ {
+		start := time.Date(year, time.July, 1, 0, 0, 0, 0, time.UTC)
+		q := s.achievementRepo.DB().Model(&models.Achievement{}).Where("date >= ? AND date < ?", start, start.AddDate(1, 0, 0))
+		levels, err := countByLevel(s.ctx, q)
+		if err != nil {
+			return nil, errorlib.MakeServerError(err)
+		}
+
+		total := 0
+		for _, level := range levels {
+			total += level.Count
+		}
+		report = append(report, payloads.ResponseAchievementReportYear{
+			AcademicYear: models.AcademicYearOf(start),
+			Total:        total,
+			Levels:       levels,
+		})
+	}
+	return report, nil
+}
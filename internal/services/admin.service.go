@@ -2,23 +2,33 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
 	"school-information-system/config"
 	"school-information-system/database/seeds"
 	"school-information-system/internal/libs/errorlib"
 	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/slicelib"
 	"school-information-system/internal/libs/validatorlib"
 	"school-information-system/internal/models"
 	"school-information-system/internal/models/payloads"
 	"school-information-system/internal/repos"
+	"slices"
+	"time"
 
 	"github.com/chesta132/goreply/reply"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Admin struct {
-	userRepo  *repos.User
-	adminRepo *repos.Admin
+	userRepo         *repos.User
+	adminRepo        *repos.Admin
+	auditRepo        *repos.PermissionAudit
+	changeRepo       *repos.ChangeRequest
+	notificationRepo *repos.Notification
 }
 
 type ContextedAdmin struct {
@@ -27,8 +37,8 @@ type ContextedAdmin struct {
 	ctx context.Context
 }
 
-func NewAdmin(userRepo *repos.User, adminRepo *repos.Admin) *Admin {
-	return &Admin{userRepo, adminRepo}
+func NewAdmin(userRepo *repos.User, adminRepo *repos.Admin, auditRepo *repos.PermissionAudit, changeRepo *repos.ChangeRequest, notificationRepo *repos.Notification) *Admin {
+	return &Admin{userRepo, adminRepo, auditRepo, changeRepo, notificationRepo}
 }
 
 func (s *Admin) ApplyContext(c *gin.Context) *ContextedAdmin {
@@ -80,19 +90,26 @@ func (s *ContextedAdmin) initiateAdminInTx(payload payloads.RequestInitiateAdmin
 		userRepo := s.userRepo.WithTx(tx)
 		adminRepo := s.adminRepo.WithTx(tx)
 
-		// update role
-		err := userRepo.UpdateByID(s.ctx, user.ID, models.User{Role: models.RoleAdmin})
+		// update role, admin is added to roles held by the user
+		user.Role = models.RoleAdmin
+		if !user.HasRole(models.RoleAdmin) {
+			user.Roles = append(user.Roles, models.RoleAdmin)
+		}
+		if user.ActivatedAt == nil {
+			now := time.Now()
+			user.ActivatedAt = &now
+		}
+		err := userRepo.UpdateByID(s.ctx, user.ID, models.User{Role: user.Role, Roles: user.Roles, ActivatedAt: user.ActivatedAt})
 		if err != nil {
 			return err
 		}
-		user.Role = models.RoleAdmin
 
 		// create admin with permission seeds
 		admin = &models.Admin{
 			StaffRole:  payload.StaffRole,
 			EmployeeID: payload.EmployeeID,
 			UserID:     user.ID,
-			TimestampJoinTime: models.TimestampJoinTime{
+			TimestampJoinTimeArchivable: models.TimestampJoinTimeArchivable{
 				JoinedAt: payload.JoinedAt,
 			},
 		}
@@ -104,3 +121,281 @@ func (s *ContextedAdmin) initiateAdminInTx(payload payloads.RequestInitiateAdmin
 	})
 	return
 }
+
+// ensureSeedsHeld makes sure every seed permission is still held by an active admin so the school can never be locked out.
+// Call it after the change in the same transaction.
+func ensureSeedsHeld(ctx context.Context, adminRepo *repos.Admin) *reply.ErrorPayload {
+	unheld, err := adminRepo.UnheldPermissions(ctx, slices.Collect(maps.Keys(seeds.PermissionSeedIDs)))
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if len(unheld) > 0 {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: fmt.Sprintf("%s to revoke", errorlib.ErrPermHaventAnotherAdmin),
+			Fields:  reply.FieldsError{"permission": "no other admin holds permission " + unheld[0].Name},
+		}
+	}
+	return nil
+}
+
+// recordAudits saves audits of permission changes made by current user
+func recordAudits(ctx context.Context, auditRepo *repos.PermissionAudit, actorID string, audits []models.PermissionAudit) *reply.ErrorPayload {
+	if len(audits) == 0 {
+		return nil
+	}
+	for i := range audits {
+		audits[i].ActorID = actorID
+	}
+	if err := auditRepo.CreateAll(ctx, &audits); err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	return nil
+}
+
+// revokeAdmin revokes permissions and bundles, then archives profile of the admin.
+// Seed permission must remain held by another admin.
+func revokeAdmin(ctx context.Context, adminRepo *repos.Admin, auditRepo *repos.PermissionAudit, actorID, adminID string) *reply.ErrorPayload {
+	admin, err := gorm.G[models.Admin](adminRepo.DB()).Preload("Permissions", nil).Preload("Bundles", nil).Where("id = ?", adminID).First(ctx)
+	if err != nil {
+		return errorlib.MakeNotFound(err, "admin not found", nil)
+	}
+
+	audits := make([]models.PermissionAudit, 0, len(admin.Permissions)+len(admin.Bundles))
+	for _, perm := range admin.Permissions {
+		audits = append(audits, models.PermissionAudit{Action: models.AuditRevokePermission, TargetAdminID: &admin.ID, PermissionID: &perm.ID, PermissionName: perm.Name})
+	}
+	for _, bundle := range admin.Bundles {
+		audits = append(audits, models.PermissionAudit{Action: models.AuditRevokeBundle, TargetAdminID: &admin.ID, BundleID: &bundle.ID, BundleName: bundle.Name})
+	}
+
+	db := adminRepo.DB().WithContext(ctx)
+	if err := db.Model(&admin).Association("Permissions").Clear(); err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if err := db.Model(&admin).Association("Bundles").Clear(); err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if err := adminRepo.Archive(ctx, "id = ?", admin.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if errPayload := ensureSeedsHeld(ctx, adminRepo); errPayload != nil {
+		return errPayload
+	}
+	return recordAudits(ctx, auditRepo, actorID, audits)
+}
+
+// getAdminUser returns user of active admin profile with permissions and additional preloads
+func (s *ContextedAdmin) getAdminUser(db *gorm.DB, adminID string, preloads ...string) (*models.User, *reply.ErrorPayload) {
+	var user models.User
+	q := db.WithContext(s.ctx).
+		Preload("AdminProfile.Permissions").
+		Preload("AdminProfile.Grants").
+		Preload("AdminProfile.Bundles")
+	for _, p := range preloads {
+		q = q.Preload(p)
+	}
+	err := q.
+		Joins("JOIN admins ON admins.user_id = users.id AND admins.deleted_at IS NULL").
+		Where("admins.id = ?", adminID).
+		First(&user).Error
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "admin not found", nil)
+	}
+	return &user, nil
+}
+
+// GetAdmins returns users of active admin profiles with their permissions
+func (s *ContextedAdmin) GetAdmins(payload payloads.RequestGetAdmins) ([]models.User, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := s.userRepo.DB().WithContext(s.ctx).
+		Preload("AdminProfile.Permissions").
+		Preload("AdminProfile.Grants").
+		Preload("AdminProfile.Bundles").
+		Joins("JOIN admins ON admins.user_id = users.id AND admins.deleted_at IS NULL").
+		Order("users.full_name").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Query != "" {
+		q = q.Where("LOWER(users.full_name) LIKE LOWER(?) OR LOWER(admins.employee_id) LIKE LOWER(?)", "%"+payload.Query+"%", "%"+payload.Query+"%")
+	}
+
+	var users []models.User
+	if err := q.Find(&users).Error; err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return users, nil
+}
+
+func (s *ContextedAdmin) GetAdmin(payload payloads.RequestGetAdmin) (*models.User, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	return s.getAdminUser(s.userRepo.DB(), payload.ID)
+}
+
+// preferredFirst returns held roles with preferred role first, as permission middleware prefers active role of the session
+func preferredFirst(preferred models.UserRole, held []models.UserRole) []models.UserRole {
+	roles := []models.UserRole{preferred}
+	for _, role := range held {
+		if role != preferred {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// GetMyPermissions returns effective permissions of current session, computed like permission middleware
+func (s *ContextedAdmin) GetMyPermissions() (models.PermissionMatrix, *reply.ErrorPayload) {
+	held := slicelib.Map(s.c.GetStringSlice("roles"), func(i int, role string) models.UserRole { return models.UserRole(role) })
+	roles := preferredFirst(models.UserRole(s.c.GetString("role")), held)
+
+	var admin *models.Admin
+	if slices.Contains(roles, models.RoleAdmin) {
+		user, err := s.userRepo.GetFirstWithPreload(s.ctx, []string{"AdminProfile.Grants.Permission", "AdminProfile.Bundles.Permissions"}, "id = ?", s.c.GetString("userID"))
+		if err != nil {
+			return nil, errorlib.MakeNotFound(err, "your user profile not found", nil)
+		}
+		admin = user.AdminProfile
+	}
+	return models.NewPermissionMatrix(roles, admin), nil
+}
+
+// GetEffectivePermissions returns effective permissions of admin signed in as primary role, computed like permission middleware
+func (s *ContextedAdmin) GetEffectivePermissions(payload payloads.RequestGetEffectivePermissions) (models.PermissionMatrix, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	user, errPayload := s.getAdminUser(s.userRepo.DB(), payload.ID, "AdminProfile.Grants.Permission", "AdminProfile.Bundles.Permissions")
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	return models.NewPermissionMatrix(preferredFirst(user.Role, user.HeldRoles()), user.AdminProfile), nil
+}
+
+func (s *ContextedAdmin) UpdateAdmin(payload payloads.RequestUpdateAdmin) (user *models.User, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.adminRepo.DB().Transaction(func(tx *gorm.DB) error {
+		adminRepo := s.adminRepo.WithTx(tx)
+
+		// check exist
+		exists, err := adminRepo.Exists(s.ctx, "id = ?", payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if !exists {
+			errPayload = errorlib.MakeNotFound(gorm.ErrRecordNotFound, "admin not found", nil)
+			return gorm.ErrRecordNotFound
+		}
+
+		// check is unique conflict, archived profiles included
+		if payload.EmployeeID != "" {
+			conflict, err := s.adminRepo.WithTx(tx.Unscoped()).Exists(s.ctx, "employee_id = ? AND id <> ?", payload.EmployeeID, payload.ID)
+			if err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+			if conflict {
+				errPayload = &reply.ErrorPayload{
+					Code:    replylib.CodeConflict,
+					Message: "employee ID unique conflict",
+					Fields:  reply.FieldsError{"employee_id": "other admin with same employee id already exist"},
+				}
+				return errors.New(errPayload.Message)
+			}
+		}
+
+		// update profile
+		err = adminRepo.Update(s.ctx, models.Admin{StaffRole: payload.StaffRole, EmployeeID: payload.EmployeeID}, "id = ?", payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		user, errPayload = s.getAdminUser(tx, payload.ID)
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		return nil
+	})
+	return
+}
+
+// DeactivateAdmin requests deactivation of the admin, it is applied once another admin approves it
+func (s *ContextedAdmin) DeactivateAdmin(payload payloads.RequestDeactivateAdmin) (*models.ChangeRequest, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	admin, err := s.adminRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "admin not found", nil)
+	}
+	requesterID := s.c.GetString("userID")
+	if admin.UserID == requesterID {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "can not deactivate yourself"}
+	}
+	return requestChange(
+		s.ctx, s.changeRepo, s.adminRepo, s.notificationRepo, requesterID,
+		models.ChangeDeactivateAdmin, admin.UserID, models.ResourceAdmin, []models.PermissionAction{models.ActionDelete}, payload,
+	)
+}
+
+// deactivateAdmin takes admin role from the user, permissions are revoked and profile is archived like moving from admin role
+func (s *ContextedAdmin) deactivateAdmin(payload payloads.RequestDeactivateAdmin) (user *models.User, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.adminRepo.DB().Transaction(func(tx *gorm.DB) error {
+		adminRepo := s.adminRepo.WithTx(tx)
+
+		admin, err := gorm.G[models.Admin](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", payload.ID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "admin not found", nil)
+			return err
+		}
+		if admin.UserID == s.c.GetString("userID") {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "can not deactivate yourself"}
+			return errors.New(errPayload.Message)
+		}
+		u, err := gorm.G[models.User](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", admin.UserID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "user of admin not found", nil)
+			return err
+		}
+		user = &u
+
+		if errPayload = revokeAdmin(s.ctx, adminRepo, s.auditRepo.WithTx(tx), s.c.GetString("userID"), admin.ID); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		// another held role becomes primary role, user without another role waits to be activated again
+		user.Roles = slices.DeleteFunc(user.Roles, func(r models.UserRole) bool { return r == models.RoleAdmin })
+		if user.Role == models.RoleAdmin {
+			user.Role = models.RoleUnsetted
+			if len(user.Roles) > 0 {
+				user.Role = user.Roles[0]
+			}
+		}
+		if errPayload = saveRoles(s.ctx, tx, user); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		return nil
+	})
+	return
+}
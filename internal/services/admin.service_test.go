@@ -0,0 +1,98 @@
+//go:build integration
+
+package services
+
+import (
+	"maps"
+	"school-information-system/database/seeds"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/repos"
+	"slices"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestEnsureSeedsHeld(t *testing.T) {
+	seedIDs := slices.Collect(maps.Keys(seeds.PermissionSeedIDs))
+
+	assertUnheld := func(t *testing.T, db *gorm.DB, want int) {
+		t.Helper()
+		adminRepo := repos.NewAdmin(db)
+		unheld, err := adminRepo.UnheldPermissions(t.Context(), seedIDs)
+		if err != nil {
+			t.Fatalf("unheld permissions: %v", err)
+		}
+		if len(unheld) != want {
+			t.Fatalf("expected %d unheld seed permissions, got %d", want, len(unheld))
+		}
+
+		errPayload := ensureSeedsHeld(t.Context(), adminRepo)
+		if want == 0 && errPayload != nil {
+			t.Fatalf("expected seeds held, got %s", errPayload.Message)
+		}
+		if want > 0 && (errPayload == nil || errPayload.Code != replylib.CodeConflict) {
+			t.Fatalf("expected conflict, got %+v", errPayload)
+		}
+	}
+
+	t.Run("no admin", func(t *testing.T) {
+		db := openTestDB(t)
+		assertUnheld(t, db, len(seedIDs))
+	})
+
+	t.Run("held directly", func(t *testing.T) {
+		db := openTestDB(t)
+		createTestAdmin(t, db, "holder", seeds.PermissionSeeds...)
+		assertUnheld(t, db, 0)
+	})
+
+	t.Run("held by bundle", func(t *testing.T) {
+		db := openTestDB(t)
+		user := createTestAdmin(t, db, "holder")
+		bundle := &models.PermissionBundle{Name: "seeds " + user.ID, Description: "every seed permission"}
+		if err := db.Create(bundle).Error; err != nil {
+			t.Fatalf("create bundle: %v", err)
+		}
+		if err := db.Model(bundle).Association("Permissions").Append(&seeds.PermissionSeeds); err != nil {
+			t.Fatalf("fill bundle: %v", err)
+		}
+		if err := db.Model(user.AdminProfile).Association("Bundles").Append(bundle); err != nil {
+			t.Fatalf("grant bundle: %v", err)
+		}
+		assertUnheld(t, db, 0)
+	})
+
+	t.Run("time-limited grant", func(t *testing.T) {
+		db := openTestDB(t)
+		user := createTestAdmin(t, db, "holder", seeds.PermissionSeeds...)
+		until := time.Now().Add(time.Hour)
+		err := db.Model(&models.AdminPermission{}).
+			Where("admin_id = ? AND permission_id = ?", user.AdminProfile.ID, seeds.PermissionSeeds[0].ID).
+			Update("valid_until", until).Error
+		if err != nil {
+			t.Fatalf("limit grant: %v", err)
+		}
+		assertUnheld(t, db, 1)
+	})
+
+	t.Run("archived admin", func(t *testing.T) {
+		db := openTestDB(t)
+		user := createTestAdmin(t, db, "holder", seeds.PermissionSeeds...)
+		if err := db.Delete(user.AdminProfile).Error; err != nil {
+			t.Fatalf("archive admin: %v", err)
+		}
+		assertUnheld(t, db, len(seedIDs))
+	})
+
+	t.Run("archived user", func(t *testing.T) {
+		db := openTestDB(t)
+		user := createTestAdmin(t, db, "holder", seeds.PermissionSeeds...)
+		if err := db.Delete(user).Error; err != nil {
+			t.Fatalf("archive user: %v", err)
+		}
+		assertUnheld(t, db, len(seedIDs))
+	})
+}
@@ -11,10 +11,13 @@ import (
 	"school-information-system/internal/models"
 	"school-information-system/internal/models/payloads"
 	"school-information-system/internal/repos"
+	"slices"
+	"time"
 
 	"github.com/chesta132/goreply/reply"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RoleSetter struct {
@@ -25,6 +28,10 @@ type RoleSetter struct {
 	parentRepo  *repos.Parent
 	teacherRepo *repos.Teacher
 	subjectRepo *repos.Subject
+	auditRepo   *repos.PermissionAudit
+
+	changeRepo       *repos.ChangeRequest
+	notificationRepo *repos.Notification
 }
 
 type ContextedRoleSetter struct {
@@ -33,251 +40,477 @@ type ContextedRoleSetter struct {
 	ctx context.Context
 }
 
-func NewRoleSetter(userRepo *repos.User, adminRepo *repos.Admin, studentRepo *repos.Student, classRepo *repos.Class, parentRepo *repos.Parent, teacherRepo *repos.Teacher, subjectRepo *repos.Subject) *RoleSetter {
-	return &RoleSetter{userRepo, adminRepo, studentRepo, classRepo, parentRepo, teacherRepo, subjectRepo}
+func NewRoleSetter(userRepo *repos.User, adminRepo *repos.Admin, studentRepo *repos.Student, classRepo *repos.Class, parentRepo *repos.Parent, teacherRepo *repos.Teacher, subjectRepo *repos.Subject, auditRepo *repos.PermissionAudit, changeRepo *repos.ChangeRequest, notificationRepo *repos.Notification) *RoleSetter {
+	return &RoleSetter{userRepo, adminRepo, studentRepo, classRepo, parentRepo, teacherRepo, subjectRepo, auditRepo, changeRepo, notificationRepo}
 }
 
 func (s *RoleSetter) ApplyContext(c *gin.Context) *ContextedRoleSetter {
 	return &ContextedRoleSetter{s, c, c.Request.Context()}
 }
 
-func (s *ContextedRoleSetter) SetRole(payload payloads.RequestSetRole) (*models.User, *reply.ErrorPayload) {
-	// validate payload
-	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
-		return nil, errPayload
-	}
+// requestRoleChange records change of admin role for approval of another admin
+func (s *ContextedRoleSetter) requestRoleChange(kind models.ChangeRequestKind, targetID string, payload any) (*models.ChangeRequest, *reply.ErrorPayload) {
+	return requestChange(
+		s.ctx, s.changeRepo, s.adminRepo, s.notificationRepo, s.c.GetString("userID"),
+		kind, targetID, models.ResourceRole, []models.PermissionAction{models.ActionRead, models.ActionUpdate}, payload,
+	)
+}
 
-	// get and check user
-	user, err := s.userRepo.GetByID(s.ctx, payload.TargetID)
+// getTarget returns user targeted by role change
+func (s *ContextedRoleSetter) getTarget(targetID string) (*models.User, *reply.ErrorPayload) {
+	user, err := s.userRepo.GetByID(s.ctx, targetID)
 	if err != nil {
 		return nil, errorlib.MakeUserByTargetIDNotFound(err)
 	}
+	return &user, nil
+}
 
-	if user.Role != models.RoleUnsetted {
-		return nil, &reply.ErrorPayload{
-			Code:    replylib.CodeConflict,
-			Message: "targeted user's role already setted",
-			Fields: reply.FieldsError{
-				"target_id": "user's role with this id already setted",
-			},
-		}
+func (s *ContextedRoleSetter) validateSetRole(payload payloads.RequestSetRole) *reply.ErrorPayload {
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
 	}
 
-	switch payload.TargetRole {
-	// set role student
-	case models.RoleStudent:
-		return &user, s.setRoleStudent(*payload.StudentData, &user)
-
-		// set role teacher
-	case models.RoleTeacher:
-		return &user, s.setRoleTeacher(*payload.TeacherData, &user)
+	if payload.TargetID == s.c.GetString("userID") {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "can not change your own role",
+			Fields:  reply.FieldsError{"target_id": "ask another admin to change your role"},
+		}
+	}
+	if payload.TargetRole == models.RoleAlumni {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeBadRequest,
+			Message: "invalid target role",
+			Fields:  reply.FieldsError{"target_role": "alumni role is only given by graduation"},
+		}
+	}
+	if payload.Append && payload.TargetRole == models.RoleUnsetted {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeBadRequest,
+			Message: "invalid target role",
+			Fields:  reply.FieldsError{"target_role": "unsetted role can not be held with another role"},
+		}
+	}
+	return nil
+}
 
-		// set role admin
-	case models.RoleAdmin:
-		return &user, s.setRoleAdmin(*payload.AdminData, &user)
+// SetRole moves user from held roles to the target role, or gives the target role in addition to held roles if payload.Append is true.
+// Profile of a left role is archived so related records are kept, and is restored when the user moves back to the role.
+// Sessions of the user are invalidated since roles are embedded in the token.
+// Giving or taking admin role is requested for approval of another admin instead.
+func (s *ContextedRoleSetter) SetRole(payload payloads.RequestSetRole) (user *models.User, request *models.ChangeRequest, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := s.validateSetRole(payload); errPayload != nil {
+		return nil, nil, errPayload
 	}
 
-	return nil, &reply.ErrorPayload{
-		Code:    replylib.CodeBadRequest,
-		Message: "invalid target role",
-		Fields:  reply.FieldsError{"target_role": "please select a valid role"},
+	target, errPayload := s.getTarget(payload.TargetID)
+	if errPayload != nil {
+		return nil, nil, errPayload
 	}
+	if payload.TargetRole == models.RoleAdmin || (!payload.Append && target.HasRole(models.RoleAdmin)) {
+		request, errPayload = s.requestRoleChange(models.ChangeSetRole, payload.TargetID, payload)
+		return
+	}
+	user, errPayload = s.setRole(payload)
+	return
 }
 
-func (s *ContextedRoleSetter) setRoleStudent(payload payloads.RequestSetRoleStudent, user *models.User) (errPayload *reply.ErrorPayload) {
-	payload.ParentIDs = slicelib.Unique(payload.ParentIDs)
-	if len(payload.ParentIDs) != 2 {
-		return &reply.ErrorPayload{
-			Code:    replylib.CodeBadRequest,
-			Message: "invalid payload",
-			Fields: reply.FieldsError{
-				"student_data.parent_ids": "id is not unique",
-			},
-		}
+func (s *ContextedRoleSetter) setRole(payload payloads.RequestSetRole) (user *models.User, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := s.validateSetRole(payload); errPayload != nil {
+		return nil, errPayload
 	}
 
 	// transaction to rollback if error
 	s.adminRepo.DB().Transaction(func(tx *gorm.DB) error {
-		userRepo := s.userRepo.WithTx(tx)
-		classRepo := s.classRepo.WithTx(tx)
-		parentRepo := s.parentRepo.WithTx(tx)
-		studentRepo := s.studentRepo.WithTx(tx)
-
-		// check is class exists
-		classExists, err := classRepo.Exists(s.ctx, "id = ?", payload.ClassID)
+		// get and check user
+		u, err := gorm.G[models.User](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", payload.TargetID).First(s.ctx)
 		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
+			errPayload = errorlib.MakeUserByTargetIDNotFound(err)
 			return err
 		}
-		if !classExists {
-			errPayload = errorlib.MakeNotFound(
-				gorm.ErrRecordNotFound,
-				"class not found",
-				reply.FieldsError{"class_id": "class with this ID not found"},
-			)
-			return gorm.ErrRecordNotFound
+		user = &u
+		held := user.HeldRoles()
+		if user.HasRole(payload.TargetRole) && (payload.Append || len(held) == 1) {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeConflict,
+				Message: "targeted user already has this role",
+				Fields:  reply.FieldsError{"target_role": "user with this id already has role " + string(payload.TargetRole)},
+			}
+			return errors.New(errPayload.Message)
 		}
 
-		// check is found parent 2
-		parents, err := parentRepo.GetByIDs(s.ctx, payload.ParentIDs)
-		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
-			return err
-		}
-		if len(parents) != 2 {
-			errPayload = &reply.ErrorPayload{Code: replylib.CodeNotFound, Message: "parent(s) not found"}
-			return gorm.ErrRecordNotFound
+		// leave held roles unless target role is given in addition to them
+		roles := []models.UserRole{}
+		if payload.Append {
+			roles = append(roles, user.Roles...)
+		} else {
+			for _, role := range held {
+				if errPayload = s.leaveRole(tx, user, role); errPayload != nil {
+					return errors.New(errPayload.Message)
+				}
+			}
 		}
 
-		// set role
-		user.Role = models.RoleStudent
-		err = userRepo.Update(s.ctx, models.User{Role: models.RoleStudent}, "id = ?", user.ID)
-		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
-			return err
-		}
+		switch payload.TargetRole {
+		// set role student
+		case models.RoleStudent:
+			errPayload = s.setRoleStudent(tx, *payload.StudentData, user)
+
+			// set role teacher
+		case models.RoleTeacher:
+			errPayload = s.setRoleTeacher(tx, *payload.TeacherData, user)
 
-		// create student
-		student := &models.Student{
-			NISN:    payload.NISN,
-			UserID:  user.ID,
-			ClassID: payload.ClassID,
+			// set role admin
+		case models.RoleAdmin:
+			errPayload = s.setRoleAdmin(tx, *payload.AdminData, user)
 		}
-		user.StudentProfile = student
-		err = studentRepo.Create(s.ctx, student)
-		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
-			return err
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
 		}
 
-		// append parents to student
-		err = tx.Model(student).Association("Parents").Append(parents)
-		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
+		// target role becomes primary role unless it is given to user who already has primary role
+		if payload.TargetRole != models.RoleUnsetted {
+			roles = append(roles, payload.TargetRole)
+		}
+		if !payload.Append || user.Role == models.RoleUnsetted {
+			user.Role = payload.TargetRole
+		}
+		user.Roles = roles
+		errPayload = saveRoles(s.ctx, tx, user)
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
 		}
-		return err
+		return nil
 	})
 	return
 }
 
-func (s *ContextedRoleSetter) setRoleTeacher(payload payloads.RequestSetRoleTeacher, user *models.User) (errPayload *reply.ErrorPayload) {
-	payload.SubjectIDs = slicelib.Unique(payload.SubjectIDs)
+func (s *ContextedRoleSetter) validateRemoveRole(payload payloads.RequestRemoveRole) *reply.ErrorPayload {
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	if payload.TargetID == s.c.GetString("userID") {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "can not change your own role",
+			Fields:  reply.FieldsError{"target_id": "ask another admin to change your role"},
+		}
+	}
+	return nil
+}
+
+// RemoveRole takes one of the roles held by user, profile of the role is archived like moving from the role.
+// Taking admin role is requested for approval of another admin instead
+func (s *ContextedRoleSetter) RemoveRole(payload payloads.RequestRemoveRole) (user *models.User, request *models.ChangeRequest, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := s.validateRemoveRole(payload); errPayload != nil {
+		return nil, nil, errPayload
+	}
+
+	target, errPayload := s.getTarget(payload.TargetID)
+	if errPayload != nil {
+		return nil, nil, errPayload
+	}
+	if payload.Role == models.RoleAdmin && target.HasRole(models.RoleAdmin) {
+		request, errPayload = s.requestRoleChange(models.ChangeRemoveRole, payload.TargetID, payload)
+		return
+	}
+	user, errPayload = s.removeRole(payload)
+	return
+}
+
+func (s *ContextedRoleSetter) removeRole(payload payloads.RequestRemoveRole) (user *models.User, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := s.validateRemoveRole(payload); errPayload != nil {
+		return nil, errPayload
+	}
 
 	// transaction to rollback if error
 	s.adminRepo.DB().Transaction(func(tx *gorm.DB) error {
-		userRepo := s.userRepo.WithTx(tx)
-		subjectRepo := s.subjectRepo.WithTx(tx)
-		teacherRepo := s.teacherRepo.WithTx(tx)
-
-		// check if all subjects exists
-		subjects, err := subjectRepo.GetByIDs(s.ctx, payload.SubjectIDs)
+		// get and check user
+		u, err := gorm.G[models.User](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", payload.TargetID).First(s.ctx)
 		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
+			errPayload = errorlib.MakeUserByTargetIDNotFound(err)
 			return err
 		}
-		if subjectIDsLen, subjectsLen := len(payload.SubjectIDs), len(subjects); subjectIDsLen > subjectsLen {
-			notFound := subjectIDsLen - subjectsLen
-			err := fmt.Errorf("%d subject(s) not found", notFound)
-			errPayload = errorlib.MakeNotFound(
-				gorm.ErrRecordNotFound,
-				"subject(s) not found",
-				reply.FieldsError{"subject_ids": err.Error()},
-			)
-			return err
-		}
-
-		// check is unique conflict
-		teacherExist, err := teacherRepo.Exists(s.ctx, "nuptk = ? OR employee_id = ?", payload.NUPTK, payload.EmployeeID)
-		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
-			return err
+		user = &u
+		if payload.Role == models.RoleUnsetted || !user.HasRole(payload.Role) {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeUnprocessableEntity,
+				Message: "targeted user doesn't hold this role",
+				Fields:  reply.FieldsError{"role": "user with this id doesn't hold role " + string(payload.Role)},
+			}
+			return errors.New(errPayload.Message)
 		}
-		if teacherExist {
-			err := errors.New("other teacher with same NUTPK or employee ID already exist")
+		if len(user.Roles) == 1 {
 			errPayload = &reply.ErrorPayload{
-				Code:    replylib.CodeConflict,
-				Message: "NUPTK and employee ID unique conflict",
-				Fields:  reply.FieldsError{"nuptk": err.Error(), "employee_id": err.Error()},
+				Code:    replylib.CodeUnprocessableEntity,
+				Message: "targeted user only holds this role",
+				Fields:  reply.FieldsError{"role": "set role of the user to unsetted to take the only role"},
 			}
-			return err
+			return errors.New(errPayload.Message)
 		}
 
-		// set role
-		user.Role = models.RoleTeacher
-		err = userRepo.Update(s.ctx, models.User{Role: models.RoleTeacher}, "id = ?", user.ID)
-		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
-			return err
+		if errPayload = s.leaveRole(tx, user, payload.Role); errPayload != nil {
+			return errors.New(errPayload.Message)
 		}
 
-		// create teacher
-		teacher := &models.Teacher{
-			NUPTK:      payload.NUPTK,
-			EmployeeID: payload.EmployeeID,
-			TimestampJoinTime: models.TimestampJoinTime{
-				JoinedAt: payload.JoinedAt,
-			},
-			UserID: user.ID,
-		}
-		user.TeacherProfile = teacher
-		err = teacherRepo.Create(s.ctx, teacher)
-		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
-			return err
+		// another held role becomes primary role if the role was primary role
+		user.Roles = slices.DeleteFunc(user.Roles, func(r models.UserRole) bool { return r == payload.Role })
+		if user.Role == payload.Role {
+			user.Role = user.Roles[0]
 		}
-
-		// append subjects to teacher
-		err = tx.Model(teacher).Association("Subjects").Append(subjects)
-		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
+		errPayload = saveRoles(s.ctx, tx, user)
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
 		}
-		return err
+		return nil
 	})
 	return
 }
 
-func (s *ContextedRoleSetter) setRoleAdmin(payload payloads.RequestSetRoleAdmin, user *models.User) (errPayload *reply.ErrorPayload) {
-	// transaction to rollback if error
-	s.adminRepo.DB().Transaction(func(tx *gorm.DB) error {
-		userRepo := s.userRepo.WithTx(tx)
-		adminRepo := s.adminRepo.WithTx(tx)
+// saveRoles saves primary role and held roles of the user and invalidates sessions of the user,
+// user getting a role for the first time is marked as activated
+func saveRoles(ctx context.Context, tx *gorm.DB, user *models.User) *reply.ErrorPayload {
+	now := time.Now()
+	user.SessionsRevokedAt = &now
+	if user.ActivatedAt == nil && user.Role != models.RoleUnsetted {
+		user.ActivatedAt = &now
+	}
+	err := tx.WithContext(ctx).Model(user).Select("role", "roles", "activated_at", "sessions_revoked_at").Updates(user).Error
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	return nil
+}
 
-		// check is unique conflict
-		adminExist, err := adminRepo.Exists(s.ctx, "employee_id = ?", payload.EmployeeID)
+// leaveRole archives profile of the role held by user, and detaches the profile from records which only active profile may hold
+func (s *ContextedRoleSetter) leaveRole(tx *gorm.DB, user *models.User, role models.UserRole) *reply.ErrorPayload {
+	switch role {
+	case models.RoleStudent:
+		if err := s.studentRepo.WithTx(tx).Archive(s.ctx, "user_id = ?", user.ID); err != nil {
+			return errorlib.MakeNotFound(err, "student profile of targeted user not found", nil)
+		}
+
+	case models.RoleTeacher:
+		teacher, err := s.teacherRepo.WithTx(tx).GetFirst(s.ctx, "user_id = ?", user.ID)
 		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
-			return err
+			return errorlib.MakeNotFound(err, "teacher profile of targeted user not found", nil)
 		}
-		if adminExist {
-			err := errors.New("other admin with same employee id already exist")
-			errPayload = &reply.ErrorPayload{
-				Code:    replylib.CodeConflict,
-				Message: "employee ID unique conflict",
-				Fields:  reply.FieldsError{"employee_id": err.Error()},
-			}
-			return err
+		// class without form teacher can be given a new one
+		if err := tx.WithContext(s.ctx).Model(&models.Class{}).Where("form_teacher_id = ?", teacher.ID).Update("form_teacher_id", nil).Error; err != nil {
+			return errorlib.MakeServerError(err)
+		}
+		if err := s.teacherRepo.WithTx(tx).Archive(s.ctx, "id = ?", teacher.ID); err != nil {
+			return errorlib.MakeServerError(err)
 		}
 
-		// set role
-		user.Role = models.RoleAdmin
-		err = userRepo.Update(s.ctx, models.User{Role: models.RoleAdmin}, "id = ?", user.ID)
+	case models.RoleAdmin:
+		admin, err := s.adminRepo.WithTx(tx).GetFirst(s.ctx, "user_id = ?", user.ID)
 		if err != nil {
-			errPayload = errorlib.MakeServerError(err)
-			return err
+			return errorlib.MakeNotFound(err, "admin profile of targeted user not found", nil)
+		}
+		if errPayload := revokeAdmin(s.ctx, s.adminRepo.WithTx(tx), s.auditRepo.WithTx(tx), s.c.GetString("userID"), admin.ID); errPayload != nil {
+			return errPayload
 		}
+	}
+	return nil
+}
+
+// archivedProfileID returns id of archived profile of the user, empty if the user never had the profile
+func archivedProfileID[T any](ctx context.Context, tx *gorm.DB, userID string) (id string, err error) {
+	err = tx.WithContext(ctx).Unscoped().Model(new(T)).Select("id").Where("user_id = ? AND deleted_at IS NOT NULL", userID).Scan(&id).Error
+	return
+}
 
-		// create admin
-		// set permissions with other route
-		admin := &models.Admin{
-			StaffRole:  payload.StaffRole,
-			EmployeeID: payload.EmployeeID,
-			TimestampJoinTime: models.TimestampJoinTime{
-				JoinedAt: payload.JoinedAt,
+func (s *ContextedRoleSetter) setRoleStudent(tx *gorm.DB, payload payloads.RequestSetRoleStudent, user *models.User) *reply.ErrorPayload {
+	payload.ParentIDs = slicelib.Unique(payload.ParentIDs)
+	if len(payload.ParentIDs) != 2 {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeBadRequest,
+			Message: "invalid payload",
+			Fields: reply.FieldsError{
+				"student_data.parent_ids": "id is not unique",
 			},
-			UserID: user.ID,
 		}
-		user.AdminProfile = admin
-		return adminRepo.Create(s.ctx, admin)
-	})
-	return
+	}
+
+	classRepo := s.classRepo.WithTx(tx)
+	parentRepo := s.parentRepo.WithTx(tx)
+	studentRepo := s.studentRepo.WithTx(tx)
+
+	// check is class exists
+	classExists, err := classRepo.Exists(s.ctx, "id = ?", payload.ClassID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if !classExists {
+		return errorlib.MakeNotFound(
+			gorm.ErrRecordNotFound,
+			"class not found",
+			reply.FieldsError{"class_id": "class with this ID not found"},
+		)
+	}
+
+	// check is found parent 2
+	parents, err := parentRepo.GetByIDs(s.ctx, payload.ParentIDs)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if len(parents) != 2 {
+		return &reply.ErrorPayload{Code: replylib.CodeNotFound, Message: "parent(s) not found"}
+	}
+
+	// check is unique conflict, archived profiles included
+	nisnExist, err := s.studentRepo.WithTx(tx.Unscoped()).Exists(s.ctx, "nisn = ? AND user_id <> ?", payload.NISN, user.ID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if nisnExist {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: "NISN unique conflict",
+			Fields:  reply.FieldsError{"student_data.nisn": "other student with same NISN already exist"},
+		}
+	}
+
+	// create student, or restore student profile of the user
+	student := &models.Student{
+		NISN:    payload.NISN,
+		UserID:  user.ID,
+		ClassID: payload.ClassID,
+	}
+	user.StudentProfile = student
+	archivedID, err := archivedProfileID[models.Student](s.ctx, tx, user.ID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if archivedID != "" {
+		student.ID = archivedID
+		err = tx.WithContext(s.ctx).Unscoped().Model(student).Select("nisn", "class_id", "deleted_at").Updates(student).Error
+	} else {
+		err = studentRepo.Create(s.ctx, student)
+	}
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+
+	// set parents of student
+	if err := tx.Model(student).Association("Parents").Replace(parents); err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	return nil
+}
+
+func (s *ContextedRoleSetter) setRoleTeacher(tx *gorm.DB, payload payloads.RequestSetRoleTeacher, user *models.User) *reply.ErrorPayload {
+	payload.SubjectIDs = slicelib.Unique(payload.SubjectIDs)
+
+	subjectRepo := s.subjectRepo.WithTx(tx)
+	teacherRepo := s.teacherRepo.WithTx(tx)
+
+	// check if all subjects exists
+	subjects, err := subjectRepo.GetByIDs(s.ctx, payload.SubjectIDs)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if subjectIDsLen, subjectsLen := len(payload.SubjectIDs), len(subjects); subjectIDsLen > subjectsLen {
+		notFound := subjectIDsLen - subjectsLen
+		err := fmt.Errorf("%d subject(s) not found", notFound)
+		return errorlib.MakeNotFound(
+			gorm.ErrRecordNotFound,
+			"subject(s) not found",
+			reply.FieldsError{"subject_ids": err.Error()},
+		)
+	}
+
+	// check is unique conflict, archived profiles included
+	teacherExist, err := s.teacherRepo.WithTx(tx.Unscoped()).Exists(s.ctx, "(nuptk = ? OR employee_id = ?) AND user_id <> ?", payload.NUPTK, payload.EmployeeID, user.ID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if teacherExist {
+		err := errors.New("other teacher with same NUTPK or employee ID already exist")
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: "NUPTK and employee ID unique conflict",
+			Fields:  reply.FieldsError{"nuptk": err.Error(), "employee_id": err.Error()},
+		}
+	}
+
+	// create teacher, or restore teacher profile of the user
+	teacher := &models.Teacher{
+		NUPTK:      payload.NUPTK,
+		EmployeeID: payload.EmployeeID,
+		TimestampJoinTimeArchivable: models.TimestampJoinTimeArchivable{
+			JoinedAt: payload.JoinedAt,
+		},
+		UserID: user.ID,
+	}
+	user.TeacherProfile = teacher
+	archivedID, err := archivedProfileID[models.Teacher](s.ctx, tx, user.ID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if archivedID != "" {
+		teacher.ID = archivedID
+		err = tx.WithContext(s.ctx).Unscoped().Model(teacher).Select("nuptk", "employee_id", "joined_at", "deleted_at").Updates(teacher).Error
+	} else {
+		err = teacherRepo.Create(s.ctx, teacher)
+	}
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+
+	// set subjects of teacher
+	if err := tx.Model(teacher).Association("Subjects").Replace(subjects); err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	return nil
+}
+
+func (s *ContextedRoleSetter) setRoleAdmin(tx *gorm.DB, payload payloads.RequestSetRoleAdmin, user *models.User) *reply.ErrorPayload {
+	adminRepo := s.adminRepo.WithTx(tx)
+
+	// check is unique conflict, archived profiles included
+	adminExist, err := s.adminRepo.WithTx(tx.Unscoped()).Exists(s.ctx, "employee_id = ? AND user_id <> ?", payload.EmployeeID, user.ID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if adminExist {
+		err := errors.New("other admin with same employee id already exist")
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: "employee ID unique conflict",
+			Fields:  reply.FieldsError{"employee_id": err.Error()},
+		}
+	}
+
+	// create admin, or restore admin profile of the user without previous permissions
+	// set permissions with other route
+	admin := &models.Admin{
+		StaffRole:  payload.StaffRole,
+		EmployeeID: payload.EmployeeID,
+		TimestampJoinTimeArchivable: models.TimestampJoinTimeArchivable{
+			JoinedAt: payload.JoinedAt,
+		},
+		UserID: user.ID,
+	}
+	user.AdminProfile = admin
+	archivedID, err := archivedProfileID[models.Admin](s.ctx, tx, user.ID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if archivedID != "" {
+		admin.ID = archivedID
+		err = tx.WithContext(s.ctx).Unscoped().Model(admin).Select("staff_role", "employee_id", "joined_at", "deleted_at").Updates(admin).Error
+	} else {
+		err = adminRepo.Create(s.ctx, admin)
+	}
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	return nil
 }
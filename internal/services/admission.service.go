@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/authlib"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/phonelib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"strings"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Admission struct {
+	admissionRepo *repos.Admission
+	userRepo      *repos.User
+	studentRepo   *repos.Student
+	classRepo     *repos.Class
+	parentRepo    *repos.Parent
+}
+
+type ContextedAdmission struct {
+	*Admission
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewAdmission(admissionRepo *repos.Admission, userRepo *repos.User, studentRepo *repos.Student, classRepo *repos.Class, parentRepo *repos.Parent) *Admission {
+	return &Admission{admissionRepo, userRepo, studentRepo, classRepo, parentRepo}
+}
+
+func (s *Admission) ApplyContext(c *gin.Context) *ContextedAdmission {
+	return &ContextedAdmission{s, c, c.Request.Context()}
+}
+
+func (s *ContextedAdmission) SubmitAdmission(payload payloads.RequestSubmitAdmission) (*models.Admission, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// format and validate phone numbers
+	formattedNumber, validNum := phonelib.FormatNumber(payload.Phone)
+	if !validNum {
+		return nil, &replylib.ErrInvalidPhone
+	}
+	for i, parent := range payload.Parents {
+		formatted, valid := phonelib.FormatNumber(parent.Phone)
+		if !valid {
+			return nil, &reply.ErrorPayload{
+				Code:    replylib.CodeBadRequest,
+				Message: "invalid payload",
+				Fields:  reply.FieldsError{fmt.Sprintf("parents[%d].phone", i): "invalid phone number"},
+			}
+		}
+		payload.Parents[i].Phone = formatted
+	}
+
+	// check registered account
+	if exists, err := s.userRepo.Exists(s.ctx, "email = ? OR phone = ?", payload.Email, formattedNumber); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		msg := "email or phone number already registered as an account"
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: "contact already registered",
+			Fields:  reply.FieldsError{"email": msg, "phone": msg},
+		}
+	}
+
+	// check other application in review
+	if exists, err := s.admissionRepo.Exists(s.ctx, "(email = ? OR nisn = ?) AND stage NOT IN ?", payload.Email, payload.NISN, []models.AdmissionStage{models.StageRejected}); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		msg := "another application with this email or NISN already submitted"
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: "application already submitted",
+			Fields:  reply.FieldsError{"email": msg, "NISN": msg},
+		}
+	}
+
+	hashedPassword, err := authlib.HashPassword(payload.Password)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	suffix, err := authlib.RandomHex(4)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	now := time.Now()
+	admission := &models.Admission{
+		RegistrationNumber: fmt.Sprintf("PPDB/%d/%s", now.Year(), strings.ToUpper(suffix)),
+		Stage:              models.StageSubmitted,
+		FullName:           payload.FullName,
+		Email:              payload.Email,
+		Phone:              formattedNumber,
+		Gender:             payload.Gender,
+		BirthPlace:         payload.BirthPlace,
+		BirthDate:          payload.BirthDate,
+		Address:            payload.Address,
+		NISN:               payload.NISN,
+		Password:           hashedPassword,
+		PreviousSchool:     payload.PreviousSchool,
+		DesiredMajor:       payload.DesiredMajor,
+		Parents:            payload.Parents,
+		Documents:          payload.Documents,
+		History:            []models.AdmissionStageLog{{Stage: models.StageSubmitted, ChangedAt: now}},
+	}
+	if err := s.admissionRepo.Create(s.ctx, admission); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	return admission, nil
+}
+
+func (s *ContextedAdmission) TrackAdmission(payload payloads.RequestTrackAdmission) (*models.Admission, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	admission, err := s.admissionRepo.GetFirst(s.ctx, "registration_number = ? AND email = ?", payload.RegistrationNumber, payload.Email)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "application not found", reply.FieldsError{
+			"registration_number": "application with this registration number and email not found",
+		})
+	}
+
+	return &admission, nil
+}
+
+func (s *ContextedAdmission) GetAdmission(payload payloads.RequestGetAdmission) (*models.Admission, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	admission, err := s.admissionRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "application not found", nil)
+	}
+
+	return &admission, nil
+}
+
+func (s *ContextedAdmission) GetAdmissions(payload payloads.RequestGetAdmissions) ([]models.Admission, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.Admission](s.admissionRepo.DB()).
+		Order("created_at").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Stage != "" {
+		q = q.Where("stage = ?", payload.Stage)
+	}
+	if payload.Major != "" {
+		q = q.Where("desired_major = ?", payload.Major)
+	}
+	if payload.Query != "" {
+		q = q.Where(
+			"LOWER(full_name) LIKE LOWER(?) OR email = ? OR registration_number = ?",
+			"%"+payload.Query+"%", payload.Query, payload.Query,
+		)
+	}
+
+	admissions, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return admissions, nil
+}
+
+func (s *ContextedAdmission) MoveStage(payload payloads.RequestMoveAdmissionStage) (admission *models.Admission, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.admissionRepo.DB().Transaction(func(tx *gorm.DB) error {
+		admissionRepo := s.admissionRepo.WithTx(tx)
+
+		adm, err := admissionRepo.GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "application not found", nil)
+			return err
+		}
+
+		// validate review flow
+		if !adm.CanMoveTo(payload.Stage) {
+			err := fmt.Errorf("can not move application from %s to %s", adm.Stage, payload.Stage)
+			next := "none, application already final"
+			if stages := adm.NextStages(); len(stages) > 0 {
+				next = fmt.Sprint(stages)
+			}
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeUnprocessableEntity,
+				Message: err.Error(),
+				Fields:  reply.FieldsError{"stage": "next stage must be one of " + next},
+			}
+			return err
+		}
+
+		adm.Stage = payload.Stage
+		adm.Note = payload.Note
+		if payload.TestScore != nil {
+			adm.TestScore = payload.TestScore
+		}
+		adm.History = append(adm.History, models.AdmissionStageLog{
+			Stage:       payload.Stage,
+			Note:        payload.Note,
+			ChangedByID: s.c.GetString("userID"),
+			ChangedAt:   time.Now(),
+		})
+
+		err = tx.Model(&adm).Select("stage", "note", "test_score", "history").Updates(&adm).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		admission = &adm
+		return nil
+	})
+	return
+}
+
+// findOrCreateParent reuses registered parent with same phone or email
+func (s *ContextedAdmission) findOrCreateParent(parentRepo *repos.Parent, data models.AdmissionParent) (*models.Parent, error) {
+	parent, err := parentRepo.GetFirst(s.ctx, "phone = ? OR email = ?", data.Phone, data.Email)
+	if err == nil {
+		return &parent, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	parent = models.Parent{
+		FullName: data.FullName,
+		Phone:    data.Phone,
+		Email:    data.Email,
+		Gender:   data.Gender,
+	}
+	return &parent, parentRepo.Create(s.ctx, &parent)
+}
+
+func (s *ContextedAdmission) Enroll(payload payloads.RequestEnrollAdmission) (user *models.User, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// transaction to rollback if error
+	s.admissionRepo.DB().Transaction(func(tx *gorm.DB) error {
+		admissionRepo := s.admissionRepo.WithTx(tx)
+		userRepo := s.userRepo.WithTx(tx)
+		classRepo := s.classRepo.WithTx(tx)
+		parentRepo := s.parentRepo.WithTx(tx)
+		studentRepo := s.studentRepo.WithTx(tx)
+
+		adm, err := admissionRepo.GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "application not found", nil)
+			return err
+		}
+		if adm.Stage != models.StageAccepted {
+			err := errors.New("only accepted application can be enrolled")
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: err.Error()}
+			return err
+		}
+		if adm.UserID != nil {
+			err := errors.New("application already enrolled")
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: err.Error()}
+			return err
+		}
+
+		// check class
+		classExists, err := classRepo.Exists(s.ctx, "id = ?", payload.ClassID)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if !classExists {
+			errPayload = errorlib.MakeNotFound(
+				gorm.ErrRecordNotFound,
+				"class not found",
+				reply.FieldsError{"class_id": "class with this ID not found"},
+			)
+			return gorm.ErrRecordNotFound
+		}
+
+		// check unique conflict, applicant may registered after submitting
+		if exists, err := userRepo.Exists(s.ctx, "email = ? OR phone = ?", adm.Email, adm.Phone); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		} else if exists {
+			err := errors.New("email or phone number of applicant already registered as an account")
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: err.Error()}
+			return err
+		}
+		if exists, err := studentRepo.Exists(s.ctx, "nisn = ?", adm.NISN); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		} else if exists {
+			err := errors.New("other student with same NISN already exist")
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: err.Error()}
+			return err
+		}
+
+		// create user directly as student
+		activatedAt := time.Now()
+		user = &models.User{
+			FullName:    adm.FullName,
+			Email:       adm.Email,
+			Password:    adm.Password,
+			Role:        models.RoleStudent,
+			Roles:       []models.UserRole{models.RoleStudent},
+			Gender:      adm.Gender,
+			Phone:       adm.Phone,
+			ActivatedAt: &activatedAt, // created with role, no activation needed
+		}
+		if err := userRepo.Create(s.ctx, user); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// create student
+		student := &models.Student{
+			NISN:    adm.NISN,
+			UserID:  user.ID,
+			ClassID: payload.ClassID,
+		}
+		user.StudentProfile = student
+		if err := studentRepo.Create(s.ctx, student); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// register parents and append to student
+		parents := make([]*models.Parent, 0, len(adm.Parents))
+		for _, data := range adm.Parents {
+			parent, err := s.findOrCreateParent(parentRepo, data)
+			if err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+			parents = append(parents, parent)
+		}
+		if parents[0].ID == parents[1].ID {
+			err := errors.New("both parents of applicant refer to the same registered parent")
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: err.Error()}
+			return err
+		}
+		if err := tx.Model(student).Association("Parents").Append(parents); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		student.Parents = parents
+
+		// link application to enrolled user
+		now := time.Now()
+		err = admissionRepo.UpdateByID(s.ctx, adm.ID, models.Admission{UserID: &user.ID, EnrolledAt: &now})
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+		}
+		return err
+	})
+	return
+}
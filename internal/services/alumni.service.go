@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/slicelib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Alumni struct {
+	alumniRepo  *repos.Alumni
+	userRepo    *repos.User
+	studentRepo *repos.Student
+	classRepo   *repos.Class
+}
+
+type ContextedAlumni struct {
+	*Alumni
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewAlumni(alumniRepo *repos.Alumni, userRepo *repos.User, studentRepo *repos.Student, classRepo *repos.Class) *Alumni {
+	return &Alumni{alumniRepo, userRepo, studentRepo, classRepo}
+}
+
+func (s *Alumni) ApplyContext(c *gin.Context) *ContextedAlumni {
+	return &ContextedAlumni{s, c, c.Request.Context()}
+}
+
+func (s *ContextedAlumni) Graduate(payload payloads.RequestGraduate) (alumni []models.Alumni, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	payload.ClassIDs = slicelib.Unique(payload.ClassIDs)
+	accessUntil := time.Now().Add(config.ALUMNI_ACCESS_DURATION)
+	if payload.AccessUntil != nil {
+		accessUntil = *payload.AccessUntil
+	}
+
+	// transaction to rollback if error
+	s.alumniRepo.DB().Transaction(func(tx *gorm.DB) error {
+		classRepo := s.classRepo.WithTx(tx)
+		studentRepo := s.studentRepo.WithTx(tx)
+		alumniRepo := s.alumniRepo.WithTx(tx)
+
+		// validate classes
+		classes, err := classRepo.GetByIDs(s.ctx, payload.ClassIDs)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if notFound := len(payload.ClassIDs) - len(classes); notFound > 0 {
+			errPayload = errorlib.MakeNotFound(gorm.ErrRecordNotFound, "class(es) not found", reply.FieldsError{
+				"class_ids": fmt.Sprintf("%d class(es) not found", notFound),
+			})
+			return gorm.ErrRecordNotFound
+		}
+		classByID := make(map[string]models.Class, len(classes))
+		for _, class := range classes {
+			if class.Grade != 12 {
+				err := fmt.Errorf("%s is not a grade 12 class", class.GetName())
+				errPayload = &reply.ErrorPayload{
+					Code:    replylib.CodeUnprocessableEntity,
+					Message: "only grade 12 class can graduate",
+					Fields:  reply.FieldsError{"class_ids": err.Error()},
+				}
+				return err
+			}
+			class.GetName()
+			classByID[class.ID] = class
+		}
+
+		// get students of classes
+		var users []models.User
+		err = tx.Preload("StudentProfile").
+			Joins("JOIN students ON students.user_id = users.id AND students.deleted_at IS NULL").
+			Where("students.class_id IN ?", payload.ClassIDs).
+			Find(&users).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if len(users) == 0 {
+			err := errors.New("no student in class(es) to graduate")
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: err.Error()}
+			return err
+		}
+
+		// create alumni records from student profiles
+		alumni = make([]models.Alumni, len(users))
+		userIDs := make([]string, len(users))
+		studentIDs := make([]string, len(users))
+		for i, user := range users {
+			class := classByID[user.StudentProfile.ClassID]
+			alumni[i] = models.Alumni{
+				NISN:           user.StudentProfile.NISN,
+				GraduationYear: payload.GraduationYear,
+				FinalClassID:   &class.ID,
+				FinalClassName: class.Name,
+				Major:          class.Major,
+				AccessUntil:    accessUntil,
+				UserID:         user.ID,
+			}
+			userIDs[i] = user.ID
+			studentIDs[i] = user.StudentProfile.ID
+		}
+		if err := alumniRepo.CreateAll(s.ctx, &alumni); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// set role, student role is replaced by alumni role and other held roles are kept
+		err = tx.WithContext(s.ctx).Model(&models.User{}).Where("id IN ?", userIDs).Updates(map[string]any{
+			"role":  models.RoleAlumni,
+			"roles": gorm.Expr("REPLACE(roles, ?, ?)", `"`+string(models.RoleStudent)+`"`, `"`+string(models.RoleAlumni)+`"`),
+		}).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// release student profiles so classes can be used by next students
+		if err := tx.Exec("DELETE FROM student_parents WHERE student_id IN ?", studentIDs).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		// deleted permanently, archived profile would keep NISN reserved and be restored if the user becomes student again
+		if _, err := studentRepo.WithTx(tx.Unscoped()).DeleteByIDs(s.ctx, studentIDs); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedAlumni) GetAlumni(payload payloads.RequestGetAlumni) (*models.User, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	var user models.User
+	err := s.userRepo.DB().WithContext(s.ctx).
+		Preload("AlumniProfile").
+		Joins("JOIN alumni ON alumni.user_id = users.id").
+		Where("alumni.id = ?", payload.ID).
+		First(&user).Error
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "alumni not found", nil)
+	}
+
+	return &user, nil
+}
+
+func (s *ContextedAlumni) GetDirectory(payload payloads.RequestGetAlumniDirectory) (users []models.User, errPayload *reply.ErrorPayload) {
+	q := s.userRepo.DB().WithContext(s.ctx).
+		Select("users.id", "users.full_name", "users.gender").
+		Preload("AlumniProfile").
+		Joins("JOIN alumni ON alumni.user_id = users.id").
+		Order("alumni.graduation_year DESC, users.full_name").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Year > 0 {
+		q = q.Where("alumni.graduation_year = ?", payload.Year)
+	}
+	if payload.Major != "" {
+		q = q.Where("alumni.major = ?", payload.Major)
+	}
+	if payload.Query != "" {
+		q = q.Where("LOWER(users.full_name) LIKE LOWER(?)", "%"+payload.Query+"%")
+	}
+
+	if err := q.Find(&users).Error; err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return
+}
+
+func (s *ContextedAlumni) GetSelf() (*models.User, *reply.ErrorPayload) {
+	user, err := s.userRepo.GetFirstWithPreload(s.ctx, []string{"AlumniProfile"}, "id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your user profile not found", nil)
+	}
+	if user.AlumniProfile == nil {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "your alumni profile not found", nil)
+	}
+	if !user.AlumniProfile.HasAccess() {
+		return nil, &replylib.ErrAlumniAccessExpired
+	}
+
+	return &user, nil
+}
+
+func (s *ContextedAlumni) UpdateAccess(payload payloads.RequestUpdateAlumniAccess) (*models.Alumni, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	alumni, err := s.alumniRepo.UpdateByIDAndGet(s.ctx, payload.ID, models.Alumni{AccessUntil: payload.AccessUntil})
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if alumni.ID == "" {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "alumni not found", nil)
+	}
+
+	return &alumni, nil
+}
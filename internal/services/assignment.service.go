@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Assignment struct {
+	assignmentRepo *repos.Assignment
+	submissionRepo *repos.Submission
+	scoreRepo      *repos.Score
+	storedFileRepo *repos.StoredFile
+	teacherRepo    *repos.Teacher
+	studentRepo    *repos.Student
+	classRepo      *repos.Class
+}
+
+type ContextedAssignment struct {
+	*Assignment
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewAssignment(assignmentRepo *repos.Assignment, submissionRepo *repos.Submission, scoreRepo *repos.Score, storedFileRepo *repos.StoredFile, teacherRepo *repos.Teacher, studentRepo *repos.Student, classRepo *repos.Class) *Assignment {
+	return &Assignment{assignmentRepo, submissionRepo, scoreRepo, storedFileRepo, teacherRepo, studentRepo, classRepo}
+}
+
+func (s *Assignment) ApplyContext(c *gin.Context) *ContextedAssignment {
+	return &ContextedAssignment{s, c, c.Request.Context()}
+}
+
+func (s *ContextedAssignment) currentStudent() (*models.Student, *reply.ErrorPayload) {
+	student, err := s.studentRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your student profile not found", nil)
+	}
+	return &student, nil
+}
+
+func (s *ContextedAssignment) currentTeacher() (*models.Teacher, *reply.ErrorPayload) {
+	teacher, err := s.teacherRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your teacher profile not found", nil)
+	}
+	return &teacher, nil
+}
+
+// ensureOwner makes sure teacher only manage own assignment, admin is always allowed
+func (s *ContextedAssignment) ensureOwner(assignment *models.Assignment) (teacher *models.Teacher, errPayload *reply.ErrorPayload) {
+	if s.c.GetString("role") != string(models.RoleTeacher) {
+		return nil, nil
+	}
+	teacher, errPayload = s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if assignment.TeacherID == nil || *assignment.TeacherID != teacher.ID {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "assignment is owned by other teacher"}
+	}
+	return teacher, nil
+}
+
+func (s *ContextedAssignment) CreateAssignment(payload payloads.RequestCreateAssignment) (*models.Assignment, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	// check class
+	if exists, err := s.classRepo.Exists(s.ctx, "id = ?", payload.ClassID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "class not found", reply.FieldsError{"class_id": "class with this ID not found"})
+	}
+
+	// teacher must teach the subject
+	teaches, err := s.teacherRepo.TeachesSubject(s.ctx, teacher.ID, payload.SubjectID)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if !teaches {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeForbidden,
+			Message: "you do not teach this subject",
+			Fields:  reply.FieldsError{"subject_id": "subject is not registered as your subject"},
+		}
+	}
+
+	assignment := &models.Assignment{
+		Title:        payload.Title,
+		Instructions: payload.Instructions,
+		DueAt:        payload.DueAt,
+		AcceptLate:   payload.AcceptLate,
+		ClassID:      payload.ClassID,
+		SubjectID:    payload.SubjectID,
+		TeacherID:    &teacher.ID,
+	}
+
+	// store attachment
+	if payload.File != nil {
+		stored, errPayload := storeUpload(s.ctx, s.storedFileRepo, "file", payload.File)
+		if errPayload != nil {
+			return nil, errPayload
+		}
+		assignment.FileID = &stored.ID
+		assignment.File = stored
+		assignment.FileName = payload.File.Filename
+	}
+
+	if err := s.assignmentRepo.Create(s.ctx, assignment); err != nil {
+		if assignment.FileID != nil {
+			releaseFile(s.ctx, s.assignmentRepo.DB(), *assignment.FileID)
+		}
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	return assignment, nil
+}
+
+func (s *ContextedAssignment) GetAssignment(payload payloads.RequestGetAssignment) (*models.Assignment, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	assignment, err := s.assignmentRepo.GetFirstWithPreload(s.ctx, []string{"File", "Subject"}, "id = ?", payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "assignment not found", nil)
+	}
+
+	// student only access assignment of own class
+	if s.c.GetString("role") == string(models.RoleStudent) {
+		student, errPayload := s.currentStudent()
+		if errPayload != nil {
+			return nil, errPayload
+		}
+		if student.ClassID != assignment.ClassID {
+			return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "assignment is not given to your class"}
+		}
+	}
+
+	return &assignment, nil
+}
+
+func (s *ContextedAssignment) GetAssignments(payload payloads.RequestGetAssignments) ([]models.Assignment, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// student only see own class
+	if s.c.GetString("role") == string(models.RoleStudent) {
+		student, errPayload := s.currentStudent()
+		if errPayload != nil {
+			return nil, errPayload
+		}
+		payload.ClassID = student.ClassID
+	}
+
+	q := gorm.G[models.Assignment](s.assignmentRepo.DB()).
+		Preload("Subject", nil).
+		Order("due_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.ClassID != "" {
+		q = q.Where("class_id = ?", payload.ClassID)
+	}
+	if payload.SubjectID != "" {
+		q = q.Where("subject_id = ?", payload.SubjectID)
+	}
+	if payload.TeacherID != "" {
+		q = q.Where("teacher_id = ?", payload.TeacherID)
+	}
+
+	assignments, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return assignments, nil
+}
+
+// GetPendingAssignments returns assignments of student's class not submitted yet and still accepting submission
+func (s *ContextedAssignment) GetPendingAssignments(payload payloads.RequestGetPendingAssignments) ([]models.Assignment, *reply.ErrorPayload) {
+	student, errPayload := s.currentStudent()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	assignments, err := gorm.G[models.Assignment](s.assignmentRepo.DB()).
+		Preload("Subject", nil).
+		Where("class_id = ?", student.ClassID).
+		Where("due_at > ? OR accept_late", time.Now()).
+		Where("NOT EXISTS (SELECT 1 FROM submissions WHERE submissions.assignment_id = assignments.id AND submissions.student_id = ?)", student.ID).
+		Order("due_at").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset).
+		Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return assignments, nil
+}
+
+// DownloadAttachment returns attachment content of assignment, caller must close the content
+func (s *ContextedAssignment) DownloadAttachment(payload payloads.RequestGetAssignment) (*models.Assignment, io.ReadCloser, *reply.ErrorPayload) {
+	assignment, errPayload := s.GetAssignment(payload)
+	if errPayload != nil {
+		return nil, nil, errPayload
+	}
+	if assignment.File == nil {
+		return nil, nil, &reply.ErrorPayload{Code: replylib.CodeNotFound, Message: "assignment has no attachment"}
+	}
+
+	content, errPayload := openStoredFile(s.ctx, assignment.File)
+	if errPayload != nil {
+		return nil, nil, errPayload
+	}
+	return assignment, content, nil
+}
+
+func (s *ContextedAssignment) DeleteAssignment(payload payloads.RequestDeleteAssignment) (errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	s.assignmentRepo.DB().Transaction(func(tx *gorm.DB) error {
+		assignmentRepo := s.assignmentRepo.WithTx(tx)
+
+		assignment, err := assignmentRepo.GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "assignment not found", nil)
+			return err
+		}
+		if _, errPayload = s.ensureOwner(&assignment); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		// collect files of submissions before cascade deletion
+		var fileIDs []string
+		err = tx.Model(&models.Submission{}).Where("assignment_id = ? AND file_id IS NOT NULL", assignment.ID).Pluck("file_id", &fileIDs).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if assignment.FileID != nil {
+			fileIDs = append(fileIDs, *assignment.FileID)
+		}
+
+		if _, err := assignmentRepo.DeleteByID(s.ctx, assignment.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// scores of the assignment no longer have source
+		if _, err := s.scoreRepo.WithTx(tx).Delete(s.ctx, "source = ? AND source_id = ?", models.ScoreAssignment, assignment.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		for _, fileID := range fileIDs {
+			if err := releaseFile(s.ctx, tx, fileID); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+		}
+		return nil
+	})
+	return
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+)
+
+func (s *ContextedAssignment) SubmitAssignment(payload payloads.RequestSubmitAssignment) (submission *models.Submission, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	student, errPayload := s.currentStudent()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	assignment, err := s.assignmentRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "assignment not found", nil)
+	}
+	if assignment.ClassID != student.ClassID {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "assignment is not given to your class"}
+	}
+
+	// check deadline
+	now := time.Now()
+	isLate := assignment.IsOverdue(now)
+	if isLate && !assignment.AcceptLate {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "assignment is past due date and does not accept late submission"}
+	}
+
+	// check previous submission
+	prev, err := s.submissionRepo.GetFirst(s.ctx, "assignment_id = ? AND student_id = ?", assignment.ID, student.ID)
+	hasPrev := err == nil
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if hasPrev && prev.IsGraded() {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "submission already graded, can not resubmit"}
+	}
+
+	submission = &models.Submission{
+		AssignmentID: assignment.ID,
+		StudentID:    student.ID,
+		Text:         payload.Text,
+		SubmittedAt:  now,
+		IsLate:       isLate,
+	}
+
+	// store file
+	if payload.File != nil {
+		stored, errPayload := storeUpload(s.ctx, s.storedFileRepo, "file", payload.File)
+		if errPayload != nil {
+			return nil, errPayload
+		}
+		submission.FileID = &stored.ID
+		submission.File = stored
+		submission.FileName = payload.File.Filename
+	}
+
+	s.submissionRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if !hasPrev {
+			if err := s.submissionRepo.WithTx(tx).Create(s.ctx, submission); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+			return nil
+		}
+
+		// replace previous submission
+		submission.ID = prev.ID
+		submission.CreatedAt = prev.CreatedAt
+		err := tx.Model(submission).Select("text", "file_id", "file_name", "submitted_at", "is_late").Updates(submission).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if prev.FileID != nil {
+			if err := releaseFile(s.ctx, tx, *prev.FileID); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+		}
+		return nil
+	})
+	if errPayload != nil && submission.FileID != nil {
+		releaseFile(s.ctx, s.submissionRepo.DB(), *submission.FileID)
+		return nil, errPayload
+	}
+	return
+}
+
+func (s *ContextedAssignment) GetSubmissions(payload payloads.RequestGetSubmissions) ([]models.Submission, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	assignment, err := s.assignmentRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "assignment not found", nil)
+	}
+	if _, errPayload := s.ensureOwner(&assignment); errPayload != nil {
+		return nil, errPayload
+	}
+
+	submissions, err := gorm.G[models.Submission](s.submissionRepo.DB()).
+		Preload("File", nil).
+		Where("assignment_id = ?", assignment.ID).
+		Order("submitted_at").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset).
+		Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return submissions, nil
+}
+
+// GetUngradedSubmissions returns submissions of teacher's assignments waiting to be graded
+func (s *ContextedAssignment) GetUngradedSubmissions(payload payloads.RequestGetUngradedSubmissions) (submissions []models.Submission, errPayload *reply.ErrorPayload) {
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	err := s.submissionRepo.DB().WithContext(s.ctx).
+		Preload("Assignment").
+		Preload("File").
+		Joins("JOIN assignments ON assignments.id = submissions.assignment_id").
+		Where("assignments.teacher_id = ? AND submissions.graded_at IS NULL", teacher.ID).
+		Order("submissions.submitted_at").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset).
+		Find(&submissions).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return submissions, nil
+}
+
+// DownloadSubmission returns file content of submission, caller must close the content
+func (s *ContextedAssignment) DownloadSubmission(payload payloads.RequestGetSubmission) (*models.Submission, io.ReadCloser, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, nil, errPayload
+	}
+
+	submission, err := s.submissionRepo.GetFirstWithPreload(s.ctx, []string{"Assignment", "File"}, "id = ?", payload.ID)
+	if err != nil {
+		return nil, nil, errorlib.MakeNotFound(err, "submission not found", nil)
+	}
+
+	// student only download own submission
+	if s.c.GetString("role") == string(models.RoleStudent) {
+		student, errPayload := s.currentStudent()
+		if errPayload != nil {
+			return nil, nil, errPayload
+		}
+		if submission.StudentID != student.ID {
+			return nil, nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "submission is owned by other student"}
+		}
+	} else if _, errPayload := s.ensureOwner(submission.Assignment); errPayload != nil {
+		return nil, nil, errPayload
+	}
+
+	if submission.File == nil {
+		return nil, nil, &reply.ErrorPayload{Code: replylib.CodeNotFound, Message: "submission has no file"}
+	}
+	content, errPayload := openStoredFile(s.ctx, submission.File)
+	if errPayload != nil {
+		return nil, nil, errPayload
+	}
+	return &submission, content, nil
+}
+
+func (s *ContextedAssignment) GradeSubmission(payload payloads.RequestGradeSubmission) (submission *models.Submission, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.submissionRepo.DB().Transaction(func(tx *gorm.DB) error {
+		sub, err := s.submissionRepo.WithTx(tx).GetFirstWithPreload(s.ctx, []string{"Assignment"}, "id = ?", payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "submission not found", nil)
+			return err
+		}
+		teacher, errP := s.ensureOwner(sub.Assignment)
+		if errP != nil {
+			errPayload = errP
+			return errors.New(errP.Message)
+		}
+
+		now := time.Now()
+		sub.Grade = &payload.Grade
+		sub.Comment = payload.Comment
+		sub.GradedAt = &now
+		if err := tx.Model(&sub).Select("grade", "comment", "graded_at").Updates(&sub).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// record to gradebook
+		if payload.ToGradebook {
+			score := &models.Score{
+				StudentID: sub.StudentID,
+				SubjectID: sub.Assignment.SubjectID,
+				Source:    models.ScoreAssignment,
+				SourceID:  sub.AssignmentID,
+				Value:     payload.Grade,
+				Note:      payload.Comment,
+			}
+			if teacher != nil {
+				score.GradedByID = &teacher.ID
+			}
+			if err := s.scoreRepo.WithTx(tx).Record(s.ctx, score); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+		}
+
+		submission = &sub
+		return nil
+	})
+	return
+}
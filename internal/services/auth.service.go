@@ -6,12 +6,14 @@ import (
 	"net/http"
 	"school-information-system/config"
 	"school-information-system/internal/libs/authlib"
+	"school-information-system/internal/libs/errorlib"
 	"school-information-system/internal/libs/phonelib"
 	"school-information-system/internal/libs/replylib"
 	"school-information-system/internal/libs/validatorlib"
 	"school-information-system/internal/models"
 	"school-information-system/internal/models/payloads"
 	"school-information-system/internal/repos"
+	"slices"
 
 	"github.com/chesta132/goreply/reply"
 	"github.com/gin-gonic/gin"
@@ -21,6 +23,7 @@ import (
 type Auth struct {
 	userRepo    *repos.User
 	revokedRepo *repos.Revoked
+	alumniRepo  *repos.Alumni
 }
 
 type ContextedAuth struct {
@@ -29,8 +32,8 @@ type ContextedAuth struct {
 	ctx context.Context
 }
 
-func NewAuth(userRepo *repos.User, revokedRepo *repos.Revoked) *Auth {
-	return &Auth{userRepo, revokedRepo}
+func NewAuth(userRepo *repos.User, revokedRepo *repos.Revoked, alumniRepo *repos.Alumni) *Auth {
+	return &Auth{userRepo, revokedRepo, alumniRepo}
 }
 
 func (s *Auth) ApplyContext(c *gin.Context) *ContextedAuth {
@@ -96,10 +99,7 @@ func (s *ContextedAuth) SignUp(payload payloads.RequestSignUp) (*models.User, []
 	}
 
 	// create cookies and return
-	access := authlib.CreateAccessCookie(newUser.ID, string(newUser.Role), payload.RememberMe)
-	refresh := authlib.CreateRefreshCookie(newUser.ID, string(newUser.Role), payload.RememberMe)
-
-	return newUser, []http.Cookie{access, refresh}, nil
+	return newUser, authlib.CreateSessionCookies(newUser, newUser.Role, payload.RememberMe), nil
 }
 
 func (s *ContextedAuth) SignIn(payload payloads.RequestSignIn) (*models.User, []http.Cookie, *reply.ErrorPayload) {
@@ -125,11 +125,73 @@ func (s *ContextedAuth) SignIn(payload payloads.RequestSignIn) (*models.User, []
 		return nil, nil, &replylib.ErrIncorrectPassword
 	}
 
+	// graduates only can sign in as alumni until their access date, sign in with another held role if any
+	role := user.Role
+	if role == models.RoleAlumni {
+		if errPayload := s.checkAlumniAccess(user.ID); errPayload != nil {
+			others := slices.DeleteFunc(slices.Clone(user.HeldRoles()), func(r models.UserRole) bool { return r == models.RoleAlumni })
+			if len(others) == 0 {
+				return nil, nil, errPayload
+			}
+			role = others[0]
+		}
+	}
+
 	// create cookies and return
-	access := authlib.CreateAccessCookie(user.ID, string(user.Role), payload.RememberMe)
-	refresh := authlib.CreateRefreshCookie(user.ID, string(user.Role), payload.RememberMe)
+	return &user, authlib.CreateSessionCookies(&user, role, payload.RememberMe), nil
+}
+
+func (s *ContextedAuth) checkAlumniAccess(userID string) *reply.ErrorPayload {
+	alumni, err := s.alumniRepo.GetFirst(s.ctx, "user_id = ?", userID)
+	if err != nil {
+		return errorlib.MakeNotFound(err, "alumni profile not found", nil)
+	}
+	if !alumni.HasAccess() {
+		return &replylib.ErrAlumniAccessExpired
+	}
+	return nil
+}
+
+// SwitchRole replaces current session with session of another held role
+func (s *ContextedAuth) SwitchRole(payload payloads.RequestSwitchRole) (*models.User, []http.Cookie, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, nil, errPayload
+	}
+
+	user, err := s.userRepo.GetByID(s.ctx, s.c.GetString("userID"))
+	if err != nil {
+		return nil, nil, errorlib.MakeNotFound(err, "your user profile not found", nil)
+	}
+	if !user.HasRole(payload.Role) {
+		return nil, nil, &reply.ErrorPayload{
+			Code:    replylib.CodeForbidden,
+			Message: "you don't hold this role",
+			Fields:  reply.FieldsError{"role": "role must be one of your roles"},
+		}
+	}
+	if payload.Role == models.RoleAlumni {
+		if errPayload := s.checkAlumniAccess(user.ID); errPayload != nil {
+			return nil, nil, errPayload
+		}
+	}
+
+	// revoke refresh token of current session
+	clientRefresh, _ := s.c.Cookie(config.REFRESH_TOKEN_KEY)
+	refresh, err := authlib.ParseRefreshToken(clientRefresh)
+	if err != nil {
+		return nil, nil, &reply.ErrorPayload{Code: replylib.CodeUnauthorized, Message: err.Error()}
+	}
+	revokedToken := &models.Revoked{
+		Token:        clientRefresh,
+		Reason:       authlib.ReasonRoleSwitched,
+		RevokedUntil: refresh.ExpiresAt.Time,
+	}
+	if err := s.revokedRepo.Create(s.ctx, revokedToken); err != nil {
+		return nil, nil, errorlib.MakeServerError(err)
+	}
 
-	return &user, []http.Cookie{access, refresh}, nil
+	return &user, authlib.CreateSessionCookies(&user, payload.Role, refresh.RememberMe), nil
 }
 
 func (s *ContextedAuth) SignOut() []http.Cookie {
@@ -0,0 +1,374 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"slices"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type QuestionBank struct {
+	bankRepo        *repos.BankQuestion
+	bankVersionRepo *repos.BankQuestionVersion
+	teacherRepo     *repos.Teacher
+}
+
+type ContextedQuestionBank struct {
+	*QuestionBank
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewQuestionBank(bankRepo *repos.BankQuestion, bankVersionRepo *repos.BankQuestionVersion, teacherRepo *repos.Teacher) *QuestionBank {
+	return &QuestionBank{bankRepo, bankVersionRepo, teacherRepo}
+}
+
+func (s *QuestionBank) ApplyContext(c *gin.Context) *ContextedQuestionBank {
+	return &ContextedQuestionBank{s, c, c.Request.Context()}
+}
+
+// currentTeacher returns nil teacher for admin
+func (s *ContextedQuestionBank) currentTeacher() (*models.Teacher, *reply.ErrorPayload) {
+	if s.c.GetString("role") != string(models.RoleTeacher) {
+		return nil, nil
+	}
+	teacher, err := s.teacherRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your teacher profile not found", nil)
+	}
+	return &teacher, nil
+}
+
+// ensureSubjectScope makes sure teacher only access bank of own subjects, admin is always allowed
+func ensureSubjectScope(ctx context.Context, teacherRepo *repos.Teacher, teacher *models.Teacher, subjectID string) *reply.ErrorPayload {
+	if teacher == nil {
+		return nil
+	}
+	teaches, err := teacherRepo.TeachesSubject(ctx, teacher.ID, subjectID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if !teaches {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeForbidden,
+			Message: "question bank is shared to teachers of the subject only",
+			Fields:  reply.FieldsError{"subject_id": "subject is not registered as your subject"},
+		}
+	}
+	return nil
+}
+
+func makeContent(payload payloads.RequestQuestionContent) models.QuestionContent {
+	return models.QuestionContent{
+		Type:    payload.Type,
+		Prompt:  payload.Prompt,
+		Options: payload.Options,
+		Answers: payload.Answers,
+		Points:  payload.Points,
+	}
+}
+
+// questionsWithCurrentVersion returns bank questions in the order of ids, each with only its current version
+func questionsWithCurrentVersion(ctx context.Context, db *gorm.DB, teacherRepo *repos.Teacher, teacher *models.Teacher, ids []string) ([]models.BankQuestion, *reply.ErrorPayload) {
+	questions, err := gorm.G[models.BankQuestion](db).
+		Preload("Versions", repos.WithCurrentVersion).
+		Preload("Subject", nil).
+		Where("id IN ?", ids).
+		Find(ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	byID := make(map[string]models.BankQuestion, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+
+	ordered := make([]models.BankQuestion, 0, len(ids))
+	checkedSubjects := make(map[string]struct{})
+	for i, id := range ids {
+		q, ok := byID[id]
+		if !ok || len(q.Versions) == 0 {
+			return nil, errorlib.MakeNotFound(
+				gorm.ErrRecordNotFound,
+				"bank question not found",
+				reply.FieldsError{fmt.Sprintf("question_ids[%d]", i): "bank question with this ID not found"},
+			)
+		}
+		if _, checked := checkedSubjects[q.SubjectID]; !checked {
+			if errPayload := ensureSubjectScope(ctx, teacherRepo, teacher, q.SubjectID); errPayload != nil {
+				return nil, errPayload
+			}
+			checkedSubjects[q.SubjectID] = struct{}{}
+		}
+		ordered = append(ordered, q)
+	}
+	return ordered, nil
+}
+
+func (s *ContextedQuestionBank) CreateQuestion(payload payloads.RequestCreateBankQuestion) (question *models.BankQuestion, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if errPayload := ensureSubjectScope(s.ctx, s.teacherRepo, teacher, payload.SubjectID); errPayload != nil {
+		return nil, errPayload
+	}
+
+	content := makeContent(payload.RequestQuestionContent)
+	if err := content.CheckAnswerKey(); err != nil {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: "invalid payload", Fields: reply.FieldsError{"answers": err.Error()}}
+	}
+
+	var authorID *string
+	if teacher != nil {
+		authorID = &teacher.ID
+	}
+
+	s.bankRepo.DB().Transaction(func(tx *gorm.DB) error {
+		question = &models.BankQuestion{
+			SubjectID:      payload.SubjectID,
+			Grade:          payload.Grade,
+			Topic:          payload.Topic,
+			Competency:     payload.Competency,
+			Difficulty:     payload.Difficulty,
+			AuthorID:       authorID,
+			CurrentVersion: 1,
+		}
+		if err := s.bankRepo.WithTx(tx).Create(s.ctx, question); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		version := models.BankQuestionVersion{QuestionID: question.ID, Version: 1, QuestionContent: content, EditedByID: authorID}
+		if err := s.bankVersionRepo.WithTx(tx).Create(s.ctx, &version); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		question.Versions = []models.BankQuestionVersion{version}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedQuestionBank) GetQuestion(payload payloads.RequestGetBankQuestion) (*models.BankQuestion, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	question, err := gorm.G[models.BankQuestion](s.bankRepo.DB()).
+		Preload("Versions", func(db gorm.PreloadBuilder) error {
+			db.Order("version DESC")
+			return nil
+		}).
+		Preload("Subject", nil).
+		Where("id = ?", payload.ID).
+		First(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "bank question not found", nil)
+	}
+
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if errPayload := ensureSubjectScope(s.ctx, s.teacherRepo, teacher, question.SubjectID); errPayload != nil {
+		return nil, errPayload
+	}
+
+	return &question, nil
+}
+
+func (s *ContextedQuestionBank) GetQuestions(payload payloads.RequestGetBankQuestions) ([]models.BankQuestion, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.BankQuestion](s.bankRepo.DB()).
+		Preload("Versions", repos.WithCurrentVersion).
+		Preload("Subject", nil).
+		Order("created_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+
+	// teacher only see bank of own subjects
+	if teacher != nil {
+		q = q.Where("subject_id IN (SELECT subject_id FROM teacher_subjects WHERE teacher_id = ?)", teacher.ID)
+	}
+	if payload.SubjectID != "" {
+		q = q.Where("subject_id = ?", payload.SubjectID)
+	}
+	if payload.Grade != 0 {
+		q = q.Where("grade = ?", payload.Grade)
+	}
+	if payload.Topic != "" {
+		q = q.Where("LOWER(topic) = LOWER(?)", payload.Topic)
+	}
+	if payload.Competency != "" {
+		q = q.Where("competency LIKE ?", payload.Competency+"%")
+	}
+	if payload.Difficulty != "" {
+		q = q.Where("difficulty = ?", payload.Difficulty)
+	}
+	if payload.Query != "" {
+		q = q.Where(`EXISTS (
+			SELECT 1 FROM bank_question_versions v
+			WHERE v.question_id = bank_questions.id AND v.version = bank_questions.current_version AND LOWER(v.prompt) LIKE LOWER(?)
+		)`, "%"+payload.Query+"%")
+	}
+
+	questions, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return questions, nil
+}
+
+func (s *ContextedQuestionBank) UpdateQuestion(payload payloads.RequestUpdateBankQuestion) (question *models.BankQuestion, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	content := makeContent(payload.RequestQuestionContent)
+	if err := content.CheckAnswerKey(); err != nil {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: "invalid payload", Fields: reply.FieldsError{"answers": err.Error()}}
+	}
+
+	s.bankRepo.DB().Transaction(func(tx *gorm.DB) error {
+		// lock question so concurrent edits get sequential versions
+		q, err := gorm.G[models.BankQuestion](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", payload.ID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "bank question not found", nil)
+			return err
+		}
+		if errP := ensureSubjectScope(s.ctx, s.teacherRepo.WithTx(tx), teacher, q.SubjectID); errP != nil {
+			errPayload = errP
+			return errors.New(errP.Message)
+		}
+
+		// content is never edited in place, save as new version
+		version := models.BankQuestionVersion{QuestionID: q.ID, Version: q.CurrentVersion + 1, QuestionContent: content}
+		if teacher != nil {
+			version.EditedByID = &teacher.ID
+		}
+		if err := s.bankVersionRepo.WithTx(tx).Create(s.ctx, &version); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		q.Grade = payload.Grade
+		q.Topic = payload.Topic
+		q.Competency = payload.Competency
+		q.Difficulty = payload.Difficulty
+		q.CurrentVersion = version.Version
+		err = tx.Model(&q).Select("grade", "topic", "competency", "difficulty", "current_version").Updates(&q).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		q.Versions = []models.BankQuestionVersion{version}
+		question = &q
+		return nil
+	})
+	return
+}
+
+func (s *ContextedQuestionBank) DeleteQuestion(payload payloads.RequestDeleteBankQuestion) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	question, err := s.bankRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return errorlib.MakeNotFound(err, "bank question not found", nil)
+	}
+
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return errPayload
+	}
+	if errPayload := ensureSubjectScope(s.ctx, s.teacherRepo, teacher, question.SubjectID); errPayload != nil {
+		return errPayload
+	}
+
+	// copied quiz questions are kept, only lose reference to the version
+	if _, err := s.bankRepo.DeleteByID(s.ctx, question.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	return nil
+}
+
+// MakeExamPaper assembles printable paper from current version of bank questions
+func (s *ContextedQuestionBank) MakeExamPaper(payload payloads.RequestMakeExamPaper) (*payloads.ResponseExamPaper, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	questions, errPayload := questionsWithCurrentVersion(s.ctx, s.bankRepo.DB(), s.teacherRepo, teacher, payload.QuestionIDs)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if payload.Shuffle {
+		rand.Shuffle(len(questions), func(a, b int) { questions[a], questions[b] = questions[b], questions[a] })
+	}
+
+	paper := &payloads.ResponseExamPaper{Title: payload.Title, Questions: make([]payloads.ResponseExamPaperQuestion, len(questions))}
+	for i, q := range questions {
+		v := q.Versions[0]
+		if subject := q.Subject; subject != nil && !slices.Contains(paper.Subjects, subject.Name) {
+			paper.Subjects = append(paper.Subjects, subject.Name)
+		}
+		paper.TotalPoints += v.Points
+		paper.Questions[i] = payloads.ResponseExamPaperQuestion{
+			Number:    i + 1,
+			Type:      v.Type,
+			Prompt:    v.Prompt,
+			Options:   v.Options,
+			Points:    v.Points,
+			VersionID: v.ID,
+		}
+		if payload.IncludeAnswers {
+			paper.Questions[i].Answers = v.Answers
+		}
+	}
+
+	return paper, nil
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ChangeRequest struct {
+	changeRepo       *repos.ChangeRequest
+	userRepo         *repos.User
+	notificationRepo *repos.Notification
+	permission       *Permission
+	roleSetter       *RoleSetter
+	admin            *Admin
+}
+
+type ContextedChangeRequest struct {
+	*ChangeRequest
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewChangeRequest(changeRepo *repos.ChangeRequest, userRepo *repos.User, notificationRepo *repos.Notification, permission *Permission, roleSetter *RoleSetter, admin *Admin) *ChangeRequest {
+	return &ChangeRequest{changeRepo, userRepo, notificationRepo, permission, roleSetter, admin}
+}
+
+func (s *ChangeRequest) ApplyContext(c *gin.Context) *ContextedChangeRequest {
+	return &ContextedChangeRequest{s, c, c.Request.Context()}
+}
+
+// requestChange records sensitive change to be applied once another admin holding the permission needed by the change approves it,
+// the holders are notified
+func requestChange(ctx context.Context, changeRepo *repos.ChangeRequest, adminRepo *repos.Admin, notificationRepo *repos.Notification, requesterID string, kind models.ChangeRequestKind, targetID string, resource models.PermissionResource, actions []models.PermissionAction, payload any) (request *models.ChangeRequest, errPayload *reply.ErrorPayload) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	// transaction to rollback if error
+	changeRepo.DB().Transaction(func(tx *gorm.DB) error {
+		request = &models.ChangeRequest{
+			Kind:        kind,
+			Status:      models.ChangePending,
+			Payload:     raw,
+			TargetID:    targetID,
+			Resource:    resource,
+			Actions:     actions,
+			RequesterID: requesterID,
+			ExpiresAt:   time.Now().Add(config.CHANGE_REQUEST_EXPIRY),
+		}
+		if err := changeRepo.WithTx(tx).Create(ctx, request); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// notify admins who can review the change
+		holders, err := adminRepo.WithTx(tx).Holders(ctx, resource, actions...)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		notifications := make([]models.Notification, 0, len(holders))
+		for _, holder := range holders {
+			if holder.UserID == requesterID {
+				continue
+			}
+			notifications = append(notifications, models.Notification{
+				UserID:  holder.UserID,
+				Title:   "change request waiting for approval",
+				Message: fmt.Sprintf("change request %s (%s) needs your approval before %s", request.ID, kind, request.ExpiresAt.Format(time.RFC3339)),
+			})
+		}
+		if len(notifications) > 0 {
+			if err := notificationRepo.WithTx(tx).CreateAll(ctx, &notifications); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedChangeRequest) GetChangeRequests(payload payloads.RequestGetChangeRequests) ([]models.ChangeRequest, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.ChangeRequest](s.changeRepo.DB()).
+		Order("created_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Status != "" {
+		q = q.Where("status = ?", payload.Status)
+	}
+	if payload.Kind != "" {
+		q = q.Where("kind = ?", payload.Kind)
+	}
+
+	requests, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return requests, nil
+}
+
+func (s *ContextedChangeRequest) GetChangeRequest(payload payloads.RequestGetChangeRequest) (*models.ChangeRequest, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	request, err := s.changeRepo.GetFirstWithPreload(s.ctx, []string{"Requester", "Reviewer"}, "id = ?", payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "change request not found", nil)
+	}
+	return &request, nil
+}
+
+// getReviewable returns pending change request which current user can review,
+// reviewer must be another admin than requester who holds the permission needed by the change
+func (s *ContextedChangeRequest) getReviewable(id string) (*models.ChangeRequest, *reply.ErrorPayload) {
+	request, err := s.changeRepo.GetByID(s.ctx, id)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "change request not found", nil)
+	}
+	if request.Status != models.ChangePending {
+		return nil, &replylib.ErrChangeRequestReviewed
+	}
+	// expired request may not be marked by cron job yet
+	if !request.ExpiresAt.After(time.Now()) {
+		return nil, &replylib.ErrChangeRequestReviewed
+	}
+
+	userID := s.c.GetString("userID")
+	if request.RequesterID == userID {
+		return nil, &replylib.ErrReviewOwnChangeRequest
+	}
+	reviewer, err := s.userRepo.GetFirstWithPreload(s.ctx, []string{"AdminProfile.Grants.Permission", "AdminProfile.Bundles.Permissions"}, "id = ?", userID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your user profile not found", nil)
+	}
+	if reviewer.AdminProfile == nil {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "your admin profile or permission not registered"}
+	}
+	if action, missing := reviewer.AdminProfile.MissingAction(request.Resource, request.Actions...); missing {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeForbidden,
+			Message: fmt.Sprintf("missing permission: %s.%s, only holder of the permission can review this change request", request.Resource, action),
+		}
+	}
+	return &request, nil
+}
+
+// notifyRequester tells requester about the review, failure is not returned since the review is already done
+func (s *ContextedChangeRequest) notifyRequester(request *models.ChangeRequest) {
+	message := fmt.Sprintf("your change request %s (%s) is %s", request.ID, request.Kind, request.Status)
+	if request.ReviewNote != "" {
+		message += ": " + request.ReviewNote
+	}
+	s.notificationRepo.Create(s.ctx, &models.Notification{
+		UserID:  request.RequesterID,
+		Title:   "change request " + string(request.Status),
+		Message: message,
+	})
+}
+
+// applyChange applies change of the request as requested, current user is recorded as actor of the change
+func (s *ContextedChangeRequest) applyChange(request *models.ChangeRequest) *reply.ErrorPayload {
+	var err error
+	var errPayload *reply.ErrorPayload
+	switch request.Kind {
+	case models.ChangeGrantPermission:
+		var payload payloads.RequestGrantPermission
+		if err = json.Unmarshal(request.Payload, &payload); err == nil {
+			_, _, errPayload = s.permission.ApplyContext(s.c).grantPermission(payload)
+		}
+	case models.ChangeRevokePermission:
+		var payload payloads.RequestRevokePermission
+		if err = json.Unmarshal(request.Payload, &payload); err == nil {
+			_, _, errPayload = s.permission.ApplyContext(s.c).revokePermission(payload)
+		}
+	case models.ChangeSetRole:
+		var payload payloads.RequestSetRole
+		if err = json.Unmarshal(request.Payload, &payload); err == nil {
+			_, errPayload = s.roleSetter.ApplyContext(s.c).setRole(payload)
+		}
+	case models.ChangeRemoveRole:
+		var payload payloads.RequestRemoveRole
+		if err = json.Unmarshal(request.Payload, &payload); err == nil {
+			_, errPayload = s.roleSetter.ApplyContext(s.c).removeRole(payload)
+		}
+	case models.ChangeGrantBundle:
+		var payload payloads.RequestGrantPermissionBundle
+		if err = json.Unmarshal(request.Payload, &payload); err == nil {
+			_, _, errPayload = s.permission.ApplyContext(s.c).grantBundle(payload)
+		}
+	case models.ChangeRevokeBundle:
+		var payload payloads.RequestRevokePermissionBundle
+		if err = json.Unmarshal(request.Payload, &payload); err == nil {
+			_, _, errPayload = s.permission.ApplyContext(s.c).revokeBundle(payload)
+		}
+	case models.ChangeUpdateBundle:
+		var payload payloads.RequestUpdatePermissionBundle
+		if err = json.Unmarshal(request.Payload, &payload); err == nil {
+			_, errPayload = s.permission.ApplyContext(s.c).updateBundle(payload)
+		}
+	case models.ChangeDeleteBundle:
+		var payload payloads.RequestDeletePermissionBundle
+		if err = json.Unmarshal(request.Payload, &payload); err == nil {
+			errPayload = s.permission.ApplyContext(s.c).deleteBundle(payload)
+		}
+	case models.ChangeDeactivateAdmin:
+		var payload payloads.RequestDeactivateAdmin
+		if err = json.Unmarshal(request.Payload, &payload); err == nil {
+			_, errPayload = s.admin.ApplyContext(s.c).deactivateAdmin(payload)
+		}
+	default:
+		err = errors.New("unknown change request kind " + string(request.Kind))
+	}
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	return errPayload
+}
+
+// ApproveChangeRequest applies the change of the request, the request is kept pending if the change fails
+func (s *ContextedChangeRequest) ApproveChangeRequest(payload payloads.RequestReviewChangeRequest) (*models.ChangeRequest, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	request, errPayload := s.getReviewable(payload.ID)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	// claim the request so it is applied once
+	now := time.Now()
+	reviewerID := s.c.GetString("userID")
+	claimed, err := s.changeRepo.Review(s.ctx, request.ID, models.ChangeApproved, reviewerID, payload.Note, now)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if !claimed {
+		return nil, &replylib.ErrChangeRequestReviewed
+	}
+
+	if errPayload := s.applyChange(request); errPayload != nil {
+		if err := s.changeRepo.Reopen(s.ctx, request.ID); err != nil {
+			return nil, errorlib.MakeServerError(err)
+		}
+		return nil, errPayload
+	}
+
+	request.Status = models.ChangeApproved
+	request.ReviewerID = &reviewerID
+	request.ReviewNote = payload.Note
+	request.ReviewedAt = &now
+	s.notifyRequester(request)
+	return request, nil
+}
+
+// RejectChangeRequest closes the request without applying the change
+func (s *ContextedChangeRequest) RejectChangeRequest(payload payloads.RequestReviewChangeRequest) (*models.ChangeRequest, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	request, errPayload := s.getReviewable(payload.ID)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	now := time.Now()
+	reviewerID := s.c.GetString("userID")
+	reviewed, err := s.changeRepo.Review(s.ctx, request.ID, models.ChangeRejected, reviewerID, payload.Note, now)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if !reviewed {
+		return nil, &replylib.ErrChangeRequestReviewed
+	}
+
+	request.Status = models.ChangeRejected
+	request.ReviewerID = &reviewerID
+	request.ReviewNote = payload.Note
+	request.ReviewedAt = &now
+	s.notifyRequester(request)
+	return request, nil
+}
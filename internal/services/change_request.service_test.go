@@ -0,0 +1,257 @@
+//go:build integration
+
+package services
+
+import (
+	"school-information-system/database/seeds"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"testing"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+)
+
+// changeRequestFixture has requester and reviewer holding every seed permission and target admin to change
+type changeRequestFixture struct {
+	db            *gorm.DB
+	rp            *repos.Repos
+	requester     *models.User
+	reviewer      *models.User
+	target        *models.User
+	permission    *Permission
+	admin         *Admin
+	changeRequest *ChangeRequest
+}
+
+func newChangeRequestFixture(t *testing.T) *changeRequestFixture {
+	db := openTestDB(t)
+	rp := repos.New(db)
+	permission := NewPermission(rp.User(), rp.Permission(), rp.Admin(), rp.PermissionBundle(), rp.PermissionAudit(), rp.ChangeRequest(), rp.Notification())
+	roleSetter := NewRoleSetter(rp.User(), rp.Admin(), rp.Student(), rp.Class(), rp.Parent(), rp.Teacher(), rp.Subject(), rp.PermissionAudit(), rp.ChangeRequest(), rp.Notification())
+	admin := NewAdmin(rp.User(), rp.Admin(), rp.PermissionAudit(), rp.ChangeRequest(), rp.Notification())
+
+	return &changeRequestFixture{
+		db:            db,
+		rp:            rp,
+		requester:     createTestAdmin(t, db, "requester", seeds.PermissionSeeds...),
+		reviewer:      createTestAdmin(t, db, "reviewer", seeds.PermissionSeeds...),
+		target:        createTestAdmin(t, db, "target", seeds.PermissionSeeds...),
+		permission:    permission,
+		admin:         admin,
+		changeRequest: NewChangeRequest(rp.ChangeRequest(), rp.User(), rp.Notification(), permission, roleSetter, admin),
+	}
+}
+
+// requestRevokeSeed requests revoking the first seed permission of target admin
+func (f *changeRequestFixture) requestRevokeSeed(t *testing.T) *models.ChangeRequest {
+	t.Helper()
+	_, _, request, errPayload := f.permission.ApplyContext(newTestContext(f.requester.ID)).RevokePermission(payloads.RequestRevokePermission{
+		TargetID:     f.target.ID,
+		PermissionID: seeds.PermissionSeeds[0].ID,
+	})
+	if errPayload != nil {
+		t.Fatalf("request revoke: %s", errPayload.Message)
+	}
+	if request == nil || request.Status != models.ChangePending || request.Kind != models.ChangeRevokePermission {
+		t.Fatalf("expected pending revoke request, got %+v", request)
+	}
+	return request
+}
+
+func (f *changeRequestFixture) review(userID, requestID string, approve bool) (*models.ChangeRequest, *reply.ErrorPayload) {
+	service := f.changeRequest.ApplyContext(newTestContext(userID))
+	payload := payloads.RequestReviewChangeRequest{ID: requestID, Note: "checked"}
+	if approve {
+		return service.ApproveChangeRequest(payload)
+	}
+	return service.RejectChangeRequest(payload)
+}
+
+func (f *changeRequestFixture) statusOf(t *testing.T, requestID string) models.ChangeRequestStatus {
+	t.Helper()
+	request, err := f.rp.ChangeRequest().GetByID(t.Context(), requestID)
+	if err != nil {
+		t.Fatalf("get change request: %v", err)
+	}
+	return request.Status
+}
+
+func TestApproveChangeRequest(t *testing.T) {
+	f := newChangeRequestFixture(t)
+	request := f.requestRevokeSeed(t)
+	seedID := seeds.PermissionSeeds[0].ID
+
+	if !holdsPermission(t, f.db, f.target.AdminProfile.ID, seedID) {
+		t.Fatal("expected permission held until the request is approved")
+	}
+	if _, errPayload := f.review(f.requester.ID, request.ID, true); errPayload == nil || errPayload.Message != replylib.ErrReviewOwnChangeRequest.Message {
+		t.Fatalf("expected requester unable to approve, got %+v", errPayload)
+	}
+
+	approved, errPayload := f.review(f.reviewer.ID, request.ID, true)
+	if errPayload != nil {
+		t.Fatalf("approve: %s", errPayload.Message)
+	}
+	if approved.Status != models.ChangeApproved || approved.ReviewerID == nil || *approved.ReviewerID != f.reviewer.ID {
+		t.Fatalf("expected approved by reviewer, got %+v", approved)
+	}
+	if holdsPermission(t, f.db, f.target.AdminProfile.ID, seedID) {
+		t.Fatal("expected permission revoked once approved")
+	}
+	if status := f.statusOf(t, request.ID); status != models.ChangeApproved {
+		t.Fatalf("expected approved status stored, got %s", status)
+	}
+
+	if _, errPayload := f.review(f.reviewer.ID, request.ID, true); errPayload == nil || errPayload.Message != replylib.ErrChangeRequestReviewed.Message {
+		t.Fatalf("expected request approved once, got %+v", errPayload)
+	}
+}
+
+func TestApproveChangeRequestFailedChange(t *testing.T) {
+	f := newChangeRequestFixture(t)
+	request := f.requestRevokeSeed(t)
+
+	// permission is revoked by other way while the request waits
+	if err := f.db.Model(f.target.AdminProfile).Association("Permissions").Delete(&seeds.PermissionSeeds[0]); err != nil {
+		t.Fatalf("revoke permission: %v", err)
+	}
+
+	if _, errPayload := f.review(f.reviewer.ID, request.ID, true); errPayload == nil {
+		t.Fatal("expected approval to fail")
+	}
+	if status := f.statusOf(t, request.ID); status != models.ChangePending {
+		t.Fatalf("expected request reopened, got %s", status)
+	}
+}
+
+func TestRejectChangeRequest(t *testing.T) {
+	f := newChangeRequestFixture(t)
+	request := f.requestRevokeSeed(t)
+
+	rejected, errPayload := f.review(f.reviewer.ID, request.ID, false)
+	if errPayload != nil {
+		t.Fatalf("reject: %s", errPayload.Message)
+	}
+	if rejected.Status != models.ChangeRejected || rejected.ReviewNote != "checked" {
+		t.Fatalf("expected rejected with note, got %+v", rejected)
+	}
+	if !holdsPermission(t, f.db, f.target.AdminProfile.ID, seeds.PermissionSeeds[0].ID) {
+		t.Fatal("expected permission kept after rejection")
+	}
+	if _, errPayload := f.review(f.reviewer.ID, request.ID, true); errPayload == nil || errPayload.Message != replylib.ErrChangeRequestReviewed.Message {
+		t.Fatalf("expected rejected request unable to be approved, got %+v", errPayload)
+	}
+}
+
+func TestExpireChangeRequest(t *testing.T) {
+	f := newChangeRequestFixture(t)
+	request := f.requestRevokeSeed(t)
+
+	past := time.Now().Add(-time.Minute)
+	if err := f.db.Model(&models.ChangeRequest{}).Where("id = ?", request.ID).Update("expires_at", past).Error; err != nil {
+		t.Fatalf("expire request: %v", err)
+	}
+
+	// expired request which is not marked yet can't be reviewed
+	if _, errPayload := f.review(f.reviewer.ID, request.ID, true); errPayload == nil || errPayload.Message != replylib.ErrChangeRequestReviewed.Message {
+		t.Fatalf("expected expired request unable to be approved, got %+v", errPayload)
+	}
+
+	expired, err := f.rp.ChangeRequest().ExpirePending(t.Context(), time.Now())
+	if err != nil {
+		t.Fatalf("expire pending: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != request.ID || expired[0].RequesterID != f.requester.ID {
+		t.Fatalf("expected the request expired, got %+v", expired)
+	}
+	if status := f.statusOf(t, request.ID); status != models.ChangeExpired {
+		t.Fatalf("expected expired status stored, got %s", status)
+	}
+	if !holdsPermission(t, f.db, f.target.AdminProfile.ID, seeds.PermissionSeeds[0].ID) {
+		t.Fatal("expected permission kept after expiry")
+	}
+}
+
+func TestGrantBundleChangeRequest(t *testing.T) {
+	f := newChangeRequestFixture(t)
+	ctx := newTestContext(f.requester.ID)
+
+	createBundle := func(name string, perms ...models.Permission) *models.PermissionBundle {
+		bundle := &models.PermissionBundle{Name: name + " " + f.target.ID, Description: "bundle of the test"}
+		if err := f.db.Create(bundle).Error; err != nil {
+			t.Fatalf("create bundle: %v", err)
+		}
+		if err := f.db.Model(bundle).Association("Permissions").Append(&perms); err != nil {
+			t.Fatalf("fill bundle: %v", err)
+		}
+		return bundle
+	}
+	plain := models.Permission{Name: "room test " + f.target.ID, Resource: models.ResourceRoom, Description: "permission of the test", Actions: []models.PermissionAction{models.ActionRead}}
+	if err := f.db.Create(&plain).Error; err != nil {
+		t.Fatalf("create permission: %v", err)
+	}
+
+	t.Run("without seed permission", func(t *testing.T) {
+		bundle := createBundle("plain", plain)
+		user, _, request, errPayload := f.permission.ApplyContext(ctx).GrantBundle(payloads.RequestGrantPermissionBundle{TargetID: f.target.ID, BundleID: bundle.ID})
+		if errPayload != nil {
+			t.Fatalf("grant bundle: %s", errPayload.Message)
+		}
+		if request != nil || user == nil {
+			t.Fatalf("expected bundle granted immediately, got request %+v", request)
+		}
+	})
+
+	t.Run("with seed permission", func(t *testing.T) {
+		bundle := createBundle("seeded", plain, seeds.PermissionSeeds[0])
+		_, _, request, errPayload := f.permission.ApplyContext(ctx).GrantBundle(payloads.RequestGrantPermissionBundle{TargetID: f.target.ID, BundleID: bundle.ID})
+		if errPayload != nil {
+			t.Fatalf("grant bundle: %s", errPayload.Message)
+		}
+		if request == nil || request.Kind != models.ChangeGrantBundle {
+			t.Fatalf("expected grant bundle request, got %+v", request)
+		}
+
+		if _, errPayload := f.review(f.reviewer.ID, request.ID, true); errPayload != nil {
+			t.Fatalf("approve: %s", errPayload.Message)
+		}
+		var count int64
+		f.db.Table("admin_bundles").Where("admin_id = ? AND permission_bundle_id = ?", f.target.AdminProfile.ID, bundle.ID).Count(&count)
+		if count != 1 {
+			t.Fatal("expected bundle granted once approved")
+		}
+	})
+}
+
+func TestDeactivateAdminChangeRequest(t *testing.T) {
+	f := newChangeRequestFixture(t)
+
+	request, errPayload := f.admin.ApplyContext(newTestContext(f.requester.ID)).DeactivateAdmin(payloads.RequestDeactivateAdmin{ID: f.target.AdminProfile.ID})
+	if errPayload != nil {
+		t.Fatalf("request deactivation: %s", errPayload.Message)
+	}
+	if request.Kind != models.ChangeDeactivateAdmin || request.TargetID != f.target.ID {
+		t.Fatalf("expected deactivation request of target, got %+v", request)
+	}
+	if exists, _ := f.rp.Admin().Exists(t.Context(), "id = ?", f.target.AdminProfile.ID); !exists {
+		t.Fatal("expected admin kept until the request is approved")
+	}
+
+	if _, errPayload := f.review(f.reviewer.ID, request.ID, true); errPayload != nil {
+		t.Fatalf("approve: %s", errPayload.Message)
+	}
+	if exists, _ := f.rp.Admin().Exists(t.Context(), "id = ?", f.target.AdminProfile.ID); exists {
+		t.Fatal("expected admin archived once approved")
+	}
+	user, err := f.rp.User().GetByID(t.Context(), f.target.ID)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if user.HasRole(models.RoleAdmin) {
+		t.Fatalf("expected admin role taken, got %v", user.Roles)
+	}
+}
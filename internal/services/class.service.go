@@ -21,6 +21,7 @@ type Class struct {
 	userRepo    *repos.User
 	teacherRepo *repos.Teacher
 	studentRepo *repos.Student
+	roomRepo    *repos.Room
 }
 
 type ContextedClass struct {
@@ -29,19 +30,28 @@ type ContextedClass struct {
 	ctx context.Context
 }
 
-func NewClass(classRepo *repos.Class, userRepo *repos.User, teacherRepo *repos.Teacher, studentRepo *repos.Student) *Class {
-	return &Class{classRepo, userRepo, teacherRepo, studentRepo}
+func NewClass(classRepo *repos.Class, userRepo *repos.User, teacherRepo *repos.Teacher, studentRepo *repos.Student, roomRepo *repos.Room) *Class {
+	return &Class{classRepo, userRepo, teacherRepo, studentRepo, roomRepo}
 }
 
 func (s *Class) ApplyContext(c *gin.Context) *ContextedClass {
 	return &ContextedClass{s, c, c.Request.Context()}
 }
 
+// ensureScope makes sure class of the ID is in scope of current admin on the resource action
+func (s *ContextedClass) ensureScope(db *gorm.DB, classID string, resource models.PermissionResource, action models.PermissionAction) *reply.ErrorPayload {
+	_, errPayload := ensureClassInScope(s.ctx, db, scopeOf(s.c, resource, action), classID)
+	return errPayload
+}
+
 func (s *ContextedClass) CreateClass(payload payloads.RequestCreateClass) (class *models.Class, errPayload *reply.ErrorPayload) {
 	// validate payload
 	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
 		return nil, errPayload
 	}
+	if !scopeOf(s.c, models.ResourceClass, models.ActionCreate).Contains(&models.Class{Grade: payload.Grade, Major: payload.Major}) {
+		return nil, &replylib.ErrOutOfScope
+	}
 
 	s.classRepo.DB().Transaction(func(tx *gorm.DB) error {
 		classRepo := s.classRepo.WithTx(tx)
@@ -71,9 +81,9 @@ func (s *ContextedClass) CreateClass(payload payloads.RequestCreateClass) (class
 
 		// create class
 		class = &models.Class{
-			Grade:         payload.Grade,
-			Major:         payload.Major,
-			ClassNumber:   payload.ClassNumber,
+			Grade:       payload.Grade,
+			Major:       payload.Major,
+			ClassNumber: payload.ClassNumber,
 		}
 		class.GetName()
 
@@ -95,10 +105,13 @@ func (s *ContextedClass) GetClass(payload payloads.RequestGetClass) (*models.Cla
 		return nil, errPayload
 	}
 
-	class, err := s.classRepo.GetByID(s.ctx, payload.ID)
+	class, err := s.classRepo.GetFirstWithPreload(s.ctx, []string{"HomeRoom"}, "id = ?", payload.ID)
 	if err != nil {
 		return nil, errorlib.MakeNotFound(err, "class not found", nil)
 	}
+	if !scopeOf(s.c, models.ResourceClass, models.ActionRead).Contains(&class) {
+		return nil, &replylib.ErrOutOfScope
+	}
 
 	class.GetName()
 	return &class, nil
@@ -115,6 +128,10 @@ func (s *ContextedClass) GetClasses(payload payloads.RequestGetClasses) ([]model
 	if payload.Major != "" {
 		q = q.Where("major = ?", payload.Major)
 	}
+	if scope := scopeOf(s.c, models.ResourceClass, models.ActionRead); scope != nil {
+		cond, args := scopeCondition(scope, "id")
+		q = q.Where(cond, args...)
+	}
 
 	classes, err := q.Find(s.ctx)
 	if err != nil {
@@ -137,6 +154,8 @@ func (s *ContextedClass) UpdateClass(payload payloads.RequestUpdateClass) (class
 	s.classRepo.DB().Transaction(func(tx *gorm.DB) error {
 		classRepo := s.classRepo.WithTx(tx)
 
+		scope := scopeOf(s.c, models.ResourceClass, models.ActionUpdate)
+
 		// check presence
 		classExists, err := classRepo.Exists(s.ctx, "id = ?", payload.ID)
 		if err != nil {
@@ -147,6 +166,9 @@ func (s *ContextedClass) UpdateClass(payload payloads.RequestUpdateClass) (class
 			errPayload = errorlib.MakeNotFound(gorm.ErrRecordNotFound, "class not found", nil)
 			return gorm.ErrRecordNotFound
 		}
+		if errPayload = s.ensureScope(tx, payload.ID, models.ResourceClass, models.ActionUpdate); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
 
 		// update class
 		update := models.Class{Grade: payload.Grade, Major: payload.Major, ClassNumber: payload.ClassNumber}
@@ -156,6 +178,11 @@ func (s *ContextedClass) UpdateClass(payload payloads.RequestUpdateClass) (class
 			errPayload = errorlib.MakeServerError(err)
 			return err
 		}
+		// class can not be moved out of the scope
+		if !scope.Contains(&classUpdt) {
+			errPayload = &replylib.ErrOutOfScope
+			return errors.New(errPayload.Message)
+		}
 		classUpdt.Name = classUpdt.GetName()
 		class = &classUpdt
 		return nil
@@ -173,6 +200,10 @@ func (s *ContextedClass) DeleteClass(payload payloads.RequestDeleteClass) (errPa
 		classRepo := s.classRepo.WithTx(tx)
 		studentRepo := s.studentRepo.WithTx(tx)
 
+		if errPayload = s.ensureScope(tx, payload.ID, models.ResourceClass, models.ActionDelete); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
 		// check student relation
 		studentExists, err := studentRepo.Exists(s.ctx, "class_id = ?", payload.ID)
 		if err != nil {
@@ -27,6 +27,9 @@ func (s *ContextedClass) GetFormTeacher(payload payloads.RequestGetClass) (*mode
 	if !exists {
 		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "class not found", nil)
 	}
+	if errPayload := s.ensureScope(s.classRepo.DB(), payload.ID, models.ResourceClass, models.ActionRead); errPayload != nil {
+		return nil, errPayload
+	}
 
 	teacher, err := s.classRepo.GetFormTeacher(s.ctx, payload.ID)
 	if err != nil {
@@ -41,6 +44,11 @@ func (s *ContextedClass) GetStudents(payload payloads.RequestGetClass) ([]models
 	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
 		return nil, errPayload
 	}
+	for _, resource := range []models.PermissionResource{models.ResourceClass, models.ResourceStudent} {
+		if errPayload := s.ensureScope(s.classRepo.DB(), payload.ID, resource, models.ActionRead); errPayload != nil {
+			return nil, errPayload
+		}
+	}
 
 	students, err := s.classRepo.GetStudents(s.ctx, payload.ID)
 	if err != nil {
@@ -63,6 +71,9 @@ func (s *ContextedClass) GetFull(payload payloads.RequestGetClass) (full *payloa
 	if err != nil {
 		return nil, errorlib.MakeNotFound(err, "class not found", nil)
 	}
+	if !scopeOf(s.c, models.ResourceClass, models.ActionRead).Contains(&class) || !scopeOf(s.c, models.ResourceStudent, models.ActionRead).Contains(&class) {
+		return nil, &replylib.ErrOutOfScope
+	}
 	class.Name = class.GetName()
 	full.Class = &class
 
@@ -125,10 +136,13 @@ func (s *ContextedClass) SetFormTeacher(payload payloads.RequestSetFormTeacher)
 		if errPayload != nil {
 			return gorm.ErrDuplicatedKey
 		}
+		if errPayload = s.ensureScope(tx, payload.ID, models.ResourceClass, models.ActionUpdate); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
 
 		// get user with teacher profile and validate is teacher exists
 		err = tx.Preload("TeacherProfile").
-			Joins("JOIN teachers ON teachers.user_id = users.id").
+			Joins("JOIN teachers ON teachers.user_id = users.id AND teachers.deleted_at IS NULL").
 			Where("teachers.id = ?", payload.TeacherID).
 			First(teacher).Error
 		if err != nil {
@@ -169,13 +183,18 @@ func (s *ContextedClass) AddStudents(payload payloads.RequestAddStudents) (stude
 			errPayload = errorlib.MakeNotFound(gorm.ErrRecordNotFound, "class not found", nil)
 			return gorm.ErrRecordNotFound
 		}
+		for _, resource := range []models.PermissionResource{models.ResourceClass, models.ResourceStudent} {
+			if errPayload = s.ensureScope(tx, payload.ID, resource, models.ActionUpdate); errPayload != nil {
+				return errors.New(errPayload.Message)
+			}
+		}
 
 		// removes duplicate ids
 		payload.StudentIDs = slicelib.Unique(payload.StudentIDs)
 
 		// get students and validate length
 		err = tx.Preload("StudentProfile").
-			Joins("JOIN students ON students.user_id = users.id").
+			Joins("JOIN students ON students.user_id = users.id AND students.deleted_at IS NULL").
 			Where("students.id IN ?", payload.StudentIDs).
 			Find(&students).Error
 		if err != nil {
@@ -194,6 +213,24 @@ func (s *ContextedClass) AddStudents(payload payloads.RequestAddStudents) (stude
 			return gorm.ErrRecordNotFound
 		}
 
+		// students can only be moved from classes in the scope
+		if scope := scopeOf(s.c, models.ResourceStudent, models.ActionUpdate); scope != nil {
+			cond, args := scopeCondition(scope, "class_id")
+			outside, err := studentRepo.Count(s.ctx, "id IN ? AND NOT ("+cond+")", append([]any{payload.StudentIDs}, args...)...)
+			if err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+			if outside > 0 {
+				errPayload = &reply.ErrorPayload{
+					Code:    replylib.CodeForbidden,
+					Message: replylib.ErrOutOfScope.Message,
+					Fields:  reply.FieldsError{"student_ids": fmt.Sprintf("%d student(s) with these id are in class out of scope of your permission", outside)},
+				}
+				return errors.New(errPayload.Message)
+			}
+		}
+
 		// update student's class_id column
 		err = studentRepo.Update(s.ctx, models.Student{ClassID: payload.ID}, "id IN ?", payload.StudentIDs)
 		if err != nil {
@@ -219,6 +256,10 @@ func (s *ContextedClass) RemoveFormTeacher(payload payloads.RequestGetClass) (te
 			errPayload = errorlib.MakeNotFound(err, "class not found", nil)
 			return err
 		}
+		if !scopeOf(s.c, models.ResourceClass, models.ActionUpdate).Contains(&class) {
+			errPayload = &replylib.ErrOutOfScope
+			return errors.New(errPayload.Message)
+		}
 
 		// check if form teacher exists
 		if class.FormTeacherID == nil {
@@ -232,7 +273,7 @@ func (s *ContextedClass) RemoveFormTeacher(payload payloads.RequestGetClass) (te
 		// get teacher data before removing
 		teacher = new(models.User)
 		err = tx.Preload("TeacherProfile").
-			Joins("JOIN teachers ON teachers.user_id = users.id").
+			Joins("JOIN teachers ON teachers.user_id = users.id AND teachers.deleted_at IS NULL").
 			Where("teachers.id = ?", class.FormTeacherID).
 			First(teacher).Error
 		if err != nil {
@@ -249,3 +290,88 @@ func (s *ContextedClass) RemoveFormTeacher(payload payloads.RequestGetClass) (te
 	})
 	return
 }
+
+func (s *ContextedClass) SetHomeRoom(payload payloads.RequestSetHomeRoom) (class *models.Class, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.classRepo.DB().Transaction(func(tx *gorm.DB) error {
+		classRepo := s.classRepo.WithTx(tx)
+
+		c, err := classRepo.GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "class not found", nil)
+			return err
+		}
+		if !scopeOf(s.c, models.ResourceClass, models.ActionUpdate).Contains(&c) {
+			errPayload = &replylib.ErrOutOfScope
+			return errors.New(errPayload.Message)
+		}
+
+		room, err := s.roomRepo.WithTx(tx).GetByID(s.ctx, payload.RoomID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "room not found", reply.FieldsError{"room_id": "room with this ID not found"})
+			return err
+		}
+
+		// a room is home of one class only
+		used, err := classRepo.Exists(s.ctx, "home_room_id = ? AND id <> ?", room.ID, c.ID)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if used {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeConflict,
+				Message: "room already used as home room of another class",
+				Fields:  reply.FieldsError{"room_id": "room already used as home room of another class"},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		err = tx.Model(new(models.Class)).Where("id = ?", c.ID).Update("home_room_id", room.ID).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		c.HomeRoomID = &room.ID
+		c.HomeRoom = &room
+		c.GetName()
+		class = &c
+		return nil
+	})
+	return
+}
+
+func (s *ContextedClass) RemoveHomeRoom(payload payloads.RequestGetClass) (*models.Class, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	class, err := s.classRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "class not found", nil)
+	}
+	if !scopeOf(s.c, models.ResourceClass, models.ActionUpdate).Contains(&class) {
+		return nil, &replylib.ErrOutOfScope
+	}
+	if class.HomeRoomID == nil {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeNotFound,
+			Message: "this class doesn't have a home room",
+		}
+	}
+
+	err = s.classRepo.DB().WithContext(s.ctx).Model(new(models.Class)).Where("id = ?", class.ID).Update("home_room_id", nil).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	class.HomeRoomID = nil
+	class.GetName()
+	return &class, nil
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/slicelib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Dormitory struct {
+	dormitoryRepo  *repos.Dormitory
+	roomRepo       *repos.DormRoom
+	assignmentRepo *repos.DormAssignment
+	rollRepo       *repos.NightRoll
+	teacherRepo    *repos.Teacher
+	studentRepo    *repos.Student
+}
+
+type ContextedDormitory struct {
+	*Dormitory
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewDormitory(dormitoryRepo *repos.Dormitory, roomRepo *repos.DormRoom, assignmentRepo *repos.DormAssignment, rollRepo *repos.NightRoll, teacherRepo *repos.Teacher, studentRepo *repos.Student) *Dormitory {
+	return &Dormitory{dormitoryRepo, roomRepo, assignmentRepo, rollRepo, teacherRepo, studentRepo}
+}
+
+func (s *Dormitory) ApplyContext(c *gin.Context) *ContextedDormitory {
+	return &ContextedDormitory{s, c, c.Request.Context()}
+}
+
+// currentTeacher returns teacher profile of current user, nil if current user is not a teacher
+func (s *ContextedDormitory) currentTeacher() (*models.Teacher, *reply.ErrorPayload) {
+	if s.c.GetString("role") != string(models.RoleTeacher) {
+		return nil, nil
+	}
+	teacher, err := s.teacherRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your teacher profile not found", nil)
+	}
+	return &teacher, nil
+}
+
+// ensureSupervisor makes sure teacher only access dormitory they supervise, admin is always allowed
+func (s *ContextedDormitory) ensureSupervisor(dormitoryID string) *reply.ErrorPayload {
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil || teacher == nil {
+		return errPayload
+	}
+
+	supervises, err := s.dormitoryRepo.HasSupervisor(s.ctx, dormitoryID, teacher.ID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if !supervises {
+		return &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "you are not supervisor of this dormitory"}
+	}
+	return nil
+}
+
+func (s *ContextedDormitory) CreateDormitory(payload payloads.RequestCreateDormitory) (*models.Dormitory, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.dormitoryRepo.Exists(s.ctx, "name = ?", payload.Name); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"name": "dormitory with this name already registered"}}
+	}
+
+	dormitory := &models.Dormitory{Name: payload.Name, Address: payload.Address}
+	if err := s.dormitoryRepo.Create(s.ctx, dormitory); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return dormitory, nil
+}
+
+func (s *ContextedDormitory) GetDormitory(payload payloads.RequestGetDormitory) (*models.Dormitory, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+	if errPayload := s.ensureSupervisor(payload.ID); errPayload != nil {
+		return nil, errPayload
+	}
+
+	dormitory, err := gorm.G[models.Dormitory](s.dormitoryRepo.DB()).
+		Preload("Supervisors", nil).
+		Preload("Rooms", func(db gorm.PreloadBuilder) error {
+			db.Order("name")
+			return nil
+		}).
+		Where("id = ?", payload.ID).
+		First(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "dormitory not found", nil)
+	}
+	return &dormitory, nil
+}
+
+func (s *ContextedDormitory) GetDormitories(payload payloads.RequestGetDormitories) ([]models.Dormitory, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.Dormitory](s.dormitoryRepo.DB()).
+		Order("name").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+
+	// teacher only see dormitories they supervise
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if teacher != nil {
+		q = q.Where("id IN (SELECT dormitory_id FROM dormitory_supervisors WHERE teacher_id = ?)", teacher.ID)
+	}
+
+	dormitories, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return dormitories, nil
+}
+
+func (s *ContextedDormitory) UpdateDormitory(payload payloads.RequestUpdateDormitory) (*models.Dormitory, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	dormitory, err := s.dormitoryRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "dormitory not found", nil)
+	}
+
+	if exists, err := s.dormitoryRepo.Exists(s.ctx, "name = ? AND id <> ?", payload.Name, dormitory.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"name": "another dormitory with this name already registered"}}
+	}
+
+	dormitory.Name = payload.Name
+	dormitory.Address = payload.Address
+	if err := s.dormitoryRepo.DB().WithContext(s.ctx).Model(&dormitory).Select("name", "address").Updates(&dormitory).Error; err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return &dormitory, nil
+}
+
+func (s *ContextedDormitory) DeleteDormitory(payload payloads.RequestDeleteDormitory) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	assigned, err := s.assignmentRepo.Exists(s.ctx, "room_id IN (SELECT id FROM dorm_rooms WHERE dormitory_id = ?)", payload.ID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if assigned {
+		return &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "dormitory still has room assignments"}
+	}
+
+	// rooms, supervisors and night rolls of the dormitory are deleted
+	if ok, err := s.dormitoryRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "dormitory not found", nil)
+	}
+	return nil
+}
+
+// SetSupervisors replaces supervisors of dormitory
+func (s *ContextedDormitory) SetSupervisors(payload payloads.RequestSetDormSupervisors) (dormitory *models.Dormitory, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// removes duplicate ids
+	payload.TeacherIDs = slicelib.Unique(payload.TeacherIDs)
+
+	s.dormitoryRepo.DB().Transaction(func(tx *gorm.DB) error {
+		found, err := s.dormitoryRepo.WithTx(tx).GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "dormitory not found", nil)
+			return err
+		}
+		dormitory = &found
+
+		teachers, err := s.teacherRepo.WithTx(tx).GetByIDs(s.ctx, payload.TeacherIDs)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if notFound := len(payload.TeacherIDs) - len(teachers); notFound > 0 {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeNotFound,
+				Message: "teacher(s) not found",
+				Fields:  reply.FieldsError{"teacher_ids": fmt.Sprintf("%d teacher(s) with these id not found", notFound)},
+			}
+			return gorm.ErrRecordNotFound
+		}
+
+		supervisors := slicelib.Map(teachers, func(_ int, teacher models.Teacher) *models.Teacher { return &teacher })
+		if err := tx.Model(dormitory).Association("Supervisors").Replace(supervisors); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		dormitory.Supervisors = supervisors
+		return nil
+	})
+	return
+}
+
+func (s *ContextedDormitory) CreateRoom(payload payloads.RequestCreateDormRoom) (*models.DormRoom, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.dormitoryRepo.Exists(s.ctx, "id = ?", payload.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "dormitory not found", nil)
+	}
+
+	if exists, err := s.roomRepo.Exists(s.ctx, "dormitory_id = ? AND name = ?", payload.ID, payload.Name); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"name": "room with this name already registered in the dormitory"}}
+	}
+
+	room := &models.DormRoom{
+		DormitoryID: payload.ID,
+		Name:        payload.Name,
+		Capacity:    payload.Capacity,
+		Gender:      payload.Gender,
+	}
+	if err := s.roomRepo.Create(s.ctx, room); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return room, nil
+}
+
+func (s *ContextedDormitory) UpdateRoom(payload payloads.RequestUpdateDormRoom) (room *models.DormRoom, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.roomRepo.DB().Transaction(func(tx *gorm.DB) error {
+		roomRepo := s.roomRepo.WithTx(tx)
+
+		// lock room so students can not be assigned while capacity changes
+		found, err := gorm.G[models.DormRoom](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", payload.ID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "dorm room not found", nil)
+			return err
+		}
+		room = &found
+
+		if exists, err := roomRepo.Exists(s.ctx, "dormitory_id = ? AND name = ? AND id <> ?", room.DormitoryID, payload.Name, room.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		} else if exists {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"name": "another room with this name already registered in the dormitory"}}
+			return errors.New(errPayload.Message)
+		}
+
+		// capacity must fit occupants of every term
+		var occupancy int
+		err = tx.WithContext(s.ctx).
+			Table("(?) AS terms", tx.Model(&models.DormAssignment{}).Select("COUNT(*) AS occupants").Where("room_id = ?", room.ID).Group("academic_year, semester")).
+			Select("COALESCE(MAX(occupants), 0)").
+			Scan(&occupancy).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if payload.Capacity < occupancy {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeUnprocessableEntity,
+				Message: "capacity is less than occupants of the room",
+				Fields:  reply.FieldsError{"capacity": fmt.Sprintf("room is occupied by %d student(s)", occupancy)},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		// gender can not be changed while students of current gender are assigned
+		if payload.Gender != room.Gender {
+			assigned, err := s.assignmentRepo.WithTx(tx).Exists(s.ctx, "room_id = ?", room.ID)
+			if err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+			if assigned {
+				errPayload = &reply.ErrorPayload{
+					Code:    replylib.CodeUnprocessableEntity,
+					Message: "room still has assigned students",
+					Fields:  reply.FieldsError{"gender": "remove assigned students before changing gender of the room"},
+				}
+				return errors.New(errPayload.Message)
+			}
+		}
+
+		room.Name = payload.Name
+		room.Capacity = payload.Capacity
+		room.Gender = payload.Gender
+		if err := tx.WithContext(s.ctx).Model(room).Select("name", "capacity", "gender").Updates(room).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedDormitory) DeleteRoom(payload payloads.RequestDeleteDormRoom) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	if assigned, err := s.assignmentRepo.Exists(s.ctx, "room_id = ?", payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if assigned {
+		return &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "dorm room still has assignments"}
+	}
+
+	if ok, err := s.roomRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "dorm room not found", nil)
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/slicelib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssignRoom assigns students to a dorm room in a term
+func (s *ContextedDormitory) AssignRoom(payload payloads.RequestAssignDormRoom) (assignments []models.DormAssignment, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// removes duplicate ids
+	payload.StudentIDs = slicelib.Unique(payload.StudentIDs)
+
+	s.assignmentRepo.DB().Transaction(func(tx *gorm.DB) error {
+		// lock room so concurrent assignments can not exceed capacity
+		room, err := gorm.G[models.DormRoom](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", payload.ID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "dorm room not found", nil)
+			return err
+		}
+
+		type studentGender struct {
+			ID     string
+			Gender models.UserGender
+		}
+		var students []studentGender
+		err = tx.WithContext(s.ctx).Table("students").
+			Select("students.id, users.gender").
+			Joins("JOIN users ON users.id = students.user_id").
+			Where("students.id IN ?", payload.StudentIDs).
+			Scan(&students).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if notFound := len(payload.StudentIDs) - len(students); notFound > 0 {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeNotFound,
+				Message: "student(s) not found",
+				Fields:  reply.FieldsError{"student_ids": fmt.Sprintf("%d student(s) with these id not found", notFound)},
+			}
+			return gorm.ErrRecordNotFound
+		}
+
+		// room is restricted to one gender
+		mismatch := slicelib.Filter(students, func(_ int, student studentGender) bool {
+			return student.Gender != room.Gender
+		})
+		if len(mismatch) > 0 {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeUnprocessableEntity,
+				Message: fmt.Sprintf("room is only for %s students", room.Gender),
+				Fields:  reply.FieldsError{"student_ids": fmt.Sprintf("%d student(s) are not %s", len(mismatch), room.Gender)},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		assignmentRepo := s.assignmentRepo.WithTx(tx)
+
+		// a student stays in one room per term
+		assigned, err := assignmentRepo.Count(s.ctx, "academic_year = ? AND semester = ? AND student_id IN ?", payload.AcademicYear, payload.Semester, payload.StudentIDs)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if assigned > 0 {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeConflict,
+				Message: "student(s) already assigned to a room",
+				Fields:  reply.FieldsError{"student_ids": fmt.Sprintf("%d student(s) already assigned to a room in the term", assigned)},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		occupants, err := assignmentRepo.Count(s.ctx, "room_id = ? AND academic_year = ? AND semester = ?", room.ID, payload.AcademicYear, payload.Semester)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if left := room.Capacity - int(occupants); len(payload.StudentIDs) > left {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeUnprocessableEntity,
+				Message: "room capacity exceeded",
+				Fields:  reply.FieldsError{"student_ids": fmt.Sprintf("room only has %d bed(s) left in the term", max(left, 0))},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		assignedByID := s.c.GetString("userID")
+		assignments = make([]models.DormAssignment, 0, len(payload.StudentIDs))
+		for _, studentID := range payload.StudentIDs {
+			assignments = append(assignments, models.DormAssignment{
+				RoomID:       room.ID,
+				StudentID:    studentID,
+				AcademicYear: payload.AcademicYear,
+				Semester:     payload.Semester,
+				AssignedByID: &assignedByID,
+			})
+		}
+		if err := assignmentRepo.CreateAll(s.ctx, &assignments); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedDormitory) GetAssignments(payload payloads.RequestGetDormAssignments) ([]models.DormAssignment, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// teacher only see dormitories they supervise
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.DormAssignment](s.assignmentRepo.DB()).
+		Preload("Room", nil).
+		Order("academic_year DESC, semester DESC, room_id").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if teacher != nil {
+		q = q.Where("room_id IN (SELECT dorm_rooms.id FROM dorm_rooms JOIN dormitory_supervisors ON dormitory_supervisors.dormitory_id = dorm_rooms.dormitory_id WHERE dormitory_supervisors.teacher_id = ?)", teacher.ID)
+	}
+	if payload.DormitoryID != "" {
+		q = q.Where("room_id IN (SELECT id FROM dorm_rooms WHERE dormitory_id = ?)", payload.DormitoryID)
+	}
+	if payload.RoomID != "" {
+		q = q.Where("room_id = ?", payload.RoomID)
+	}
+	if payload.StudentID != "" {
+		q = q.Where("student_id = ?", payload.StudentID)
+	}
+	if payload.AcademicYear != "" {
+		q = q.Where("academic_year = ?", payload.AcademicYear)
+	}
+	if payload.Semester != 0 {
+		q = q.Where("semester = ?", payload.Semester)
+	}
+
+	assignments, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return assignments, nil
+}
+
+func (s *ContextedDormitory) DeleteAssignment(payload payloads.RequestDeleteDormAssignment) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	if ok, err := s.assignmentRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "dorm assignment not found", nil)
+	}
+	return nil
+}
+
+// parseRollDate parses date of night roll in format 2006-01-02
+func parseRollDate(date string) (time.Time, *reply.ErrorPayload) {
+	parsed, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}, &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: "invalid payload", Fields: reply.FieldsError{"date": "date must be in format 2006-01-02"}}
+	}
+	return parsed, nil
+}
+
+// boardersAt returns query of assignments of the dormitory in term of the date
+func boardersAt(db *gorm.DB, dormitoryID string, date time.Time) *gorm.DB {
+	return db.Table("dorm_assignments").
+		Joins("JOIN dorm_rooms ON dorm_rooms.id = dorm_assignments.room_id").
+		Where("dorm_rooms.dormitory_id = ?", dormitoryID).
+		Where("dorm_assignments.academic_year = ? AND dorm_assignments.semester = ?", models.AcademicYearOf(date), models.SemesterOf(date))
+}
+
+// RecordNightRoll records night attendance of boarding students, recorded student of the date is replaced
+func (s *ContextedDormitory) RecordNightRoll(payload payloads.RequestRecordNightRoll) (rolls []models.NightRoll, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	date, errPayload := parseRollDate(payload.Date)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if date.After(time.Now()) {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "night roll can not be recorded for future date", Fields: reply.FieldsError{"date": "date must not be after today"}}
+	}
+
+	if exists, err := s.dormitoryRepo.Exists(s.ctx, "id = ?", payload.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "dormitory not found", nil)
+	}
+	if errPayload := s.ensureSupervisor(payload.ID); errPayload != nil {
+		return nil, errPayload
+	}
+
+	studentIDs := slicelib.Map(payload.Entries, func(_ int, entry payloads.RequestNightRollEntry) string { return entry.StudentID })
+	if len(slicelib.Unique(studentIDs)) != len(studentIDs) {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: "invalid payload", Fields: reply.FieldsError{"entries": "a student can only be recorded once"}}
+	}
+
+	// only students staying in the dormitory at the date can be recorded
+	var boarders int64
+	err := boardersAt(s.assignmentRepo.DB().WithContext(s.ctx), payload.ID, date).
+		Where("dorm_assignments.student_id IN ?", studentIDs).
+		Count(&boarders).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if notBoarding := len(studentIDs) - int(boarders); notBoarding > 0 {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "student(s) not staying in the dormitory",
+			Fields:  reply.FieldsError{"entries": fmt.Sprintf("%d student(s) are not assigned to this dormitory at the date", notBoarding)},
+		}
+	}
+
+	recordedByID := s.c.GetString("userID")
+	rolls = make([]models.NightRoll, 0, len(payload.Entries))
+	for _, entry := range payload.Entries {
+		rolls = append(rolls, models.NightRoll{
+			DormitoryID:  payload.ID,
+			StudentID:    entry.StudentID,
+			Date:         date,
+			Status:       entry.Status,
+			Note:         entry.Note,
+			RecordedByID: &recordedByID,
+		})
+	}
+	if err := s.rollRepo.Record(s.ctx, &rolls); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return rolls, nil
+}
+
+// GetNightRoll returns all boarding students of the dormitory with their night roll at the date
+func (s *ContextedDormitory) GetNightRoll(payload payloads.RequestGetNightRoll) ([]payloads.ResponseNightRollEntry, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	date, errPayload := parseRollDate(payload.Date)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.dormitoryRepo.Exists(s.ctx, "id = ?", payload.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "dormitory not found", nil)
+	}
+	if errPayload := s.ensureSupervisor(payload.ID); errPayload != nil {
+		return nil, errPayload
+	}
+
+	entries := []payloads.ResponseNightRollEntry{}
+	err := boardersAt(s.assignmentRepo.DB().WithContext(s.ctx), payload.ID, date).
+		Select("dorm_assignments.student_id, users.full_name, dorm_rooms.name AS room, night_rolls.status, COALESCE(night_rolls.note, '') AS note").
+		Joins("JOIN students ON students.id = dorm_assignments.student_id AND students.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = students.user_id").
+		Joins("LEFT JOIN night_rolls ON night_rolls.student_id = dorm_assignments.student_id AND night_rolls.date = ?", date).
+		Order("dorm_rooms.name, users.full_name").
+		Scan(&entries).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return entries, nil
+}
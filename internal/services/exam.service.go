@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Exam struct {
+	periodRepo      *repos.ExamPeriod
+	roomRepo        *repos.ExamRoom
+	sessionRepo     *repos.ExamSession
+	seatRepo        *repos.ExamSeat
+	invigilatorRepo *repos.ExamInvigilator
+	subjectRepo     *repos.Subject
+	teacherRepo     *repos.Teacher
+	studentRepo     *repos.Student
+	facilityRepo    *repos.Room
+}
+
+type ContextedExam struct {
+	*Exam
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewExam(periodRepo *repos.ExamPeriod, roomRepo *repos.ExamRoom, sessionRepo *repos.ExamSession, seatRepo *repos.ExamSeat, invigilatorRepo *repos.ExamInvigilator, subjectRepo *repos.Subject, teacherRepo *repos.Teacher, studentRepo *repos.Student, facilityRepo *repos.Room) *Exam {
+	return &Exam{periodRepo, roomRepo, sessionRepo, seatRepo, invigilatorRepo, subjectRepo, teacherRepo, studentRepo, facilityRepo}
+}
+
+func (s *Exam) ApplyContext(c *gin.Context) *ContextedExam {
+	return &ContextedExam{s, c, c.Request.Context()}
+}
+
+// periods
+
+func (s *ContextedExam) CreatePeriod(payload payloads.RequestCreateExamPeriod) (*models.ExamPeriod, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	period := &models.ExamPeriod{
+		Name:      payload.Name,
+		Type:      payload.Type,
+		StartDate: payload.StartDate,
+		EndDate:   payload.EndDate,
+	}
+	if err := s.periodRepo.Create(s.ctx, period); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return period, nil
+}
+
+func (s *ContextedExam) GetPeriod(payload payloads.RequestGetExamPeriod) (*models.ExamPeriod, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	period, err := gorm.G[models.ExamPeriod](s.periodRepo.DB()).
+		Preload("Sessions", func(db gorm.PreloadBuilder) error {
+			db.Order("start_at, grade")
+			return nil
+		}).
+		Preload("Sessions.Subject", nil).
+		Preload("Sessions.Invigilators", nil).
+		Where("id = ?", payload.ID).
+		First(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "exam period not found", nil)
+	}
+	return &period, nil
+}
+
+func (s *ContextedExam) GetPeriods(payload payloads.RequestGetExamPeriods) ([]models.ExamPeriod, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.ExamPeriod](s.periodRepo.DB()).
+		Order("start_date DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Type != "" {
+		q = q.Where("type = ?", payload.Type)
+	}
+
+	periods, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return periods, nil
+}
+
+func (s *ContextedExam) DeletePeriod(payload payloads.RequestDeleteExamPeriod) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	if ok, err := s.periodRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "exam period not found", nil)
+	}
+	return nil
+}
+
+// rooms
+
+// linkFacility checks room to be linked as exam room, empty roomID means no link
+func (s *ContextedExam) linkFacility(roomID, examRoomID string) (*string, *reply.ErrorPayload) {
+	if roomID == "" {
+		return nil, nil
+	}
+	if exists, err := s.facilityRepo.Exists(s.ctx, "id = ?", roomID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "room not found", reply.FieldsError{"room_id": "room with this ID not found"})
+	}
+	q := s.roomRepo.DB().Where("room_id = ?", roomID)
+	if examRoomID != "" {
+		q = q.Where("id <> ?", examRoomID)
+	}
+	if linked, err := s.roomRepo.Exists(s.ctx, q); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if linked {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"room_id": "room already linked to another exam room"}}
+	}
+	return &roomID, nil
+}
+
+func (s *ContextedExam) CreateRoom(payload payloads.RequestCreateExamRoom) (*models.ExamRoom, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.roomRepo.Exists(s.ctx, "name = ?", payload.Name); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"name": "another room with this name already registered"}}
+	}
+
+	facilityID, errPayload := s.linkFacility(payload.RoomID, "")
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	room := &models.ExamRoom{Name: payload.Name, Capacity: payload.Capacity, RoomID: facilityID}
+	if err := s.roomRepo.Create(s.ctx, room); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return room, nil
+}
+
+func (s *ContextedExam) GetRooms(payload payloads.RequestGetExamRooms) ([]models.ExamRoom, *reply.ErrorPayload) {
+	rooms, err := gorm.G[models.ExamRoom](s.roomRepo.DB()).
+		Order("name").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset).
+		Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return rooms, nil
+}
+
+func (s *ContextedExam) UpdateRoom(payload payloads.RequestUpdateExamRoom) (*models.ExamRoom, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	room, err := s.roomRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "exam room not found", nil)
+	}
+
+	if exists, err := s.roomRepo.Exists(s.ctx, "name = ? AND id <> ?", payload.Name, room.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"name": "another room with this name already registered"}}
+	}
+
+	// allocated seats must still fit the room
+	var maxSeat int
+	if err := s.seatRepo.DB().WithContext(s.ctx).Model(&models.ExamSeat{}).Where("room_id = ?", room.ID).Select("COALESCE(MAX(seat_number), 0)").Scan(&maxSeat).Error; err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if payload.Capacity < maxSeat {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: "room already has allocated seats beyond the new capacity",
+			Fields:  reply.FieldsError{"capacity": "capacity must be at least the highest allocated seat number"},
+		}
+	}
+
+	facilityID, errPayload := s.linkFacility(payload.RoomID, room.ID)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	room.Name = payload.Name
+	room.Capacity = payload.Capacity
+	room.RoomID = facilityID
+	if err := s.roomRepo.DB().WithContext(s.ctx).Model(&room).Select("name", "capacity", "room_id").Updates(&room).Error; err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return &room, nil
+}
+
+func (s *ContextedExam) DeleteRoom(payload payloads.RequestDeleteExamRoom) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	// room with allocation is restricted by database, check first for readable error
+	if used, err := s.seatRepo.Exists(s.ctx, "room_id = ?", payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if used {
+		return &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "room is used by exam allocation"}
+	}
+	if used, err := s.invigilatorRepo.Exists(s.ctx, "room_id = ?", payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if used {
+		return &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "room is used by exam invigilation"}
+	}
+
+	if ok, err := s.roomRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "exam room not found", nil)
+	}
+	return nil
+}
+
+// sessions
+
+func (s *ContextedExam) CreateSession(payload payloads.RequestCreateExamSession) (*models.ExamSession, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	period, err := s.periodRepo.GetByID(s.ctx, payload.PeriodID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "exam period not found", nil)
+	}
+	// end date is inclusive
+	if payload.StartAt.Before(period.StartDate) || payload.EndAt.After(period.EndDate.AddDate(0, 0, 1)) {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeBadRequest,
+			Message: "session must be held within the exam period",
+			Fields:  reply.FieldsError{"start_at": "session is outside of exam period"},
+		}
+	}
+
+	if exists, err := s.subjectRepo.Exists(s.ctx, "id = ?", payload.SubjectID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "subject not found", reply.FieldsError{"subject_id": "subject with this ID not found"})
+	}
+
+	if exists, err := s.sessionRepo.Exists(s.ctx, "period_id = ? AND subject_id = ? AND grade = ?", period.ID, payload.SubjectID, payload.Grade); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "subject of this grade already scheduled in the period"}
+	}
+
+	// a grade sits one exam at a time
+	if clash, err := s.sessionRepo.Exists(s.ctx, "grade = ? AND start_at < ? AND end_at > ?", payload.Grade, payload.EndAt, payload.StartAt); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if clash {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: "grade already has another exam at this time",
+			Fields:  reply.FieldsError{"start_at": "overlaps another session of the grade"},
+		}
+	}
+
+	session := &models.ExamSession{
+		PeriodID:  period.ID,
+		SubjectID: payload.SubjectID,
+		Grade:     payload.Grade,
+		StartAt:   payload.StartAt,
+		EndAt:     payload.EndAt,
+	}
+	if err := s.sessionRepo.Create(s.ctx, session); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return session, nil
+}
+
+func (s *ContextedExam) DeleteSession(payload payloads.RequestDeleteExamSession) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	if ok, err := s.sessionRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "exam session not found", nil)
+	}
+	return nil
+}
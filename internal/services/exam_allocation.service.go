@@ -0,0 +1,389 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"slices"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// interleaveByClass orders students so that consecutive students come from different classes
+func interleaveByClass(students []models.Student) []models.Student {
+	groups := make([][]models.Student, 0)
+	index := make(map[string]int)
+	for _, st := range students {
+		i, ok := index[st.ClassID]
+		if !ok {
+			i = len(groups)
+			index[st.ClassID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], st)
+	}
+
+	result := make([]models.Student, 0, len(students))
+	for round := 0; len(result) < len(students); round++ {
+		for _, group := range groups {
+			if round < len(group) {
+				result = append(result, group[round])
+			}
+		}
+	}
+	return result
+}
+
+// overlappingSessions is subquery of sessions other than sessionID overlapping the period
+func overlappingSessions(db *gorm.DB, sessionID string, startAt, endAt time.Time) *gorm.DB {
+	return db.Model(&models.ExamSession{}).Select("id").Where("id <> ? AND start_at < ? AND end_at > ?", sessionID, endAt, startAt)
+}
+
+// AllocateSeats replaces seats of the session. Students of the grade are spread across rooms class by class,
+// seats taken by other sessions at the same time are kept so grades can share a room
+func (s *ContextedExam) AllocateSeats(payload payloads.RequestAllocateExamSeats) (lists []payloads.ResponseExamRoomList, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	roomIDs := make([]string, 0, len(payload.RoomIDs))
+	for _, id := range payload.RoomIDs {
+		if !slices.Contains(roomIDs, id) {
+			roomIDs = append(roomIDs, id)
+		}
+	}
+
+	s.sessionRepo.DB().Transaction(func(tx *gorm.DB) error {
+		session, err := gorm.G[models.ExamSession](tx).Preload("Subject", nil).Where("id = ?", payload.ID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "exam session not found", nil)
+			return err
+		}
+
+		// lock rooms so concurrent allocations of overlapping sessions do not take the same seat
+		rooms, err := gorm.G[models.ExamRoom](tx, clause.Locking{Strength: "UPDATE"}).Where("id IN ?", roomIDs).Find(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		roomByID := make(map[string]models.ExamRoom, len(rooms))
+		for _, r := range rooms {
+			roomByID[r.ID] = r
+		}
+		for i, id := range roomIDs {
+			if _, ok := roomByID[id]; !ok {
+				errPayload = errorlib.MakeNotFound(gorm.ErrRecordNotFound, "exam room not found", reply.FieldsError{fmt.Sprintf("room_ids[%d]", i): "room with this ID not found"})
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		students, err := gorm.G[models.Student](tx).
+			Where("class_id IN (SELECT id FROM classes WHERE grade = ?)", session.Grade).
+			Where("user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)").
+			Order("class_id, nisn").
+			Find(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if len(students) == 0 {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "grade of the session has no active students"}
+			return errors.New(errPayload.Message)
+		}
+
+		// seats taken by other sessions at the same time
+		var taken []models.ExamSeat
+		err = tx.WithContext(s.ctx).
+			Where("room_id IN ? AND session_id IN (?)", roomIDs, overlappingSessions(tx, session.ID, session.StartAt, session.EndAt)).
+			Find(&taken).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		takenByRoom := make(map[string]map[int]struct{})
+		for _, seat := range taken {
+			if takenByRoom[seat.RoomID] == nil {
+				takenByRoom[seat.RoomID] = make(map[int]struct{})
+			}
+			takenByRoom[seat.RoomID][seat.SeatNumber] = struct{}{}
+		}
+
+		freeSeats := make([][]int, len(roomIDs))
+		totalFree := 0
+		for i, id := range roomIDs {
+			for number := 1; number <= roomByID[id].Capacity; number++ {
+				if _, ok := takenByRoom[id][number]; !ok {
+					freeSeats[i] = append(freeSeats[i], number)
+				}
+			}
+			totalFree += len(freeSeats[i])
+		}
+		if totalFree < len(students) {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeConflict,
+				Message: fmt.Sprintf("rooms capacity is not enough, %d more seats needed", len(students)-totalFree),
+				Fields:  reply.FieldsError{"room_ids": "add more rooms"},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		// deal students to rooms in turn so every class is spread across the rooms
+		seats := make([]models.ExamSeat, 0, len(students))
+		used := make([]int, len(roomIDs))
+		room := 0
+		for _, student := range interleaveByClass(students) {
+			for used[room] >= len(freeSeats[room]) {
+				room = (room + 1) % len(roomIDs)
+			}
+			seats = append(seats, models.ExamSeat{
+				SessionID:  session.ID,
+				StudentID:  student.ID,
+				RoomID:     roomIDs[room],
+				SeatNumber: freeSeats[room][used[room]],
+			})
+			used[room]++
+			room = (room + 1) % len(roomIDs)
+		}
+
+		if _, err := s.seatRepo.WithTx(tx).Delete(s.ctx, "session_id = ?", session.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if err := s.seatRepo.WithTx(tx).CreateAll(s.ctx, &seats); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// invigilators of rooms no longer used by the session are released
+		if _, err := s.invigilatorRepo.WithTx(tx).Delete(s.ctx, "session_id = ? AND room_id NOT IN ?", session.ID, roomIDs); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		lists, errPayload = s.roomLists(tx, &session)
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedExam) AssignInvigilator(payload payloads.RequestAssignInvigilator) (invigilator *models.ExamInvigilator, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.sessionRepo.DB().Transaction(func(tx *gorm.DB) error {
+		session, err := s.sessionRepo.WithTx(tx).GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "exam session not found", nil)
+			return err
+		}
+
+		// lock teacher so concurrent assignments can not double book
+		teacher, err := gorm.G[models.Teacher](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", payload.TeacherID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "teacher not found", reply.FieldsError{"teacher_id": "teacher with this ID not found"})
+			return err
+		}
+
+		if used, err := s.seatRepo.WithTx(tx).Exists(s.ctx, "session_id = ? AND room_id = ?", session.ID, payload.RoomID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		} else if !used {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeUnprocessableEntity,
+				Message: "room is not used by the session",
+				Fields:  reply.FieldsError{"room_id": "allocate seats to this room first"},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		booked, err := s.invigilatorRepo.WithTx(tx).Exists(
+			s.ctx,
+			"teacher_id = ? AND session_id IN (?)", teacher.ID, tx.Model(&models.ExamSession{}).Select("id").Where("start_at < ? AND end_at > ?", session.EndAt, session.StartAt),
+		)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if booked {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeConflict,
+				Message: "teacher already invigilates another room at this time",
+				Fields:  reply.FieldsError{"teacher_id": "teacher is double booked"},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		invigilator = &models.ExamInvigilator{SessionID: session.ID, TeacherID: teacher.ID, RoomID: payload.RoomID}
+		if err := s.invigilatorRepo.WithTx(tx).Create(s.ctx, invigilator); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedExam) RemoveInvigilator(payload payloads.RequestRemoveInvigilator) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	if ok, err := s.invigilatorRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "invigilator not found", nil)
+	}
+	return nil
+}
+
+func (s *ContextedExam) roomLists(db *gorm.DB, session *models.ExamSession) ([]payloads.ResponseExamRoomList, *reply.ErrorPayload) {
+	var seats []struct {
+		RoomID string
+		payloads.ResponseExamRoomSeat
+		Grade       int
+		Major       string
+		ClassNumber int
+	}
+	err := db.WithContext(s.ctx).Table("exam_seats").
+		Select("exam_seats.room_id, exam_seats.seat_number, students.id AS student_id, students.nisn, users.full_name, classes.grade, classes.major, classes.class_number").
+		Joins("JOIN students ON students.id = exam_seats.student_id AND students.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = students.user_id").
+		Joins("JOIN classes ON classes.id = students.class_id").
+		Where("exam_seats.session_id = ?", session.ID).
+		Order("exam_seats.seat_number").
+		Scan(&seats).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	var invigilators []struct {
+		RoomID   string
+		FullName string
+	}
+	err = db.WithContext(s.ctx).Table("exam_invigilators").
+		Select("exam_invigilators.room_id, users.full_name").
+		Joins("JOIN teachers ON teachers.id = exam_invigilators.teacher_id AND teachers.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = teachers.user_id").
+		Where("exam_invigilators.session_id = ?", session.ID).
+		Order("users.full_name").
+		Scan(&invigilators).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	var rooms []models.ExamRoom
+	err = db.WithContext(s.ctx).
+		Where("id IN (SELECT room_id FROM exam_seats WHERE session_id = ?)", session.ID).
+		Order("name").
+		Find(&rooms).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	subject := ""
+	if session.Subject != nil {
+		subject = session.Subject.Name
+	}
+	lists := make([]payloads.ResponseExamRoomList, len(rooms))
+	for i, room := range rooms {
+		list := payloads.ResponseExamRoomList{
+			Room:         room,
+			Subject:      subject,
+			Grade:        session.Grade,
+			StartAt:      session.StartAt,
+			EndAt:        session.EndAt,
+			Invigilators: []string{},
+			Seats:        []payloads.ResponseExamRoomSeat{},
+		}
+		for _, inv := range invigilators {
+			if inv.RoomID == room.ID {
+				list.Invigilators = append(list.Invigilators, inv.FullName)
+			}
+		}
+		for _, seat := range seats {
+			if seat.RoomID == room.ID {
+				class := models.Class{Grade: seat.Grade, Major: seat.Major, ClassNumber: seat.ClassNumber}
+				seat.ClassName = class.GetName()
+				list.Seats = append(list.Seats, seat.ResponseExamRoomSeat)
+			}
+		}
+		lists[i] = list
+	}
+	return lists, nil
+}
+
+// GetRoomLists returns printable list of every room used by the session
+func (s *ContextedExam) GetRoomLists(payload payloads.RequestGetExamRoomLists) ([]payloads.ResponseExamRoomList, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	session, err := gorm.G[models.ExamSession](s.sessionRepo.DB()).Preload("Subject", nil).Where("id = ?", payload.ID).First(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "exam session not found", nil)
+	}
+	return s.roomLists(s.sessionRepo.DB(), &session)
+}
+
+// GetExamCard returns printable exam card of student, student only get own card
+func (s *ContextedExam) GetExamCard(payload payloads.RequestGetExamCard) (*payloads.ResponseExamCard, *reply.ErrorPayload) {
+	if s.c.GetString("role") == string(models.RoleStudent) {
+		student, err := s.studentRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+		if err != nil {
+			return nil, errorlib.MakeNotFound(err, "your student profile not found", nil)
+		}
+		payload.StudentID = student.ID
+	}
+
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+	if payload.StudentID == "" {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: "invalid payload", Fields: reply.FieldsError{"student_id": "student_id is required"}}
+	}
+
+	period, err := s.periodRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "exam period not found", nil)
+	}
+
+	student, errPayload := findStudentIdentity(s.ctx, s.studentRepo.DB(), payload.StudentID)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	sessions := []payloads.ResponseExamCardSession{}
+	err = s.seatRepo.DB().WithContext(s.ctx).Table("exam_seats").
+		Select("subjects.name AS subject, exam_sessions.start_at, exam_sessions.end_at, exam_rooms.name AS room, exam_seats.seat_number").
+		Joins("JOIN exam_sessions ON exam_sessions.id = exam_seats.session_id").
+		Joins("JOIN subjects ON subjects.id = exam_sessions.subject_id").
+		Joins("JOIN exam_rooms ON exam_rooms.id = exam_seats.room_id").
+		Where("exam_sessions.period_id = ? AND exam_seats.student_id = ?", period.ID, student.StudentID).
+		Order("exam_sessions.start_at").
+		Scan(&sessions).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	return &payloads.ResponseExamCard{
+		Period:                  period.Name,
+		ResponseStudentIdentity: *student,
+		Sessions:                sessions,
+	}, nil
+}
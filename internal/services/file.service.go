@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/storagelib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/repos"
+	"slices"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+)
+
+// storeUpload validates and saves uploaded file, returns existing record if the same content already stored
+func storeUpload(ctx context.Context, storedFileRepo *repos.StoredFile, fieldName string, header *multipart.FileHeader) (*models.StoredFile, *reply.ErrorPayload) {
+	if header.Size > config.MAX_UPLOAD_SIZE {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeBadRequest,
+			Message: "file too large",
+			Fields:  reply.FieldsError{fieldName: fmt.Sprintf("file size must be less than %d MB", config.MAX_UPLOAD_SIZE>>20)},
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	defer file.Close()
+
+	// detect and validate mime
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, errorlib.MakeServerError(err)
+	}
+	mimeType := storagelib.DetectMime(head[:n], header.Filename)
+	if !slices.Contains(config.ALLOWED_UPLOAD_MIMES, mimeType) {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeBadRequest,
+			Message: "file type not allowed",
+			Fields:  reply.FieldsError{fieldName: fmt.Sprintf("%s is not allowed", mimeType)},
+		}
+	}
+
+	// checksum for dedup
+	hasher := sha256.New()
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if _, err := io.Copy(hasher, file); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+
+	stored, err := storedFileRepo.GetFirst(ctx, "checksum = ?", checksum)
+	if err == nil {
+		return &stored, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	// save content, checksum as key
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if err := storagelib.Client.Save(ctx, checksum, file); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	stored = models.StoredFile{Checksum: checksum, Size: header.Size, MimeType: mimeType}
+	if err := storedFileRepo.Create(ctx, &stored); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return &stored, nil
+}
+
+// fileReferences is tables referencing stored file by file_id column
+var fileReferences = []string{"learning_materials", "assignments", "submissions", "achievements"}
+
+// releaseFile deletes stored file and its content if no record references it anymore
+func releaseFile(ctx context.Context, db *gorm.DB, fileID string) error {
+	for _, table := range fileReferences {
+		var used bool
+		if err := db.WithContext(ctx).Raw(fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE file_id = ?)", table), fileID).Scan(&used).Error; err != nil {
+			return err
+		}
+		if used {
+			return nil
+		}
+	}
+
+	storedFileRepo := repos.NewStoredFile(db)
+	stored, err := storedFileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if _, err := storedFileRepo.DeleteByID(ctx, fileID); err != nil {
+		return err
+	}
+	return storagelib.Client.Delete(ctx, stored.Checksum)
+}
+
+// openStoredFile opens content of stored file
+func openStoredFile(ctx context.Context, stored *models.StoredFile) (io.ReadCloser, *reply.ErrorPayload) {
+	content, err := storagelib.Client.Open(ctx, stored.Checksum)
+	if err != nil {
+		if errors.Is(err, storagelib.ErrNotFound) {
+			return nil, &reply.ErrorPayload{Code: replylib.CodeNotFound, Message: err.Error()}
+		}
+		return nil, errorlib.MakeServerError(err)
+	}
+	return content, nil
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Health struct {
+	healthRepo  *repos.StudentHealth
+	visitRepo   *repos.ClinicVisit
+	studentRepo *repos.Student
+	classRepo   *repos.Class
+	teacherRepo *repos.Teacher
+}
+
+type ContextedHealth struct {
+	*Health
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewHealth(healthRepo *repos.StudentHealth, visitRepo *repos.ClinicVisit, studentRepo *repos.Student, classRepo *repos.Class, teacherRepo *repos.Teacher) *Health {
+	return &Health{healthRepo, visitRepo, studentRepo, classRepo, teacherRepo}
+}
+
+func (s *Health) ApplyContext(c *gin.Context) *ContextedHealth {
+	return &ContextedHealth{s, c, c.Request.Context()}
+}
+
+func (s *ContextedHealth) checkStudent(studentID, field string) *reply.ErrorPayload {
+	if exists, err := s.studentRepo.Exists(s.ctx, "id = ?", studentID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !exists {
+		var fields reply.FieldsError
+		if field != "" {
+			fields = reply.FieldsError{field: "student with this ID not found"}
+		}
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "student not found", fields)
+	}
+	return nil
+}
+
+func (s *ContextedHealth) GetStudentHealth(payload payloads.RequestGetStudentHealth) (*models.StudentHealth, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if errPayload := s.checkStudent(payload.ID, ""); errPayload != nil {
+		return nil, errPayload
+	}
+
+	health, err := s.healthRepo.GetFirst(s.ctx, "student_id = ?", payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "health record of the student not found", nil)
+	}
+	return &health, nil
+}
+
+func (s *ContextedHealth) SetStudentHealth(payload payloads.RequestSetStudentHealth) (*models.StudentHealth, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if errPayload := s.checkStudent(payload.ID, ""); errPayload != nil {
+		return nil, errPayload
+	}
+
+	updatedByID := s.c.GetString("userID")
+	health := &models.StudentHealth{
+		StudentID:         payload.ID,
+		BloodType:         payload.BloodType,
+		Allergies:         payload.Allergies,
+		ChronicConditions: payload.ChronicConditions,
+		Immunizations:     payload.Immunizations,
+		Notes:             payload.Notes,
+		UpdatedByID:       &updatedByID,
+	}
+	if health.Allergies == nil {
+		health.Allergies = []string{}
+	}
+	if health.ChronicConditions == nil {
+		health.ChronicConditions = []string{}
+	}
+	if health.Immunizations == nil {
+		health.Immunizations = []models.Immunization{}
+	}
+	if err := s.healthRepo.Set(s.ctx, health); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	// reload for created_at of existing record
+	saved, err := s.healthRepo.GetFirst(s.ctx, "student_id = ?", payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return &saved, nil
+}
+
+func (s *ContextedHealth) CreateVisit(payload payloads.RequestCreateClinicVisit) (*models.ClinicVisit, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if errPayload := s.checkStudent(payload.StudentID, "student_id"); errPayload != nil {
+		return nil, errPayload
+	}
+
+	handledByID := s.c.GetString("userID")
+	visit := &models.ClinicVisit{
+		StudentID:      payload.StudentID,
+		VisitedAt:      payload.VisitedAt,
+		Complaint:      payload.Complaint,
+		ActionTaken:    payload.ActionTaken,
+		ParentNotified: payload.ParentNotified,
+		HandledByID:    &handledByID,
+	}
+	if visit.ParentNotified {
+		now := time.Now()
+		visit.ParentNotifiedAt = &now
+	}
+	if err := s.visitRepo.Create(s.ctx, visit); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return visit, nil
+}
+
+func (s *ContextedHealth) GetVisits(payload payloads.RequestGetClinicVisits) ([]models.ClinicVisit, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.ClinicVisit](s.visitRepo.DB()).
+		Preload("Student.Class", nil).
+		Order("visited_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.StudentID != "" {
+		q = q.Where("student_id = ?", payload.StudentID)
+	}
+	if payload.ClassID != "" {
+		q = q.Where("student_id IN (SELECT id FROM students WHERE class_id = ? AND deleted_at IS NULL)", payload.ClassID)
+	}
+	if !payload.From.IsZero() {
+		q = q.Where("visited_at >= ?", payload.From)
+	}
+	if !payload.To.IsZero() {
+		q = q.Where("visited_at < ?", payload.To)
+	}
+	if payload.ParentNotNotified {
+		q = q.Where("parent_notified = ?", false)
+	}
+
+	visits, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return visits, nil
+}
+
+func (s *ContextedHealth) NotifyVisitParent(payload payloads.RequestNotifyClinicVisitParent) (*models.ClinicVisit, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	visit, err := s.visitRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "clinic visit not found", nil)
+	}
+	if visit.ParentNotified {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "parent already notified"}
+	}
+
+	now := time.Now()
+	visit.ParentNotified = true
+	visit.ParentNotifiedAt = &now
+	if err := s.visitRepo.DB().WithContext(s.ctx).Model(&visit).Select("parent_notified", "parent_notified_at").Updates(&visit).Error; err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return &visit, nil
+}
+
+func (s *ContextedHealth) DeleteVisit(payload payloads.RequestDeleteClinicVisit) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	if ok, err := s.visitRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "clinic visit not found", nil)
+	}
+	return nil
+}
+
+// GetClassAllergies returns allergy flags of students in the class, only students with allergy are listed.
+// Teacher only reads class they are form teacher of or teach in timetable
+func (s *ContextedHealth) GetClassAllergies(payload payloads.RequestGetClassAllergies) ([]payloads.ResponseAllergyFlag, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.classRepo.Exists(s.ctx, "id = ?", payload.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "class not found", nil)
+	}
+	if s.c.GetString("role") == string(models.RoleTeacher) {
+		teacher, err := s.teacherRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+		if err != nil {
+			return nil, errorlib.MakeNotFound(err, "your teacher profile not found", nil)
+		}
+		if teaches, err := s.teacherRepo.TeachesClass(s.ctx, teacher.ID, payload.ID); err != nil {
+			return nil, errorlib.MakeServerError(err)
+		} else if !teaches {
+			return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "you don't teach this class"}
+		}
+	}
+
+	flags := []payloads.ResponseAllergyFlag{}
+	err := s.healthRepo.DB().WithContext(s.ctx).Table("student_healths").
+		Select("students.id AS student_id, users.full_name, student_healths.allergies").
+		Joins("JOIN students ON students.id = student_healths.student_id AND students.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = students.user_id AND users.deleted_at IS NULL").
+		Where("students.class_id = ? AND student_healths.allergies <> ?", payload.ID, "[]").
+		Order("users.full_name").
+		Scan(&flags).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return flags, nil
+}
@@ -0,0 +1,444 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/slicelib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"strings"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Internship struct {
+	partnerRepo   *repos.IndustryPartner
+	placementRepo *repos.InternshipPlacement
+	journalRepo   *repos.InternshipJournal
+	visitRepo     *repos.MonitoringVisit
+	teacherRepo   *repos.Teacher
+	studentRepo   *repos.Student
+}
+
+type ContextedInternship struct {
+	*Internship
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewInternship(partnerRepo *repos.IndustryPartner, placementRepo *repos.InternshipPlacement, journalRepo *repos.InternshipJournal, visitRepo *repos.MonitoringVisit, teacherRepo *repos.Teacher, studentRepo *repos.Student) *Internship {
+	return &Internship{partnerRepo, placementRepo, journalRepo, visitRepo, teacherRepo, studentRepo}
+}
+
+func (s *Internship) ApplyContext(c *gin.Context) *ContextedInternship {
+	return &ContextedInternship{s, c, c.Request.Context()}
+}
+
+func (s *ContextedInternship) currentTeacher() (*models.Teacher, *reply.ErrorPayload) {
+	teacher, err := s.teacherRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your teacher profile not found", nil)
+	}
+	return &teacher, nil
+}
+
+func (s *ContextedInternship) currentStudent() (*models.Student, *reply.ErrorPayload) {
+	student, err := s.studentRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your student profile not found", nil)
+	}
+	return &student, nil
+}
+
+// ensurePlacementAccess makes sure teacher only access placement they supervise and student only own placement, admin is always allowed
+func (s *ContextedInternship) ensurePlacementAccess(placement *models.InternshipPlacement) *reply.ErrorPayload {
+	switch s.c.GetString("role") {
+	case string(models.RoleTeacher):
+		teacher, errPayload := s.currentTeacher()
+		if errPayload != nil {
+			return errPayload
+		}
+		if placement.SupervisorID == nil || *placement.SupervisorID != teacher.ID {
+			return &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "you are not supervisor of this internship"}
+		}
+	case string(models.RoleStudent):
+		student, errPayload := s.currentStudent()
+		if errPayload != nil {
+			return errPayload
+		}
+		if placement.StudentID != student.ID {
+			return &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "this internship is not yours"}
+		}
+	}
+	return nil
+}
+
+func (s *ContextedInternship) CreatePartner(payload payloads.RequestCreateIndustryPartner) (*models.IndustryPartner, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.partnerRepo.Exists(s.ctx, "name = ?", payload.Name); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"name": "industry partner with this name already registered"}}
+	}
+
+	partner := &models.IndustryPartner{
+		Name:         payload.Name,
+		Field:        payload.Field,
+		Address:      payload.Address,
+		ContactName:  payload.ContactName,
+		ContactPhone: payload.ContactPhone,
+		ContactEmail: payload.ContactEmail,
+		Majors:       slicelib.Unique(payload.Majors),
+		Active:       true,
+	}
+	if err := s.partnerRepo.Create(s.ctx, partner); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return partner, nil
+}
+
+func (s *ContextedInternship) GetPartner(payload payloads.RequestGetIndustryPartner) (*models.IndustryPartner, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	partner, err := s.partnerRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "industry partner not found", nil)
+	}
+	return &partner, nil
+}
+
+func (s *ContextedInternship) GetPartners(payload payloads.RequestGetIndustryPartners) ([]models.IndustryPartner, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.IndustryPartner](s.partnerRepo.DB()).
+		Order("name").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Query != "" {
+		q = q.Where("LOWER(name) LIKE LOWER(?) OR LOWER(field) LIKE LOWER(?)", "%"+payload.Query+"%", "%"+payload.Query+"%")
+	}
+	if payload.Major != "" {
+		// majors is stored as json array, empty array accepts every major
+		q = q.Where("majors IS NULL OR majors IN ('null', '[]') OR majors LIKE ?", `%"`+payload.Major+`"%`)
+	}
+	if payload.Active != nil {
+		q = q.Where("active = ?", *payload.Active)
+	}
+
+	partners, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return partners, nil
+}
+
+func (s *ContextedInternship) UpdatePartner(payload payloads.RequestUpdateIndustryPartner) (*models.IndustryPartner, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	partner, err := s.partnerRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "industry partner not found", nil)
+	}
+
+	if exists, err := s.partnerRepo.Exists(s.ctx, "name = ? AND id <> ?", payload.Name, partner.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"name": "another industry partner with this name already registered"}}
+	}
+
+	partner.Name = payload.Name
+	partner.Field = payload.Field
+	partner.Address = payload.Address
+	partner.ContactName = payload.ContactName
+	partner.ContactPhone = payload.ContactPhone
+	partner.ContactEmail = payload.ContactEmail
+	partner.Majors = slicelib.Unique(payload.Majors)
+	partner.Active = payload.Active
+	err = s.partnerRepo.DB().WithContext(s.ctx).Model(&partner).
+		Select("name", "field", "address", "contact_name", "contact_phone", "contact_email", "majors", "active").
+		Updates(&partner).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return &partner, nil
+}
+
+func (s *ContextedInternship) DeletePartner(payload payloads.RequestDeleteIndustryPartner) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	if exists, err := s.placementRepo.Exists(s.ctx, "partner_id = ?", payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if exists {
+		return &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "industry partner already has placements, deactivate it instead"}
+	}
+
+	if ok, err := s.partnerRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "industry partner not found", nil)
+	}
+	return nil
+}
+
+// checkSupervisor makes sure supervisor of placement is a registered teacher
+func (s *ContextedInternship) checkSupervisor(tx *gorm.DB, supervisorID string) *reply.ErrorPayload {
+	if supervisorID == "" {
+		return nil
+	}
+	if exists, err := s.teacherRepo.WithTx(tx).Exists(s.ctx, "id = ?", supervisorID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !exists {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "teacher not found", reply.FieldsError{"supervisor_id": "teacher with this ID not found"})
+	}
+	return nil
+}
+
+// checkPlacementOverlap makes sure a student only has one internship at a time
+func (s *ContextedInternship) checkPlacementOverlap(tx *gorm.DB, studentID string, startDate, endDate time.Time, excludeID string) *reply.ErrorPayload {
+	q := tx.Where("student_id = ? AND start_date <= ? AND end_date >= ?", studentID, endDate, startDate)
+	if excludeID != "" {
+		q = q.Where("id <> ?", excludeID)
+	}
+	if overlaps, err := s.placementRepo.WithTx(tx).Exists(s.ctx, q); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if overlaps {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: "student already has internship in this period",
+			Fields:  reply.FieldsError{"start_date": "period overlaps other internship of the student"},
+		}
+	}
+	return nil
+}
+
+func (s *ContextedInternship) CreatePlacement(payload payloads.RequestCreatePlacement) (placement *models.InternshipPlacement, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.placementRepo.DB().Transaction(func(tx *gorm.DB) error {
+		// lock student so concurrent placements can not overlap
+		student, err := gorm.G[models.Student](tx, clause.Locking{Strength: "UPDATE"}).Preload("Class", nil).Where("id = ?", payload.StudentID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "student not found", reply.FieldsError{"student_id": "student with this ID not found"})
+			return err
+		}
+
+		partner, err := s.partnerRepo.WithTx(tx).GetByID(s.ctx, payload.PartnerID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "industry partner not found", reply.FieldsError{"partner_id": "industry partner with this ID not found"})
+			return err
+		}
+		if !partner.Active {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "industry partner is not active"}
+			return errors.New(errPayload.Message)
+		}
+		if student.Class != nil && !partner.AcceptsMajor(student.Class.Major) {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeUnprocessableEntity,
+				Message: "industry partner does not accept major of the student",
+				Fields:  reply.FieldsError{"partner_id": "partner only accepts " + strings.Join(partner.Majors, ", ")},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		if errPayload = s.checkSupervisor(tx, payload.SupervisorID); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		startDate, endDate := models.DateOf(payload.StartDate), models.DateOf(payload.EndDate)
+		if errPayload = s.checkPlacementOverlap(tx, student.ID, startDate, endDate, ""); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		placement = &models.InternshipPlacement{
+			StudentID:   student.ID,
+			PartnerID:   partner.ID,
+			MentorName:  payload.MentorName,
+			MentorPhone: payload.MentorPhone,
+			MentorEmail: payload.MentorEmail,
+			StartDate:   startDate,
+			EndDate:     endDate,
+		}
+		if payload.SupervisorID != "" {
+			placement.SupervisorID = &payload.SupervisorID
+		}
+		if err := s.placementRepo.WithTx(tx).Create(s.ctx, placement); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		placement.Partner = &partner
+		return nil
+	})
+	return
+}
+
+func (s *ContextedInternship) GetPlacement(payload payloads.RequestGetPlacement) (*models.InternshipPlacement, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	placement, err := gorm.G[models.InternshipPlacement](s.placementRepo.DB()).
+		Preload("Partner", nil).
+		Preload("Visits", func(db gorm.PreloadBuilder) error {
+			db.Order("visited_at")
+			return nil
+		}).
+		Where("id = ?", payload.ID).
+		First(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "internship placement not found", nil)
+	}
+	if errPayload := s.ensurePlacementAccess(&placement); errPayload != nil {
+		return nil, errPayload
+	}
+	return &placement, nil
+}
+
+func (s *ContextedInternship) GetPlacements(payload payloads.RequestGetPlacements) ([]models.InternshipPlacement, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// teacher only see placements they supervise, student only see own placements
+	switch s.c.GetString("role") {
+	case string(models.RoleTeacher):
+		teacher, errPayload := s.currentTeacher()
+		if errPayload != nil {
+			return nil, errPayload
+		}
+		payload.SupervisorID = teacher.ID
+	case string(models.RoleStudent):
+		student, errPayload := s.currentStudent()
+		if errPayload != nil {
+			return nil, errPayload
+		}
+		payload.StudentID = student.ID
+	}
+
+	q := gorm.G[models.InternshipPlacement](s.placementRepo.DB()).
+		Preload("Partner", nil).
+		Order("start_date DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.StudentID != "" {
+		q = q.Where("student_id = ?", payload.StudentID)
+	}
+	if payload.PartnerID != "" {
+		q = q.Where("partner_id = ?", payload.PartnerID)
+	}
+	if payload.SupervisorID != "" {
+		q = q.Where("supervisor_id = ?", payload.SupervisorID)
+	}
+	if payload.ClassID != "" {
+		q = q.Where("student_id IN (SELECT id FROM students WHERE class_id = ? AND deleted_at IS NULL)", payload.ClassID)
+	}
+	if payload.Ongoing {
+		today := models.DateOf(time.Now())
+		q = q.Where("start_date <= ? AND end_date >= ?", today, today)
+	}
+
+	placements, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return placements, nil
+}
+
+func (s *ContextedInternship) UpdatePlacement(payload payloads.RequestUpdatePlacement) (placement *models.InternshipPlacement, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.placementRepo.DB().Transaction(func(tx *gorm.DB) error {
+		found, err := s.placementRepo.WithTx(tx).GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "internship placement not found", nil)
+			return err
+		}
+		placement = &found
+
+		// lock student so concurrent placements can not overlap
+		if _, err := gorm.G[models.Student](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", placement.StudentID).First(s.ctx); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		if errPayload = s.checkSupervisor(tx, payload.SupervisorID); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		startDate, endDate := models.DateOf(payload.StartDate), models.DateOf(payload.EndDate)
+		if errPayload = s.checkPlacementOverlap(tx, placement.StudentID, startDate, endDate, placement.ID); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		placement.SupervisorID = nil
+		if payload.SupervisorID != "" {
+			placement.SupervisorID = &payload.SupervisorID
+		}
+		placement.MentorName = payload.MentorName
+		placement.MentorPhone = payload.MentorPhone
+		placement.MentorEmail = payload.MentorEmail
+		placement.StartDate = startDate
+		placement.EndDate = endDate
+		err = tx.WithContext(s.ctx).Model(placement).
+			Select("supervisor_id", "mentor_name", "mentor_phone", "mentor_email", "start_date", "end_date").
+			Updates(placement).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedInternship) DeletePlacement(payload payloads.RequestDeletePlacement) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	placement, err := s.placementRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return errorlib.MakeNotFound(err, "internship placement not found", nil)
+	}
+	// evaluated internship is kept for accreditation
+	if placement.EvaluatedAt != nil {
+		return &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "internship is already evaluated"}
+	}
+
+	// journals and visits of the placement are deleted
+	if _, err := s.placementRepo.DeleteByID(s.ctx, placement.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"errors"
+	"school-information-system/config"
+	"school-information-system/internal/libs/authlib"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WriteJournal writes daily journal of own internship, journal of the same date is replaced until it is reviewed
+func (s *ContextedInternship) WriteJournal(payload payloads.RequestWriteJournal) (*models.InternshipJournal, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	placement, err := s.placementRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "internship placement not found", nil)
+	}
+	if errPayload := s.ensurePlacementAccess(&placement); errPayload != nil {
+		return nil, errPayload
+	}
+
+	date := models.DateOf(payload.Date)
+	if date.Before(placement.StartDate) || date.After(placement.EndDate) {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "date is outside of internship period",
+			Fields:  reply.FieldsError{"date": "date must be between start and end date of the internship"},
+		}
+	}
+	if date.After(models.DateOf(time.Now())) {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "journal can not be written for future date", Fields: reply.FieldsError{"date": "date must not be after today"}}
+	}
+
+	journal, err := s.journalRepo.GetFirst(s.ctx, "placement_id = ? AND date = ?", placement.ID, date)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	// journal of the date already written
+	if err == nil {
+		if journal.ReviewedAt != nil {
+			return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "journal of this date is already reviewed"}
+		}
+		journal.Activity = payload.Activity
+		if err := s.journalRepo.DB().WithContext(s.ctx).Model(&journal).Select("activity").Updates(&journal).Error; err != nil {
+			return nil, errorlib.MakeServerError(err)
+		}
+		return &journal, nil
+	}
+
+	journal = models.InternshipJournal{PlacementID: placement.ID, Date: date, Activity: payload.Activity}
+	if err := s.journalRepo.Create(s.ctx, &journal); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return &journal, nil
+}
+
+func (s *ContextedInternship) GetJournals(payload payloads.RequestGetJournals) ([]models.InternshipJournal, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	placement, err := s.placementRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "internship placement not found", nil)
+	}
+	if errPayload := s.ensurePlacementAccess(&placement); errPayload != nil {
+		return nil, errPayload
+	}
+
+	journals, err := gorm.G[models.InternshipJournal](s.journalRepo.DB()).
+		Where("placement_id = ?", placement.ID).
+		Order("date DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset).
+		Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return journals, nil
+}
+
+// ReviewJournal gives feedback of school supervisor to a journal
+func (s *ContextedInternship) ReviewJournal(payload payloads.RequestReviewJournal) (*models.InternshipJournal, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	journal, err := s.journalRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "journal not found", nil)
+	}
+	placement, err := s.placementRepo.GetByID(s.ctx, journal.PlacementID)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if errPayload := s.ensurePlacementAccess(&placement); errPayload != nil {
+		return nil, errPayload
+	}
+
+	now := time.Now()
+	journal.Feedback = payload.Feedback
+	journal.ReviewedAt = &now
+	if err := s.journalRepo.DB().WithContext(s.ctx).Model(&journal).Select("feedback", "reviewed_at").Updates(&journal).Error; err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return &journal, nil
+}
+
+// CreateVisit records monitoring visit of supervisor to the industry partner
+func (s *ContextedInternship) CreateVisit(payload payloads.RequestCreateMonitoringVisit) (*models.MonitoringVisit, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	placement, err := s.placementRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "internship placement not found", nil)
+	}
+	if errPayload := s.ensurePlacementAccess(&placement); errPayload != nil {
+		return nil, errPayload
+	}
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	if payload.VisitedAt.After(time.Now()) {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "visit can not be recorded before it happens", Fields: reply.FieldsError{"visited_at": "visited_at must not be in the future"}}
+	}
+
+	visit := &models.MonitoringVisit{
+		PlacementID: placement.ID,
+		VisitedAt:   payload.VisitedAt,
+		Findings:    payload.Findings,
+		FollowUp:    payload.FollowUp,
+		TeacherID:   &teacher.ID,
+	}
+	if err := s.visitRepo.Create(s.ctx, visit); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return visit, nil
+}
+
+func (s *ContextedInternship) DeleteVisit(payload payloads.RequestDeleteMonitoringVisit) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	visit, err := s.visitRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return errorlib.MakeNotFound(err, "monitoring visit not found", nil)
+	}
+
+	// teacher only delete own visit
+	if s.c.GetString("role") == string(models.RoleTeacher) {
+		teacher, errPayload := s.currentTeacher()
+		if errPayload != nil {
+			return errPayload
+		}
+		if visit.TeacherID == nil || *visit.TeacherID != teacher.ID {
+			return &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "monitoring visit is recorded by other teacher"}
+		}
+	}
+
+	if _, err := s.visitRepo.DeleteByID(s.ctx, visit.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	return nil
+}
+
+// CreateEvaluationLink creates token of evaluation link for industry mentor, previous token is replaced
+func (s *ContextedInternship) CreateEvaluationLink(payload payloads.RequestCreateEvaluationLink) (*payloads.ResponseEvaluationLink, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	placement, err := s.placementRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "internship placement not found", nil)
+	}
+	if errPayload := s.ensurePlacementAccess(&placement); errPayload != nil {
+		return nil, errPayload
+	}
+	if placement.EvaluatedAt != nil {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "internship is already evaluated"}
+	}
+
+	token, err := authlib.RandomHex(32)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	hashed := authlib.HashToken(token)
+	expiresAt := time.Now().Add(config.INTERNSHIP_EVALUATION_LINK_EXPIRY)
+
+	placement.EvaluationTokenHash = &hashed
+	placement.EvaluationTokenExpiresAt = &expiresAt
+	err = s.placementRepo.DB().WithContext(s.ctx).Model(&placement).
+		Select("evaluation_token_hash", "evaluation_token_expires_at").
+		Updates(&placement).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	return &payloads.ResponseEvaluationLink{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// evaluationLinkQuery returns query of placement with valid evaluation token
+func evaluationLinkQuery(db *gorm.DB, token string) *gorm.DB {
+	return db.Where("evaluation_token_hash = ? AND evaluation_token_expires_at > ? AND evaluated_at IS NULL", authlib.HashToken(token), time.Now())
+}
+
+// GetEvaluationForm returns internship data for industry mentor, no account is needed
+func (s *ContextedInternship) GetEvaluationForm(payload payloads.RequestGetEvaluationForm) (*payloads.ResponseEvaluationForm, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	var placement models.InternshipPlacement
+	err := evaluationLinkQuery(s.placementRepo.DB().WithContext(s.ctx), payload.Token).Preload("Partner").First(&placement).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &reply.ErrorPayload{Code: replylib.CodeNotFound, Message: "evaluation link is invalid or expired"}
+		}
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	student, errPayload := findStudentIdentity(s.ctx, s.studentRepo.DB(), placement.StudentID)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	return &payloads.ResponseEvaluationForm{
+		StudentName: student.FullName,
+		ClassName:   student.ClassName,
+		Partner:     placement.Partner.Name,
+		MentorName:  placement.MentorName,
+		StartDate:   placement.StartDate,
+		EndDate:     placement.EndDate,
+	}, nil
+}
+
+// SubmitEvaluation saves final evaluation of industry mentor, evaluation link can only be used once
+func (s *ContextedInternship) SubmitEvaluation(payload payloads.RequestSubmitEvaluation) (evaluation *models.InternshipEvaluation, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.placementRepo.DB().Transaction(func(tx *gorm.DB) error {
+		// lock placement so the link can not be used twice
+		var placement models.InternshipPlacement
+		err := evaluationLinkQuery(tx.WithContext(s.ctx), payload.Token).Clauses(clause.Locking{Strength: "UPDATE"}).First(&placement).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				errPayload = &reply.ErrorPayload{Code: replylib.CodeNotFound, Message: "evaluation link is invalid or expired"}
+			} else {
+				errPayload = errorlib.MakeServerError(err)
+			}
+			return err
+		}
+
+		if time.Now().Before(placement.StartDate) {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "internship has not started yet"}
+			return errors.New(errPayload.Message)
+		}
+
+		total := 0.0
+		for _, aspect := range payload.Aspects {
+			total += aspect.Score
+		}
+		now := time.Now()
+		evaluation = &models.InternshipEvaluation{
+			Aspects:    payload.Aspects,
+			FinalScore: total / float64(len(payload.Aspects)),
+			Notes:      payload.Notes,
+		}
+		placement.Evaluation = evaluation
+		placement.EvaluatedAt = &now
+		placement.EvaluationTokenHash = nil
+		placement.EvaluationTokenExpiresAt = nil
+		err = tx.WithContext(s.ctx).Model(&placement).
+			Select("evaluation", "evaluated_at", "evaluation_token_hash", "evaluation_token_expires_at").
+			Updates(&placement).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
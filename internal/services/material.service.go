@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Material struct {
+	materialRepo   *repos.Material
+	storedFileRepo *repos.StoredFile
+	teacherRepo    *repos.Teacher
+	studentRepo    *repos.Student
+	classRepo      *repos.Class
+}
+
+type ContextedMaterial struct {
+	*Material
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewMaterial(materialRepo *repos.Material, storedFileRepo *repos.StoredFile, teacherRepo *repos.Teacher, studentRepo *repos.Student, classRepo *repos.Class) *Material {
+	return &Material{materialRepo, storedFileRepo, teacherRepo, studentRepo, classRepo}
+}
+
+func (s *Material) ApplyContext(c *gin.Context) *ContextedMaterial {
+	return &ContextedMaterial{s, c, c.Request.Context()}
+}
+
+// ensureCanAccess makes sure student only access material of own class
+func (s *ContextedMaterial) ensureCanAccess(material *models.LearningMaterial) *reply.ErrorPayload {
+	if s.c.GetString("role") != string(models.RoleStudent) {
+		return nil
+	}
+	student, err := s.studentRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return errorlib.MakeNotFound(err, "your student profile not found", nil)
+	}
+	if student.ClassID != material.ClassID {
+		return &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "material is not shared to your class"}
+	}
+	return nil
+}
+
+func (s *ContextedMaterial) CreateMaterial(payload payloads.RequestCreateMaterial) (material *models.LearningMaterial, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	teacher, err := s.teacherRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your teacher profile not found", nil)
+	}
+
+	// check class
+	if exists, err := s.classRepo.Exists(s.ctx, "id = ?", payload.ClassID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "class not found", reply.FieldsError{"class_id": "class with this ID not found"})
+	}
+
+	// teacher must teach the subject
+	teaches, err := s.teacherRepo.TeachesSubject(s.ctx, teacher.ID, payload.SubjectID)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if !teaches {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeForbidden,
+			Message: "you do not teach this subject",
+			Fields:  reply.FieldsError{"subject_id": "subject is not registered as your subject"},
+		}
+	}
+
+	material = &models.LearningMaterial{
+		Title:       payload.Title,
+		Description: payload.Description,
+		Type:        payload.Type,
+		ClassID:     payload.ClassID,
+		SubjectID:   payload.SubjectID,
+		TeacherID:   &teacher.ID,
+	}
+
+	if payload.Type == models.MaterialLink {
+		material.URL = payload.URL
+		if err := s.materialRepo.Create(s.ctx, material); err != nil {
+			return nil, errorlib.MakeServerError(err)
+		}
+		return material, nil
+	}
+
+	stored, errPayload := storeUpload(s.ctx, s.storedFileRepo, "file", payload.File)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	material.FileID = &stored.ID
+	material.File = stored
+	material.FileName = payload.File.Filename
+	if err := s.materialRepo.Create(s.ctx, material); err != nil {
+		releaseFile(s.ctx, s.materialRepo.DB(), stored.ID)
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	return material, nil
+}
+
+func (s *ContextedMaterial) GetMaterial(payload payloads.RequestGetMaterial) (*models.LearningMaterial, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	material, err := s.materialRepo.GetFirstWithPreload(s.ctx, []string{"File", "Subject"}, "id = ?", payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "material not found", nil)
+	}
+	if errPayload := s.ensureCanAccess(&material); errPayload != nil {
+		return nil, errPayload
+	}
+
+	return &material, nil
+}
+
+func (s *ContextedMaterial) GetMaterials(payload payloads.RequestGetMaterials) ([]models.LearningMaterial, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// student only see own class
+	if s.c.GetString("role") == string(models.RoleStudent) {
+		student, err := s.studentRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+		if err != nil {
+			return nil, errorlib.MakeNotFound(err, "your student profile not found", nil)
+		}
+		payload.ClassID = student.ClassID
+	}
+
+	q := gorm.G[models.LearningMaterial](s.materialRepo.DB()).
+		Preload("File", nil).
+		Preload("Subject", nil).
+		Order("created_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.ClassID != "" {
+		q = q.Where("class_id = ?", payload.ClassID)
+	}
+	if payload.SubjectID != "" {
+		q = q.Where("subject_id = ?", payload.SubjectID)
+	}
+	if payload.TeacherID != "" {
+		q = q.Where("teacher_id = ?", payload.TeacherID)
+	}
+	if payload.Query != "" {
+		q = q.Where("LOWER(title) LIKE LOWER(?)", "%"+payload.Query+"%")
+	}
+
+	materials, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return materials, nil
+}
+
+// DownloadMaterial returns content of file material, caller must close the content
+func (s *ContextedMaterial) DownloadMaterial(payload payloads.RequestGetMaterial) (*models.LearningMaterial, io.ReadCloser, *reply.ErrorPayload) {
+	material, errPayload := s.GetMaterial(payload)
+	if errPayload != nil {
+		return nil, nil, errPayload
+	}
+	if material.Type != models.MaterialFile || material.File == nil {
+		return nil, nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "material is a link, open the url instead"}
+	}
+
+	content, errPayload := openStoredFile(s.ctx, material.File)
+	if errPayload != nil {
+		return nil, nil, errPayload
+	}
+	return material, content, nil
+}
+
+func (s *ContextedMaterial) DeleteMaterial(payload payloads.RequestDeleteMaterial) (errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	s.materialRepo.DB().Transaction(func(tx *gorm.DB) error {
+		materialRepo := s.materialRepo.WithTx(tx)
+
+		material, err := materialRepo.GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "material not found", nil)
+			return err
+		}
+
+		// teacher only delete own material
+		if s.c.GetString("role") == string(models.RoleTeacher) {
+			teacher, err := s.teacherRepo.WithTx(tx).GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+			if err != nil {
+				errPayload = errorlib.MakeNotFound(err, "your teacher profile not found", nil)
+				return err
+			}
+			if material.TeacherID == nil || *material.TeacherID != teacher.ID {
+				err := errors.New("can not delete material of other teacher")
+				errPayload = &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: err.Error()}
+				return err
+			}
+		}
+
+		if _, err := materialRepo.DeleteByID(s.ctx, material.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		if material.FileID != nil {
+			if err := releaseFile(s.ctx, tx, *material.FileID); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+		}
+		return nil
+	})
+	return
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Notification struct {
+	notificationRepo *repos.Notification
+}
+
+type ContextedNotification struct {
+	*Notification
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewNotification(notificationRepo *repos.Notification) *Notification {
+	return &Notification{notificationRepo}
+}
+
+func (s *Notification) ApplyContext(c *gin.Context) *ContextedNotification {
+	return &ContextedNotification{s, c, c.Request.Context()}
+}
+
+// GetNotifications returns notifications of current user from the latest
+func (s *ContextedNotification) GetNotifications(payload payloads.RequestGetNotifications) ([]models.Notification, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.Notification](s.notificationRepo.DB()).
+		Where("user_id = ?", s.c.GetString("userID")).
+		Order("created_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Unread {
+		q = q.Where("read_at IS NULL")
+	}
+
+	notifications, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return notifications, nil
+}
+
+// ReadNotification marks notification of current user as read
+func (s *ContextedNotification) ReadNotification(payload payloads.RequestReadNotification) (*models.Notification, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	notification, err := s.notificationRepo.GetFirst(s.ctx, "id = ? AND user_id = ?", payload.ID, s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "notification not found", nil)
+	}
+	if notification.ReadAt != nil {
+		return &notification, nil
+	}
+
+	now := time.Now()
+	if err := s.notificationRepo.Update(s.ctx, models.Notification{ReadAt: &now}, "id = ?", notification.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	notification.ReadAt = &now
+	return &notification, nil
+}
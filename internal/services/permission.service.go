@@ -13,7 +13,9 @@ import (
 	"school-information-system/internal/models"
 	"school-information-system/internal/models/payloads"
 	"school-information-system/internal/repos"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/chesta132/goreply/reply"
 	"github.com/gin-gonic/gin"
@@ -21,8 +23,13 @@ import (
 )
 
 type Permission struct {
-	userRepo       *repos.User
-	permissionRepo *repos.Permission
+	userRepo         *repos.User
+	permissionRepo   *repos.Permission
+	adminRepo        *repos.Admin
+	bundleRepo       *repos.PermissionBundle
+	auditRepo        *repos.PermissionAudit
+	changeRepo       *repos.ChangeRequest
+	notificationRepo *repos.Notification
 }
 
 type ContextedPermission struct {
@@ -31,8 +38,8 @@ type ContextedPermission struct {
 	ctx context.Context
 }
 
-func NewPermission(userRepo *repos.User, permissionRepo *repos.Permission) *Permission {
-	return &Permission{userRepo, permissionRepo}
+func NewPermission(userRepo *repos.User, permissionRepo *repos.Permission, adminRepo *repos.Admin, bundleRepo *repos.PermissionBundle, auditRepo *repos.PermissionAudit, changeRepo *repos.ChangeRequest, notificationRepo *repos.Notification) *Permission {
+	return &Permission{userRepo, permissionRepo, adminRepo, bundleRepo, auditRepo, changeRepo, notificationRepo}
 }
 
 func (s *Permission) ApplyContext(c *gin.Context) *ContextedPermission {
@@ -42,12 +49,12 @@ func (s *Permission) ApplyContext(c *gin.Context) *ContextedPermission {
 func (s *ContextedPermission) validateAdminAndPermission(ctx context.Context, tx *gorm.DB, targetID, permissionID string) (user *models.User, permission *models.Permission, errPayload *reply.ErrorPayload, err error) {
 	userRepo := s.userRepo.WithTx(tx)
 
-	u, err := userRepo.GetFirstWithPreload(ctx, []string{"AdminProfile", "AdminProfile.Permissions"}, "id = ?", targetID)
+	u, err := userRepo.GetFirstWithPreload(ctx, []string{"AdminProfile", "AdminProfile.Permissions", "AdminProfile.Grants"}, "id = ?", targetID)
 	if err != nil {
 		errPayload = errorlib.MakeUserByTargetIDNotFound(err)
 		return
 	}
-	if u.AdminProfile == nil || u.Role != models.RoleAdmin {
+	if u.AdminProfile == nil || !u.HasRole(models.RoleAdmin) {
 		errPayload = &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: errorlib.ErrTargetInvalidRole.Error()}
 		err = errorlib.ErrTargetInvalidRole
 		return
@@ -83,6 +90,36 @@ func (s *ContextedPermission) CreatePermission(payload payloads.RequestCreatePer
 
 		// prevent double
 		payload.Actions = slicelib.Unique(payload.Actions)
+		scope := models.PermissionScope{
+			Grades:   slicelib.Unique(append([]int{}, payload.Scope.Grades...)),
+			Majors:   slicelib.Unique(append([]string{}, payload.Scope.Majors...)),
+			ClassIDs: slicelib.Unique(append([]string{}, payload.Scope.ClassIDs...)),
+		}
+
+		// validate scope, only class-bound resources can be limited
+		if !scope.IsEmpty() && !slices.Contains(models.ScopableResources, payload.Resource) {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeUnprocessableEntity,
+				Message: "invalid payload",
+				Fields:  reply.FieldsError{"scope": fmt.Sprintf("%s resource can not be limited by scope", payload.Resource)},
+			}
+			return errors.New(errPayload.Message)
+		}
+		if len(scope.ClassIDs) > 0 {
+			count, err := gorm.G[models.Class](tx).Where("id IN ?", scope.ClassIDs).Count(s.ctx, "id")
+			if err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+			if notFound := len(scope.ClassIDs) - int(count); notFound > 0 {
+				errPayload = &reply.ErrorPayload{
+					Code:    replylib.CodeNotFound,
+					Message: "class(es) not found",
+					Fields:  reply.FieldsError{"scope.class_ids": fmt.Sprintf("%d class(es) with these id not found", notFound)},
+				}
+				return gorm.ErrRecordNotFound
+			}
+		}
 
 		// create permission
 		permission = &models.Permission{
@@ -90,6 +127,7 @@ func (s *ContextedPermission) CreatePermission(payload payloads.RequestCreatePer
 			Resource:    payload.Resource,
 			Description: payload.Description,
 			Actions:     payload.Actions,
+			Scope:       scope,
 		}
 		err := permissionRepo.Create(s.ctx, permission)
 		if err != nil {
@@ -234,6 +272,15 @@ func (s *ContextedPermission) DeletePermission(payload payloads.RequestDeletePer
 			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "permission still granted by other admin(s)"}
 			return errors.New("can't delete granted permission")
 		}
+		err = tx.Raw("SELECT EXISTS (SELECT 1 FROM bundle_permissions WHERE permission_id = ? LIMIT 1)", payload.ID).Scan(&m2mExist).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if m2mExist {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "permission still contained by permission bundle(s)"}
+			return errors.New("can't delete bundled permission")
+		}
 
 		// delete permission
 		exists, err := permissionRepo.DeleteByID(s.ctx, payload.ID)
@@ -250,11 +297,70 @@ func (s *ContextedPermission) DeletePermission(payload payloads.RequestDeletePer
 	return
 }
 
-func (s *ContextedPermission) GrantPermission(payload payloads.RequestGrantPermission) (user *models.User, permission *models.Permission, errPayload *reply.ErrorPayload) {
-	// validate payload
+// requestPermissionChange validates grant or revoke of seed permission, then records it for approval of another admin
+func (s *ContextedPermission) requestPermissionChange(kind models.ChangeRequestKind, targetID, permissionID string, payload any) (*models.ChangeRequest, *reply.ErrorPayload) {
+	_, permission, errPayload, _ := s.validateAdminAndPermission(s.ctx, s.userRepo.DB(), targetID, permissionID)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if kind == models.ChangeGrantPermission && permission != nil {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: errorlib.ErrTargetHavePermission.Error()}
+	}
+	if kind == models.ChangeRevokePermission && permission == nil {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: errorlib.ErrTargetDoesntHavePerm.Error()}
+	}
+
+	return requestChange(
+		s.ctx, s.changeRepo, s.adminRepo, s.notificationRepo, s.c.GetString("userID"),
+		kind, targetID, models.ResourcePermission, []models.PermissionAction{models.ActionUpdate}, payload,
+	)
+}
+
+// validateGrant validates payload and period of the grant
+func validateGrant(payload payloads.RequestGrantPermission) *reply.ErrorPayload {
 	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	now := time.Now()
+	if payload.ValidUntil != nil && !payload.ValidUntil.After(now) {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "invalid payload",
+			Fields:  reply.FieldsError{"valid_until": "valid_until must be in the future"},
+		}
+	}
+	if payload.ValidFrom != nil && payload.ValidUntil != nil && !payload.ValidUntil.After(*payload.ValidFrom) {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "invalid payload",
+			Fields:  reply.FieldsError{"valid_until": "valid_until must be after valid_from"},
+		}
+	}
+	return nil
+}
+
+// GrantPermission grants permission to admin, grant of seed permission is requested for approval of another admin instead
+func (s *ContextedPermission) GrantPermission(payload payloads.RequestGrantPermission) (user *models.User, permission *models.Permission, request *models.ChangeRequest, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validateGrant(payload); errPayload != nil {
+		return nil, nil, nil, errPayload
+	}
+
+	if seeds.IsPermissionSeed(&models.Permission{Id: models.Id{ID: payload.PermissionID}}) {
+		request, errPayload = s.requestPermissionChange(models.ChangeGrantPermission, payload.TargetID, payload.PermissionID, payload)
+		return
+	}
+	user, permission, errPayload = s.grantPermission(payload)
+	return
+}
+
+func (s *ContextedPermission) grantPermission(payload payloads.RequestGrantPermission) (user *models.User, permission *models.Permission, errPayload *reply.ErrorPayload) {
+	// validate payload, period may pass while the grant waits for approval
+	if errPayload := validateGrant(payload); errPayload != nil {
 		return nil, nil, errPayload
 	}
+	now := time.Now()
 
 	// transaction to rollback if error
 	s.userRepo.DB().Transaction(func(tx *gorm.DB) error {
@@ -266,9 +372,25 @@ func (s *ContextedPermission) GrantPermission(payload payloads.RequestGrantPermi
 		if err != nil {
 			return err
 		}
-		if permission != nil {
-			errPayload = &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: errorlib.ErrTargetHavePermission.Error()}
-			return errorlib.ErrTargetHavePermission
+		regrant := permission != nil
+		if regrant {
+			// expired grant which is not cleaned up yet can be granted again
+			grant, err := gorm.G[models.AdminPermission](tx).
+				Where("admin_id = ? AND permission_id = ?", user.AdminProfile.ID, payload.PermissionID).
+				First(s.ctx)
+			if err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+			if grant.ValidUntil == nil || grant.ValidUntil.After(now) {
+				errPayload = &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: errorlib.ErrTargetHavePermission.Error()}
+				return errorlib.ErrTargetHavePermission
+			}
+			if _, err := gorm.G[models.AdminPermission](tx).Where("admin_id = ? AND permission_id = ?", grant.AdminID, grant.PermissionID).Delete(s.ctx); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+			user.AdminProfile.Grants = slices.DeleteFunc(user.AdminProfile.Grants, func(g *models.AdminPermission) bool { return g.PermissionID == grant.PermissionID })
 		}
 
 		// get permission to grant
@@ -284,16 +406,49 @@ func (s *ContextedPermission) GrantPermission(payload payloads.RequestGrantPermi
 		permission = &perm
 
 		// grant permission
-		err = tx.Model(user.AdminProfile).Association("Permissions").Append(&perm)
-		if err != nil {
+		grantedByID := s.c.GetString("userID")
+		grant := &models.AdminPermission{
+			AdminID:      user.AdminProfile.ID,
+			PermissionID: perm.ID,
+			GrantedByID:  &grantedByID,
+			ValidFrom:    payload.ValidFrom,
+			ValidUntil:   payload.ValidUntil,
+		}
+		if err = gorm.G[models.AdminPermission](tx).Create(s.ctx, grant); err != nil {
 			errPayload = errorlib.MakeServerError(err)
+			return err
 		}
-		return err
+		if !regrant {
+			user.AdminProfile.Permissions = append(user.AdminProfile.Permissions, &perm)
+		}
+		user.AdminProfile.Grants = append(user.AdminProfile.Grants, grant)
+		errPayload = recordAudits(s.ctx, s.auditRepo.WithTx(tx), s.c.GetString("userID"), []models.PermissionAudit{
+			{Action: models.AuditGrantPermission, TargetAdminID: &user.AdminProfile.ID, PermissionID: &perm.ID, PermissionName: perm.Name},
+		})
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		return nil
 	})
 	return
 }
 
-func (s *ContextedPermission) RevokePermission(payload payloads.RequestRevokePermission) (user *models.User, permission *models.Permission, errPayload *reply.ErrorPayload) {
+// RevokePermission revokes permission of admin, revoke of seed permission is requested for approval of another admin instead
+func (s *ContextedPermission) RevokePermission(payload payloads.RequestRevokePermission) (user *models.User, permission *models.Permission, request *models.ChangeRequest, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, nil, nil, errPayload
+	}
+
+	if seeds.IsPermissionSeed(&models.Permission{Id: models.Id{ID: payload.PermissionID}}) {
+		request, errPayload = s.requestPermissionChange(models.ChangeRevokePermission, payload.TargetID, payload.PermissionID, payload)
+		return
+	}
+	user, permission, errPayload = s.revokePermission(payload)
+	return
+}
+
+func (s *ContextedPermission) revokePermission(payload payloads.RequestRevokePermission) (user *models.User, permission *models.Permission, errPayload *reply.ErrorPayload) {
 	// validate payload
 	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
 		return nil, nil, errPayload
@@ -312,35 +467,25 @@ func (s *ContextedPermission) RevokePermission(payload payloads.RequestRevokePer
 			return errorlib.ErrTargetDoesntHavePerm
 		}
 
-		// make sure if permission is seed, it has another granted admin
-		if seeds.IsPermissionSeed(permission) {
-			selfVal := s.c.MustGet("user")
-			self, _ := selfVal.(models.User)
-
-			var exists bool
-			err = tx.Raw(
-				"SELECT EXISTS (SELECT 1 FROM admin_permissions WHERE admin_id != ? AND permission_id = ? LIMIT 1)",
-				self.AdminProfile.ID,
-				permission.ID,
-			).Scan(&exists).Error
-			if err != nil {
-				errPayload = errorlib.MakeServerError(err)
-				return err
-			}
-
-			if !exists {
-				err = fmt.Errorf("%w to revoke", errorlib.ErrPermHaventAnotherAdmin)
-				errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: err.Error()}
-				return err
-			}
-		}
-
 		// revoke permission
 		err = tx.Model(user.AdminProfile).Association("Permissions").Delete(permission)
 		if err != nil {
 			errPayload = errorlib.MakeServerError(err)
+			return err
 		}
-		return err
+		user.AdminProfile.Grants = slices.DeleteFunc(user.AdminProfile.Grants, func(g *models.AdminPermission) bool { return g.PermissionID == permission.ID })
+
+		// make sure if permission is seed, it is still held by another admin or by bundle
+		if errPayload = ensureSeedsHeld(s.ctx, s.adminRepo.WithTx(tx)); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		errPayload = recordAudits(s.ctx, s.auditRepo.WithTx(tx), s.c.GetString("userID"), []models.PermissionAudit{
+			{Action: models.AuditRevokePermission, TargetAdminID: &user.AdminProfile.ID, PermissionID: &permission.ID, PermissionName: permission.Name},
+		})
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		return nil
 	})
 	return
 }
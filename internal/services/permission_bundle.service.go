@@ -0,0 +1,481 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/database/seeds"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/slicelib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"slices"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// getBundlePermissions returns permissions of the IDs, error if any of them not found
+func (s *ContextedPermission) getBundlePermissions(tx *gorm.DB, ids []string) ([]*models.Permission, *reply.ErrorPayload) {
+	perms, err := s.permissionRepo.WithTx(tx).GetByIDs(s.ctx, ids)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if notFound := len(ids) - len(perms); notFound > 0 {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "permission(s) not found", reply.FieldsError{
+			"permission_ids": fmt.Sprintf("%d permission(s) not found", notFound),
+		})
+	}
+	result := make([]*models.Permission, len(perms))
+	for i := range perms {
+		result[i] = &perms[i]
+	}
+	return result, nil
+}
+
+// getTargetAdmin returns targeted user with admin profile and bundles of the profile
+func (s *ContextedPermission) getTargetAdmin(tx *gorm.DB, targetID string) (*models.User, *reply.ErrorPayload) {
+	user, err := s.userRepo.WithTx(tx).GetFirstWithPreload(s.ctx, []string{"AdminProfile.Bundles"}, "id = ?", targetID)
+	if err != nil {
+		return nil, errorlib.MakeUserByTargetIDNotFound(err)
+	}
+	if user.AdminProfile == nil || !user.HasRole(models.RoleAdmin) {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: errorlib.ErrTargetInvalidRole.Error()}
+	}
+	return &user, nil
+}
+
+// requestBundleChange records change of bundle holding seed permission for approval of another admin
+func (s *ContextedPermission) requestBundleChange(kind models.ChangeRequestKind, targetID string, action models.PermissionAction, payload any) (*models.ChangeRequest, *reply.ErrorPayload) {
+	return requestChange(
+		s.ctx, s.changeRepo, s.adminRepo, s.notificationRepo, s.c.GetString("userID"),
+		kind, targetID, models.ResourcePermission, []models.PermissionAction{action}, payload,
+	)
+}
+
+// getBundleWithPermissions returns bundle of the ID with its permissions
+func (s *ContextedPermission) getBundleWithPermissions(db *gorm.DB, id string, fields reply.FieldsError) (*models.PermissionBundle, *reply.ErrorPayload) {
+	bundle, err := s.bundleRepo.WithTx(db).GetFirstWithPreload(s.ctx, []string{"Permissions"}, "id = ?", id)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "permission bundle not found", fields)
+	}
+	return &bundle, nil
+}
+
+// seedsChanged reports whether replacing permissions of bundle by permissions of the IDs adds or removes seed permission
+func seedsChanged(current []*models.Permission, ids []string) bool {
+	for id := range seeds.PermissionSeedIDs {
+		held := slices.ContainsFunc(current, func(perm *models.Permission) bool { return perm.ID == id })
+		if held != slices.Contains(ids, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// heldBundle returns bundle of the ID held by admin of the user, nil if not held
+func heldBundle(user *models.User, bundleID string) *models.PermissionBundle {
+	for _, held := range user.AdminProfile.Bundles {
+		if held.ID == bundleID {
+			return held
+		}
+	}
+	return nil
+}
+
+func (s *ContextedPermission) CreateBundle(payload payloads.RequestCreatePermissionBundle) (bundle *models.PermissionBundle, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.bundleRepo.DB().Transaction(func(tx *gorm.DB) error {
+		bundleRepo := s.bundleRepo.WithTx(tx)
+
+		// validate unique
+		if exists, err := bundleRepo.Exists(s.ctx, "name = ?", payload.Name); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		} else if exists {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "permission bundle name already exist"}
+			return errors.New(errPayload.Message)
+		}
+
+		var perms []*models.Permission
+		perms, errPayload = s.getBundlePermissions(tx, slicelib.Unique(payload.PermissionIDs))
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		bundle = &models.PermissionBundle{
+			Name:        payload.Name,
+			Description: payload.Description,
+			Permissions: perms,
+		}
+		if err := bundleRepo.Create(s.ctx, bundle); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedPermission) GetBundle(payload payloads.RequestGetPermissionBundle) (*models.PermissionBundle, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	bundle, err := s.bundleRepo.GetFirstWithPreload(s.ctx, []string{"Permissions"}, "id = ?", payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "permission bundle not found", nil)
+	}
+	return &bundle, nil
+}
+
+func (s *ContextedPermission) GetBundles(payload payloads.RequestGetPermissionBundles) ([]models.PermissionBundle, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.PermissionBundle](s.bundleRepo.DB()).
+		Preload("Permissions", nil).
+		Order("name").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Query != "" {
+		q = q.Where("LOWER(name) LIKE LOWER(?)", "%"+payload.Query+"%")
+	}
+
+	bundles, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return bundles, nil
+}
+
+// UpdateBundle updates bundle, replaced permissions are granted to or revoked from every holder of the bundle.
+// Adding or removing seed permission is requested for approval of another admin instead
+func (s *ContextedPermission) UpdateBundle(payload payloads.RequestUpdatePermissionBundle) (bundle *models.PermissionBundle, request *models.ChangeRequest, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, nil, errPayload
+	}
+
+	if len(payload.PermissionIDs) > 0 {
+		current, errPayload := s.getBundleWithPermissions(s.bundleRepo.DB(), payload.ID, nil)
+		if errPayload != nil {
+			return nil, nil, errPayload
+		}
+		if seedsChanged(current.Permissions, payload.PermissionIDs) {
+			request, errPayload = s.requestBundleChange(models.ChangeUpdateBundle, payload.ID, models.ActionUpdate, payload)
+			return nil, request, errPayload
+		}
+	}
+	bundle, errPayload = s.updateBundle(payload)
+	return
+}
+
+func (s *ContextedPermission) updateBundle(payload payloads.RequestUpdatePermissionBundle) (bundle *models.PermissionBundle, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.bundleRepo.DB().Transaction(func(tx *gorm.DB) error {
+		bundleRepo := s.bundleRepo.WithTx(tx)
+
+		b, err := gorm.G[models.PermissionBundle](tx, clause.Locking{Strength: "UPDATE"}).Preload("Permissions", nil).Where("id = ?", payload.ID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "permission bundle not found", nil)
+			return err
+		}
+		bundle = &b
+
+		// validate unique
+		if payload.Name != "" && payload.Name != bundle.Name {
+			if exists, err := bundleRepo.Exists(s.ctx, "name = ?", payload.Name); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			} else if exists {
+				errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "permission bundle name already exist"}
+				return errors.New(errPayload.Message)
+			}
+		}
+
+		// update bundle
+		err = bundleRepo.Update(s.ctx, models.PermissionBundle{Name: payload.Name, Description: payload.Description}, "id = ?", bundle.ID)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if payload.Name != "" {
+			bundle.Name = payload.Name
+		}
+		if payload.Description != "" {
+			bundle.Description = payload.Description
+		}
+		if len(payload.PermissionIDs) == 0 {
+			return nil
+		}
+
+		// replace permissions and audit the difference
+		var perms []*models.Permission
+		perms, errPayload = s.getBundlePermissions(tx, slicelib.Unique(payload.PermissionIDs))
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		current := make(map[string]*models.Permission, len(bundle.Permissions))
+		for _, perm := range bundle.Permissions {
+			current[perm.ID] = perm
+		}
+		var audits []models.PermissionAudit
+		for _, perm := range perms {
+			if _, ok := current[perm.ID]; ok {
+				delete(current, perm.ID)
+				continue
+			}
+			audits = append(audits, models.PermissionAudit{Action: models.AuditBundleAdd, BundleID: &bundle.ID, BundleName: bundle.Name, PermissionID: &perm.ID, PermissionName: perm.Name})
+		}
+		for _, perm := range current {
+			audits = append(audits, models.PermissionAudit{Action: models.AuditBundleRemove, BundleID: &bundle.ID, BundleName: bundle.Name, PermissionID: &perm.ID, PermissionName: perm.Name})
+		}
+
+		if err := tx.Model(bundle).Association("Permissions").Replace(perms); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		bundle.Permissions = perms
+
+		if errPayload = ensureSeedsHeld(s.ctx, s.adminRepo.WithTx(tx)); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		if errPayload = recordAudits(s.ctx, s.auditRepo.WithTx(tx), s.c.GetString("userID"), audits); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		return nil
+	})
+	return
+}
+
+// DeleteBundle deletes bundle, permissions of the bundle are revoked from every holder of the bundle.
+// Deleting bundle holding seed permission is requested for approval of another admin instead
+func (s *ContextedPermission) DeleteBundle(payload payloads.RequestDeletePermissionBundle) (request *models.ChangeRequest, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload = validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return
+	}
+
+	bundle, errPayload := s.getBundleWithPermissions(s.bundleRepo.DB(), payload.ID, nil)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if seeds.IsPermissionSeed(bundle.Permissions...) {
+		return s.requestBundleChange(models.ChangeDeleteBundle, payload.ID, models.ActionDelete, payload)
+	}
+	return nil, s.deleteBundle(payload)
+}
+
+func (s *ContextedPermission) deleteBundle(payload payloads.RequestDeletePermissionBundle) (errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload = validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return
+	}
+
+	s.bundleRepo.DB().Transaction(func(tx *gorm.DB) error {
+		bundle, err := s.bundleRepo.WithTx(tx).GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "permission bundle not found", nil)
+			return err
+		}
+
+		// release holders and permissions of bundle
+		if err := tx.Exec("DELETE FROM admin_bundles WHERE permission_bundle_id = ?", bundle.ID).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if err := tx.Exec("DELETE FROM bundle_permissions WHERE permission_bundle_id = ?", bundle.ID).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if _, err := s.bundleRepo.WithTx(tx).DeleteByID(s.ctx, bundle.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		if errPayload = ensureSeedsHeld(s.ctx, s.adminRepo.WithTx(tx)); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		errPayload = recordAudits(s.ctx, s.auditRepo.WithTx(tx), s.c.GetString("userID"), []models.PermissionAudit{
+			{Action: models.AuditBundleDelete, BundleID: &bundle.ID, BundleName: bundle.Name},
+		})
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		return nil
+	})
+	return
+}
+
+// GrantBundle grants bundle to admin, bundle holding seed permission is requested for approval of another admin instead
+func (s *ContextedPermission) GrantBundle(payload payloads.RequestGrantPermissionBundle) (user *models.User, bundle *models.PermissionBundle, request *models.ChangeRequest, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, nil, nil, errPayload
+	}
+
+	db := s.bundleRepo.DB()
+	if user, errPayload = s.getTargetAdmin(db, payload.TargetID); errPayload != nil {
+		return
+	}
+	if heldBundle(user, payload.BundleID) != nil {
+		return nil, nil, nil, &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: "targeted user already has the permission bundle"}
+	}
+	if bundle, errPayload = s.getBundleWithPermissions(db, payload.BundleID, reply.FieldsError{"bundle_id": "permission bundle with this ID not found"}); errPayload != nil {
+		return nil, nil, nil, errPayload
+	}
+	if seeds.IsPermissionSeed(bundle.Permissions...) {
+		request, errPayload = s.requestBundleChange(models.ChangeGrantBundle, payload.TargetID, models.ActionUpdate, payload)
+		return nil, nil, request, errPayload
+	}
+	user, bundle, errPayload = s.grantBundle(payload)
+	return
+}
+
+func (s *ContextedPermission) grantBundle(payload payloads.RequestGrantPermissionBundle) (user *models.User, bundle *models.PermissionBundle, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, nil, errPayload
+	}
+
+	// transaction to rollback if error
+	s.bundleRepo.DB().Transaction(func(tx *gorm.DB) error {
+		user, errPayload = s.getTargetAdmin(tx, payload.TargetID)
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		if heldBundle(user, payload.BundleID) != nil {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: "targeted user already has the permission bundle"}
+			return errors.New(errPayload.Message)
+		}
+
+		bundle, errPayload = s.getBundleWithPermissions(tx, payload.BundleID, reply.FieldsError{"bundle_id": "permission bundle with this ID not found"})
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		// grant bundle
+		if err := tx.Model(user.AdminProfile).Association("Bundles").Append(bundle); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		errPayload = recordAudits(s.ctx, s.auditRepo.WithTx(tx), s.c.GetString("userID"), []models.PermissionAudit{
+			{Action: models.AuditGrantBundle, TargetAdminID: &user.AdminProfile.ID, BundleID: &bundle.ID, BundleName: bundle.Name},
+		})
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		return nil
+	})
+	return
+}
+
+// RevokeBundle revokes bundle of admin, bundle holding seed permission is requested for approval of another admin instead
+func (s *ContextedPermission) RevokeBundle(payload payloads.RequestRevokePermissionBundle) (user *models.User, bundle *models.PermissionBundle, request *models.ChangeRequest, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, nil, nil, errPayload
+	}
+
+	db := s.bundleRepo.DB()
+	if user, errPayload = s.getTargetAdmin(db, payload.TargetID); errPayload != nil {
+		return
+	}
+	if heldBundle(user, payload.BundleID) == nil {
+		return nil, nil, nil, &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: "targeted user doesn't have the permission bundle"}
+	}
+	if bundle, errPayload = s.getBundleWithPermissions(db, payload.BundleID, nil); errPayload != nil {
+		return nil, nil, nil, errPayload
+	}
+	if seeds.IsPermissionSeed(bundle.Permissions...) {
+		request, errPayload = s.requestBundleChange(models.ChangeRevokeBundle, payload.TargetID, models.ActionUpdate, payload)
+		return nil, nil, request, errPayload
+	}
+	user, bundle, errPayload = s.revokeBundle(payload)
+	return
+}
+
+func (s *ContextedPermission) revokeBundle(payload payloads.RequestRevokePermissionBundle) (user *models.User, bundle *models.PermissionBundle, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, nil, errPayload
+	}
+
+	// transaction to rollback if error
+	s.bundleRepo.DB().Transaction(func(tx *gorm.DB) error {
+		user, errPayload = s.getTargetAdmin(tx, payload.TargetID)
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		bundle = heldBundle(user, payload.BundleID)
+		if bundle == nil {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeBadRequest, Message: "targeted user doesn't have the permission bundle"}
+			return errors.New(errPayload.Message)
+		}
+
+		// revoke bundle
+		if err := tx.Model(user.AdminProfile).Association("Bundles").Delete(bundle); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// make sure seed permissions of the bundle are still held by another admin
+		if errPayload = ensureSeedsHeld(s.ctx, s.adminRepo.WithTx(tx)); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		errPayload = recordAudits(s.ctx, s.auditRepo.WithTx(tx), s.c.GetString("userID"), []models.PermissionAudit{
+			{Action: models.AuditRevokeBundle, TargetAdminID: &user.AdminProfile.ID, BundleID: &bundle.ID, BundleName: bundle.Name},
+		})
+		if errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedPermission) GetAudits(payload payloads.RequestGetPermissionAudits) ([]models.PermissionAudit, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.PermissionAudit](s.auditRepo.DB()).
+		Order("created_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Action != "" {
+		q = q.Where("action = ?", payload.Action)
+	}
+	if payload.ActorID != "" {
+		q = q.Where("actor_id = ?", payload.ActorID)
+	}
+	if payload.TargetAdminID != "" {
+		q = q.Where("target_admin_id = ?", payload.TargetAdminID)
+	}
+	if payload.PermissionID != "" {
+		q = q.Where("permission_id = ?", payload.PermissionID)
+	}
+	if payload.BundleID != "" {
+		q = q.Where("bundle_id = ?", payload.BundleID)
+	}
+
+	audits, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return audits, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/models"
+	"strings"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// scopeOf returns class scope of the action set by permission middleware, nil if current admin may act on the whole resource
+func scopeOf(c *gin.Context, resource models.PermissionResource, action models.PermissionAction) models.ClassScope {
+	scopeInterface, _ := c.Get(models.ScopeContextKey(resource, action))
+	scope, _ := scopeInterface.(models.ClassScope)
+	return scope
+}
+
+// scopeCondition returns condition limiting class ID column to classes in the scope, scope must not be nil
+func scopeCondition(scope models.ClassScope, classIDColumn string) (string, []any) {
+	conds := make([]string, 0, len(scope))
+	args := make([]any, 0, len(scope)*3)
+	for _, sc := range scope {
+		var parts []string
+		if len(sc.Grades) > 0 {
+			parts = append(parts, "grade IN ?")
+			args = append(args, sc.Grades)
+		}
+		if len(sc.Majors) > 0 {
+			parts = append(parts, "major IN ?")
+			args = append(args, sc.Majors)
+		}
+		cond := strings.Join(parts, " AND ")
+		if len(sc.ClassIDs) > 0 {
+			if cond != "" {
+				cond = "(" + cond + ") OR "
+			}
+			cond += "id IN ?"
+			args = append(args, sc.ClassIDs)
+		}
+		conds = append(conds, "("+cond+")")
+	}
+	return classIDColumn + " IN (SELECT id FROM classes WHERE " + strings.Join(conds, " OR ") + ")", args
+}
+
+// ensureClassInScope makes sure class of the ID is in the scope, returns the class if scope is not nil
+func ensureClassInScope(ctx context.Context, db *gorm.DB, scope models.ClassScope, classID string) (*models.Class, *reply.ErrorPayload) {
+	if scope == nil {
+		return nil, nil
+	}
+	class, err := gorm.G[models.Class](db).Where("id = ?", classID).First(ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "class not found", nil)
+	}
+	if !scope.Contains(&class) {
+		return nil, &replylib.ErrOutOfScope
+	}
+	return &class, nil
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Quiz struct {
+	quizRepo        *repos.Quiz
+	quizWindowRepo  *repos.QuizWindow
+	quizAttemptRepo *repos.QuizAttempt
+	quizAnswerRepo  *repos.QuizAnswer
+	scoreRepo       *repos.Score
+	teacherRepo     *repos.Teacher
+	studentRepo     *repos.Student
+	classRepo       *repos.Class
+	bankRepo        *repos.BankQuestion
+}
+
+type ContextedQuiz struct {
+	*Quiz
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewQuiz(quizRepo *repos.Quiz, quizWindowRepo *repos.QuizWindow, quizAttemptRepo *repos.QuizAttempt, quizAnswerRepo *repos.QuizAnswer, scoreRepo *repos.Score, teacherRepo *repos.Teacher, studentRepo *repos.Student, classRepo *repos.Class, bankRepo *repos.BankQuestion) *Quiz {
+	return &Quiz{quizRepo, quizWindowRepo, quizAttemptRepo, quizAnswerRepo, scoreRepo, teacherRepo, studentRepo, classRepo, bankRepo}
+}
+
+func (s *Quiz) ApplyContext(c *gin.Context) *ContextedQuiz {
+	return &ContextedQuiz{s, c, c.Request.Context()}
+}
+
+func (s *ContextedQuiz) currentStudent() (*models.Student, *reply.ErrorPayload) {
+	student, err := s.studentRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your student profile not found", nil)
+	}
+	return &student, nil
+}
+
+func (s *ContextedQuiz) currentTeacher() (*models.Teacher, *reply.ErrorPayload) {
+	teacher, err := s.teacherRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your teacher profile not found", nil)
+	}
+	return &teacher, nil
+}
+
+// ensureOwner makes sure teacher only manage own quiz, admin is always allowed
+func (s *ContextedQuiz) ensureOwner(quiz *models.Quiz) (teacher *models.Teacher, errPayload *reply.ErrorPayload) {
+	if s.c.GetString("role") != string(models.RoleTeacher) {
+		return nil, nil
+	}
+	teacher, errPayload = s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if quiz.TeacherID == nil || *quiz.TeacherID != teacher.ID {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "quiz is owned by other teacher"}
+	}
+	return teacher, nil
+}
+
+// hideAnswerKeys removes answer keys so questions safe to be shown to student
+func hideAnswerKeys(questions []models.QuizQuestion) {
+	for i := range questions {
+		questions[i].Answers = nil
+	}
+}
+
+func (s *ContextedQuiz) CreateQuiz(payload payloads.RequestCreateQuiz) (*models.Quiz, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	// teacher must teach the subject
+	teaches, err := s.teacherRepo.TeachesSubject(s.ctx, teacher.ID, payload.SubjectID)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if !teaches {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeForbidden,
+			Message: "you do not teach this subject",
+			Fields:  reply.FieldsError{"subject_id": "subject is not registered as your subject"},
+		}
+	}
+
+	// validate answer key of each question
+	questions := make([]models.QuizQuestion, len(payload.Questions))
+	for i, q := range payload.Questions {
+		questions[i] = models.QuizQuestion{
+			QuestionContent: models.QuestionContent{
+				Type:    q.Type,
+				Prompt:  q.Prompt,
+				Options: q.Options,
+				Answers: q.Answers,
+				Points:  q.Points,
+			},
+			Position: i + 1,
+		}
+		if err := questions[i].CheckAnswerKey(); err != nil {
+			return nil, &reply.ErrorPayload{
+				Code:    replylib.CodeBadRequest,
+				Message: "invalid payload",
+				Fields:  reply.FieldsError{fmt.Sprintf("questions[%d]", i): err.Error()},
+			}
+		}
+	}
+
+	quiz := &models.Quiz{
+		Title:            payload.Title,
+		Description:      payload.Description,
+		SubjectID:        payload.SubjectID,
+		TeacherID:        &teacher.ID,
+		TimeLimit:        payload.TimeLimit,
+		MaxAttempts:      payload.MaxAttempts,
+		ShuffleQuestions: payload.ShuffleQuestions,
+		ShuffleOptions:   payload.ShuffleOptions,
+		Questions:        questions,
+	}
+	if err := s.quizRepo.Create(s.ctx, quiz); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	return quiz, nil
+}
+
+// AddBankQuestions copies current version of bank questions to the end of quiz
+func (s *ContextedQuiz) AddBankQuestions(payload payloads.RequestAddBankQuestionsToQuiz) (quiz *models.Quiz, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.quizRepo.DB().Transaction(func(tx *gorm.DB) error {
+		q, err := s.quizRepo.WithTx(tx).GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "quiz not found", nil)
+			return err
+		}
+		teacher, errP := s.ensureOwner(&q)
+		if errP != nil {
+			errPayload = errP
+			return errors.New(errP.Message)
+		}
+
+		// questions of attempted quiz must not change, scores would be inconsistent
+		if attempted, err := s.quizAttemptRepo.WithTx(tx).Exists(s.ctx, "quiz_id = ?", q.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		} else if attempted {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "quiz already attempted by students"}
+			return errors.New(errPayload.Message)
+		}
+
+		bankQuestions, errP := questionsWithCurrentVersion(s.ctx, tx, s.teacherRepo.WithTx(tx), teacher, payload.QuestionIDs)
+		if errP != nil {
+			errPayload = errP
+			return errors.New(errP.Message)
+		}
+
+		var lastPosition int
+		if err := tx.Model(&models.QuizQuestion{}).Where("quiz_id = ?", q.ID).Select("COALESCE(MAX(position), 0)").Scan(&lastPosition).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		questions := make([]models.QuizQuestion, len(bankQuestions))
+		for i, bq := range bankQuestions {
+			if bq.SubjectID != q.SubjectID {
+				errPayload = &reply.ErrorPayload{
+					Code:    replylib.CodeBadRequest,
+					Message: "bank question is not of the quiz subject",
+					Fields:  reply.FieldsError{fmt.Sprintf("question_ids[%d]", i): "subject of bank question differs from the quiz"},
+				}
+				return errors.New(errPayload.Message)
+			}
+			version := bq.Versions[0]
+			questions[i] = models.QuizQuestion{
+				QuizID:          q.ID,
+				QuestionContent: version.QuestionContent,
+				Position:        lastPosition + i + 1,
+				BankVersionID:   &version.ID,
+			}
+		}
+		if err := tx.WithContext(s.ctx).Create(&questions).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		q.Questions = questions
+		quiz = &q
+		return nil
+	})
+	return
+}
+
+func (s *ContextedQuiz) GetQuiz(payload payloads.RequestGetQuiz) (*models.Quiz, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	quiz, err := gorm.G[models.Quiz](s.quizRepo.DB()).
+		Preload("Questions", func(db gorm.PreloadBuilder) error {
+			db.Order("position")
+			return nil
+		}).
+		Preload("Windows", nil).
+		Preload("Subject", nil).
+		Where("id = ?", payload.ID).
+		First(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "quiz not found", nil)
+	}
+
+	// student only see window of own class, questions are shown in attempt
+	if s.c.GetString("role") == string(models.RoleStudent) {
+		student, errPayload := s.currentStudent()
+		if errPayload != nil {
+			return nil, errPayload
+		}
+		windows := make([]models.QuizWindow, 0, 1)
+		for _, w := range quiz.Windows {
+			if w.ClassID == student.ClassID {
+				windows = append(windows, w)
+			}
+		}
+		if len(windows) == 0 {
+			return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "quiz is not given to your class"}
+		}
+		quiz.Windows = windows
+		quiz.Questions = nil
+	}
+
+	return &quiz, nil
+}
+
+func (s *ContextedQuiz) GetQuizzes(payload payloads.RequestGetQuizzes) ([]models.Quiz, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// student only see quizzes of own class
+	if s.c.GetString("role") == string(models.RoleStudent) {
+		student, errPayload := s.currentStudent()
+		if errPayload != nil {
+			return nil, errPayload
+		}
+		payload.ClassID = student.ClassID
+	}
+
+	q := gorm.G[models.Quiz](s.quizRepo.DB()).
+		Preload("Subject", nil).
+		Order("created_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.ClassID != "" {
+		q = q.Where("EXISTS (SELECT 1 FROM quiz_windows WHERE quiz_windows.quiz_id = quizzes.id AND quiz_windows.class_id = ?)", payload.ClassID).
+			Preload("Windows", func(db gorm.PreloadBuilder) error {
+				db.Where("class_id = ?", payload.ClassID)
+				return nil
+			})
+	}
+	if payload.SubjectID != "" {
+		q = q.Where("subject_id = ?", payload.SubjectID)
+	}
+	if payload.TeacherID != "" {
+		q = q.Where("teacher_id = ?", payload.TeacherID)
+	}
+
+	quizzes, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return quizzes, nil
+}
+
+func (s *ContextedQuiz) SetWindow(payload payloads.RequestSetQuizWindow) (*models.QuizWindow, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	quiz, err := s.quizRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "quiz not found", nil)
+	}
+	if _, errPayload := s.ensureOwner(&quiz); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// check class
+	if exists, err := s.classRepo.Exists(s.ctx, "id = ?", payload.ClassID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "class not found", reply.FieldsError{"class_id": "class with this ID not found"})
+	}
+
+	window := &models.QuizWindow{
+		QuizID:  quiz.ID,
+		ClassID: payload.ClassID,
+		StartAt: payload.StartAt,
+		EndAt:   payload.EndAt,
+	}
+	if err := s.quizWindowRepo.Set(s.ctx, window); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	return window, nil
+}
+
+func (s *ContextedQuiz) DeleteQuiz(payload payloads.RequestDeleteQuiz) (errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	s.quizRepo.DB().Transaction(func(tx *gorm.DB) error {
+		quizRepo := s.quizRepo.WithTx(tx)
+
+		quiz, err := quizRepo.GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "quiz not found", nil)
+			return err
+		}
+		if _, errPayload = s.ensureOwner(&quiz); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		if _, err := quizRepo.DeleteByID(s.ctx, quiz.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// scores of the quiz no longer have source
+		if _, err := s.scoreRepo.WithTx(tx).Delete(s.ctx, "source = ? AND source_id = ?", models.ScoreQuiz, quiz.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
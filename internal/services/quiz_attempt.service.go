@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"slices"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+)
+
+// quizPaper orders questions and options as given to the attempt
+func quizPaper(attempt *models.QuizAttempt, questions []models.QuizQuestion) *payloads.ResponseQuizPaper {
+	byID := make(map[string]models.QuizQuestion, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+
+	ordered := make([]models.QuizQuestion, 0, len(attempt.QuestionOrder))
+	for _, id := range attempt.QuestionOrder {
+		q, ok := byID[id]
+		if !ok {
+			continue
+		}
+		if keys, ok := attempt.OptionOrder[id]; ok {
+			options := make([]models.QuestionOption, 0, len(q.Options))
+			for _, key := range keys {
+				if i := slices.IndexFunc(q.Options, func(o models.QuestionOption) bool { return o.Key == key }); i >= 0 {
+					options = append(options, q.Options[i])
+				}
+			}
+			q.Options = options
+		}
+		ordered = append(ordered, q)
+	}
+
+	return &payloads.ResponseQuizPaper{Attempt: attempt, Questions: ordered}
+}
+
+// recordQuizScore records best graded attempt of student to gradebook
+func recordQuizScore(ctx context.Context, tx *gorm.DB, quiz *models.Quiz, studentID string, gradedByID *string) error {
+	attempts, err := repos.NewQuizAttempt(tx).GetAll(ctx, "quiz_id = ? AND student_id = ? AND score IS NOT NULL", quiz.ID, studentID)
+	if err != nil || len(attempts) == 0 {
+		return err
+	}
+
+	best := attempts[0].Percentage()
+	for _, a := range attempts[1:] {
+		best = max(best, a.Percentage())
+	}
+
+	if gradedByID == nil {
+		gradedByID = quiz.TeacherID
+	}
+	return repos.NewScore(tx).Record(ctx, &models.Score{
+		StudentID:  studentID,
+		SubjectID:  quiz.SubjectID,
+		Source:     models.ScoreQuiz,
+		SourceID:   quiz.ID,
+		Value:      best,
+		GradedByID: gradedByID,
+	})
+}
+
+func (s *ContextedQuiz) StartAttempt(payload payloads.RequestStartQuizAttempt) (paper *payloads.ResponseQuizPaper, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	student, errPayload := s.currentStudent()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	quiz, err := s.quizRepo.GetFirstWithPreload(s.ctx, []string{"Questions"}, "id = ?", payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "quiz not found", nil)
+	}
+	hideAnswerKeys(quiz.Questions)
+
+	// check window of student's class
+	window, err := s.quizWindowRepo.GetFirst(s.ctx, "quiz_id = ? AND class_id = ?", quiz.ID, student.ClassID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "quiz is not given to your class"}
+		}
+		return nil, errorlib.MakeServerError(err)
+	}
+	now := time.Now()
+	if !window.IsOpen(now) {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "quiz is not open at this time"}
+	}
+
+	s.quizAttemptRepo.DB().Transaction(func(tx *gorm.DB) error {
+		attemptRepo := s.quizAttemptRepo.WithTx(tx)
+
+		// continue running attempt
+		running, err := attemptRepo.GetFirst(s.ctx, "quiz_id = ? AND student_id = ? AND submitted_at IS NULL AND deadline > ?", quiz.ID, student.ID, now)
+		if err == nil {
+			paper = quizPaper(&running, quiz.Questions)
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// check attempt limit
+		if quiz.MaxAttempts > 0 {
+			count, err := attemptRepo.Count(s.ctx, "quiz_id = ? AND student_id = ?", quiz.ID, student.ID)
+			if err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+			if count >= int64(quiz.MaxAttempts) {
+				err := errors.New("attempt limit of quiz reached")
+				errPayload = &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: err.Error()}
+				return err
+			}
+		}
+
+		// randomize order for this student
+		slices.SortFunc(quiz.Questions, func(a, b models.QuizQuestion) int { return a.Position - b.Position })
+		questionOrder := make([]string, len(quiz.Questions))
+		optionOrder := make(map[string][]string)
+		var maxScore float64
+		for i, q := range quiz.Questions {
+			questionOrder[i] = q.ID
+			maxScore += q.Points
+			if len(q.Options) == 0 {
+				continue
+			}
+			keys := make([]string, len(q.Options))
+			for j, o := range q.Options {
+				keys[j] = o.Key
+			}
+			if quiz.ShuffleOptions {
+				rand.Shuffle(len(keys), func(a, b int) { keys[a], keys[b] = keys[b], keys[a] })
+			}
+			optionOrder[q.ID] = keys
+		}
+		if quiz.ShuffleQuestions {
+			rand.Shuffle(len(questionOrder), func(a, b int) { questionOrder[a], questionOrder[b] = questionOrder[b], questionOrder[a] })
+		}
+
+		// deadline is end of window or time limit, whichever comes first
+		deadline := window.EndAt
+		if quiz.TimeLimit > 0 {
+			if limit := now.Add(time.Duration(quiz.TimeLimit) * time.Minute); limit.Before(deadline) {
+				deadline = limit
+			}
+		}
+
+		attempt := &models.QuizAttempt{
+			QuizID:        quiz.ID,
+			StudentID:     student.ID,
+			QuestionOrder: questionOrder,
+			OptionOrder:   optionOrder,
+			StartedAt:     now,
+			Deadline:      deadline,
+			MaxScore:      maxScore,
+		}
+		if err := attemptRepo.Create(s.ctx, attempt); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		paper = quizPaper(attempt, quiz.Questions)
+		return nil
+	})
+	return
+}
+
+func (s *ContextedQuiz) SubmitAttempt(payload payloads.RequestSubmitQuizAttempt) (paper *payloads.ResponseQuizPaper, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	student, errPayload := s.currentStudent()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.quizAttemptRepo.DB().Transaction(func(tx *gorm.DB) error {
+		attempt, err := s.quizAttemptRepo.WithTx(tx).GetFirstWithPreload(s.ctx, []string{"Quiz.Questions"}, "id = ?", payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "attempt not found", nil)
+			return err
+		}
+		if attempt.StudentID != student.ID {
+			err := errors.New("attempt is owned by other student")
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: err.Error()}
+			return err
+		}
+		if attempt.IsSubmitted() {
+			err := errors.New("attempt already submitted")
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: err.Error()}
+			return err
+		}
+		now := time.Now()
+		if now.After(attempt.Deadline.Add(config.QUIZ_SUBMIT_GRACE)) {
+			err := errors.New("attempt time is over")
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: err.Error()}
+			return err
+		}
+
+		responses := make(map[string][]string, len(payload.Answers))
+		for _, ans := range payload.Answers {
+			if !slices.Contains(attempt.QuestionOrder, ans.QuestionID) {
+				err := errors.New("answered question is not part of the attempt")
+				errPayload = &reply.ErrorPayload{
+					Code:    replylib.CodeBadRequest,
+					Message: err.Error(),
+					Fields:  reply.FieldsError{"answers": ans.QuestionID + " is not part of the attempt"},
+				}
+				return err
+			}
+			responses[ans.QuestionID] = ans.Response
+		}
+
+		// auto grade objective questions, unanswered question still recorded
+		answers := make([]models.QuizAnswer, 0, len(attempt.Quiz.Questions))
+		var score float64
+		needManual := false
+		for _, q := range attempt.Quiz.Questions {
+			answer := models.QuizAnswer{AttemptID: attempt.ID, QuestionID: q.ID, Response: responses[q.ID]}
+			if points, ok := q.AutoGrade(answer.Response); ok {
+				answer.Points = &points
+				score += points
+			} else {
+				needManual = true
+			}
+			answers = append(answers, answer)
+		}
+		if len(answers) > 0 {
+			if err := s.quizAnswerRepo.WithTx(tx).CreateAll(s.ctx, &answers); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+		}
+
+		attempt.SubmittedAt = &now
+		if !needManual {
+			attempt.Score = &score
+		}
+		if err := tx.Model(&attempt).Select("submitted_at", "score").Updates(&attempt).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		if !needManual {
+			if err := recordQuizScore(s.ctx, tx, attempt.Quiz, student.ID, nil); err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+		}
+
+		questions := attempt.Quiz.Questions
+		hideAnswerKeys(questions)
+		attempt.Quiz = nil
+		attempt.Answers = answers
+		paper = quizPaper(&attempt, questions)
+		return nil
+	})
+	return
+}
+
+func (s *ContextedQuiz) GetAttempt(payload payloads.RequestGetQuizAttempt) (*payloads.ResponseQuizPaper, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	attempt, err := s.quizAttemptRepo.GetFirstWithPreload(s.ctx, []string{"Quiz.Questions", "Answers"}, "id = ?", payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "attempt not found", nil)
+	}
+
+	questions := attempt.Quiz.Questions
+	if s.c.GetString("role") == string(models.RoleStudent) {
+		student, errPayload := s.currentStudent()
+		if errPayload != nil {
+			return nil, errPayload
+		}
+		if attempt.StudentID != student.ID {
+			return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "attempt is owned by other student"}
+		}
+		hideAnswerKeys(questions)
+	} else if _, errPayload := s.ensureOwner(attempt.Quiz); errPayload != nil {
+		return nil, errPayload
+	}
+
+	attempt.Quiz = nil
+	return quizPaper(&attempt, questions), nil
+}
+
+func (s *ContextedQuiz) GetAttempts(payload payloads.RequestGetQuizAttempts) ([]models.QuizAttempt, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	quiz, err := s.quizRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "quiz not found", nil)
+	}
+	if _, errPayload := s.ensureOwner(&quiz); errPayload != nil {
+		return nil, errPayload
+	}
+
+	attempts, err := gorm.G[models.QuizAttempt](s.quizAttemptRepo.DB()).
+		Where("quiz_id = ?", quiz.ID).
+		Order("started_at").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset).
+		Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return attempts, nil
+}
+
+// GradeAnswer grades answer manually, attempt is scored once every answer graded
+func (s *ContextedQuiz) GradeAnswer(payload payloads.RequestGradeQuizAnswer) (answer *models.QuizAnswer, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.quizAnswerRepo.DB().Transaction(func(tx *gorm.DB) error {
+		ans, err := s.quizAnswerRepo.WithTx(tx).GetFirstWithPreload(s.ctx, []string{"Question"}, "id = ?", payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "answer not found", nil)
+			return err
+		}
+		attempt, err := s.quizAttemptRepo.WithTx(tx).GetFirstWithPreload(s.ctx, []string{"Quiz", "Answers"}, "id = ?", ans.AttemptID)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		teacher, errP := s.ensureOwner(attempt.Quiz)
+		if errP != nil {
+			errPayload = errP
+			return errors.New(errP.Message)
+		}
+		if payload.Points > ans.Question.Points {
+			err := errors.New("points exceed points of the question")
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeBadRequest,
+				Message: err.Error(),
+				Fields:  reply.FieldsError{"points": "points must be at most points of the question"},
+			}
+			return err
+		}
+
+		ans.Points = &payload.Points
+		ans.Feedback = payload.Feedback
+		if err := tx.Model(&ans).Select("points", "feedback").Updates(&ans).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// score attempt if every answer graded
+		var score float64
+		for _, a := range attempt.Answers {
+			if a.ID == ans.ID {
+				a.Points = ans.Points
+			}
+			if a.Points == nil {
+				answer = &ans
+				return nil
+			}
+			score += *a.Points
+		}
+		attempt.Score = &score
+		if err := tx.Model(&attempt).Select("score").Updates(&attempt).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		var gradedByID *string
+		if teacher != nil {
+			gradedByID = &teacher.ID
+		}
+		if err := recordQuizScore(s.ctx, tx, attempt.Quiz, attempt.StudentID, gradedByID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		answer = &ans
+		return nil
+	})
+	return
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Room struct {
+	roomRepo        *repos.Room
+	bookingRepo     *repos.RoomBooking
+	examSessionRepo *repos.ExamSession
+	slotRepo        *repos.TimetableSlot
+}
+
+type ContextedRoom struct {
+	*Room
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewRoom(roomRepo *repos.Room, bookingRepo *repos.RoomBooking, examSessionRepo *repos.ExamSession, slotRepo *repos.TimetableSlot) *Room {
+	return &Room{roomRepo, bookingRepo, examSessionRepo, slotRepo}
+}
+
+func (s *Room) ApplyContext(c *gin.Context) *ContextedRoom {
+	return &ContextedRoom{s, c, c.Request.Context()}
+}
+
+func (s *ContextedRoom) CreateRoom(payload payloads.RequestCreateRoom) (*models.Room, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.roomRepo.Exists(s.ctx, "code = ?", payload.Code); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"code": "another room with this code already registered"}}
+	}
+
+	room := &models.Room{
+		Code:     payload.Code,
+		Name:     payload.Name,
+		Building: payload.Building,
+		Capacity: payload.Capacity,
+		Type:     payload.Type,
+	}
+	if err := s.roomRepo.Create(s.ctx, room); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return room, nil
+}
+
+func (s *ContextedRoom) GetRoom(payload payloads.RequestGetRoom) (*models.Room, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	room, err := s.roomRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "room not found", nil)
+	}
+	return &room, nil
+}
+
+func (s *ContextedRoom) GetRooms(payload payloads.RequestGetRooms) ([]models.Room, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.Room](s.roomRepo.DB()).
+		Order("building, code").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Type != "" {
+		q = q.Where("type = ?", payload.Type)
+	}
+	if payload.Building != "" {
+		q = q.Where("building = ?", payload.Building)
+	}
+	if payload.MinCapacity > 0 {
+		q = q.Where("capacity >= ?", payload.MinCapacity)
+	}
+
+	rooms, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return rooms, nil
+}
+
+func (s *ContextedRoom) UpdateRoom(payload payloads.RequestUpdateRoom) (*models.Room, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	room, err := s.roomRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "room not found", nil)
+	}
+
+	if exists, err := s.roomRepo.Exists(s.ctx, "code = ? AND id <> ?", payload.Code, room.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"code": "another room with this code already registered"}}
+	}
+
+	room.Code = payload.Code
+	room.Name = payload.Name
+	room.Building = payload.Building
+	room.Capacity = payload.Capacity
+	room.Type = payload.Type
+	if err := s.roomRepo.DB().WithContext(s.ctx).Model(&room).Select("code", "name", "building", "capacity", "type").Updates(&room).Error; err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return &room, nil
+}
+
+func (s *ContextedRoom) DeleteRoom(payload payloads.RequestDeleteRoom) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	// bookings of the room are deleted, home room and exam room link are cleared
+	if ok, err := s.roomRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "room not found", nil)
+	}
+	return nil
+}
+
+// checkRoomFree makes sure room is not booked nor used by exam in the period
+func (s *ContextedRoom) checkRoomFree(tx *gorm.DB, roomID string, startAt, endAt time.Time, excludeBookingID string) *reply.ErrorPayload {
+	if booked, err := s.bookingRepo.WithTx(tx).Collides(s.ctx, roomID, startAt, endAt, excludeBookingID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if booked {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: "room already booked at this time",
+			Fields:  reply.FieldsError{"start_at": "overlaps another booking of the room"},
+		}
+	}
+	if used, err := s.examSessionRepo.WithTx(tx).UsesRoom(s.ctx, roomID, startAt, endAt); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if used {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeConflict,
+			Message: "room is used for exam at this time",
+			Fields:  reply.FieldsError{"start_at": "overlaps exam session in the room"},
+		}
+	}
+
+	// lessons repeat weekly within their term, so slots of every academic year the period touches are checked
+	slots, err := s.slotRepo.WithTx(tx).UsingRoom(s.ctx, roomID, models.AcademicYearOf(startAt), models.AcademicYearOf(endAt))
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	loc := schoolLocation()
+	for _, slot := range slots {
+		if slot.OverlapsPeriod(startAt, endAt, loc) {
+			return &reply.ErrorPayload{
+				Code:    replylib.CodeConflict,
+				Message: "room is used for lesson at this time",
+				Fields:  reply.FieldsError{"start_at": "overlaps lesson from " + slot.StartTime + " to " + slot.EndTime + " in timetable"},
+			}
+		}
+	}
+	return nil
+}
+
+// CreateBooking books room, booking of lab and hall waits for approval while other rooms are approved directly
+func (s *ContextedRoom) CreateBooking(payload payloads.RequestCreateRoomBooking) (booking *models.RoomBooking, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.roomRepo.DB().Transaction(func(tx *gorm.DB) error {
+		// lock room so concurrent bookings of the same room are checked one by one
+		room, err := gorm.G[models.Room](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", payload.RoomID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "room not found", reply.FieldsError{"room_id": "room with this ID not found"})
+			return err
+		}
+
+		if errPayload = s.checkRoomFree(tx, room.ID, payload.StartAt, payload.EndAt, ""); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		booking = &models.RoomBooking{
+			RoomID:     room.ID,
+			Purpose:    payload.Purpose,
+			StartAt:    payload.StartAt,
+			EndAt:      payload.EndAt,
+			Status:     models.BookingApproved,
+			BookedByID: s.c.GetString("userID"),
+		}
+		if room.NeedsApproval() {
+			booking.Status = models.BookingPending
+		}
+		if err := s.bookingRepo.WithTx(tx).Create(s.ctx, booking); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		booking.Room = &room
+		return nil
+	})
+	return
+}
+
+func (s *ContextedRoom) GetBookings(payload payloads.RequestGetRoomBookings) ([]models.RoomBooking, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.RoomBooking](s.bookingRepo.DB()).
+		Preload("Room", nil).
+		Order("start_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.RoomID != "" {
+		q = q.Where("room_id = ?", payload.RoomID)
+	}
+	if payload.Status != "" {
+		q = q.Where("status = ?", payload.Status)
+	}
+	if !payload.From.IsZero() {
+		q = q.Where("end_at > ?", payload.From)
+	}
+	if !payload.To.IsZero() {
+		q = q.Where("start_at < ?", payload.To)
+	}
+	if payload.Mine {
+		q = q.Where("booked_by_id = ?", s.c.GetString("userID"))
+	}
+
+	bookings, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return bookings, nil
+}
+
+func (s *ContextedRoom) ReviewBooking(payload payloads.RequestReviewRoomBooking) (booking *models.RoomBooking, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.bookingRepo.DB().Transaction(func(tx *gorm.DB) error {
+		b, err := s.bookingRepo.WithTx(tx).GetByID(s.ctx, payload.ID)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "booking not found", nil)
+			return err
+		}
+
+		// lock room, approval must not race with new bookings
+		room, err := gorm.G[models.Room](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", b.RoomID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if err := tx.WithContext(s.ctx).First(&b, "id = ?", b.ID).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if b.Status != models.BookingPending {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "booking already " + string(b.Status)}
+			return errors.New(errPayload.Message)
+		}
+
+		// exam may be allocated to the room after the booking was made
+		if payload.Status == models.BookingApproved {
+			if errPayload = s.checkRoomFree(tx, room.ID, b.StartAt, b.EndAt, b.ID); errPayload != nil {
+				return errors.New(errPayload.Message)
+			}
+		}
+
+		now := time.Now()
+		reviewerID := s.c.GetString("userID")
+		b.Status = payload.Status
+		b.ReviewNote = payload.Note
+		b.ReviewedByID = &reviewerID
+		b.ReviewedAt = &now
+		if err := tx.WithContext(s.ctx).Model(&b).Select("status", "review_note", "reviewed_by_id", "reviewed_at").Updates(&b).Error; err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		b.Room = &room
+		booking = &b
+		return nil
+	})
+	return
+}
+
+// CancelBooking cancels pending or approved booking, teacher only cancel own booking
+func (s *ContextedRoom) CancelBooking(payload payloads.RequestCancelRoomBooking) (*models.RoomBooking, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	booking, err := s.bookingRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "booking not found", nil)
+	}
+	if s.c.GetString("role") == string(models.RoleTeacher) && booking.BookedByID != s.c.GetString("userID") {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "booking is owned by other user"}
+	}
+	if !booking.Blocking() {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "booking already " + string(booking.Status)}
+	}
+
+	// conditional update so concurrent review does not get overwritten
+	result := s.bookingRepo.DB().WithContext(s.ctx).Model(&booking).
+		Where("status IN ?", []models.BookingStatus{models.BookingPending, models.BookingApproved}).
+		Update("status", models.BookingCancelled)
+	if result.Error != nil {
+		return nil, errorlib.MakeServerError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "booking was changed, please reload"}
+	}
+	booking.Status = models.BookingCancelled
+	return &booking, nil
+}
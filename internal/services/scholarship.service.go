@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Scholarship struct {
+	programRepo      *repos.ScholarshipProgram
+	recipientRepo    *repos.ScholarshipRecipient
+	disbursementRepo *repos.ScholarshipDisbursement
+	studentRepo      *repos.Student
+}
+
+type ContextedScholarship struct {
+	*Scholarship
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewScholarship(programRepo *repos.ScholarshipProgram, recipientRepo *repos.ScholarshipRecipient, disbursementRepo *repos.ScholarshipDisbursement, studentRepo *repos.Student) *Scholarship {
+	return &Scholarship{programRepo, recipientRepo, disbursementRepo, studentRepo}
+}
+
+func (s *Scholarship) ApplyContext(c *gin.Context) *ContextedScholarship {
+	return &ContextedScholarship{s, c, c.Request.Context()}
+}
+
+func (s *ContextedScholarship) CreateProgram(payload payloads.RequestCreateScholarshipProgram) (*models.ScholarshipProgram, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.programRepo.Exists(s.ctx, "name = ?", payload.Name); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"name": "scholarship program with this name already registered"}}
+	}
+
+	program := &models.ScholarshipProgram{
+		Name:          payload.Name,
+		Source:        payload.Source,
+		Provider:      payload.Provider,
+		Eligibility:   payload.Eligibility,
+		AmountPerTerm: payload.AmountPerTerm,
+		FeeDiscount:   payload.FeeDiscount,
+		Active:        true,
+	}
+	if err := s.programRepo.Create(s.ctx, program); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return program, nil
+}
+
+func (s *ContextedScholarship) GetProgram(payload payloads.RequestGetScholarshipProgram) (*models.ScholarshipProgram, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	program, err := s.programRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "scholarship program not found", nil)
+	}
+	return &program, nil
+}
+
+func (s *ContextedScholarship) GetPrograms(payload payloads.RequestGetScholarshipPrograms) ([]models.ScholarshipProgram, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.ScholarshipProgram](s.programRepo.DB()).
+		Order("name").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Source != "" {
+		q = q.Where("source = ?", payload.Source)
+	}
+	if payload.Active != nil {
+		q = q.Where("active = ?", *payload.Active)
+	}
+
+	programs, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return programs, nil
+}
+
+func (s *ContextedScholarship) UpdateProgram(payload payloads.RequestUpdateScholarshipProgram) (*models.ScholarshipProgram, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	program, err := s.programRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "scholarship program not found", nil)
+	}
+
+	if exists, err := s.programRepo.Exists(s.ctx, "name = ? AND id <> ?", payload.Name, program.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if exists {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "invalid payload", Fields: reply.FieldsError{"name": "another scholarship program with this name already registered"}}
+	}
+
+	program.Name = payload.Name
+	program.Source = payload.Source
+	program.Provider = payload.Provider
+	program.Eligibility = payload.Eligibility
+	program.AmountPerTerm = payload.AmountPerTerm
+	program.FeeDiscount = payload.FeeDiscount
+	program.Active = payload.Active
+	err = s.programRepo.DB().WithContext(s.ctx).Model(&program).
+		Select("name", "source", "provider", "eligibility", "amount_per_term", "fee_discount", "active").
+		Updates(&program).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return &program, nil
+}
+
+func (s *ContextedScholarship) DeleteProgram(payload payloads.RequestDeleteScholarshipProgram) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	// recipients are kept for government reporting, program with recipients must be deactivated instead
+	if exists, err := s.recipientRepo.Exists(s.ctx, "program_id = ?", payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if exists {
+		return &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "scholarship program already has recipients, deactivate it instead"}
+	}
+
+	if ok, err := s.programRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "scholarship program not found", nil)
+	}
+	return nil
+}
+
+// GetFeeDiscount returns school fee discount of a student in a term from all received programs
+func (s *ContextedScholarship) GetFeeDiscount(payload payloads.RequestGetFeeDiscount) (*payloads.ResponseFeeDiscount, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.studentRepo.Exists(s.ctx, "id = ?", payload.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "student not found", nil)
+	}
+
+	programs, err := gorm.G[models.ScholarshipProgram](s.programRepo.DB()).
+		Where("fee_discount > 0").
+		Where("id IN (SELECT program_id FROM scholarship_recipients WHERE student_id = ? AND academic_year = ? AND semester = ?)", payload.ID, payload.AcademicYear, payload.Semester).
+		Order("name").
+		Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	discount := 0
+	for _, program := range programs {
+		discount += program.FeeDiscount
+	}
+
+	return &payloads.ResponseFeeDiscount{
+		StudentID:    payload.ID,
+		AcademicYear: payload.AcademicYear,
+		Semester:     payload.Semester,
+		FeeDiscount:  min(discount, 100),
+		Programs:     programs,
+	}, nil
+}
+
+// GetReport returns recipient count and disbursed amount per program and class of a term
+func (s *ContextedScholarship) GetReport(payload payloads.RequestGetScholarshipReport) ([]payloads.ResponseScholarshipReportRow, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	var rows []struct {
+		payloads.ResponseScholarshipReportRow
+		Grade       int
+		Major       string
+		ClassNumber int
+	}
+	q := s.recipientRepo.DB().WithContext(s.ctx).Table("scholarship_recipients").
+		Select("scholarship_programs.id AS program_id, scholarship_programs.name AS program, scholarship_programs.source, "+
+			"classes.id AS class_id, classes.grade, classes.major, classes.class_number, "+
+			"COUNT(DISTINCT scholarship_recipients.student_id) AS recipients, COALESCE(SUM(disbursed.total), 0) AS disbursed").
+		Joins("JOIN scholarship_programs ON scholarship_programs.id = scholarship_recipients.program_id").
+		Joins("JOIN students ON students.id = scholarship_recipients.student_id AND students.deleted_at IS NULL").
+		Joins("JOIN classes ON classes.id = students.class_id").
+		Joins("LEFT JOIN (SELECT recipient_id, SUM(amount) AS total FROM scholarship_disbursements GROUP BY recipient_id) AS disbursed ON disbursed.recipient_id = scholarship_recipients.id").
+		Where("scholarship_recipients.academic_year = ?", payload.AcademicYear)
+	if payload.Semester != 0 {
+		q = q.Where("scholarship_recipients.semester = ?", payload.Semester)
+	}
+	err := q.Group("scholarship_programs.id, scholarship_programs.name, scholarship_programs.source, classes.id, classes.grade, classes.major, classes.class_number").
+		Order("scholarship_programs.name, classes.grade, classes.major, classes.class_number").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	report := make([]payloads.ResponseScholarshipReportRow, 0, len(rows))
+	for _, row := range rows {
+		class := models.Class{Grade: row.Grade, Major: row.Major, ClassNumber: row.ClassNumber}
+		row.ClassName = class.GetName()
+		report = append(report, row.ResponseScholarshipReportRow)
+	}
+	return report, nil
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/slicelib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddRecipients registers students as recipients of a program in a term
+func (s *ContextedScholarship) AddRecipients(payload payloads.RequestAddScholarshipRecipients) (recipients []models.ScholarshipRecipient, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// removes duplicate ids
+	payload.StudentIDs = slicelib.Unique(payload.StudentIDs)
+
+	s.recipientRepo.DB().Transaction(func(tx *gorm.DB) error {
+		// lock program so it is not deactivated while adding recipients
+		var program models.ScholarshipProgram
+		err := tx.WithContext(s.ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&program, "id = ?", payload.ID).Error
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "scholarship program not found", nil)
+			return err
+		}
+		if !program.Active {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "scholarship program is not active"}
+			return errors.New(errPayload.Message)
+		}
+
+		students, err := s.studentRepo.WithTx(tx).GetByIDs(s.ctx, payload.StudentIDs)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if notFound := len(payload.StudentIDs) - len(students); notFound > 0 {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeNotFound,
+				Message: "student(s) not found",
+				Fields:  reply.FieldsError{"student_ids": fmt.Sprintf("%d student(s) with these id not found", notFound)},
+			}
+			return gorm.ErrRecordNotFound
+		}
+
+		recipientRepo := s.recipientRepo.WithTx(tx)
+		count, err := recipientRepo.Count(s.ctx, "program_id = ? AND academic_year = ? AND semester = ? AND student_id IN ?", program.ID, payload.AcademicYear, payload.Semester, payload.StudentIDs)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if count > 0 {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeConflict,
+				Message: "student(s) already recipient of this program",
+				Fields:  reply.FieldsError{"student_ids": fmt.Sprintf("%d student(s) already recipient of this program in the term", count)},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		awardedByID := s.c.GetString("userID")
+		recipients = make([]models.ScholarshipRecipient, 0, len(payload.StudentIDs))
+		for _, studentID := range payload.StudentIDs {
+			recipients = append(recipients, models.ScholarshipRecipient{
+				ProgramID:    program.ID,
+				StudentID:    studentID,
+				AcademicYear: payload.AcademicYear,
+				Semester:     payload.Semester,
+				Note:         payload.Note,
+				AwardedByID:  &awardedByID,
+			})
+		}
+		if err := recipientRepo.CreateAll(s.ctx, &recipients); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedScholarship) GetRecipient(payload payloads.RequestGetScholarshipRecipient) (*models.ScholarshipRecipient, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	recipient, err := gorm.G[models.ScholarshipRecipient](s.recipientRepo.DB()).
+		Preload("Program", nil).
+		Preload("Disbursements", func(db gorm.PreloadBuilder) error {
+			db.Order("disbursed_at")
+			return nil
+		}).
+		Where("id = ?", payload.ID).
+		First(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "scholarship recipient not found", nil)
+	}
+	return &recipient, nil
+}
+
+func (s *ContextedScholarship) GetRecipients(payload payloads.RequestGetScholarshipRecipients) ([]models.ScholarshipRecipient, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.ScholarshipRecipient](s.recipientRepo.DB()).
+		Preload("Program", nil).
+		Order("academic_year DESC, semester DESC, created_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.ProgramID != "" {
+		q = q.Where("program_id = ?", payload.ProgramID)
+	}
+	if payload.StudentID != "" {
+		q = q.Where("student_id = ?", payload.StudentID)
+	}
+	if payload.ClassID != "" {
+		q = q.Where("student_id IN (SELECT id FROM students WHERE class_id = ? AND deleted_at IS NULL)", payload.ClassID)
+	}
+	if payload.AcademicYear != "" {
+		q = q.Where("academic_year = ?", payload.AcademicYear)
+	}
+	if payload.Semester != 0 {
+		q = q.Where("semester = ?", payload.Semester)
+	}
+
+	recipients, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return recipients, nil
+}
+
+func (s *ContextedScholarship) RemoveRecipient(payload payloads.RequestRemoveScholarshipRecipient) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	// disbursed scholarship must stay on record
+	if exists, err := s.disbursementRepo.Exists(s.ctx, "recipient_id = ?", payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if exists {
+		return &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "scholarship already disbursed to this recipient"}
+	}
+
+	if ok, err := s.recipientRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "scholarship recipient not found", nil)
+	}
+	return nil
+}
+
+func (s *ContextedScholarship) CreateDisbursement(payload payloads.RequestCreateDisbursement) (*models.ScholarshipDisbursement, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if exists, err := s.recipientRepo.Exists(s.ctx, "id = ?", payload.ID); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	} else if !exists {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "scholarship recipient not found", nil)
+	}
+
+	if payload.DisbursedAt.After(time.Now()) {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "disbursement can not be recorded before it happens",
+			Fields:  reply.FieldsError{"disbursed_at": "disbursed_at must not be in the future"},
+		}
+	}
+
+	recordedByID := s.c.GetString("userID")
+	disbursement := &models.ScholarshipDisbursement{
+		RecipientID:  payload.ID,
+		Amount:       payload.Amount,
+		DisbursedAt:  payload.DisbursedAt,
+		Reference:    payload.Reference,
+		Note:         payload.Note,
+		RecordedByID: &recordedByID,
+	}
+	if err := s.disbursementRepo.Create(s.ctx, disbursement); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return disbursement, nil
+}
+
+func (s *ContextedScholarship) DeleteDisbursement(payload payloads.RequestDeleteDisbursement) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	if ok, err := s.disbursementRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "disbursement not found", nil)
+	}
+	return nil
+}
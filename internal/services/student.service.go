@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"school-information-system/internal/libs/errorlib"
 	"school-information-system/internal/libs/replylib"
 	"school-information-system/internal/libs/slicelib"
@@ -16,8 +17,11 @@ import (
 )
 
 type Student struct {
-	studentRepo *repos.Student
-	parentRepo  *repos.Parent
+	studentRepo  *repos.Student
+	parentRepo   *repos.Parent
+	userRepo     *repos.User
+	classRepo    *repos.Class
+	transferRepo *repos.StudentTransfer
 }
 
 type ContextedStudent struct {
@@ -26,8 +30,8 @@ type ContextedStudent struct {
 	ctx context.Context
 }
 
-func NewStudent(studentRepo *repos.Student, parentRepo *repos.Parent) *Student {
-	return &Student{studentRepo, parentRepo}
+func NewStudent(studentRepo *repos.Student, parentRepo *repos.Parent, userRepo *repos.User, classRepo *repos.Class, transferRepo *repos.StudentTransfer) *Student {
+	return &Student{studentRepo, parentRepo, userRepo, classRepo, transferRepo}
 }
 
 func (s *Student) ApplyContext(c *gin.Context) *ContextedStudent {
@@ -58,6 +62,18 @@ func (s *ContextedStudent) UpdateStudent(payload payloads.RequestUpdateStudent)
 			errPayload = errorlib.MakeNotFound(gorm.ErrRecordNotFound, "student profile not found", nil)
 			return gorm.ErrRecordNotFound
 		}
+		if scope := scopeOf(s.c, models.ResourceStudent, models.ActionUpdate); scope != nil {
+			cond, args := scopeCondition(scope, "class_id")
+			inScope, err := studentRepo.Exists(s.ctx, "id = ? AND "+cond, append([]any{payload.ID}, args...)...)
+			if err != nil {
+				errPayload = errorlib.MakeServerError(err)
+				return err
+			}
+			if !inScope {
+				errPayload = &replylib.ErrOutOfScope
+				return errors.New(errPayload.Message)
+			}
+		}
 
 		// update student profile
 		if payload.NISN != "" {
@@ -94,3 +110,35 @@ func (s *ContextedStudent) UpdateStudent(payload payloads.RequestUpdateStudent)
 
 	return
 }
+
+// findStudentIdentity returns identity of student for printable documents
+func findStudentIdentity(ctx context.Context, db *gorm.DB, studentID string) (*payloads.ResponseStudentIdentity, *reply.ErrorPayload) {
+	var row struct {
+		ID          string
+		NISN        string
+		FullName    string
+		Grade       int
+		Major       string
+		ClassNumber int
+	}
+	result := db.WithContext(ctx).Table("students").
+		Select("students.id, students.nisn, users.full_name, classes.grade, classes.major, classes.class_number").
+		Joins("JOIN users ON users.id = students.user_id").
+		Joins("JOIN classes ON classes.id = students.class_id").
+		Where("students.id = ?", studentID).
+		Scan(&row)
+	if result.Error != nil {
+		return nil, errorlib.MakeServerError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "student not found", reply.FieldsError{"student_id": "student with this ID not found"})
+	}
+
+	class := models.Class{Grade: row.Grade, Major: row.Major, ClassNumber: row.ClassNumber}
+	return &payloads.ResponseStudentIdentity{
+		StudentID: row.ID,
+		NISN:      row.NISN,
+		FullName:  row.FullName,
+		ClassName: class.GetName(),
+	}, nil
+}
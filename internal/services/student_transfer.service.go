@@ -0,0 +1,330 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/authlib"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/phonelib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/slicelib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"strings"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+)
+
+// makeLetterNumber creates transfer letter number, e.g. TRF/OUT/2026/A1B2C3D4
+func makeLetterNumber(direction models.TransferDirection, at time.Time) (string, error) {
+	suffix, err := authlib.RandomHex(4)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("TRF/%s/%d/%s", strings.ToUpper(string(direction)), at.Year(), strings.ToUpper(suffix)), nil
+}
+
+func (s *ContextedStudent) TransferIn(payload payloads.RequestTransferIn) (user *models.User, transfer *models.StudentTransfer, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, nil, errPayload
+	}
+
+	payload.ParentIDs = slicelib.Unique(payload.ParentIDs)
+	if len(payload.ParentIDs) != 2 {
+		return nil, nil, &reply.ErrorPayload{
+			Code:    replylib.CodeBadRequest,
+			Message: "invalid payload",
+			Fields:  reply.FieldsError{"parent_ids": "id is not unique"},
+		}
+	}
+
+	// format and validate phone number
+	formattedNumber, validNum := phonelib.FormatNumber(payload.Phone)
+	if !validNum {
+		return nil, nil, &replylib.ErrInvalidPhone
+	}
+
+	hashedPassword, err := authlib.HashPassword(payload.Password)
+	if err != nil {
+		return nil, nil, errorlib.MakeServerError(err)
+	}
+
+	letterNumber, err := makeLetterNumber(models.TransferIn, payload.TransferredAt)
+	if err != nil {
+		return nil, nil, errorlib.MakeServerError(err)
+	}
+
+	// transaction to rollback if error
+	s.studentRepo.DB().Transaction(func(tx *gorm.DB) error {
+		userRepo := s.userRepo.WithTx(tx)
+		classRepo := s.classRepo.WithTx(tx)
+		parentRepo := s.parentRepo.WithTx(tx)
+		studentRepo := s.studentRepo.WithTx(tx)
+		transferRepo := s.transferRepo.WithTx(tx)
+
+		// check email
+		if exists, err := userRepo.Exists(s.ctx, "email = ?", payload.Email); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		} else if exists {
+			errPayload = &replylib.ErrEmailRegistered
+			return errors.New(errPayload.Message)
+		}
+
+		// check phone number
+		if exists, err := userRepo.Exists(s.ctx, "phone = ?", formattedNumber); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		} else if exists {
+			errPayload = &replylib.ErrPhoneRegistered
+			return errors.New(errPayload.Message)
+		}
+
+		// check NISN
+		if exists, err := studentRepo.Exists(s.ctx, "nisn = ?", payload.NISN); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		} else if exists {
+			err := errors.New("other student with same NISN already exist")
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeConflict,
+				Message: "NISN unique conflict",
+				Fields:  reply.FieldsError{"NISN": err.Error()},
+			}
+			return err
+		}
+
+		// check is class exists
+		classExists, err := classRepo.Exists(s.ctx, "id = ?", payload.ClassID)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if !classExists {
+			errPayload = errorlib.MakeNotFound(
+				gorm.ErrRecordNotFound,
+				"class not found",
+				reply.FieldsError{"class_id": "class with this ID not found"},
+			)
+			return gorm.ErrRecordNotFound
+		}
+		if _, errPayload = ensureClassInScope(s.ctx, tx, scopeOf(s.c, models.ResourceStudent, models.ActionCreate), payload.ClassID); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		// check is found parent 2
+		parents, err := parentRepo.GetByIDs(s.ctx, payload.ParentIDs)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if len(parents) != 2 {
+			errPayload = &reply.ErrorPayload{Code: replylib.CodeNotFound, Message: "parent(s) not found"}
+			return gorm.ErrRecordNotFound
+		}
+
+		// create user directly as student
+		activatedAt := time.Now()
+		user = &models.User{
+			FullName:    payload.FullName,
+			Email:       payload.Email,
+			Password:    hashedPassword,
+			Role:        models.RoleStudent,
+			Roles:       []models.UserRole{models.RoleStudent},
+			Gender:      payload.Gender,
+			Phone:       formattedNumber,
+			ActivatedAt: &activatedAt, // created with role, no activation needed
+		}
+		if err := userRepo.Create(s.ctx, user); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// create student
+		student := &models.Student{
+			NISN:    payload.NISN,
+			UserID:  user.ID,
+			ClassID: payload.ClassID,
+		}
+		user.StudentProfile = student
+		if err := studentRepo.Create(s.ctx, student); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// append parents to student
+		if err := tx.Model(student).Association("Parents").Append(parents); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// record transfer with imported records
+		transfer = &models.StudentTransfer{
+			Direction:        models.TransferIn,
+			UserID:           &user.ID,
+			StudentName:      user.FullName,
+			NISN:             student.NISN,
+			SchoolName:       payload.OriginSchool,
+			Reason:           payload.Reason,
+			LetterNumber:     letterNumber,
+			TransferredAt:    payload.TransferredAt,
+			PriorGrades:      payload.PriorGrades,
+			PriorAttendances: payload.PriorAttendances,
+			ProcessedByID:    s.c.GetString("userID"),
+		}
+		err = transferRepo.Create(s.ctx, transfer)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+		}
+		return err
+	})
+	return
+}
+
+func (s *ContextedStudent) TransferOut(payload payloads.RequestTransferOut) (transfer *models.StudentTransfer, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	letterNumber, err := makeLetterNumber(models.TransferOut, payload.TransferredAt)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	// transaction to rollback if error
+	s.studentRepo.DB().Transaction(func(tx *gorm.DB) error {
+		userRepo := s.userRepo.WithTx(tx)
+		transferRepo := s.transferRepo.WithTx(tx)
+
+		// get user with student profile
+		var user models.User
+		err := tx.Preload("StudentProfile.Class").
+			Preload("StudentProfile.Parents").
+			Joins("JOIN students ON students.user_id = users.id AND students.deleted_at IS NULL").
+			Where("students.id = ?", payload.ID).
+			First(&user).Error
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "student profile not found", nil)
+			return err
+		}
+		if scope := scopeOf(s.c, models.ResourceStudent, models.ActionDelete); scope != nil && (user.StudentProfile.Class == nil || !scope.Contains(user.StudentProfile.Class)) {
+			errPayload = &replylib.ErrOutOfScope
+			return errors.New(errPayload.Message)
+		}
+
+		// build data export, records of student are only the imported records from transfer in
+		export := &models.TransferExport{
+			FullName:    user.FullName,
+			Email:       user.Email,
+			Phone:       user.Phone,
+			Gender:      user.Gender,
+			NISN:        user.StudentProfile.NISN,
+			Parents:     []models.Parent{},
+			Grades:      []models.TransferGrade{},
+			Attendances: []models.TransferAttendance{},
+		}
+		if user.StudentProfile.Class != nil {
+			export.ClassName = user.StudentProfile.Class.GetName()
+		}
+		for _, parent := range user.StudentProfile.Parents {
+			export.Parents = append(export.Parents, *parent)
+		}
+
+		prevTransfers, err := transferRepo.GetAll(s.ctx, "user_id = ? AND direction = ?", user.ID, models.TransferIn)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		for _, prev := range prevTransfers {
+			export.Grades = append(export.Grades, prev.PriorGrades...)
+			export.Attendances = append(export.Attendances, prev.PriorAttendances...)
+		}
+
+		// archive user, purged by cron job after configured duration
+		if err := userRepo.Archive(s.ctx, "id = ?", user.ID); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+
+		// record transfer
+		transfer = &models.StudentTransfer{
+			Direction:     models.TransferOut,
+			UserID:        &user.ID,
+			StudentName:   user.FullName,
+			NISN:          user.StudentProfile.NISN,
+			SchoolName:    payload.DestinationSchool,
+			Reason:        payload.Reason,
+			LetterNumber:  letterNumber,
+			TransferredAt: payload.TransferredAt,
+			Export:        export,
+			ProcessedByID: s.c.GetString("userID"),
+		}
+		err = transferRepo.Create(s.ctx, transfer)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+		}
+		return err
+	})
+	return
+}
+
+// transferScopeCondition limits transfers to students of classes in read scope of current admin, transfer of purged student is out of any scope
+func (s *ContextedStudent) transferScopeCondition() (cond string, args []any, scoped bool) {
+	scope := scopeOf(s.c, models.ResourceStudent, models.ActionRead)
+	if scope == nil {
+		return "", nil, false
+	}
+	cond, args = scopeCondition(scope, "class_id")
+	return "user_id IN (SELECT user_id FROM students WHERE " + cond + ")", args, true
+}
+
+func (s *ContextedStudent) GetTransfer(payload payloads.RequestGetTransfer) (*models.StudentTransfer, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.StudentTransfer](s.transferRepo.DB()).Where("id = ?", payload.ID)
+	if cond, args, scoped := s.transferScopeCondition(); scoped {
+		q = q.Where(cond, args...)
+	}
+	transfer, err := q.First(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "transfer not found", nil)
+	}
+
+	return &transfer, nil
+}
+
+func (s *ContextedStudent) GetTransfers(payload payloads.RequestGetTransfers) ([]models.StudentTransfer, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.StudentTransfer](s.transferRepo.DB()).
+		Omit("export", "prior_grades", "prior_attendances").
+		Order("transferred_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Direction != "" {
+		q = q.Where("direction = ?", payload.Direction)
+	}
+	if payload.Query != "" {
+		q = q.Where("LOWER(student_name) LIKE LOWER(?) OR nisn = ?", "%"+payload.Query+"%", payload.Query)
+	}
+	if cond, args, scoped := s.transferScopeCondition(); scoped {
+		q = q.Where(cond, args...)
+	}
+
+	transfers, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return transfers, nil
+}
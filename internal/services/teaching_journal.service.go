@@ -0,0 +1,240 @@
+package services
+
+import (
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+)
+
+// currentTeacher returns teacher profile of current user, nil if current user is not a teacher
+func (s *ContextedTimetable) currentTeacher() (*models.Teacher, *reply.ErrorPayload) {
+	if s.c.GetString("role") != string(models.RoleTeacher) {
+		return nil, nil
+	}
+	teacher, err := s.teacherRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your teacher profile not found", nil)
+	}
+	return &teacher, nil
+}
+
+// getOwnJournal returns journal for teacher who wrote it, admin can access every journal
+func (s *ContextedTimetable) getOwnJournal(id string) (*models.TeachingJournal, *reply.ErrorPayload) {
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	journal, err := s.journalRepo.GetByID(s.ctx, id)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "teaching journal not found", nil)
+	}
+	if teacher != nil && journal.TeacherID != teacher.ID {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "this teaching journal is not yours"}
+	}
+	return &journal, nil
+}
+
+// checkAttendanceCount makes sure attendance does not exceed students of the class
+func (s *ContextedTimetable) checkAttendanceCount(classID string, attendanceCount int) *reply.ErrorPayload {
+	students, err := s.studentRepo.Count(s.ctx, "class_id = ?", classID)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	if int64(attendanceCount) > students {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "attendance exceeds students of the class",
+			Fields:  reply.FieldsError{"attendance_count": fmt.Sprintf("class only has %d student(s)", students)},
+		}
+	}
+	return nil
+}
+
+// CreateJournal logs a lesson taught by current teacher, lesson of a slot can only be logged once a day
+func (s *ContextedTimetable) CreateJournal(payload payloads.RequestCreateTeachingJournal) (*models.TeachingJournal, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	teacher, err := s.teacherRepo.GetFirst(s.ctx, "user_id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your teacher profile not found", nil)
+	}
+
+	date := models.DateOf(payload.Date)
+	if date.After(time.Now()) {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "lesson can not be logged before it happens",
+			Fields:  reply.FieldsError{"date": "date must not be in the future"},
+		}
+	}
+
+	journal := &models.TeachingJournal{
+		TeacherID:       teacher.ID,
+		ClassID:         payload.ClassID,
+		SubjectID:       payload.SubjectID,
+		Date:            date,
+		Topic:           payload.Topic,
+		Method:          payload.Method,
+		AttendanceCount: payload.AttendanceCount,
+		Notes:           payload.Notes,
+	}
+
+	if payload.SlotID != "" {
+		slot, err := s.slotRepo.GetByID(s.ctx, payload.SlotID)
+		if err != nil {
+			return nil, errorlib.MakeNotFound(err, "timetable slot not found", reply.FieldsError{"slot_id": "timetable slot with this ID not found"})
+		}
+		if slot.TeacherID != teacher.ID {
+			return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: "this lesson is not yours, log it without slot if you replace the teacher"}
+		}
+		if !slot.HeldOn(date) {
+			return nil, &reply.ErrorPayload{
+				Code:    replylib.CodeUnprocessableEntity,
+				Message: "lesson is not held on this date",
+				Fields:  reply.FieldsError{"date": "date must be on day and term of the slot"},
+			}
+		}
+		if exists, err := s.journalRepo.Exists(s.ctx, "slot_id = ? AND date = ?", slot.ID, date); err != nil {
+			return nil, errorlib.MakeServerError(err)
+		} else if exists {
+			return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "lesson already logged on this date"}
+		}
+		journal.SlotID = &slot.ID
+		journal.ClassID = slot.ClassID
+		journal.SubjectID = slot.SubjectID
+	} else {
+		if exists, err := s.classRepo.Exists(s.ctx, "id = ?", journal.ClassID); err != nil {
+			return nil, errorlib.MakeServerError(err)
+		} else if !exists {
+			return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "class not found", reply.FieldsError{"class_id": "class with this ID not found"})
+		}
+		if exists, err := s.subjectRepo.Exists(s.ctx, "id = ?", journal.SubjectID); err != nil {
+			return nil, errorlib.MakeServerError(err)
+		} else if !exists {
+			return nil, errorlib.MakeNotFound(gorm.ErrRecordNotFound, "subject not found", reply.FieldsError{"subject_id": "subject with this ID not found"})
+		}
+	}
+
+	if errPayload := s.checkAttendanceCount(journal.ClassID, journal.AttendanceCount); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if err := s.journalRepo.Create(s.ctx, journal); err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return journal, nil
+}
+
+func (s *ContextedTimetable) GetJournal(payload payloads.RequestGetTeachingJournal) (*models.TeachingJournal, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	journal, errPayload := s.getOwnJournal(payload.ID)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	return journal, nil
+}
+
+// GetJournals returns teaching journals from the latest date, teacher only get own journals
+func (s *ContextedTimetable) GetJournals(payload payloads.RequestGetTeachingJournals) ([]models.TeachingJournal, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if teacher != nil {
+		payload.TeacherID = teacher.ID
+	}
+
+	q := gorm.G[models.TeachingJournal](s.journalRepo.DB()).
+		Preload("Class", nil).
+		Preload("Subject", nil).
+		Order("date DESC, created_at DESC").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.TeacherID != "" {
+		q = q.Where("teacher_id = ?", payload.TeacherID)
+	}
+	if payload.ClassID != "" {
+		q = q.Where("class_id = ?", payload.ClassID)
+	}
+	if payload.SubjectID != "" {
+		q = q.Where("subject_id = ?", payload.SubjectID)
+	}
+	if payload.From != "" {
+		q = q.Where("date >= ?", payload.From)
+	}
+	if payload.To != "" {
+		q = q.Where("date <= ?", payload.To)
+	}
+
+	journals, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	for i := range journals {
+		journals[i].Class.GetName()
+	}
+	return journals, nil
+}
+
+// UpdateJournal updates content of journal, date and lesson of journal can not be changed
+func (s *ContextedTimetable) UpdateJournal(payload payloads.RequestUpdateTeachingJournal) (*models.TeachingJournal, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	journal, errPayload := s.getOwnJournal(payload.ID)
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if errPayload := s.checkAttendanceCount(journal.ClassID, payload.AttendanceCount); errPayload != nil {
+		return nil, errPayload
+	}
+
+	journal.Topic = payload.Topic
+	journal.Method = payload.Method
+	journal.AttendanceCount = payload.AttendanceCount
+	journal.Notes = payload.Notes
+	err := s.journalRepo.DB().WithContext(s.ctx).Model(journal).
+		Select("topic", "method", "attendance_count", "notes").
+		Updates(journal).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return journal, nil
+}
+
+func (s *ContextedTimetable) DeleteJournal(payload payloads.RequestDeleteTeachingJournal) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	journal, errPayload := s.getOwnJournal(payload.ID)
+	if errPayload != nil {
+		return errPayload
+	}
+	if _, err := s.journalRepo.DeleteByID(s.ctx, journal.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	return nil
+}
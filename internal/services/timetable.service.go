@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/slicelib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Timetable struct {
+	slotRepo    *repos.TimetableSlot
+	journalRepo *repos.TeachingJournal
+	teacherRepo *repos.Teacher
+	classRepo   *repos.Class
+	subjectRepo *repos.Subject
+	studentRepo *repos.Student
+	bookingRepo *repos.RoomBooking
+}
+
+type ContextedTimetable struct {
+	*Timetable
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewTimetable(slotRepo *repos.TimetableSlot, journalRepo *repos.TeachingJournal, teacherRepo *repos.Teacher, classRepo *repos.Class, subjectRepo *repos.Subject, studentRepo *repos.Student, bookingRepo *repos.RoomBooking) *Timetable {
+	return &Timetable{slotRepo, journalRepo, teacherRepo, classRepo, subjectRepo, studentRepo, bookingRepo}
+}
+
+func (s *Timetable) ApplyContext(c *gin.Context) *ContextedTimetable {
+	return &ContextedTimetable{s, c, c.Request.Context()}
+}
+
+// schoolLocation returns timezone of the school which lesson clocks are read in, UTC if it can't be loaded
+func schoolLocation() *time.Location {
+	loc, err := time.LoadLocation(config.APP_TIMEZONE)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// maxComplianceDays limits date range of journal compliance report
+const maxComplianceDays = 62
+
+// checkSlot makes sure related data of the slot exist and the slot does not collide with other slot of the class, teacher or room
+func (s *ContextedTimetable) checkSlot(tx *gorm.DB, slot *models.TimetableSlot) *reply.ErrorPayload {
+	if slot.StartTime >= slot.EndTime {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "invalid lesson time",
+			Fields:  reply.FieldsError{"end_time": "end_time must be after start_time"},
+		}
+	}
+
+	// lock class and teacher so concurrent slots of them are checked one by one
+	var class models.Class
+	if err := tx.WithContext(s.ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&class, "id = ?", slot.ClassID).Error; err != nil {
+		return errorlib.MakeNotFound(err, "class not found", reply.FieldsError{"class_id": "class with this ID not found"})
+	}
+	if err := tx.WithContext(s.ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Teacher{}, "id = ?", slot.TeacherID).Error; err != nil {
+		return errorlib.MakeNotFound(err, "teacher not found", reply.FieldsError{"teacher_id": "teacher with this ID not found"})
+	}
+	if exists, err := s.subjectRepo.WithTx(tx).Exists(s.ctx, "id = ?", slot.SubjectID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !exists {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "subject not found", reply.FieldsError{"subject_id": "subject with this ID not found"})
+	}
+	// lock room like room booking does, lesson without room is held in home room of the class
+	roomID := class.HomeRoomID
+	if slot.RoomID != nil {
+		roomID = slot.RoomID
+		if err := tx.WithContext(s.ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Room{}, "id = ?", *slot.RoomID).Error; err != nil {
+			return errorlib.MakeNotFound(err, "room not found", reply.FieldsError{"room_id": "room with this ID not found"})
+		}
+	} else if roomID != nil {
+		if err := tx.WithContext(s.ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Room{}, "id = ?", *roomID).Error; err != nil {
+			return errorlib.MakeServerError(err)
+		}
+	}
+
+	if teaches, err := s.teacherRepo.WithTx(tx).TeachesSubject(s.ctx, slot.TeacherID, slot.SubjectID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !teaches {
+		return &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "teacher does not teach this subject",
+			Fields:  reply.FieldsError{"teacher_id": "teacher is not assigned to this subject"},
+		}
+	}
+
+	// lessons collide when their time overlaps in the same day of the same term
+	cond, args := "class_id = ? OR teacher_id = ?", []any{slot.ClassID, slot.TeacherID}
+	if slot.RoomID != nil {
+		cond += " OR room_id = ?"
+		args = append(args, *slot.RoomID)
+	}
+	q := tx.Where("academic_year = ? AND semester = ? AND weekday = ?", slot.AcademicYear, slot.Semester, slot.Weekday).
+		Where("start_time < ? AND end_time > ?", slot.EndTime, slot.StartTime).
+		Where(cond, args...)
+	if slot.ID != "" {
+		q = q.Where("id <> ?", slot.ID)
+	}
+	collided, err := s.slotRepo.WithTx(tx).GetFirst(s.ctx, q)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.checkRoomBookings(tx, slot, roomID)
+	} else if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+
+	errPayload := &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "lesson collides with other lesson from " + collided.StartTime + " to " + collided.EndTime}
+	switch {
+	case collided.ClassID == slot.ClassID:
+		errPayload.Fields = reply.FieldsError{"class_id": "class already has lesson at this time"}
+	case collided.TeacherID == slot.TeacherID:
+		errPayload.Fields = reply.FieldsError{"teacher_id": "teacher already teaches other class at this time"}
+	default:
+		errPayload.Fields = reply.FieldsError{"room_id": "room is used by other class at this time"}
+	}
+	return errPayload
+}
+
+// checkRoomBookings makes sure lessons of the slot don't overlap pending or approved booking of the room within the term
+func (s *ContextedTimetable) checkRoomBookings(tx *gorm.DB, slot *models.TimetableSlot, roomID *string) *reply.ErrorPayload {
+	if roomID == nil {
+		return nil
+	}
+	loc := schoolLocation()
+	termStart, termEnd := models.TermPeriod(slot.AcademicYear, slot.Semester, loc)
+	bookings, err := s.bookingRepo.WithTx(tx).Active(s.ctx, *roomID, termStart, termEnd)
+	if err != nil {
+		return errorlib.MakeServerError(err)
+	}
+	for _, booking := range bookings {
+		if slot.OverlapsPeriod(booking.StartAt, booking.EndAt, loc) {
+			return &reply.ErrorPayload{
+				Code:    replylib.CodeConflict,
+				Message: "room is booked at this time on " + booking.StartAt.In(loc).Format(time.DateOnly),
+				Fields:  reply.FieldsError{"room_id": "room is booked for " + booking.Purpose},
+			}
+		}
+	}
+	return nil
+}
+
+func (s *ContextedTimetable) CreateSlot(payload payloads.RequestCreateTimetableSlot) (slot *models.TimetableSlot, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	slot = &models.TimetableSlot{
+		ClassID:      payload.ClassID,
+		SubjectID:    payload.SubjectID,
+		TeacherID:    payload.TeacherID,
+		AcademicYear: payload.AcademicYear,
+		Semester:     payload.Semester,
+		Weekday:      time.Weekday(payload.Weekday),
+		StartTime:    payload.StartTime,
+		EndTime:      payload.EndTime,
+	}
+	if payload.RoomID != "" {
+		slot.RoomID = &payload.RoomID
+	}
+
+	s.slotRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if errPayload = s.checkSlot(tx, slot); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+		if err := s.slotRepo.WithTx(tx).Create(s.ctx, slot); err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+func (s *ContextedTimetable) GetSlot(payload payloads.RequestGetTimetableSlot) (*models.TimetableSlot, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	slot, err := s.slotRepo.GetFirstWithPreload(s.ctx, []string{"Class", "Subject", "Room"}, "id = ?", payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "timetable slot not found", nil)
+	}
+	slot.Class.GetName()
+	return &slot, nil
+}
+
+// GetSlots returns lessons of a term ordered by day and time, teacher only get own lessons
+func (s *ContextedTimetable) GetSlots(payload payloads.RequestGetTimetableSlots) ([]models.TimetableSlot, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	teacher, errPayload := s.currentTeacher()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	if teacher != nil {
+		payload.TeacherID = teacher.ID
+	}
+
+	q := gorm.G[models.TimetableSlot](s.slotRepo.DB()).
+		Preload("Class", nil).
+		Preload("Subject", nil).
+		Preload("Room", nil).
+		Where("academic_year = ? AND semester = ?", payload.AcademicYear, payload.Semester).
+		Order("weekday, start_time")
+	if payload.ClassID != "" {
+		q = q.Where("class_id = ?", payload.ClassID)
+	}
+	if payload.TeacherID != "" {
+		q = q.Where("teacher_id = ?", payload.TeacherID)
+	}
+	if payload.RoomID != "" {
+		q = q.Where("room_id = ?", payload.RoomID)
+	}
+	if payload.Weekday != 0 {
+		q = q.Where("weekday = ?", payload.Weekday)
+	}
+
+	slots, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	for i := range slots {
+		slots[i].Class.GetName()
+	}
+	return slots, nil
+}
+
+func (s *ContextedTimetable) UpdateSlot(payload payloads.RequestUpdateTimetableSlot) (slot *models.TimetableSlot, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	s.slotRepo.DB().Transaction(func(tx *gorm.DB) error {
+		found, err := gorm.G[models.TimetableSlot](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", payload.ID).First(s.ctx)
+		if err != nil {
+			errPayload = errorlib.MakeNotFound(err, "timetable slot not found", nil)
+			return err
+		}
+		slot = &found
+
+		slot.ClassID = payload.ClassID
+		slot.SubjectID = payload.SubjectID
+		slot.TeacherID = payload.TeacherID
+		slot.RoomID = nil
+		if payload.RoomID != "" {
+			slot.RoomID = &payload.RoomID
+		}
+		slot.AcademicYear = payload.AcademicYear
+		slot.Semester = payload.Semester
+		slot.Weekday = time.Weekday(payload.Weekday)
+		slot.StartTime = payload.StartTime
+		slot.EndTime = payload.EndTime
+		if errPayload = s.checkSlot(tx, slot); errPayload != nil {
+			return errors.New(errPayload.Message)
+		}
+
+		err = tx.WithContext(s.ctx).Model(slot).
+			Select("class_id", "subject_id", "teacher_id", "room_id", "academic_year", "semester", "weekday", "start_time", "end_time").
+			Updates(slot).Error
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		return nil
+	})
+	return
+}
+
+// DeleteSlot deletes lesson from timetable, journals of the lesson are kept without slot
+func (s *ContextedTimetable) DeleteSlot(payload payloads.RequestDeleteTimetableSlot) *reply.ErrorPayload {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return errPayload
+	}
+
+	if ok, err := s.slotRepo.DeleteByID(s.ctx, payload.ID); err != nil {
+		return errorlib.MakeServerError(err)
+	} else if !ok {
+		return errorlib.MakeNotFound(gorm.ErrRecordNotFound, "timetable slot not found", nil)
+	}
+	return nil
+}
+
+// GetCompliance lists lessons held by timetable in the date range which have no teaching journal, dates after today are not counted
+func (s *ContextedTimetable) GetCompliance(payload payloads.RequestGetJournalCompliance) (*payloads.ResponseJournalCompliance, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	from, _ := time.Parse(time.DateOnly, payload.From)
+	to, _ := time.Parse(time.DateOnly, payload.To)
+	if to.Before(from) {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "invalid date range", Fields: reply.FieldsError{"to": "to must not be before from"}}
+	}
+	if to.Sub(from) >= maxComplianceDays*24*time.Hour {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "date range is too long", Fields: reply.FieldsError{"to": "date range must not exceed 62 days"}}
+	}
+	if today := models.DateOf(time.Now()); to.After(today) {
+		to = today
+	}
+
+	report := &payloads.ResponseJournalCompliance{
+		From:     payload.From,
+		To:       payload.To,
+		Teachers: []payloads.ResponseTeacherCompliance{},
+		Missing:  []payloads.ResponseMissingJournal{},
+	}
+	if to.Before(from) {
+		return report, nil
+	}
+
+	q := gorm.G[models.TimetableSlot](s.slotRepo.DB()).
+		Preload("Class", nil).
+		Preload("Subject", nil).
+		Where("academic_year IN ?", slicelib.Unique([]string{models.AcademicYearOf(from), models.AcademicYearOf(to)})).
+		Order("weekday, start_time")
+	if payload.TeacherID != "" {
+		q = q.Where("teacher_id = ?", payload.TeacherID)
+	}
+	if payload.ClassID != "" {
+		q = q.Where("class_id = ?", payload.ClassID)
+	}
+	slots, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	if len(slots) == 0 {
+		return report, nil
+	}
+	slotIDs := make([]string, 0, len(slots))
+	teacherIDs := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		slotIDs = append(slotIDs, slot.ID)
+		teacherIDs = append(teacherIDs, slot.TeacherID)
+	}
+	teacherIDs = slicelib.Unique(teacherIDs)
+
+	var journals []struct {
+		SlotID string
+		Date   time.Time
+	}
+	err = s.journalRepo.DB().WithContext(s.ctx).Model(&models.TeachingJournal{}).
+		Select("slot_id, date").
+		Where("slot_id IN ? AND date BETWEEN ? AND ?", slotIDs, from, to).
+		Scan(&journals).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	filled := make(map[string]struct{}, len(journals))
+	for _, journal := range journals {
+		filled[journal.SlotID+journal.Date.Format(time.DateOnly)] = struct{}{}
+	}
+
+	var teachers []payloads.ResponseTeacherCompliance
+	err = s.teacherRepo.DB().WithContext(s.ctx).Table("teachers").
+		Select("teachers.id AS teacher_id, users.full_name").
+		Joins("JOIN users ON users.id = teachers.user_id").
+		Where("teachers.id IN ?", teacherIDs).
+		Order("users.full_name").
+		Scan(&teachers).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	teacherIndex := make(map[string]int, len(teachers))
+	for i, teacher := range teachers {
+		teacherIndex[teacher.TeacherID] = i
+	}
+
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		for i := range slots {
+			slot := &slots[i]
+			if !slot.HeldOn(date) {
+				continue
+			}
+			teacher := &teachers[teacherIndex[slot.TeacherID]]
+			report.Scheduled++
+			teacher.Scheduled++
+			if _, ok := filled[slot.ID+date.Format(time.DateOnly)]; ok {
+				report.Filled++
+				teacher.Filled++
+				continue
+			}
+			report.Missing = append(report.Missing, payloads.ResponseMissingJournal{
+				Date:        date.Format(time.DateOnly),
+				ClassName:   slot.Class.GetName(),
+				SubjectName: slot.Subject.Name,
+				TeacherName: teacher.FullName,
+				Slot:        slot,
+			})
+		}
+	}
+	report.Teachers = teachers
+	return report, nil
+}
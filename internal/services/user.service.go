@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"school-information-system/internal/repos"
+	"strings"
+
+	"github.com/chesta132/goreply/reply"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type User struct {
+	userRepo *repos.User
+}
+
+type ContextedUser struct {
+	*User
+	c   *gin.Context
+	ctx context.Context
+}
+
+func NewUser(userRepo *repos.User) *User {
+	return &User{userRepo}
+}
+
+func (s *User) ApplyContext(c *gin.Context) *ContextedUser {
+	return &ContextedUser{s, c, c.Request.Context()}
+}
+
+// currentAdmin returns admin profile of current user with permissions
+func (s *ContextedUser) currentAdmin() (*models.Admin, *reply.ErrorPayload) {
+	user, err := s.userRepo.GetFirstWithPreload(s.ctx, []string{"AdminProfile.Grants.Permission", "AdminProfile.Bundles.Permissions"}, "id = ?", s.c.GetString("userID"))
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "your user profile not found", nil)
+	}
+	if user.AdminProfile == nil {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeConflict, Message: "your admin profile or permission not registered"}
+	}
+	return user.AdminProfile, nil
+}
+
+// ensurePermissionOnUser makes sure current admin has permission on every resource which manages roles of the user,
+// student must also be in class scope of the permission
+func (s *ContextedUser) ensurePermissionOnUser(user *models.User, actions ...models.PermissionAction) *reply.ErrorPayload {
+	admin, errPayload := s.currentAdmin()
+	if errPayload != nil {
+		return errPayload
+	}
+	for _, role := range user.HeldRoles() {
+		resource := models.ResourceOfRole(role)
+		if action, missing := admin.MissingAction(resource, actions...); missing {
+			return &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: fmt.Sprintf("missing permission: %s.%s", resource, action)}
+		}
+	}
+
+	if !user.HasRole(models.RoleStudent) {
+		return nil
+	}
+	for _, action := range actions {
+		scope := admin.ScopeOf(models.ResourceStudent, action)
+		if scope == nil {
+			continue
+		}
+		cond, args := scopeCondition(scope, "class_id")
+		var count int64
+		err := s.userRepo.DB().WithContext(s.ctx).Model(new(models.Student)).Where("user_id = ? AND "+cond, append([]any{user.ID}, args...)...).Count(&count).Error
+		if err != nil {
+			return errorlib.MakeServerError(err)
+		}
+		if count == 0 {
+			return &replylib.ErrOutOfScope
+		}
+	}
+	return nil
+}
+
+// GetUsers searches users, admin only get users holding a role they have read permission on and students in their class scope
+func (s *ContextedUser) GetUsers(payload payloads.RequestGetUsers) ([]models.User, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	admin, errPayload := s.currentAdmin()
+	if errPayload != nil {
+		return nil, errPayload
+	}
+	roles := make([]models.UserRole, 0, len(models.UserRoles))
+	for _, role := range models.UserRoles {
+		if payload.Role != "" && role != payload.Role {
+			continue
+		}
+		if _, missing := admin.MissingAction(models.ResourceOfRole(role), models.ActionRead); !missing {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		message := "missing read permission on every user resource"
+		if payload.Role != "" {
+			message = fmt.Sprintf("missing permission: %s.%s", models.ResourceOfRole(payload.Role), models.ActionRead)
+		}
+		return nil, &reply.ErrorPayload{Code: replylib.CodeForbidden, Message: message}
+	}
+
+	db := s.userRepo.DB()
+	if payload.Archived {
+		db = db.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	conds := make([]string, len(roles))
+	args := make([]any, 0, len(roles))
+	for i, role := range roles {
+		if role == models.RoleUnsetted {
+			conds[i] = "role = ?"
+			args = append(args, role)
+			continue
+		}
+		conds[i] = "roles LIKE ?"
+		args = append(args, models.RolesPattern(role))
+
+		// students are limited to classes in scope of the permission
+		if role == models.RoleStudent {
+			if scope := admin.ScopeOf(models.ResourceStudent, models.ActionRead); scope != nil {
+				cond, scopeArgs := scopeCondition(scope, "class_id")
+				conds[i] = "(roles LIKE ? AND id IN (SELECT user_id FROM students WHERE deleted_at IS NULL AND " + cond + "))"
+				args = append(args, scopeArgs...)
+			}
+		}
+	}
+	q := gorm.G[models.User](db).
+		Where("("+strings.Join(conds, " OR ")+")", args...).
+		Order("full_name").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Gender != "" {
+		q = q.Where("gender = ?", payload.Gender)
+	}
+	if payload.Query != "" {
+		q = q.Where("LOWER(full_name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)", "%"+payload.Query+"%", "%"+payload.Query+"%")
+	}
+
+	users, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	return users, nil
+}
+
+// GetUser returns user with profile of the role, archived user included
+func (s *ContextedUser) GetUser(payload payloads.RequestGetUser) (*models.User, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	user, err := gorm.G[models.User](s.userRepo.DB().Unscoped()).
+		Preload("StudentProfile.Class", nil).
+		Preload("StudentProfile.Parents", nil).
+		Preload("TeacherProfile.Subjects", nil).
+		Preload("AdminProfile.Permissions", nil).
+		Preload("AdminProfile.Grants", nil).
+		Preload("AdminProfile.Bundles.Permissions", nil).
+		Preload("AlumniProfile", nil).
+		Where("id = ?", payload.ID).
+		First(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "user not found", nil)
+	}
+	if errPayload := s.ensurePermissionOnUser(&user, models.ActionRead); errPayload != nil {
+		return nil, errPayload
+	}
+	if user.StudentProfile != nil && user.StudentProfile.Class != nil {
+		user.StudentProfile.Class.GetName()
+	}
+	return &user, nil
+}
+
+// ArchiveUser archives user, archived user can not sign in and is deleted permanently by cron job after configured duration.
+// Admin must be deactivated first
+func (s *ContextedUser) ArchiveUser(payload payloads.RequestArchiveUser) (*models.User, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if payload.ID == s.c.GetString("userID") {
+		return nil, &reply.ErrorPayload{Code: replylib.CodeUnprocessableEntity, Message: "can not archive yourself"}
+	}
+
+	user, err := s.userRepo.GetByID(s.ctx, payload.ID)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "user not found", nil)
+	}
+	if errPayload := s.ensurePermissionOnUser(&user, models.ActionDelete); errPayload != nil {
+		return nil, errPayload
+	}
+	// permissions of admin must be revoked first so seed permissions stay held
+	if user.HasRole(models.RoleAdmin) {
+		return nil, &reply.ErrorPayload{
+			Code:    replylib.CodeUnprocessableEntity,
+			Message: "targeted user is an admin",
+			Fields:  reply.FieldsError{"id": "deactivate the admin before archiving the user"},
+		}
+	}
+
+	if err := s.userRepo.Archive(s.ctx, "id = ?", user.ID); err != nil {
+		return nil, errorlib.MakeNotFound(err, "user not found", nil)
+	}
+	return &user, nil
+}
+
+func (s *ContextedUser) RestoreUser(payload payloads.RequestRestoreUser) (*models.User, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	user, err := gorm.G[models.User](s.userRepo.DB().Unscoped()).Where("id = ? AND deleted_at IS NOT NULL", payload.ID).First(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeNotFound(err, "archived user not found", nil)
+	}
+	if errPayload := s.ensurePermissionOnUser(&user, models.ActionUpdate); errPayload != nil {
+		return nil, errPayload
+	}
+
+	if err := s.userRepo.Restore(s.ctx, "id = ?", user.ID); err != nil {
+		return nil, errorlib.MakeNotFound(err, "archived user not found", nil)
+	}
+	user.DeletedAt = gorm.DeletedAt{}
+	return &user, nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"school-information-system/config"
+	"school-information-system/internal/libs/errorlib"
+	"school-information-system/internal/libs/replylib"
+	"school-information-system/internal/libs/slicelib"
+	"school-information-system/internal/libs/validatorlib"
+	"school-information-system/internal/models"
+	"school-information-system/internal/models/payloads"
+	"strings"
+	"time"
+
+	"github.com/chesta132/goreply/reply"
+	"gorm.io/gorm"
+)
+
+// GetPendingUsers returns queue of self-registered users waiting for activation from the oldest, with hints of possible duplicate
+func (s *ContextedUser) GetPendingUsers(payload payloads.RequestGetPendingUsers) ([]payloads.ResponsePendingUser, *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	q := gorm.G[models.User](s.userRepo.DB()).
+		Where("role = ? AND activated_at IS NULL", models.RoleUnsetted).
+		Order("created_at").
+		Limit(config.LIMIT_PAGINATED_DATA + 1).Offset(payload.Offset)
+	if payload.Query != "" {
+		q = q.Where("LOWER(full_name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)", "%"+payload.Query+"%", "%"+payload.Query+"%")
+	}
+	users, err := q.Find(s.ctx)
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	pending := make([]payloads.ResponsePendingUser, 0, len(users))
+	if len(users) == 0 {
+		return pending, nil
+	}
+
+	phones := make([]string, 0, len(users))
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		phones = append(phones, user.Phone)
+		names = append(names, strings.ToLower(user.FullName))
+	}
+	names = slicelib.Unique(names)
+
+	// parents are registered by admin, so a parent may sign up with the same phone or name
+	var parents []models.Parent
+	err = s.userRepo.DB().WithContext(s.ctx).
+		Where("phone IN ? OR LOWER(full_name) IN ?", phones, names).
+		Find(&parents).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+	var students []struct {
+		ID       string
+		FullName string
+	}
+	err = s.userRepo.DB().WithContext(s.ctx).Table("students").
+		Select("students.id, users.full_name").
+		Joins("JOIN users ON users.id = students.user_id AND users.deleted_at IS NULL").
+		Where("LOWER(users.full_name) IN ?", names).
+		Scan(&students).Error
+	if err != nil {
+		return nil, errorlib.MakeServerError(err)
+	}
+
+	now := time.Now()
+	for _, user := range users {
+		hints := []payloads.ResponseDuplicateHint{}
+		for _, parent := range parents {
+			switch {
+			case parent.Phone == user.Phone:
+				hints = append(hints, payloads.ResponseDuplicateHint{Kind: "parent", ID: parent.ID, FullName: parent.FullName, MatchedBy: "phone"})
+			case strings.EqualFold(parent.FullName, user.FullName):
+				hints = append(hints, payloads.ResponseDuplicateHint{Kind: "parent", ID: parent.ID, FullName: parent.FullName, MatchedBy: "name"})
+			}
+		}
+		for _, student := range students {
+			if strings.EqualFold(student.FullName, user.FullName) {
+				hints = append(hints, payloads.ResponseDuplicateHint{Kind: "student", ID: student.ID, FullName: student.FullName, MatchedBy: "name"})
+			}
+		}
+		pending = append(pending, payloads.ResponsePendingUser{
+			User:        user,
+			WaitingDays: int(now.Sub(user.CreatedAt).Hours() / 24),
+			Duplicates:  hints,
+		})
+	}
+	return pending, nil
+}
+
+// RejectPendingUsers archives self-registered users waiting for activation, every user must still be waiting
+func (s *ContextedUser) RejectPendingUsers(payload payloads.RequestRejectPendingUsers) (rejected *payloads.ResponseRejectPendingUsers, errPayload *reply.ErrorPayload) {
+	// validate payload
+	if errPayload := validatorlib.ValidateStructToReply(payload); errPayload != nil {
+		return nil, errPayload
+	}
+
+	// removes duplicate ids
+	payload.IDs = slicelib.Unique(payload.IDs)
+
+	s.userRepo.DB().Transaction(func(tx *gorm.DB) error {
+		userRepo := s.userRepo.WithTx(tx)
+
+		rows, err := userRepo.ArchiveAll(s.ctx, "id IN ? AND role = ? AND activated_at IS NULL", payload.IDs, models.RoleUnsetted)
+		if err != nil {
+			errPayload = errorlib.MakeServerError(err)
+			return err
+		}
+		if notFound := len(payload.IDs) - rows; notFound > 0 {
+			errPayload = &reply.ErrorPayload{
+				Code:    replylib.CodeNotFound,
+				Message: "pending user(s) not found",
+				Fields:  reply.FieldsError{"ids": fmt.Sprintf("%d user(s) with these id not found or already activated", notFound)},
+			}
+			return errors.New(errPayload.Message)
+		}
+
+		rejected = &payloads.ResponseRejectPendingUsers{Rejected: rows}
+		return nil
+	})
+	return
+}
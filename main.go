@@ -33,11 +33,28 @@ func main() {
 		router.RegisterBase(r)
 		router.RegisterAuth(api.Group("/auth"))
 		router.RegisterAdmin(api.Group("/admins"))
+		router.RegisterUser(api.Group("/users"))
 		router.RegisterSubject(api.Group("/subjects"))
 		router.RegisterClass(api.Group("/classes"))
 		router.RegisterParent(api.Group("/parents"))
 		router.RegisterStudent(api.Group("/students"))
 		router.RegisterTeacher(api.Group("/teachers"))
+		router.RegisterAlumni(api.Group("/alumni"))
+		router.RegisterAdmission(api.Group("/admissions"))
+		router.RegisterMaterial(api.Group("/materials"))
+		router.RegisterAssignment(api.Group("/assignments"))
+		router.RegisterQuiz(api.Group("/quizzes"))
+		router.RegisterQuestionBank(api.Group("/question-bank"))
+		router.RegisterExam(api.Group("/exams"))
+		router.RegisterRoom(api.Group("/rooms"))
+		router.RegisterHealth(api.Group("/student-health"))
+		router.RegisterAchievement(api.Group("/achievements"))
+		router.RegisterScholarship(api.Group("/scholarships"))
+		router.RegisterDormitory(api.Group("/dormitories"))
+		router.RegisterInternship(api.Group("/internships"))
+		router.RegisterTimetable(api.Group("/timetables"))
+		router.RegisterNotification(api.Group("/notifications"))
+		router.RegisterMe(api.Group("/me"))
 	}
 
 	// start cron jobs